@@ -0,0 +1,192 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+// Package client provides structured access to the Limes v2 API.
+//
+// The v2 API is split into two parts that appear as separate services in the Keystone catalog:
+// Use [NewResourcesClient] for all endpoints below /resources/v2/, and [NewRatesClient] for all endpoints below /rates/v2/.
+// All request and response payloads are the types declared in package apiv2 and its subpackages.
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/sapcc/go-api-declarations/opts"
+	. "go.xyrillian.de/gg/option"
+
+	ratesv2 "github.com/sapcc/limes/apideclarations/apiv2/rates"
+)
+
+// ClientOpts contains additional options for NewResourcesClient() and NewRatesClient().
+type ClientOpts struct {
+	// The service type of the respective API in the Keystone catalog.
+	// If not given, the default service type ("limitas-resources" or "limitas-rates") is used.
+	ServiceType string
+
+	// Skips inspecting the Keystone catalog and assumes that the API is located at this base URL,
+	// e.g. "https://limes.example.com/resources/v2/".
+	EndpointOverride string
+}
+
+func newServiceClient(provider *gophercloud.ProviderClient, endpointOpts gophercloud.EndpointOpts, clientOpts ClientOpts, defaultServiceType string) (gophercloud.ServiceClient, error) {
+	if clientOpts.ServiceType == "" {
+		clientOpts.ServiceType = defaultServiceType
+	}
+
+	endpoint := clientOpts.EndpointOverride
+	if endpoint == "" {
+		endpointOpts.ApplyDefaults(clientOpts.ServiceType)
+		var err error
+		endpoint, err = provider.EndpointLocator(endpointOpts)
+		if err != nil {
+			return gophercloud.ServiceClient{}, err
+		}
+	}
+
+	return gophercloud.ServiceClient{
+		ProviderClient: provider,
+		Endpoint:       gophercloud.NormalizeURL(endpoint),
+		Type:           clientOpts.ServiceType,
+	}, nil
+}
+
+// Error is returned by all client methods when Limes responds with an error status.
+// If Limes rejects the token, the status code is 401 (Unauthorized).
+// If the token does not confer the required permissions, the status code is 403 (Forbidden).
+type Error struct {
+	Method     string
+	URL        string
+	StatusCode int
+	// Message is the error message from the response body.
+	Message string
+	// RetryAfter is the value of the Retry-After header, if any.
+	// Limes sets this header when a commitment is rejected with status 409 (Conflict) for lack of capacity,
+	// if it can estimate when a retry might succeed.
+	RetryAfter Option[time.Time]
+	// UnacceptableRateLimits is only filled for errors returned by RatesClient.PutProject().
+	UnacceptableRateLimits []ratesv2.UnacceptableRateLimit
+
+	inner gophercloud.ErrUnexpectedResponseCode
+}
+
+// Error implements the builtin/error interface.
+func (e Error) Error() string {
+	return fmt.Sprintf("%s %s returned %d: %s", e.Method, e.URL, e.StatusCode, e.Message)
+}
+
+// Unwrap implements the interface implied by package errors.
+// The inner error is a gophercloud.ErrUnexpectedResponseCode, so gophercloud.ResponseCodeIs() works on Error.
+func (e Error) Unwrap() error {
+	return e.inner
+}
+
+// translateError converts errors for unexpected response codes into type Error.
+// Other errors (e.g. from the network layer) are returned unchanged.
+func translateError(err error) error {
+	var inner gophercloud.ErrUnexpectedResponseCode
+	if !errors.As(err, &inner) {
+		return err
+	}
+	result := Error{
+		Method:     inner.Method,
+		URL:        inner.URL,
+		StatusCode: inner.Actual,
+		Message:    strings.TrimSpace(string(inner.Body)),
+		RetryAfter: parseRetryAfter(inner.ResponseHeader.Get("Retry-After"), time.Now()),
+		inner:      inner,
+	}
+
+	// some errors carry a structured response body instead of a plain error message
+	if strings.HasPrefix(inner.ResponseHeader.Get("Content-Type"), "application/json") {
+		var body ratesv2.ProjectRateLimitErrorResponse
+		if json.Unmarshal(inner.Body, &body) == nil && len(body.UnacceptableRateLimits) > 0 {
+			result.UnacceptableRateLimits = body.UnacceptableRateLimits
+			messages := make([]string, len(body.UnacceptableRateLimits))
+			for idx, rl := range body.UnacceptableRateLimits {
+				messages[idx] = fmt.Sprintf("%s/%s: %s", rl.ServiceType, rl.RateName, rl.Message)
+			}
+			result.Message = strings.Join(messages, "; ")
+		}
+	}
+	return result
+}
+
+// parseRetryAfter parses a Retry-After header, which contains either an HTTP date or a number of seconds.
+func parseRetryAfter(value string, now time.Time) Option[time.Time] {
+	if value == "" {
+		return None[time.Time]()
+	}
+	seconds, err := strconv.ParseUint(value, 10, 32)
+	if err == nil {
+		return Some(now.Add(time.Duration(seconds) * time.Second))
+	}
+	t, err := http.ParseTime(value)
+	if err == nil {
+		return Some(t)
+	}
+	return None[time.Time]()
+}
+
+type idempotencyKeyContextKey struct{}
+
+// WithIdempotencyKey returns a context that makes all requests executed with it carry the given Idempotency-Key header.
+// Limes honors this header on all endpoints that create or change commitments:
+// When a request is retried with the same key (e.g. after a network error), the commitments are not changed again,
+// and the response of the original request is returned instead.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyContextKey{}, key)
+}
+
+// sendRequest executes a request against the given service client.
+// If `headers` is not nil, these headers are added to the request,
+// as well as the Idempotency-Key header if one was given via WithIdempotencyKey().
+// If `reqBody` is not nil, it is sent as the JSON request body.
+// If `result` is not nil, the response body is decoded into it.
+func sendRequest(ctx context.Context, sc *gophercloud.ServiceClient, method, url string, headers map[string]string, queryOpts, reqBody any, okCode int, result any) error {
+	if queryOpts != nil {
+		query, err := opts.BuildQueryString(queryOpts)
+		if err != nil {
+			return err
+		}
+		if len(query) > 0 {
+			url += "?" + query.Encode()
+		}
+	}
+
+	reqOpts := gophercloud.RequestOpts{KeepResponseBody: true, OkCodes: []int{okCode}}
+	if reqBody != nil {
+		reqOpts.JSONBody = reqBody
+	}
+	reqOpts.MoreHeaders = maps.Clone(headers)
+	if key, ok := ctx.Value(idempotencyKeyContextKey{}).(string); ok && key != "" {
+		if reqOpts.MoreHeaders == nil {
+			reqOpts.MoreHeaders = make(map[string]string)
+		}
+		reqOpts.MoreHeaders["Idempotency-Key"] = key
+	}
+	resp, err := sc.Request(ctx, method, url, &reqOpts)
+	if err != nil {
+		return translateError(err)
+	}
+	defer resp.Body.Close()
+	if result == nil {
+		return nil
+	}
+
+	// Unlike the server, we do not DisallowUnknownFields() here,
+	// so that older clients keep working when newer versions of Limes add fields to the response.
+	err = json.NewDecoder(resp.Body).Decode(result)
+	if err != nil {
+		return fmt.Errorf("could not parse response body from %s %s: %w", method, url, err)
+	}
+	return nil
+}
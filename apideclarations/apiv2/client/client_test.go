@@ -0,0 +1,136 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gophercloud/gophercloud/v2"
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"go.xyrillian.de/gg/assert"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/apideclarations/apiv2/client"
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	ratesv2 "github.com/sapcc/limes/apideclarations/apiv2/rates"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+)
+
+func TestResourcesClient(t *testing.T) {
+	retryAt := time.Unix(1767225600, 0).UTC()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /resources/v2/commitments", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.URL.RawQuery, "service=first&status=pending&status=planned")
+		assert.Equal(t, r.Header.Get("X-Auth-Token"), "dummy-token")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"commitments":[{"uuid":"uuid-for-commitment","amount":10,"unknown_field":42}]}`)
+	})
+	mux.HandleFunc("POST /resources/v2/commitments/new", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, string(body), `{"dry_run":false,"amount":10,"duration":"1 year","project_id":"uuid-for-berlin","service_type":"first","resource_name":"capacity","availability_zone":"az-one","status":"pending"}`)
+		w.Header().Set("Retry-After", retryAt.Format(http.TimeFormat))
+		http.Error(w, "not enough capacity!", http.StatusConflict)
+	})
+	mux.HandleFunc("POST /resources/v2/commitments/{uuid}/accept-transfer", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.PathValue("uuid"), "uuid-for-commitment")
+		assert.Equal(t, r.Header.Get("Transfer-Token"), "dummy-transfer-token")
+		assert.Equal(t, r.Header.Get("Idempotency-Key"), "dummy-idempotency-key")
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, string(body), `{"project_id":"uuid-for-dresden"}`)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"uuid":"uuid-for-commitment","amount":10,"project_id":"uuid-for-dresden"}`)
+	})
+	mux.HandleFunc("DELETE /resources/v2/commitments/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	provider := &gophercloud.ProviderClient{TokenID: "dummy-token"}
+	c, err := client.NewResourcesClient(provider, gophercloud.EndpointOpts{}, client.ClientOpts{
+		EndpointOverride: srv.URL + "/resources/v2", // trailing slash is added automatically
+	})
+	assert.ErrEqual(t, err, nil)
+
+	// successful request: query options are encoded, unknown response fields are ignored
+	list, err := c.GetCommitments(t.Context(), common.CommitmentListOpts{
+		ServiceType: Some[common.ServiceType]("first"),
+		Status:      []liquid.CommitmentStatus{liquid.CommitmentStatusPending, liquid.CommitmentStatusPlanned},
+	})
+	assert.ErrEqual(t, err, nil)
+	assert.Equal(t, len(list.Commitments), 1)
+	assert.Equal(t, list.Commitments[0].UUID, "uuid-for-commitment")
+	assert.Equal(t, list.Commitments[0].Amount, 10)
+
+	// rejection with Retry-After
+	duration, err := limesresources.ParseCommitmentDuration("1 year")
+	assert.ErrEqual(t, err, nil)
+	_, err = c.CreateCommitment(t.Context(), resourcesv2.CommitmentRequest{
+		Amount:           10,
+		Duration:         duration,
+		ProjectUUID:      "uuid-for-berlin",
+		ServiceType:      "first",
+		ResourceName:     "capacity",
+		AvailabilityZone: "az-one",
+		Status:           liquid.CommitmentStatusPending,
+	})
+	var apiErr client.Error
+	if assert.Equal(t, errors.As(err, &apiErr), true) {
+		assert.Equal(t, apiErr.StatusCode, http.StatusConflict)
+		assert.Equal(t, apiErr.Message, "not enough capacity!")
+		assert.Equal(t, apiErr.RetryAfter, Some(retryAt))
+	}
+	assert.Equal(t, gophercloud.ResponseCodeIs(err, http.StatusConflict), true)
+
+	// headers: the transfer token is a method argument, the Idempotency-Key comes from the context
+	ctx := client.WithIdempotencyKey(t.Context(), "dummy-idempotency-key")
+	accepted, err := c.AcceptCommitmentTransfer(ctx, "uuid-for-commitment", "dummy-transfer-token", resourcesv2.CommitmentAcceptTransferRequest{
+		ProjectUUID: "uuid-for-dresden",
+	})
+	assert.ErrEqual(t, err, nil)
+	assert.Equal(t, accepted.ProjectUUID, "uuid-for-dresden")
+
+	// rejection without Retry-After
+	err = c.DeleteCommitment(t.Context(), "uuid-for-commitment")
+	if assert.Equal(t, errors.As(err, &apiErr), true) {
+		assert.Equal(t, apiErr.StatusCode, http.StatusForbidden)
+		assert.Equal(t, apiErr.RetryAfter, None[time.Time]())
+	}
+}
+
+func TestRatesClientWithUnacceptableRateLimits(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /rates/v2/projects/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_, _ = io.WriteString(w, `{"unacceptable_rate_limits":[{"service_type":"first","rate_name":"objects:create","status":422,"message":"limit too high"}]}`)
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c, err := client.NewRatesClient(&gophercloud.ProviderClient{}, gophercloud.EndpointOpts{}, client.ClientOpts{
+		EndpointOverride: srv.URL + "/rates/v2/",
+	})
+	assert.ErrEqual(t, err, nil)
+
+	err = c.PutProject(t.Context(), "uuid-for-berlin", ratesv2.ProjectRateLimitRequest{})
+	var apiErr client.Error
+	if assert.Equal(t, errors.As(err, &apiErr), true) {
+		assert.Equal(t, apiErr.StatusCode, http.StatusUnprocessableEntity)
+		assert.Equal(t, apiErr.Message, "first/objects:create: limit too high")
+		assert.Equal(t, apiErr.UnacceptableRateLimits, []ratesv2.UnacceptableRateLimit{{
+			ServiceType: "first",
+			RateName:    "objects:create",
+			Status:      http.StatusUnprocessableEntity,
+			Message:     "limit too high",
+		}})
+	}
+}
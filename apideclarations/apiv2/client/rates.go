@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/sapcc/go-api-declarations/liquid"
+
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	ratesv2 "github.com/sapcc/limes/apideclarations/apiv2/rates"
+)
+
+// RatesClient provides structured access to the endpoints below /rates/v2/.
+type RatesClient struct {
+	gophercloud.ServiceClient
+}
+
+// NewRatesClient creates a RatesClient.
+// Unless overridden in the ClientOpts, the endpoint is located in the Keystone catalog under the service type "limitas-rates".
+func NewRatesClient(provider *gophercloud.ProviderClient, endpointOpts gophercloud.EndpointOpts, clientOpts ClientOpts) (*RatesClient, error) {
+	sc, err := newServiceClient(provider, endpointOpts, clientOpts, "limitas-rates")
+	if err != nil {
+		return nil, err
+	}
+	return &RatesClient{ServiceClient: sc}, nil
+}
+
+// GetInfo executes GET /rates/v2/info.
+func (c *RatesClient) GetInfo(ctx context.Context) (result ratesv2.InfoReport, err error) {
+	url := c.ServiceURL("info")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, nil, nil, http.StatusOK, &result)
+	return
+}
+
+// GetCluster executes GET /rates/v2/cluster.
+func (c *RatesClient) GetCluster(ctx context.Context, opts common.ClusterRateReportOpts) (result ratesv2.ClusterGetResponse, err error) {
+	url := c.ServiceURL("cluster")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// GetDomains executes GET /rates/v2/domains.
+func (c *RatesClient) GetDomains(ctx context.Context, opts common.DomainRateReportOpts) (result ratesv2.DomainGetResponse, err error) {
+	url := c.ServiceURL("domains")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// GetDomain executes GET /rates/v2/domains/:domain_uuid.
+func (c *RatesClient) GetDomain(ctx context.Context, domainUUID string, opts common.DomainRateReportOpts) (result ratesv2.DomainGetResponse, err error) {
+	url := c.ServiceURL("domains", domainUUID)
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// GetProjects executes GET /rates/v2/projects.
+func (c *RatesClient) GetProjects(ctx context.Context, opts common.ProjectRateReportOpts) (result ratesv2.ProjectGetResponse, err error) {
+	url := c.ServiceURL("projects")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// GetProject executes GET /rates/v2/projects/:project_uuid.
+func (c *RatesClient) GetProject(ctx context.Context, projectUUID liquid.ProjectUUID, opts common.ProjectRateReportOpts) (result ratesv2.ProjectGetResponse, err error) {
+	url := c.ServiceURL("projects", string(projectUUID))
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// PutProject executes PUT /rates/v2/projects/:project_uuid.
+// If any of the requested rate limits is unacceptable, the returned Error explains why in its UnacceptableRateLimits field.
+func (c *RatesClient) PutProject(ctx context.Context, projectUUID liquid.ProjectUUID, req ratesv2.ProjectRateLimitRequest) error {
+	url := c.ServiceURL("projects", string(projectUUID))
+	return sendRequest(ctx, &c.ServiceClient, http.MethodPut, url, nil, nil, req, http.StatusNoContent, nil)
+}
@@ -0,0 +1,204 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package client
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gophercloud/gophercloud/v2"
+	"github.com/sapcc/go-api-declarations/liquid"
+
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+)
+
+// ResourcesClient provides structured access to the endpoints below /resources/v2/.
+type ResourcesClient struct {
+	gophercloud.ServiceClient
+}
+
+// NewResourcesClient creates a ResourcesClient.
+// Unless overridden in the ClientOpts, the endpoint is located in the Keystone catalog under the service type "limitas-resources".
+func NewResourcesClient(provider *gophercloud.ProviderClient, endpointOpts gophercloud.EndpointOpts, clientOpts ClientOpts) (*ResourcesClient, error) {
+	sc, err := newServiceClient(provider, endpointOpts, clientOpts, "limitas-resources")
+	if err != nil {
+		return nil, err
+	}
+	return &ResourcesClient{ServiceClient: sc}, nil
+}
+
+// GetInfo executes GET /resources/v2/info.
+func (c *ResourcesClient) GetInfo(ctx context.Context) (result resourcesv2.InfoReport, err error) {
+	url := c.ServiceURL("info")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, nil, nil, http.StatusOK, &result)
+	return
+}
+
+// GetCluster executes GET /resources/v2/cluster.
+func (c *ResourcesClient) GetCluster(ctx context.Context, opts common.ClusterResourceReportOpts) (result resourcesv2.ClusterGetResponse, err error) {
+	url := c.ServiceURL("cluster")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// GetDomains executes GET /resources/v2/domains.
+func (c *ResourcesClient) GetDomains(ctx context.Context, opts common.DomainResourceReportOpts) (result resourcesv2.DomainGetResponse, err error) {
+	url := c.ServiceURL("domains")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// GetDomain executes GET /resources/v2/domains/:domain_uuid.
+func (c *ResourcesClient) GetDomain(ctx context.Context, domainUUID string, opts common.DomainResourceReportOpts) (result resourcesv2.DomainGetResponse, err error) {
+	url := c.ServiceURL("domains", domainUUID)
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// GetProjects executes GET /resources/v2/projects.
+func (c *ResourcesClient) GetProjects(ctx context.Context, opts common.ProjectResourceReportOpts) (result resourcesv2.ProjectGetResponse, err error) {
+	url := c.ServiceURL("projects")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// GetProject executes GET /resources/v2/projects/:project_uuid.
+func (c *ResourcesClient) GetProject(ctx context.Context, projectUUID liquid.ProjectUUID, opts common.ProjectResourceReportOpts) (result resourcesv2.ProjectGetResponse, err error) {
+	url := c.ServiceURL("projects", string(projectUUID))
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// PutProjectMaxQuota executes PUT /resources/v2/projects/:project_uuid/max-quota.
+func (c *ResourcesClient) PutProjectMaxQuota(ctx context.Context, projectUUID liquid.ProjectUUID, req resourcesv2.ProjectMaxQuotaRequest) error {
+	url := c.ServiceURL("projects", string(projectUUID), "max-quota")
+	return sendRequest(ctx, &c.ServiceClient, http.MethodPut, url, nil, nil, req, http.StatusNoContent, nil)
+}
+
+// PutProjectForbidAutogrowth executes PUT /resources/v2/projects/:project_uuid/forbid-autogrowth.
+func (c *ResourcesClient) PutProjectForbidAutogrowth(ctx context.Context, projectUUID liquid.ProjectUUID, req resourcesv2.ProjectForbidAutogrowthRequest) error {
+	url := c.ServiceURL("projects", string(projectUUID), "forbid-autogrowth")
+	return sendRequest(ctx, &c.ServiceClient, http.MethodPut, url, nil, nil, req, http.StatusNoContent, nil)
+}
+
+// GetAvailability executes GET /resources/v2/availability.
+func (c *ResourcesClient) GetAvailability(ctx context.Context, opts common.AvailabilityReportOpts) (result resourcesv2.AvailabilityGetResponse, err error) {
+	url := c.ServiceURL("availability")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// GetInconsistencies executes GET /resources/v2/admin/inconsistencies.
+func (c *ResourcesClient) GetInconsistencies(ctx context.Context, opts common.AdminReportOpts) (result resourcesv2.InconsistenciesGetResponse, err error) {
+	url := c.ServiceURL("admin", "inconsistencies")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// GetScrapeErrors executes GET /resources/v2/admin/scrape-errors.
+func (c *ResourcesClient) GetScrapeErrors(ctx context.Context, opts common.AdminReportOpts) (result resourcesv2.ScrapeErrorsGetResponse, err error) {
+	url := c.ServiceURL("admin", "scrape-errors")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// GetArchivedCommitments executes GET /resources/v2/admin/archived-commitments.
+func (c *ResourcesClient) GetArchivedCommitments(ctx context.Context, opts common.ArchivedCommitmentListOpts) (result resourcesv2.ArchivedCommitmentListResponse, err error) {
+	url := c.ServiceURL("admin", "archived-commitments")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// GetCommitments executes GET /resources/v2/commitments.
+func (c *ResourcesClient) GetCommitments(ctx context.Context, opts common.CommitmentListOpts) (result resourcesv2.CommitmentListResponse, err error) {
+	url := c.ServiceURL("commitments")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, opts, nil, http.StatusOK, &result)
+	return
+}
+
+// CreateCommitment executes POST /resources/v2/commitments/new.
+func (c *ResourcesClient) CreateCommitment(ctx context.Context, req resourcesv2.CommitmentRequest) (result resourcesv2.Commitment, err error) {
+	url := c.ServiceURL("commitments", "new")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodPost, url, nil, nil, req, http.StatusCreated, &result)
+	return
+}
+
+// CreateCommitmentBatch executes POST /resources/v2/commitments/batch.
+func (c *ResourcesClient) CreateCommitmentBatch(ctx context.Context, req resourcesv2.CommitmentBatchRequest) (result resourcesv2.CommitmentOperationResponse, err error) {
+	url := c.ServiceURL("commitments", "batch")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodPost, url, nil, nil, req, http.StatusCreated, &result)
+	return
+}
+
+// MergeCommitments executes POST /resources/v2/commitments/merge.
+func (c *ResourcesClient) MergeCommitments(ctx context.Context, req resourcesv2.CommitmentMergeRequest) (result resourcesv2.CommitmentOperationResponse, err error) {
+	url := c.ServiceURL("commitments", "merge")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodPost, url, nil, nil, req, http.StatusOK, &result)
+	return
+}
+
+// GetCommitment executes GET /resources/v2/commitments/:uuid.
+func (c *ResourcesClient) GetCommitment(ctx context.Context, commitmentUUID liquid.CommitmentUUID) (result resourcesv2.Commitment, err error) {
+	url := c.ServiceURL("commitments", string(commitmentUUID))
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, nil, nil, http.StatusOK, &result)
+	return
+}
+
+// DeleteCommitment executes DELETE /resources/v2/commitments/:uuid.
+func (c *ResourcesClient) DeleteCommitment(ctx context.Context, commitmentUUID liquid.CommitmentUUID) error {
+	url := c.ServiceURL("commitments", string(commitmentUUID))
+	return sendRequest(ctx, &c.ServiceClient, http.MethodDelete, url, nil, nil, nil, http.StatusNoContent, nil)
+}
+
+// GetCommitmentLineage executes GET /resources/v2/commitments/:uuid/lineage.
+func (c *ResourcesClient) GetCommitmentLineage(ctx context.Context, commitmentUUID liquid.CommitmentUUID) (result resourcesv2.CommitmentLineageResponse, err error) {
+	url := c.ServiceURL("commitments", string(commitmentUUID), "lineage")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, nil, nil, http.StatusOK, &result)
+	return
+}
+
+// SplitCommitment executes POST /resources/v2/commitments/:uuid/split.
+func (c *ResourcesClient) SplitCommitment(ctx context.Context, commitmentUUID liquid.CommitmentUUID, req resourcesv2.CommitmentSplitRequest) (result resourcesv2.CommitmentOperationResponse, err error) {
+	url := c.ServiceURL("commitments", string(commitmentUUID), "split")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodPost, url, nil, nil, req, http.StatusOK, &result)
+	return
+}
+
+// ConvertCommitment executes POST /resources/v2/commitments/:uuid/convert.
+func (c *ResourcesClient) ConvertCommitment(ctx context.Context, commitmentUUID liquid.CommitmentUUID, req resourcesv2.CommitmentConvertRequest) (result resourcesv2.CommitmentOperationResponse, err error) {
+	url := c.ServiceURL("commitments", string(commitmentUUID), "convert")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodPost, url, nil, nil, req, http.StatusOK, &result)
+	return
+}
+
+// AmendCommitment executes POST /resources/v2/commitments/:uuid/amend.
+func (c *ResourcesClient) AmendCommitment(ctx context.Context, commitmentUUID liquid.CommitmentUUID, req resourcesv2.CommitmentAmendRequest) (result resourcesv2.CommitmentOperationResponse, err error) {
+	url := c.ServiceURL("commitments", string(commitmentUUID), "amend")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodPost, url, nil, nil, req, http.StatusOK, &result)
+	return
+}
+
+// StartCommitmentTransfer executes POST /resources/v2/commitments/:uuid/start-transfer.
+func (c *ResourcesClient) StartCommitmentTransfer(ctx context.Context, commitmentUUID liquid.CommitmentUUID, req resourcesv2.CommitmentTransferRequest) (result resourcesv2.Commitment, err error) {
+	url := c.ServiceURL("commitments", string(commitmentUUID), "start-transfer")
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodPost, url, nil, nil, req, http.StatusOK, &result)
+	return
+}
+
+// GetCommitmentByTransferToken executes GET /resources/v2/commitments/by-transfer-token/:token.
+func (c *ResourcesClient) GetCommitmentByTransferToken(ctx context.Context, transferToken string) (result resourcesv2.Commitment, err error) {
+	url := c.ServiceURL("commitments", "by-transfer-token", transferToken)
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodGet, url, nil, nil, nil, http.StatusOK, &result)
+	return
+}
+
+// AcceptCommitmentTransfer executes POST /resources/v2/commitments/:uuid/accept-transfer.
+// The transfer token is the one that was generated when the transfer was started.
+func (c *ResourcesClient) AcceptCommitmentTransfer(ctx context.Context, commitmentUUID liquid.CommitmentUUID, transferToken string, req resourcesv2.CommitmentAcceptTransferRequest) (result resourcesv2.Commitment, err error) {
+	url := c.ServiceURL("commitments", string(commitmentUUID), "accept-transfer")
+	headers := map[string]string{"Transfer-Token": transferToken}
+	err = sendRequest(ctx, &c.ServiceClient, http.MethodPost, url, headers, nil, req, http.StatusOK, &result)
+	return
+}
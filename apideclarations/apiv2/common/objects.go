@@ -3,6 +3,13 @@
 
 package common
 
+// ServiceType identifies a backend service that can have resources or rates.
+//
+// It is legally distinct from `limes.ServiceType` from the v1 API to ensure that
+// the ResourceBehavior.IdentityInV1API mapping is applied when converting between
+// v1 and v2 identifiers.
+type ServiceType string
+
 // DomainMetadata contains the metadata for a domain.
 // It appears in types [resourcesv2.DomainReport] and [ratesv2.DomainReport].
 type DomainMetadata struct {
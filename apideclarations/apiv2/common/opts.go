@@ -0,0 +1,195 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"time"
+
+	"github.com/sapcc/go-api-declarations/limes"
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	. "go.xyrillian.de/gg/option"
+)
+
+// GenericReportOpts contains query parameter options shared between
+// resources and rates reports.
+// It appears in types ResourceReportOpts and RateReportOpts.
+type GenericReportOpts struct {
+	// Area is a grouping, used to filter for multiple services
+	Area Option[string] `q:"area"`
+	// ServiceType filters services by type
+	ServiceType Option[ServiceType] `q:"service"`
+	// Category is a grouping, used to filter for multiple resources or rates
+	Category Option[liquid.CategoryName] `q:"category"`
+	// enrich response with InfoReport
+	WithInfo bool `q:"with,value:info"`
+}
+
+// ResourceReportOpts contains query parameter options for resource reports.
+// It appears in types ClusterResourceReportOpts, DomainResourceReportOpts and ProjectResourceReportOpts.
+type ResourceReportOpts struct {
+	GenericReportOpts
+	// ResourceName filters resources by name
+	ResourceName Option[liquid.ResourceName] `q:"resource"`
+	// WithUserSpecifiedConstraints enriches the response with MaxQuota and ForbidAutogrowth values
+	WithUserSpecifiedConstraints bool `q:"with,value:constraints"`
+	// WithCommitmentStats enriches the response with Committed values
+	WithCommitmentStats bool `q:"with,value:commitment_stats"`
+	// MinScrapedAt and MaxScrapedAt allow to filter services by their latest successful scrape time
+	MinScrapedAt Option[time.Time] `q:"min_scraped_at,format:RFC3339"`
+	MaxScrapedAt Option[time.Time] `q:"max_scraped_at,format:RFC3339"`
+}
+
+// ClusterResourceReportOpts contains query parameter options for cluster
+// resource reports.
+type ClusterResourceReportOpts struct {
+	ResourceReportOpts
+	// WithTiming enriches the response with ScrapedAt values
+	WithTiming bool `q:"with,value:timing"`
+	// WithSubcapacities enriches the response with Subcapacities values which is only allowed for users with certain permissions
+	WithSubcapacities bool `q:"with,value:subcapacities"`
+}
+
+// DomainResourceReportOpts contains query parameter options for domain
+// resource reports.
+type DomainResourceReportOpts struct {
+	ResourceReportOpts
+}
+
+// ProjectPaginationOpts contains query parameter options for paging through project reports.
+// It appears in types ProjectResourceReportOpts and ProjectRateReportOpts.
+type ProjectPaginationOpts struct {
+	// Limit restricts the report to at most this many projects.
+	// If more projects are in scope, the report contains a link to the next page.
+	Limit Option[uint64] `q:"limit"`
+	// Marker restricts the report to projects whose UUID sorts after this value.
+	// It is usually taken from the link to the next page of a previous report.
+	Marker Option[liquid.ProjectUUID] `q:"marker"`
+}
+
+// ProjectResourceReportOpts contains query parameter options for project
+// resource reports.
+type ProjectResourceReportOpts struct {
+	ResourceReportOpts
+	ProjectPaginationOpts
+	// WithTiming enriches the response with ScrapedAt values which is only allowed for users with certain permissions
+	WithTiming bool `q:"with,value:timing"`
+	// WithSubresources enriches the response with Subresources values which is only allowed for users with certain permissions
+	WithSubresources bool `q:"with,value:subresources"`
+	// DomainUUID is a special entity filter which is only allowed for users with certain permissions
+	DomainUUID Option[string] `q:"domain_uuid"`
+}
+
+// AvailabilityReportOpts contains query parameter options for the report on
+// committable capacity.
+type AvailabilityReportOpts struct {
+	// ProjectUUID identifies the project for which committable amounts are calculated. It is required.
+	ProjectUUID Option[liquid.ProjectUUID] `q:"project_uuid"`
+	// Area is a grouping, used to filter for multiple services
+	Area Option[string] `q:"area"`
+	// ServiceType filters services by type
+	ServiceType Option[ServiceType] `q:"service"`
+	// Category is a grouping, used to filter for multiple resources
+	Category Option[liquid.CategoryName] `q:"category"`
+	// ResourceName filters resources by name
+	ResourceName Option[liquid.ResourceName] `q:"resource"`
+}
+
+// AdminReportOpts contains query parameter options for the admin reports on
+// inconsistencies and scrape errors.
+type AdminReportOpts struct {
+	// Area is a grouping, used to filter for multiple services
+	Area Option[string] `q:"area"`
+	// ServiceType filters services by type
+	ServiceType Option[ServiceType] `q:"service"`
+	// DomainUUID restricts the report to the projects in a single domain.
+	DomainUUID Option[string] `q:"domain_uuid"`
+	// AvailabilityZone restricts the report on inconsistencies to a single AZ (including the pseudo-AZ "total").
+	// It is ignored by the report on scrape errors, since scraping is not done per AZ.
+	AvailabilityZone Option[limes.AvailabilityZone] `q:"az"`
+}
+
+// ArchivedCommitmentListOpts contains query parameter options for listing archived commitments.
+// Since archived commitments may refer to projects and resources that do not exist anymore,
+// the filters are matched against the archived values without checking for existence.
+type ArchivedCommitmentListOpts struct {
+	// UUID restricts the listing to the commitment with this UUID.
+	UUID Option[liquid.CommitmentUUID] `q:"uuid"`
+	// ProjectUUID restricts the listing to the commitments of a single project.
+	ProjectUUID Option[liquid.ProjectUUID] `q:"project_uuid"`
+	// DomainUUID restricts the listing to the commitments of the projects in a single domain.
+	DomainUUID Option[string] `q:"domain_uuid"`
+	// ServiceType filters commitments by the type of their service
+	ServiceType Option[ServiceType] `q:"service"`
+	// ResourceName filters commitments by the name of their resource
+	ResourceName Option[liquid.ResourceName] `q:"resource"`
+	// AvailabilityZone filters commitments by their AZ
+	AvailabilityZone Option[limes.AvailabilityZone] `q:"az"`
+	// MinArchivedAt and MaxArchivedAt filter commitments by the time when they were moved into the archive
+	MinArchivedAt Option[time.Time] `q:"min_archived_at,format:RFC3339"`
+	MaxArchivedAt Option[time.Time] `q:"max_archived_at,format:RFC3339"`
+	// MinExpiresAt and MaxExpiresAt filter commitments by their expiry date
+	MinExpiresAt Option[time.Time] `q:"min_expires_at,format:RFC3339"`
+	MaxExpiresAt Option[time.Time] `q:"max_expires_at,format:RFC3339"`
+	// Limit restricts the listing to at most this many commitments.
+	// If more commitments match, the listing contains a link to the next page.
+	Limit Option[uint64] `q:"limit"`
+	// Marker restricts the listing to commitments that sort after the commitment with this UUID.
+	// It is usually taken from the link to the next page of a previous listing.
+	Marker Option[liquid.CommitmentUUID] `q:"marker"`
+}
+
+// CommitmentListOpts contains query parameter options for listing commitments.
+type CommitmentListOpts struct {
+	// ProjectUUID restricts the listing to the commitments of a single project.
+	ProjectUUID Option[liquid.ProjectUUID] `q:"project_uuid"`
+	// DomainUUID restricts the listing to the commitments of the projects in a single domain.
+	// It may not be set together with ProjectUUID.
+	DomainUUID Option[string] `q:"domain_uuid"`
+	// ServiceType filters commitments by the type of their service
+	ServiceType Option[ServiceType] `q:"service"`
+	// ResourceName filters commitments by the name of their resource
+	ResourceName Option[liquid.ResourceName] `q:"resource"`
+	// AvailabilityZone filters commitments by their AZ
+	AvailabilityZone Option[limes.AvailabilityZone] `q:"az"`
+	// Status filters commitments by their status. If multiple values are given, any of them matches.
+	// If no value is given, commitments in status "superseded" and "expired" are not shown.
+	Status []liquid.CommitmentStatus `q:"status"`
+	// TransferStatus filters commitments by their transfer status
+	TransferStatus Option[limesresources.CommitmentTransferStatus] `q:"transfer_status"`
+	// MinExpiresAt and MaxExpiresAt filter commitments by their expiry date
+	MinExpiresAt Option[time.Time] `q:"min_expires_at,format:RFC3339"`
+	MaxExpiresAt Option[time.Time] `q:"max_expires_at,format:RFC3339"`
+}
+
+// RateReportOpts contains query parameter options for rate reports.
+// It appears in type ClusterRateReportOpts, DomainRateReportOpts and ProjectRateReportOpts.
+type RateReportOpts struct {
+	GenericReportOpts
+	// RateName filters rates by name
+	RateName Option[liquid.RateName] `q:"rate"`
+}
+
+// ClusterRateReportOpts contains query parameter options for cluster
+// rate reports.
+type ClusterRateReportOpts struct {
+	RateReportOpts
+}
+
+// DomainRateReportOpts contains query parameter options for domain
+// rate reports.
+type DomainRateReportOpts struct {
+	RateReportOpts
+}
+
+// ProjectRateReportOpts contains query parameter options for project
+// rate reports.
+type ProjectRateReportOpts struct {
+	RateReportOpts
+	ProjectPaginationOpts
+	// WithTiming enriches the response with ScrapedAt values which is only allowed for users with certain permissions
+	WithTiming bool `q:"with,value:timing"`
+	// DomainUUID is a special entity filter which is only allowed for users with certain permissions
+	DomainUUID Option[string] `q:"domain_uuid"`
+}
@@ -0,0 +1,331 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+// Package apiv2 contains the specification for the v2 API of [Limes], an OpenStack service for managing quotas as well as usage and capacity data.
+//
+// # Concepts
+//
+// TODO: copy this section from v1 API and/or the LIQUID API when the API is finished
+//
+// # API structure
+//
+// The Limes v2 API is structured as a REST-like HTTP API akin to those of the various OpenStack services.
+// Like with any other OpenStack API, clients authenticate by providing their [Keystone token] in the HTTP header "X-Auth-Token".
+// Requests without a valid token will be rejected with status 401 (Unauthorized).
+// Requests with a valid token that confers insufficient access will be rejected with status 403 (Forbidden).
+//
+// Some endpoints will provide different information based on the scope of the token, as documented in the section for the respective endpoint.
+// In this context, a "cloud-admin token" refers to whatever scope the administrator of the respective OpenStack has designated as cloud-admin scope.
+// This may either mean a system-scoped token, or a token scoped to a certain well-known cloud-admin project.
+//
+// Each individual endpoint is documented in a section of this docstring whose title starts with "Endpoint:".
+// Unless noted otherwise, a liquid must implement all documented endpoints.
+// The full URL of the endpoint is obtained by appending the subpath from the section header to the liquid's base URL from the Keystone service catalog.
+//
+// The documentation for an endpoint may refer to a request body being expected or a response body being generated on success.
+// In all such cases, the request or response body will be encoded as "Content-Type: application/json".
+// The structure of the payload must conform to how the referenced Go type would be serialized by the Go standard library's "encoding/json" package.
+//
+// When producing a successful response, the status code shall be 200 (OK) unless noted otherwise.
+// When producing an error response (with a status code between 400 and 599), the liquid shall include a response body of "Content-Type: text/plain" to indicate the error.
+//
+// Successful responses to GET requests carry an "ETag" header.
+// When a client repeats a GET request with the header "If-None-Match" containing this ETag, and the response body has not changed since,
+// the response will have status 304 (Not Modified) and no response body.
+// For resource and rate reports, the ETag is derived from the data that the report is built from,
+// so a changed ETag does not necessarily imply a changed report, but an unchanged ETag implies an unchanged report.
+//
+// All endpoints that create or modify commitments accept an optional "Idempotency-Key" header with an arbitrary value of up to 255 bytes.
+// When a request with this header succeeds, its response is remembered together with the key (by default for 24 hours).
+// Sending the same request with the same key again within this period will not execute it again, but return the original response.
+// This allows clients to safely retry requests that timed out, without creating duplicate commitments.
+// Using a key for a different request (i.e. a different method, path or request body) will fail with status 422 (Unprocessable Entity),
+// and using a key while the original request is still being processed will fail with status 409 (Conflict).
+// If the original request has not completed within 5 minutes, it is assumed to have been aborted, and a retry will be executed instead.
+// Failed requests are not remembered, so they can be retried with the same key.
+//
+// Requests that are rejected for insufficient committable capacity fail with status 409 (Conflict).
+// If Limes can estimate when a retry might succeed, the response carries a "Retry-After" header containing an HTTP date.
+// For resources where commitments are managed by the liquid, this is the retry time reported by the liquid, if any.
+// Otherwise, this is the time of the next capacity scrape for the respective service, since committable capacity is only reassessed at that point.
+// No header is set if this time has already passed.
+//
+// A machine-readable description of all endpoints is available as an [OpenAPI 3.1] document at "GET /v2/openapi.json".
+// This document is generated from the types in this package, and can be used to generate clients for languages other than Go.
+// Unlike all other endpoints, it can be accessed without a Keystone token.
+//
+// # Common query arguments
+//
+// TODO: fill when implemented
+//
+// # Endpoint: GET /resources/v2/info
+//
+// Returns information about the cluster's resources, potentially limited to those resources that are accessible within the authenticated scope:
+// A project-scoped token will receive information about all resources available in that project.
+// A domain-scoped token will receive information about all resources available in at least one project of that domain.
+// A cloud-admin token will receive information about all resources.
+//   - On success, the response body payload will be of type [resourcesv2.InfoReport].
+//
+// # Endpoint: GET /resources/v2/cluster
+//
+// Returns the latest state of all dynamic resource data for the whole cluster.
+// This path is only available to users with cloud-admin token.
+//   - On success, the response body payload will be of type [resourcesv2.ClusterGetResponse].
+//
+// # Endpoint: GET /resources/v2/domains(/:domain_uuid)?
+//
+// Returns the latest state of all dynamic resource data for one or many domains.
+// A project-scoped token cannot access this path.
+// A domain-scoped token can only access this path, if its domain_uuid is set in the URL parameter.
+// A cloud-admin token can receive data for requests with and without domain_uuid.
+//   - On success, the response body payload will be of type [resourcesv2.DomainGetResponse].
+//
+// # Endpoint: GET /resources/v2/projects(/:project_uuid)?
+//
+// Returns the latest state of all dynamic resource data for one or many projects.
+// A project-scoped token can only access this path, if its project_uuid is set in the URL parameter.
+// A domain-scoped token can only access this path, if a project_uuid from its domain is set in the URL parameter or when a domain_uuid query parameter is set.
+// A cloud-admin token can receive data for requests with and without project_uuid/ domain_uuid.
+// Large reports can be paginated with the query options limit and marker (see [common.ProjectPaginationOpts]).
+//   - On success, the response body payload will be of type [resourcesv2.ProjectGetResponse].
+//     If there are more projects in scope than the limit allows, the field "next" contains a link to the next page.
+//
+// # Endpoint: PUT /resources/v2/projects/:project_uuid/max-quota
+//
+// Sets or removes max_quota constraints on resources of a single project.
+// The computed project quota of all affected resources is reevaluated immediately.
+// This path is only available to users with cloud-admin token or domain-level administrators of the project's domain.
+//   - The request body payload must be of type [resourcesv2.ProjectMaxQuotaRequest].
+//   - On success, status code 204 (No Content) will be returned, without a response body.
+//
+// # Endpoint: PUT /resources/v2/projects/:project_uuid/forbid-autogrowth
+//
+// Sets or clears the forbid_autogrowth flag on resources of a single project.
+// Only resources that accept commitments in this project can be targeted.
+// The computed project quota of all affected resources is reevaluated immediately.
+// This path is available to all users that can create commitments in the project on POST /resources/v2/commitments/new.
+//   - The request body payload must be of type [resourcesv2.ProjectForbidAutogrowthRequest].
+//   - On success, status code 204 (No Content) will be returned, without a response body.
+//
+// # Endpoint: GET /resources/v2/availability
+//
+// Returns how much a single project could commit right now in each AZ resource that accepts commitments in this project.
+// The project must be identified by the required query parameter "project_uuid".
+// The query parameters "area", "service", "category" and "resource" can be used to restrict the report to a subset of resources.
+// This path is available to all users that can access the report for a single project on GET /resources/v2/projects/:project_uuid.
+//
+// Committable amounts are calculated with the same capacity checks that apply when confirming a commitment, including the limit on the committable portion of capacity where configured.
+// Since these checks do not consider transferable commitments of other projects, POST /resources/v2/commitments/new may still accept larger amounts.
+//   - On success, the response body payload will be of type [resourcesv2.AvailabilityGetResponse].
+//
+// # Endpoint: GET /resources/v2/admin/inconsistencies
+//
+// Returns a list of project resources whose quota is inconsistent:
+// Either usage exceeds quota, or the quota in the backend differs from the quota computed by Limes.
+// Quotas are checked in each AZ where they are tracked, i.e. per AZ for resources with AZSeparatedTopology and in the pseudo-AZ "total" otherwise.
+// The query parameters defined in [common.AdminReportOpts] can be used to restrict the report to a subset of services, domains or AZs.
+// This path is only available to users with cloud-admin token.
+//   - On success, the response body payload will be of type [resourcesv2.InconsistenciesGetResponse].
+//
+// # Endpoint: GET /resources/v2/admin/scrape-errors
+//
+// Returns a list of errors that occurred during the most recent scrape of project data.
+// Identical errors for the same service in multiple projects are grouped into one entry.
+// The query parameters "area", "service" and "domain_uuid" from [common.AdminReportOpts] can be used to restrict the report.
+// This path is only available to users with cloud-admin token.
+//   - On success, the response body payload will be of type [resourcesv2.ScrapeErrorsGetResponse].
+//
+// # Endpoint: GET /resources/v2/admin/archived-commitments
+//
+// Returns a list of archived commitments, potentially limited by the query parameters defined in [common.ArchivedCommitmentListOpts].
+// Commitments are moved into the archive once they have been expired, superseded or deleted for longer than the configured retention period.
+// Large listings can be paginated with the query options limit and marker.
+// This path is only available to users with cloud-admin token.
+//   - On success, the response body payload will be of type [resourcesv2.ArchivedCommitmentListResponse].
+//     If there are more matching commitments than the limit allows, the field "next" contains a link to the next page.
+//
+// # Endpoint: GET /resources/v2/commitments
+//
+// Returns a list of commitments, potentially limited by the query parameters defined in [common.CommitmentListOpts].
+// A project-scoped token can only access this path, if a project_uuid query parameter is set to its own project.
+// A domain-scoped token can only access this path, if a project_uuid from its domain is set or when a domain_uuid query parameter is set to its own domain.
+// A cloud-admin token can list commitments across the whole cluster.
+//   - On success, the response body payload will be of type [resourcesv2.CommitmentListResponse].
+//
+// # Endpoint: POST /resources/v2/commitments/new
+//
+// Creates a new commitment (or performs a dry run of a commitment creation request).
+// Commitments created in status "confirmed" or "guaranteed" are only accepted if sufficient committable capacity is available.
+// For guaranteed commitments, this capacity stays reserved until they move into status "confirmed" at their ConfirmBy date.
+//
+//   - The request body payload must be of type [resourcesv2.CommitmentRequest].
+//   - On success, status code 201 (Created) will be returned, including for dry runs.
+//   - On success, the response body payload will be of type [resourcesv2.Commitment].
+//   - Errors caused by insufficient committable capacity will be marked with status code 409 (Conflict) and might have a Retry-After header.
+//
+// # Endpoint: POST /resources/v2/commitments/batch
+//
+// Creates several new commitments at once (or performs a dry run of such a request).
+// Either all commitments are created, or none of them are.
+// The commitments may belong to different projects, resources and AZs.
+// The user must be able to create each of them individually on POST /resources/v2/commitments/new.
+// Capacity is checked jointly for all commitments that go into the same AZ of the same service.
+// Like on POST /resources/v2/commitments/new, commitments created in status "confirmed" might consume commitments that are marked for transfer.
+//
+//   - The request body payload must be of type [resourcesv2.CommitmentBatchRequest].
+//   - On success, status code 201 (Created) will be returned, including for dry runs.
+//   - On success, the response body payload will be of type [resourcesv2.CommitmentOperationResponse].
+//     The commitments are listed in the same order as in the request.
+//   - Errors caused by insufficient committable capacity will be marked with status code 409 (Conflict) and might have a Retry-After header.
+//
+// # Endpoint: GET /resources/v2/commitments/:uuid
+//
+// Returns a single commitment.
+// This path is available to all users that can access the report for a single project on GET /resources/v2/projects/:project_uuid for the commitment's project.
+// Deleted commitments cannot be shown.
+//   - On success, the response body payload will be of type [resourcesv2.Commitment].
+//
+// # Endpoint: DELETE /resources/v2/commitments/:uuid
+//
+// Deletes a single commitment.
+// The user must be able to view this commitment on GET /resources/v2/commitments/:uuid, and the commitment must have its CanBeDeleted attribute set there.
+// Commitments in status "superseded" or "expired" cannot be deleted.
+//   - On success, status code 204 (No Content) will be returned, without a response body.
+//
+// # Endpoint: GET /resources/v2/commitments/:uuid/lineage
+//
+// Returns a single commitment together with all commitments that it was created from, and all commitments that were created from it,
+// by operations like split, convert, merge, amend, renew, or the consumption of a commitment that was marked for transfer.
+// This path is available to the same users as GET /resources/v2/commitments/:uuid.
+// Related commitments are only shown if the user can view them on GET /resources/v2/commitments/:uuid.
+//   - On success, the response body payload will be of type [resourcesv2.CommitmentLineageResponse].
+//
+// # Endpoint: POST /resources/v2/commitments/:uuid/split
+//
+// Splits a commitment into two new commitments with the same attributes, whose amounts add up to that of the original commitment.
+// The original commitment moves into status "superseded".
+// This path is available to all users that can create commitments in the commitment's project on POST /resources/v2/commitments/new.
+// Commitments in status "superseded" or "expired", as well as commitments that are marked for transfer, cannot be split.
+//   - The request body payload must be of type [resourcesv2.CommitmentSplitRequest].
+//   - On success, the response body payload will be of type [resourcesv2.CommitmentOperationResponse], including for dry runs.
+//
+// # Endpoint: POST /resources/v2/commitments/merge
+//
+// Merges several confirmed commitments in the same project and AZ resource into one new commitment,
+// which expires at the latest expiration date of all merged commitments.
+// The merged commitments move into status "superseded".
+// This path is available to all users that can create commitments in the commitments' project on POST /resources/v2/commitments/new.
+//   - The request body payload must be of type [resourcesv2.CommitmentMergeRequest].
+//   - On success, the response body payload will be of type [resourcesv2.CommitmentOperationResponse], including for dry runs.
+//
+// # Endpoint: POST /resources/v2/commitments/:uuid/convert
+//
+// Converts a commitment (or a part of it) into a commitment for a different resource in the same AZ,
+// using the conversion rate defined by the resources' commitment behavior.
+// The original commitment moves into status "superseded".
+// This path is available to all users that can create commitments in the commitment's project on POST /resources/v2/commitments/new.
+// The target resource must accept commitments of the same duration as the original commitment.
+//   - The request body payload must be of type [resourcesv2.CommitmentConvertRequest].
+//   - On success, the response body payload will be of type [resourcesv2.CommitmentOperationResponse], including for dry runs.
+//   - Errors caused by insufficient committable capacity will be marked with status code 409 (Conflict) and might have a Retry-After header.
+//
+// # Endpoint: POST /resources/v2/commitments/:uuid/amend
+//
+// Increases the amount of a confirmed commitment in place.
+// The additional amount is confirmed in the same way as a new commitment on POST /resources/v2/commitments/new,
+// so it requires sufficient committable capacity and might consume commitments that are marked for transfer.
+// The original commitment moves into status "superseded" and is replaced by a new commitment covering the total amount.
+// Depending on the resource's commitment behavior, the new commitment either keeps the original expiration date,
+// or its duration is restarted at the time of the amendment.
+// This path is available to all users that can create commitments in the commitment's project on POST /resources/v2/commitments/new.
+// Commitments that are marked for transfer or that have already been renewed cannot be amended.
+//   - The request body payload must be of type [resourcesv2.CommitmentAmendRequest].
+//   - On success, the response body payload will be of type [resourcesv2.CommitmentOperationResponse], including for dry runs.
+//   - Errors caused by insufficient committable capacity will be marked with status code 409 (Conflict) and might have a Retry-After header.
+//
+// # Endpoint: POST /resources/v2/commitments/:uuid/start-transfer
+//
+// Marks a commitment (or a part of it) for transfer into another project, or withdraws an existing transfer offer.
+// This path is available to all users that can create commitments in the commitment's project on POST /resources/v2/commitments/new.
+// If only a part of the commitment is offered, the commitment is superseded by two new commitments, one of which is marked for transfer.
+// Commitments in status "superseded" or "expired" cannot be offered for transfer.
+//   - The request body payload must be of type [resourcesv2.CommitmentTransferRequest].
+//   - On success, the response body payload will be of type [resourcesv2.Commitment] and describe the commitment that was marked for transfer.
+//     Its TransferToken must be handed to the receiving side.
+//
+// # Endpoint: GET /resources/v2/commitments/by-transfer-token/:token
+//
+// Returns the commitment that has been marked for transfer with the given transfer token.
+// This path is available to all users that are members of any project.
+//   - On success, the response body payload will be of type [resourcesv2.Commitment].
+//
+// # Endpoint: POST /resources/v2/commitments/:uuid/accept-transfer
+//
+// Moves a commitment that has been marked for transfer into the target project.
+// The transfer token must be given in the Transfer-Token request header.
+// This path is available to all users that can create commitments in the target project on POST /resources/v2/commitments/new.
+//   - The request body payload must be of type [resourcesv2.CommitmentAcceptTransferRequest].
+//   - On success, the response body payload will be of type [resourcesv2.Commitment].
+//   - Errors caused by insufficient committable capacity will be marked with status code 409 (Conflict) and might have a Retry-After header.
+//
+// # Endpoint: GET /rates/v2/info
+//
+// Returns information about the cluster's rates, potentially limited to those rates that are accessible within the authenticated scope:
+// A project-scoped token will receive information about all rates available in that project.
+// A domain-scoped token will receive information about all rates available in at least one project of that domain.
+// A cloud-admin token will receive information about all rates.
+//   - On success, the response body payload will be of type [ratesv2.InfoReport].
+//
+// # Endpoint: GET /rates/v2/cluster
+//
+// Returns the latest state of all dynamic rate data for the whole cluster.
+// This path is only available to users with cloud-admin token.
+//   - On success, the response body payload will be of type [ratesv2.ClusterGetResponse].
+//
+// # Endpoint: GET /rates/v2/domains(/:domain_uuid)?
+//
+// Returns the latest state of all dynamic rate data for one or many domains.
+// A project-scoped token cannot access this path.
+// A domain-scoped token can only access this path, if its domain_uuid is set in the URL parameter.
+// A cloud-admin token can receive data for requests with and without domain_uuid.
+//   - On success, the response body payload will be of type [ratesv2.DomainGetResponse].
+//
+// # Endpoint: GET /rates/v2/projects(/:project_uuid)?
+//
+// Returns the latest state of all dynamic rate data for one or many projects.
+// A project-scoped token can only access this path, if its project_uuid is set in the URL parameter.
+// A domain-scoped token can only access this path, if a project_uuid from its domain is set in the URL parameter or when a domain_uuid query parameter is set.
+// A cloud-admin token can receive data for requests with and without project_uuid/ domain_uuid.
+// Large reports can be paginated with the query options limit and marker (see [common.ProjectPaginationOpts]).
+//   - On success, the response body payload will be of type [ratesv2.ProjectGetResponse].
+//     If there are more projects in scope than the limit allows, the field "next" contains a link to the next page.
+//
+// # Endpoint: PUT /rates/v2/projects/:project_uuid
+//
+// Sets rate limits for a single project. Only rates that have a project default rate limit in the cluster configuration can be targeted.
+// If the request body sets "dry_run", all validations are performed, but no changes are persisted.
+// This path is available to all users that can read the project's rate data, but each changed rate limit additionally requires permission to set rate limits for its service.
+//   - The request body payload must be of type [ratesv2.ProjectRateLimitRequest].
+//   - On success, status code 204 (No Content) will be returned, without a response body.
+//   - If any requested rate limit is unacceptable, no changes are made and the response body payload will be of type [ratesv2.ProjectRateLimitErrorResponse].
+//     If all unacceptable rate limits fail with the same status code, that status code is returned. Otherwise, status code 422 (Unprocessable Entity) is returned.
+//
+// [Keystone token]: https://docs.openstack.org/api-ref/identity/v3/index.html#password-authentication-with-scoped-authorization
+// [Limes]: https://github.com/sapcc/limes
+// [OpenAPI 3.1]: https://spec.openapis.org/oas/v3.1.0
+package apiv2
+
+import (
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	ratesv2 "github.com/sapcc/limes/apideclarations/apiv2/rates"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+)
+
+var (
+	// need to make sure these packages are imported for docstring links to work
+	_ = resourcesv2.InfoReport{}
+	_ = common.CommitmentListOpts{}
+	_ = ratesv2.InfoReport{}
+)
+
+// TODO: switch all json:"..." tags from snake_case to camelCase for consistency with LIQUID
@@ -7,7 +7,7 @@ import (
 	"github.com/sapcc/go-api-declarations/liquid"
 	. "go.xyrillian.de/gg/option"
 
-	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
 )
 
 // ClusterGetResponse is the response type for GET /rates/v2/cluster.
@@ -30,7 +30,7 @@ type ClusterReport struct {
 // ClusterAreaReport contains data for one area.
 // It appears in [ClusterReport].
 type ClusterAreaReport struct {
-	Services map[db.ServiceType]ClusterServiceReport `json:"services"`
+	Services map[common.ServiceType]ClusterServiceReport `json:"services"`
 }
 
 // ClusterServiceReport contains data for one service.
@@ -6,8 +6,7 @@ package ratesv2
 import (
 	"github.com/sapcc/go-api-declarations/liquid"
 
-	"github.com/sapcc/limes/internal/apideclarations/apiv2/common"
-	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
 
 	. "go.xyrillian.de/gg/option"
 )
@@ -33,7 +32,7 @@ type DomainReport struct {
 // DomainAreaReport contains data for one area.
 // It appears in [DomainReport].
 type DomainAreaReport struct {
-	Services map[db.ServiceType]DomainServiceReport `json:"services"`
+	Services map[common.ServiceType]DomainServiceReport `json:"services"`
 }
 
 // DomainServiceReport contains data for one service.
@@ -8,7 +8,7 @@ import (
 	"github.com/sapcc/go-api-declarations/liquid"
 	. "go.xyrillian.de/gg/option"
 
-	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
 )
 
 // InfoReport is the response type for GET /rates/v2/info.
@@ -24,8 +24,8 @@ type InfoReport struct {
 // AreaInfoReport groups services into areas, which are defined in the config.
 // It appears in [InfoReport].
 type AreaInfoReport struct {
-	DisplayName string                               `json:"display_name"`
-	Services    map[db.ServiceType]ServiceInfoReport `json:"services"`
+	DisplayName string                                   `json:"display_name"`
+	Services    map[common.ServiceType]ServiceInfoReport `json:"services"`
 }
 
 // ServiceInfoReport contains details about a service.
@@ -8,8 +8,7 @@ import (
 	limesrates "github.com/sapcc/go-api-declarations/limes/rates"
 	"github.com/sapcc/go-api-declarations/liquid"
 
-	"github.com/sapcc/limes/internal/apideclarations/apiv2/common"
-	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
 
 	. "go.xyrillian.de/gg/option"
 )
@@ -22,6 +21,9 @@ type ProjectGetResponse struct {
 	// It is only returned when the respective query option with=info is set.
 	InfoReport    Option[InfoReport]                `json:"info,omitzero"`
 	DomainReports map[string]ProjectsByDomainReport `json:"domains"`
+	// NextLink points to the next page of this report (as an absolute path including the query string).
+	// It is only returned when the query option limit is set and there are more projects in scope.
+	NextLink Option[string] `json:"next,omitzero"`
 }
 
 // ProjectsByDomainReport groups all projects for one domain.
@@ -41,7 +43,7 @@ type ProjectReport struct {
 // ProjectAreaReport contains data for one area.
 // It appears in [ProjectReport].
 type ProjectAreaReport struct {
-	Services map[db.ServiceType]ProjectServiceReport `json:"services"`
+	Services map[common.ServiceType]ProjectServiceReport `json:"services"`
 }
 
 // ProjectServiceReport contains data for one service.
@@ -72,3 +74,35 @@ type ProjectRateReport struct {
 	ProjectLimit  Option[uint64]            `json:"project_limit,omitzero"`
 	ProjectWindow Option[limesrates.Window] `json:"project_window,omitzero"`
 }
+
+// ProjectRateLimitRequest is the request payload format for PUT /rates/v2/projects/:project_uuid.
+type ProjectRateLimitRequest struct {
+	// DryRun can be set to true to only validate the requested rate limits without changing them.
+	DryRun bool `json:"dry_run"`
+	// RateLimits contains the requested project rate limits, grouped by service type and rate name.
+	// Rates that are not mentioned are not changed.
+	RateLimits map[common.ServiceType]map[liquid.RateName]RateLimitRequest `json:"rate_limits"`
+}
+
+// RateLimitRequest contains the requested values for a single project rate limit.
+// It appears in [ProjectRateLimitRequest].
+type RateLimitRequest struct {
+	Limit  uint64            `json:"limit"`
+	Window limesrates.Window `json:"window"`
+}
+
+// ProjectRateLimitErrorResponse is the response payload format for PUT /rates/v2/projects/:project_uuid
+// when at least one of the requested rate limits is unacceptable.
+type ProjectRateLimitErrorResponse struct {
+	UnacceptableRateLimits []UnacceptableRateLimit `json:"unacceptable_rate_limits"`
+}
+
+// UnacceptableRateLimit explains why a single requested rate limit was rejected.
+// It appears in [ProjectRateLimitErrorResponse].
+type UnacceptableRateLimit struct {
+	ServiceType common.ServiceType `json:"service_type"`
+	RateName    liquid.RateName    `json:"rate_name"`
+	// Status is an HTTP status code that classifies the error, e.g. 403 (Forbidden) for insufficient permissions.
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesv2
+
+import (
+	"github.com/sapcc/go-api-declarations/limes"
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+)
+
+// InconsistenciesGetResponse is the response type for GET /resources/v2/admin/inconsistencies.
+// It lists project resources whose quota is not in the expected relation to their usage or backend quota.
+type InconsistenciesGetResponse struct {
+	OverspentQuotas []OverspentQuotaReport `json:"project_quota_overspent"`
+	MismatchQuotas  []MismatchQuotaReport  `json:"project_quota_mismatch"`
+}
+
+// OverspentQuotaReport describes a project resource in a single AZ where usage exceeds quota.
+// It appears in [InconsistenciesGetResponse].
+type OverspentQuotaReport struct {
+	Project      common.ProjectMetadata `json:"project"`
+	ServiceType  common.ServiceType     `json:"service_type"`
+	ResourceName liquid.ResourceName    `json:"resource_name"`
+	// AvailabilityZone is the pseudo-AZ "total" for resources whose quota is not tracked per AZ.
+	AvailabilityZone limes.AvailabilityZone `json:"availability_zone"`
+	Unit             liquid.Unit            `json:"unit,omitzero"`
+	Quota            uint64                 `json:"quota"`
+	Usage            uint64                 `json:"usage"`
+}
+
+// MismatchQuotaReport describes a project resource in a single AZ where the quota
+// in the backend differs from the quota computed by Limes.
+// It appears in [InconsistenciesGetResponse].
+type MismatchQuotaReport struct {
+	Project      common.ProjectMetadata `json:"project"`
+	ServiceType  common.ServiceType     `json:"service_type"`
+	ResourceName liquid.ResourceName    `json:"resource_name"`
+	// AvailabilityZone is the pseudo-AZ "total" for resources whose quota is not tracked per AZ.
+	AvailabilityZone limes.AvailabilityZone `json:"availability_zone"`
+	Unit             liquid.Unit            `json:"unit,omitzero"`
+	Quota            uint64                 `json:"quota"`
+	BackendQuota     int64                  `json:"backend_quota"`
+}
+
+// ScrapeErrorsGetResponse is the response type for GET /resources/v2/admin/scrape-errors.
+type ScrapeErrorsGetResponse struct {
+	ScrapeErrors []ScrapeErrorReport `json:"scrape_errors"`
+}
+
+// ScrapeErrorReport describes an error that occurred while scraping project data.
+// Identical errors for the same service in multiple projects are grouped into one report.
+// It appears in [ScrapeErrorsGetResponse].
+type ScrapeErrorReport struct {
+	// Project is one of the affected projects (the first one when sorting by domain name and project name).
+	Project common.ProjectMetadata `json:"project"`
+	// AffectedProjects is the number of projects where scraping failed with this error.
+	AffectedProjects uint64             `json:"affected_projects"`
+	ServiceType      common.ServiceType `json:"service_type"`
+	// CheckedAt is when scraping was last attempted for Project.
+	CheckedAt Option[limes.UnixEncodedTime] `json:"checked_at,omitzero"`
+	Message   string                        `json:"message"`
+}
+
+// ArchivedCommitmentListResponse is the response type for GET /resources/v2/admin/archived-commitments.
+type ArchivedCommitmentListResponse struct {
+	// ArchivedCommitments are sorted by the time of archival, oldest first.
+	// Commitments archived at the same time are sorted by creation, oldest first.
+	ArchivedCommitments []ArchivedCommitment `json:"archived_commitments"`
+	// NextLink points to the next page of this listing (as an absolute path including the query string).
+	// It is only returned when the query option limit is set and there are more matching commitments.
+	NextLink Option[string] `json:"next,omitzero"`
+}
+
+// ArchivedCommitment is a commitment that has been moved into the archive after its retention period has passed.
+// It appears in [ArchivedCommitmentListResponse].
+//
+// See documentation on [Commitment] for the semantics of fields that appear in both types.
+// Since the project of an archived commitment may not exist anymore, it is identified only by UUID.
+type ArchivedCommitment struct {
+	UUID     liquid.CommitmentUUID             `json:"uuid"`
+	Amount   uint64                            `json:"amount"`
+	Duration limesresources.CommitmentDuration `json:"duration"`
+
+	DomainUUID       string                 `json:"domain_id"`
+	ProjectUUID      liquid.ProjectUUID     `json:"project_id"`
+	ServiceType      common.ServiceType     `json:"service_type"`
+	ResourceName     liquid.ResourceName    `json:"resource_name"`
+	AvailabilityZone limes.AvailabilityZone `json:"availability_zone"`
+
+	// Status is the status of the commitment at the time of archival, i.e. "superseded", "expired" or "deleted".
+	Status liquid.CommitmentStatus `json:"status"`
+
+	CreatedAt    limes.UnixEncodedTime         `json:"created_at"`
+	CreatorUUID  string                        `json:"creator_uuid,omitempty"`
+	CreatorName  string                        `json:"creator_name,omitempty"`
+	ConfirmBy    Option[limes.UnixEncodedTime] `json:"confirm_by,omitzero"`
+	ConfirmedAt  Option[limes.UnixEncodedTime] `json:"confirmed_at,omitzero"`
+	ExpiresAt    limes.UnixEncodedTime         `json:"expires_at"`
+	SupersededAt Option[limes.UnixEncodedTime] `json:"superseded_at,omitzero"`
+	DeletedAt    Option[limes.UnixEncodedTime] `json:"deleted_at,omitzero"`
+	UpdatedAt    limes.UnixEncodedTime         `json:"updated_at"`
+	ArchivedAt   limes.UnixEncodedTime         `json:"archived_at"`
+}
@@ -0,0 +1,56 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesv2
+
+import (
+	"github.com/sapcc/go-api-declarations/limes"
+	"github.com/sapcc/go-api-declarations/liquid"
+
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+)
+
+// AvailabilityGetResponse is the response type for GET /resources/v2/availability.
+// It contains the amounts that one project can currently commit in each resource,
+// grouped by areas, which are defined in the config.
+// Resources that do not accept commitments in this project are not shown.
+type AvailabilityGetResponse struct {
+	Areas map[string]AvailabilityAreaReport `json:"service_areas"`
+}
+
+// AvailabilityAreaReport contains data for one area.
+// It appears in [AvailabilityGetResponse].
+type AvailabilityAreaReport struct {
+	Services map[common.ServiceType]AvailabilityServiceReport `json:"services"`
+}
+
+// AvailabilityServiceReport contains data for one service.
+// It appears in [AvailabilityAreaReport].
+type AvailabilityServiceReport struct {
+	Categories map[liquid.CategoryName]AvailabilityCategoryReport `json:"categories"`
+}
+
+// AvailabilityCategoryReport groups resources into categories, which are defined in the config.
+// It appears in [AvailabilityServiceReport].
+type AvailabilityCategoryReport struct {
+	Resources map[liquid.ResourceName]AvailabilityResourceReport `json:"resources"`
+}
+
+// AvailabilityResourceReport contains data for one resource.
+// It appears in [AvailabilityCategoryReport].
+type AvailabilityResourceReport struct {
+	// For resources with FlatTopology, only the pseudo-AZ "any" is shown.
+	// Otherwise, all configured AZs are shown, but not the pseudo-AZs "any" and "unknown".
+	// This matches which AZs are accepted by POST /resources/v2/commitments/new.
+	AvailabilityZones map[limes.AvailabilityZone]AvailabilityAZReport `json:"availability_zones"`
+}
+
+// AvailabilityAZReport contains the data for an availability zone.
+// It appears in [AvailabilityResourceReport].
+type AvailabilityAZReport struct {
+	// Committable is the largest amount for which a new commitment could be confirmed right now
+	// without being rejected for lack of capacity.
+	// Confirming a commitment may consume transferable commitments of other projects,
+	// so a commitment request with a larger amount may still succeed.
+	Committable uint64 `json:"committable"`
+}
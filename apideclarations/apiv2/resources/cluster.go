@@ -11,7 +11,7 @@ import (
 	"github.com/sapcc/go-api-declarations/liquid"
 	. "go.xyrillian.de/gg/option"
 
-	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
 )
 
 // ClusterGetResponse is the response type for GET /resources/v2/cluster.
@@ -34,7 +34,7 @@ type ClusterReport struct {
 // ClusterAreaReport contains data for one area.
 // It appears in [ClusterReport].
 type ClusterAreaReport struct {
-	Services map[db.ServiceType]ClusterServiceReport `json:"services"`
+	Services map[common.ServiceType]ClusterServiceReport `json:"services"`
 }
 
 // ClusterServiceReport contains data for one service.
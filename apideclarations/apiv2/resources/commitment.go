@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package resourcesv2
+
+import (
+	"github.com/sapcc/go-api-declarations/limes"
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+)
+
+// Commitment is the response payload format for GET /resources/v2/commitments/:uuid and several endpoints that create or modify commitments.
+type Commitment struct {
+	UUID liquid.CommitmentUUID `json:"uuid"`
+	// Amount refers to the amount of resource that is committed, in terms of the unit of the committed resource.
+	Amount   uint64                            `json:"amount"`
+	Duration limesresources.CommitmentDuration `json:"duration"`
+
+	ProjectUUID      liquid.ProjectUUID     `json:"project_id"`
+	ServiceType      common.ServiceType     `json:"service_type"`
+	ResourceName     liquid.ResourceName    `json:"resource_name"`
+	AvailabilityZone limes.AvailabilityZone `json:"availability_zone"`
+
+	Status liquid.CommitmentStatus `json:"status"`
+	// TransferStatus and TransferToken are only shown while the commitment is marked for transfer.
+	TransferStatus limesresources.CommitmentTransferStatus `json:"transfer_status,omitempty"`
+	TransferToken  Option[string]                          `json:"transfer_token,omitzero"`
+
+	CreatedAt limes.UnixEncodedTime `json:"created_at"`
+	// CreatorUUID and CreatorName identify the user who created this commitment.
+	// CreatorName is in the format `fmt.Sprintf("%s@%s", userName, userDomainName)`and intended for informational displays only.
+	CreatorUUID string `json:"creator_uuid,omitempty"`
+	CreatorName string `json:"creator_name,omitempty"`
+	// CanBeDeleted will be true if the commitment can be deleted by the same user who saw this object in response to a GET query.
+	CanBeDeleted bool `json:"can_be_deleted,omitempty"`
+	// ConfirmBy is unset if and only if the commitment was created in status "confirmed".
+	ConfirmBy Option[limes.UnixEncodedTime] `json:"confirm_by,omitzero"`
+	// ConfirmedAt is only filled after the commitment was confirmed.
+	ConfirmedAt Option[limes.UnixEncodedTime] `json:"confirmed_at,omitzero"`
+	ExpiresAt   limes.UnixEncodedTime         `json:"expires_at"`
+	// UpdatedAt is updated at least every time any field of this type changes.
+	UpdatedAt limes.UnixEncodedTime `json:"updated_at"`
+
+	// NotifyOnConfirm can only be set if ConfirmBy is filled.
+	// If true, a mail notification will be set to the project owners when the commitment is confirmed.
+	NotifyOnConfirm bool `json:"notify_on_confirm,omitempty"`
+	// WasRenewed indicates whether this commitment has been renewed.
+	// This means that a new commitment was created that will be confirmed when this commitment is set to expire.
+	WasRenewed bool `json:"was_renewed,omitempty"`
+}
+
+// CommitmentListResponse is the response payload format for GET /resources/v2/commitments.
+type CommitmentListResponse struct {
+	// Commitments are sorted by creation, oldest first.
+	Commitments []Commitment `json:"commitments"`
+}
+
+// CommitmentRequest is the request payload format for POST /resources/v2/commitments/new.
+//
+// See documentation on [Commitment] for the semantics of all fields.
+// Documentation on this type's fields only mentions specifics related to the commitment creation process.
+type CommitmentRequest struct {
+	// DryRun can be set to true to avoid any side effects: No data will be saved in the system.
+	// The response from a dry run will be identical to if a commitment had actually been created,
+	// except that the UUID will be set to a dummy value.
+	DryRun bool   `json:"dry_run"`
+	Amount uint64 `json:"amount"`
+	// Duration must be one of the values that appear in the resource's [ResourceInfoReport].
+	Duration limesresources.CommitmentDuration `json:"duration"`
+
+	ProjectUUID      liquid.ProjectUUID     `json:"project_id"`
+	ServiceType      common.ServiceType     `json:"service_type"`
+	ResourceName     liquid.ResourceName    `json:"resource_name"`
+	AvailabilityZone limes.AvailabilityZone `json:"availability_zone"`
+
+	// Status must be one of:
+	//   - liquid.CommitmentStatusPlanned
+	//   - liquid.CommitmentStatusPending
+	//   - liquid.CommitmentStatusConfirmed
+	//   - liquid.CommitmentStatusGuaranteed
+	Status liquid.CommitmentStatus `json:"status"`
+	// ConfirmBy must be set for statuses "planned" and "guaranteed", and may not be set otherwise.
+	// Commitments created in status "pending" will have a ConfirmBy value equal to the current time.
+	ConfirmBy Option[limes.UnixEncodedTime] `json:"confirm_by,omitzero"`
+	// NotifyOnConfirm may not be set for commitments that are created in status "confirmed".
+	NotifyOnConfirm bool `json:"notify_on_confirm,omitempty"`
+}
+
+// CommitmentBatchRequest is the request payload format for POST /resources/v2/commitments/batch.
+type CommitmentBatchRequest struct {
+	// DryRun can be set to true to avoid any side effects, like in [CommitmentRequest].
+	DryRun bool `json:"dry_run"`
+	// Commitments contains the commitments that shall be created.
+	// Each entry is validated like a request on POST /resources/v2/commitments/new, except that its DryRun field may not be set.
+	Commitments []CommitmentRequest `json:"commitments"`
+}
+
+// CommitmentTransferRequest is the request payload format for POST /resources/v2/commitments/:uuid/start-transfer.
+type CommitmentTransferRequest struct {
+	// Amount is the part of the commitment that shall be offered for transfer.
+	// If it is smaller than the commitment's amount, the commitment will be split into two new commitments,
+	// one of which receives the requested Amount and is marked for transfer.
+	// Amount is ignored when TransferStatus is empty.
+	Amount uint64 `json:"amount"`
+	// TransferStatus must be one of:
+	//   - limesresources.CommitmentTransferStatusUnlisted (can only be found by those who know the transfer token)
+	//   - limesresources.CommitmentTransferStatusPublic (can additionally be consumed automatically when other projects confirm commitments)
+	//   - limesresources.CommitmentTransferStatusNone (withdraws an existing transfer offer)
+	TransferStatus limesresources.CommitmentTransferStatus `json:"transfer_status"`
+}
+
+// CommitmentAcceptTransferRequest is the request payload format for POST /resources/v2/commitments/:uuid/accept-transfer.
+type CommitmentAcceptTransferRequest struct {
+	// ProjectUUID identifies the project that shall receive the commitment.
+	ProjectUUID liquid.ProjectUUID `json:"project_id"`
+}
+
+// CommitmentSplitRequest is the request payload format for POST /resources/v2/commitments/:uuid/split.
+type CommitmentSplitRequest struct {
+	// DryRun can be set to true to avoid any side effects, like in [CommitmentRequest].
+	DryRun bool `json:"dry_run"`
+	// Amount is the amount that shall be split off into a new commitment.
+	// The remaining amount is placed in a second new commitment.
+	// Amount must be greater than zero and smaller than the amount of the original commitment.
+	Amount uint64 `json:"amount"`
+}
+
+// CommitmentMergeRequest is the request payload format for POST /resources/v2/commitments/merge.
+type CommitmentMergeRequest struct {
+	// DryRun can be set to true to avoid any side effects, like in [CommitmentRequest].
+	DryRun bool `json:"dry_run"`
+	// CommitmentUUIDs must refer to at least two confirmed commitments in the same project and AZ resource.
+	CommitmentUUIDs []liquid.CommitmentUUID `json:"commitment_ids"`
+}
+
+// CommitmentConvertRequest is the request payload format for POST /resources/v2/commitments/:uuid/convert.
+type CommitmentConvertRequest struct {
+	// DryRun can be set to true to avoid any side effects, like in [CommitmentRequest].
+	DryRun bool `json:"dry_run"`
+	// TargetServiceType and TargetResourceName identify the resource that the commitment shall be converted into.
+	// The availability zone is not changed by the conversion.
+	TargetServiceType  common.ServiceType  `json:"target_service_type"`
+	TargetResourceName liquid.ResourceName `json:"target_resource_name"`
+	// SourceAmount is the amount of the original commitment that shall be converted.
+	// If it is smaller than the commitment's amount, the remaining amount is placed in a new commitment on the original resource.
+	SourceAmount uint64 `json:"source_amount"`
+	// TargetAmount must be equal to the amount that SourceAmount converts into, according to the conversion rate between both resources.
+	TargetAmount uint64 `json:"target_amount"`
+}
+
+// CommitmentAmendRequest is the request payload format for POST /resources/v2/commitments/:uuid/amend.
+type CommitmentAmendRequest struct {
+	// DryRun can be set to true to avoid any side effects, like in [CommitmentRequest].
+	DryRun bool `json:"dry_run"`
+	// AdditionalAmount is the amount by which the commitment shall be increased.
+	// It must be greater than zero.
+	AdditionalAmount uint64 `json:"additional_amount"`
+}
+
+// CommitmentOperationResponse is the response payload format for endpoints that create several commitments at once,
+// e.g. POST /resources/v2/commitments/merge.
+type CommitmentOperationResponse struct {
+	// Commitments contains the commitments that were created by the operation.
+	// If the operation replaced existing commitments, those are not shown; they have moved into status "superseded".
+	Commitments []Commitment `json:"commitments"`
+}
+
+// CommitmentLineageResponse is the response payload format for GET /resources/v2/commitments/:uuid/lineage.
+type CommitmentLineageResponse struct {
+	// Commitment is the commitment whose lineage was requested.
+	Commitment Commitment `json:"commitment"`
+	// Ancestors contains all commitments that the requested commitment was created from, directly or indirectly (e.g. the original commitment of a split).
+	// Descendants contains all commitments that were created from the requested commitment, directly or indirectly.
+	// Both lists are sorted by creation, oldest first.
+	// Commitments in projects that the user is not allowed to see are not shown, and neither are their own ancestors or descendants.
+	Ancestors   []Commitment `json:"ancestors"`
+	Descendants []Commitment `json:"descendants"`
+	// Relations contains all direct relations between the commitments in this response.
+	Relations []CommitmentRelation `json:"relations"`
+}
+
+// CommitmentRelation describes that one commitment was created from another.
+// It appears in type [CommitmentLineageResponse].
+type CommitmentRelation struct {
+	ParentUUID liquid.CommitmentUUID    `json:"parent_id"`
+	ChildUUID  liquid.CommitmentUUID    `json:"child_id"`
+	Reason     CommitmentRelationReason `json:"reason"`
+	// CreatedAt is when the relation was established.
+	// For renewals, this is when the child was created; otherwise, this is when the parent was superseded.
+	CreatedAt limes.UnixEncodedTime `json:"created_at"`
+}
+
+// CommitmentRelationReason is an enum. It appears in type [CommitmentRelation].
+type CommitmentRelationReason string
+
+const (
+	// CommitmentRelationReasonSplit means that the parent was split into the child and other commitments.
+	CommitmentRelationReasonSplit CommitmentRelationReason = "split"
+	// CommitmentRelationReasonConvert means that the parent was converted into the child, which is usually on a different resource.
+	CommitmentRelationReasonConvert CommitmentRelationReason = "convert"
+	// CommitmentRelationReasonMerge means that the parent was merged with other commitments into the child.
+	CommitmentRelationReasonMerge CommitmentRelationReason = "merge"
+	// CommitmentRelationReasonRenew means that the child was created as a renewal of the parent.
+	// Unlike all other reasons, the parent is not superseded by the child.
+	CommitmentRelationReasonRenew CommitmentRelationReason = "renew"
+	// CommitmentRelationReasonConsume means that the parent was offered for transfer and consumed when the child was confirmed.
+	// The child may be located in a different project.
+	CommitmentRelationReasonConsume CommitmentRelationReason = "consume"
+	// CommitmentRelationReasonAmend means that the parent was superseded by the child when its amount was increased.
+	// Besides the original commitment, the parents include a commitment for the additional amount,
+	// which only exists in order to confirm that amount like a new commitment.
+	CommitmentRelationReasonAmend CommitmentRelationReason = "amend"
+)
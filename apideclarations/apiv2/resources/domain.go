@@ -8,8 +8,7 @@ import (
 	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
 	"github.com/sapcc/go-api-declarations/liquid"
 
-	"github.com/sapcc/limes/internal/apideclarations/apiv2/common"
-	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
 
 	. "go.xyrillian.de/gg/option"
 )
@@ -35,7 +34,7 @@ type DomainReport struct {
 // DomainAreaReport contains data for one area.
 // It appears in [DomainReport].
 type DomainAreaReport struct {
-	Services map[db.ServiceType]DomainServiceReport `json:"services"`
+	Services map[common.ServiceType]DomainServiceReport `json:"services"`
 }
 
 // DomainServiceReport contains data for one service.
@@ -62,6 +61,9 @@ type DomainResourceReport struct {
 type DomainAvailabilityZoneReport struct {
 	// Usage is the sum of the usages across projects in this domain as reported by the service.
 	Usage uint64 `json:"usage"`
+	// Quota is the sum of the quotas across projects in this domain.
+	// It is only reported for resources which have quota.
+	Quota Option[uint64] `json:"quota,omitzero"`
 	// Committed is the sum of committed amounts across projects in this domain grouped by their Status and then CommitmentDuration.
 	// It is only returned when the respective query option with=commitment_stats is set.
 	Committed map[liquid.CommitmentStatus]map[limesresources.CommitmentDuration]uint64 `json:"committed,omitempty"`
@@ -11,8 +11,7 @@ import (
 	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
 	"github.com/sapcc/go-api-declarations/liquid"
 
-	"github.com/sapcc/limes/internal/apideclarations/apiv2/common"
-	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
 
 	. "go.xyrillian.de/gg/option"
 )
@@ -25,6 +24,9 @@ type ProjectGetResponse struct {
 	// It is only returned when the respective query option with=info is set.
 	InfoReport    Option[InfoReport]                `json:"info,omitzero"`
 	DomainReports map[string]ProjectsByDomainReport `json:"domains"`
+	// NextLink points to the next page of this report (as an absolute path including the query string).
+	// It is only returned when the query option limit is set and there are more projects in scope.
+	NextLink Option[string] `json:"next,omitzero"`
 }
 
 // ProjectsByDomainReport groups all projects for one domain.
@@ -44,7 +46,7 @@ type ProjectReport struct {
 // ProjectAreaReport contains data for one area.
 // It appears in [ProjectReport].
 type ProjectAreaReport struct {
-	Services map[db.ServiceType]ProjectServiceReport `json:"services"`
+	Services map[common.ServiceType]ProjectServiceReport `json:"services"`
 }
 
 // ProjectServiceReport contains data for one service.
@@ -105,3 +107,18 @@ type ProjectHistoricalReport struct {
 	MaxUsage uint64            `json:"max_usage"`
 	Duration limesrates.Window `json:"duration"`
 }
+
+// ProjectMaxQuotaRequest is the request payload format for PUT /resources/v2/projects/:project_uuid/max-quota.
+type ProjectMaxQuotaRequest struct {
+	// MaxQuota contains the requested max_quota constraints, grouped by service type and resource name.
+	// Values are given in terms of the unit of the respective resource.
+	// A null value removes an existing max_quota constraint. Resources that are not mentioned are not changed.
+	MaxQuota map[common.ServiceType]map[liquid.ResourceName]Option[uint64] `json:"max_quota"`
+}
+
+// ProjectForbidAutogrowthRequest is the request payload format for PUT /resources/v2/projects/:project_uuid/forbid-autogrowth.
+type ProjectForbidAutogrowthRequest struct {
+	// ForbidAutogrowth contains the requested forbid_autogrowth flags, grouped by service type and resource name.
+	// Resources that are not mentioned are not changed.
+	ForbidAutogrowth map[common.ServiceType]map[liquid.ResourceName]bool `json:"forbid_autogrowth"`
+}
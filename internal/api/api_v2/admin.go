@@ -0,0 +1,189 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sapcc/go-api-declarations/limes"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-api-declarations/opts"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/respondwith"
+	"github.com/sapcc/go-bits/sqlext"
+	. "go.xyrillian.de/gg/option"
+	"go.xyrillian.de/gg/options"
+
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+	"github.com/sapcc/limes/internal/api/reports_v2"
+	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/internal/util"
+)
+
+// handleGetInconsistencies handles GET /resources/v2/admin/inconsistencies.
+func (p *v2Provider) handleGetInconsistencies(r *http.Request, token *gopherpolicy.Token) (_ resourcesv2.InconsistenciesGetResponse, err error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/admin/inconsistencies")
+	none := resourcesv2.InconsistenciesGetResponse{}
+
+	err = token.Enforce("v2:cluster:show_errors")
+	if err != nil {
+		return none, err
+	}
+	options, err := opts.ParseQueryString[common.AdminReportOpts](r.URL.Query())
+	if err != nil {
+		return none, err
+	}
+	filter, err := reports_v2.FilterFromAdminReportOpts(p.Cluster, options)
+	if err != nil {
+		return none, err
+	}
+	scope, err := reports_v2.NewScope(false, r, options.DomainUUID, token, p.DB)
+	if err != nil {
+		return none, err
+	}
+	return reports_v2.GetInconsistencies(p.Cluster, filter, scope, options)
+}
+
+// handleGetScrapeErrors handles GET /resources/v2/admin/scrape-errors.
+func (p *v2Provider) handleGetScrapeErrors(r *http.Request, token *gopherpolicy.Token) (_ resourcesv2.ScrapeErrorsGetResponse, err error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/admin/scrape-errors")
+	none := resourcesv2.ScrapeErrorsGetResponse{}
+
+	err = token.Enforce("v2:cluster:show_errors")
+	if err != nil {
+		return none, err
+	}
+	options, err := opts.ParseQueryString[common.AdminReportOpts](r.URL.Query())
+	if err != nil {
+		return none, err
+	}
+	filter, err := reports_v2.FilterFromAdminReportOpts(p.Cluster, options)
+	if err != nil {
+		return none, err
+	}
+	scope, err := reports_v2.NewScope(false, r, options.DomainUUID, token, p.DB)
+	if err != nil {
+		return none, err
+	}
+	return reports_v2.GetScrapeErrors(p.Cluster, filter, scope)
+}
+
+var listArchivedCommitmentsQuery = sqlext.SimplifyWhitespace(`
+	SELECT *
+	  FROM project_commitments_archive
+	 WHERE ($1::TEXT IS NULL OR uuid = $1)
+	   AND ($2::TEXT IS NULL OR project_uuid = $2)
+	   AND ($3::TEXT IS NULL OR domain_uuid = $3)
+	   AND ($4::TEXT IS NULL OR service_type = $4)
+	   AND ($5::TEXT IS NULL OR resource_name = $5)
+	   AND ($6::TEXT IS NULL OR availability_zone = $6)
+	   AND ($7::TIMESTAMPTZ IS NULL OR archived_at >= $7)
+	   AND ($8::TIMESTAMPTZ IS NULL OR archived_at <= $8)
+	   AND ($9::TIMESTAMPTZ IS NULL OR expires_at >= $9)
+	   AND ($10::TIMESTAMPTZ IS NULL OR expires_at <= $10)
+	   AND ($11::TIMESTAMPTZ IS NULL OR (archived_at, id) > ($11, $12::BIGINT))
+	 ORDER BY archived_at, id
+	 LIMIT $13
+`)
+
+var findArchivedCommitmentPositionQuery = sqlext.SimplifyWhitespace(`
+	SELECT archived_at, id FROM project_commitments_archive WHERE uuid = $1
+`)
+
+// handleGetArchivedCommitments handles GET /resources/v2/admin/archived-commitments.
+func (p *v2Provider) handleGetArchivedCommitments(r *http.Request, token *gopherpolicy.Token) (resourcesv2.ArchivedCommitmentListResponse, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/admin/archived-commitments")
+	none := resourcesv2.ArchivedCommitmentListResponse{}
+
+	err := token.Enforce("v2:cluster:show_archived_commitments")
+	if err != nil {
+		return none, err
+	}
+	listOpts, err := opts.ParseQueryString[common.ArchivedCommitmentListOpts](r.URL.Query())
+	if err != nil {
+		return none, err
+	}
+
+	limit, hasLimit := listOpts.Limit.Unpack()
+	if hasLimit && limit == 0 {
+		return none, respondwith.CustomStatus(http.StatusBadRequest, errors.New("limit must be greater than zero"))
+	}
+
+	// the marker refers to the last commitment on the previous page, so we continue after its position in the ordering
+	var (
+		markerArchivedAt Option[time.Time]
+		markerID         Option[db.ProjectCommitmentID]
+	)
+	if marker, ok := listOpts.Marker.Unpack(); ok {
+		var (
+			archivedAt time.Time
+			id         db.ProjectCommitmentID
+		)
+		err = p.DB.QueryRow(findArchivedCommitmentPositionQuery, marker).Scan(&archivedAt, &id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return none, respondwith.CustomStatus(http.StatusBadRequest, fmt.Errorf("no archived commitment found for marker %q", marker))
+		}
+		if err != nil {
+			return none, err
+		}
+		markerArchivedAt = Some(archivedAt)
+		markerID = Some(id)
+	}
+
+	// query one more commitment than requested to find out if there is a next page
+	var sqlLimit Option[uint64]
+	if hasLimit {
+		sqlLimit = Some(limit + 1)
+	}
+
+	var dbCommitments []db.ArchivedProjectCommitment
+	_, err = p.DB.Select(&dbCommitments, listArchivedCommitmentsQuery,
+		listOpts.UUID, listOpts.ProjectUUID, listOpts.DomainUUID, listOpts.ServiceType, listOpts.ResourceName, listOpts.AvailabilityZone,
+		listOpts.MinArchivedAt, listOpts.MaxArchivedAt, listOpts.MinExpiresAt, listOpts.MaxExpiresAt,
+		markerArchivedAt, markerID, sqlLimit)
+	if err != nil {
+		return none, err
+	}
+
+	var nextMarker Option[liquid.CommitmentUUID]
+	if hasLimit && uint64(len(dbCommitments)) > limit {
+		dbCommitments = dbCommitments[:limit]
+		nextMarker = Some(dbCommitments[limit-1].UUID)
+	}
+
+	result := resourcesv2.ArchivedCommitmentListResponse{
+		ArchivedCommitments: make([]resourcesv2.ArchivedCommitment, 0, len(dbCommitments)),
+		NextLink:            reports_v2.NextPageLink(r.URL, nextMarker),
+	}
+	for _, c := range dbCommitments {
+		result.ArchivedCommitments = append(result.ArchivedCommitments, resourcesv2.ArchivedCommitment{
+			UUID:             c.UUID,
+			Amount:           c.Amount,
+			Duration:         c.Duration,
+			DomainUUID:       c.DomainUUID,
+			ProjectUUID:      c.ProjectUUID,
+			ServiceType:      c.ServiceType,
+			ResourceName:     c.ResourceName,
+			AvailabilityZone: c.AvailabilityZone,
+			Status:           c.Status,
+			CreatedAt:        limes.UnixEncodedTime{Time: c.CreatedAt},
+			CreatorUUID:      c.CreatorUUID,
+			CreatorName:      c.CreatorName,
+			ConfirmBy:        options.Map(c.ConfirmBy, util.IntoUnixEncodedTime),
+			ConfirmedAt:      options.Map(c.ConfirmedAt, util.IntoUnixEncodedTime),
+			ExpiresAt:        limes.UnixEncodedTime{Time: c.ExpiresAt},
+			SupersededAt:     options.Map(c.SupersededAt, util.IntoUnixEncodedTime),
+			DeletedAt:        options.Map(c.DeletedAt, util.IntoUnixEncodedTime),
+			UpdatedAt:        limes.UnixEncodedTime{Time: c.UpdatedAt},
+			ArchivedAt:       limes.UnixEncodedTime{Time: c.ArchivedAt},
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,386 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/jsonmatch"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/internal/test"
+	"github.com/sapcc/limes/internal/util"
+)
+
+func TestV2InconsistencyReport(t *testing.T) {
+	s := test.NewSetup(t,
+		test.WithConfig(resourceReportConfigJSON),
+		test.WithPersistedServiceInfo("first", test.DefaultLiquidServiceInfo("First")),
+		test.WithPersistedServiceInfo("second", test.DefaultLiquidServiceInfo("Second")),
+		test.WithInitialDiscovery,
+		test.WithEmptyResourceRecordsAsNeeded,
+	)
+
+	s.TokenValidator.Enforcer.AllowCluster = false
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/inconsistencies").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowCluster = true
+
+	// initially, there are no inconsistencies
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/inconsistencies").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"project_quota_overspent": jsonmatch.Array{},
+		"project_quota_mismatch":  jsonmatch.Array{},
+	})
+
+	// put in some inconsistencies in individual AZs as well as in the pseudo-AZ "total"
+	s.MustDBExec(`UPDATE project_az_resources SET quota = 5, usage = 10 WHERE project_id = $1 AND az_resource_id = $2`,
+		s.GetProjectID("berlin"), s.GetAZResourceID("first", "capacity", "az-one"))
+	s.MustDBExec(`UPDATE project_az_resources SET quota = 20, backend_quota = 10 WHERE project_id = $1 AND az_resource_id = $2`,
+		s.GetProjectID("dresden"), s.GetAZResourceID("first", "capacity", liquid.AvailabilityZoneTotal))
+	s.MustDBExec(`UPDATE project_az_resources SET quota = 3, usage = 4 WHERE project_id = $1 AND az_resource_id = $2`,
+		s.GetProjectID("paris"), s.GetAZResourceID("second", "capacity", "az-two"))
+
+	berlin := jsonmatch.Object{
+		"uuid":        "uuid-for-berlin",
+		"name":        "berlin",
+		"parent_uuid": "uuid-for-germany",
+		"domain":      jsonmatch.Object{"uuid": "uuid-for-germany", "name": "germany"},
+	}
+	dresden := jsonmatch.Object{
+		"uuid":        "uuid-for-dresden",
+		"name":        "dresden",
+		"parent_uuid": "uuid-for-berlin",
+		"domain":      jsonmatch.Object{"uuid": "uuid-for-germany", "name": "germany"},
+	}
+	paris := jsonmatch.Object{
+		"uuid":        "uuid-for-paris",
+		"name":        "paris",
+		"parent_uuid": "uuid-for-france",
+		"domain":      jsonmatch.Object{"uuid": "uuid-for-france", "name": "france"},
+	}
+	overspentInBerlin := jsonmatch.Object{
+		"project":           berlin,
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"unit":              "B",
+		"quota":             5,
+		"usage":             10,
+	}
+	overspentInParis := jsonmatch.Object{
+		"project":           paris,
+		"service_type":      "second",
+		"resource_name":     "capacity",
+		"availability_zone": "az-two",
+		"unit":              "B",
+		"quota":             3,
+		"usage":             4,
+	}
+	mismatchInDresden := jsonmatch.Object{
+		"project":           dresden,
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "total",
+		"unit":              "B",
+		"quota":             20,
+		"backend_quota":     10,
+	}
+
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/inconsistencies").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"project_quota_overspent": jsonmatch.Array{overspentInParis, overspentInBerlin},
+		"project_quota_mismatch":  jsonmatch.Array{mismatchInDresden},
+	})
+
+	// filtering
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/inconsistencies?area=first").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"project_quota_overspent": jsonmatch.Array{overspentInBerlin},
+		"project_quota_mismatch":  jsonmatch.Array{mismatchInDresden},
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/inconsistencies?service=second").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"project_quota_overspent": jsonmatch.Array{overspentInParis},
+		"project_quota_mismatch":  jsonmatch.Array{},
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/inconsistencies?domain_uuid=uuid-for-germany").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"project_quota_overspent": jsonmatch.Array{overspentInBerlin},
+		"project_quota_mismatch":  jsonmatch.Array{mismatchInDresden},
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/inconsistencies?az=total").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"project_quota_overspent": jsonmatch.Array{},
+		"project_quota_mismatch":  jsonmatch.Array{mismatchInDresden},
+	})
+
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/inconsistencies?domain_uuid=does-not-exist").
+		ExpectText(t, http.StatusNotFound, "no such domain (UUID = does-not-exist)\n")
+}
+
+func TestV2ScrapeErrorReport(t *testing.T) {
+	s := test.NewSetup(t,
+		test.WithConfig(resourceReportConfigJSON),
+		test.WithPersistedServiceInfo("first", test.DefaultLiquidServiceInfo("First")),
+		test.WithPersistedServiceInfo("second", test.DefaultLiquidServiceInfo("Second")),
+		test.WithInitialDiscovery,
+		test.WithEmptyResourceRecordsAsNeeded,
+	)
+
+	s.TokenValidator.Enforcer.AllowCluster = false
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/scrape-errors").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowCluster = true
+
+	// initially, there are no scrape errors
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/scrape-errors").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"scrape_errors": jsonmatch.Array{},
+	})
+
+	// the same error in multiple projects is reported only once
+	s.Clock.StepBy(time.Hour)
+	s.MustDBExec(`UPDATE project_services SET scrape_error_message = $1, checked_at = $2 WHERE service_id = $3 AND project_id IN ($4, $5)`,
+		"liquid is down", s.Clock.Now(), s.GetServiceID("first"), s.GetProjectID("berlin"), s.GetProjectID("dresden"))
+	s.MustDBExec(`UPDATE project_services SET scrape_error_message = $1 WHERE service_id = $2 AND project_id = $3`,
+		"project not found", s.GetServiceID("second"), s.GetProjectID("paris"))
+
+	errorInGermany := jsonmatch.Object{
+		"project": jsonmatch.Object{
+			"uuid":        "uuid-for-berlin",
+			"name":        "berlin",
+			"parent_uuid": "uuid-for-germany",
+			"domain":      jsonmatch.Object{"uuid": "uuid-for-germany", "name": "germany"},
+		},
+		"affected_projects": 2,
+		"service_type":      "first",
+		"checked_at":        s.Clock.Now().Unix(),
+		"message":           "liquid is down",
+	}
+	errorInFrance := jsonmatch.Object{
+		"project": jsonmatch.Object{
+			"uuid":        "uuid-for-paris",
+			"name":        "paris",
+			"parent_uuid": "uuid-for-france",
+			"domain":      jsonmatch.Object{"uuid": "uuid-for-france", "name": "france"},
+		},
+		"affected_projects": 1,
+		"service_type":      "second",
+		"message":           "project not found",
+	}
+
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/scrape-errors").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"scrape_errors": jsonmatch.Array{errorInGermany, errorInFrance},
+	})
+
+	// filtering
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/scrape-errors?area=second").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"scrape_errors": jsonmatch.Array{errorInFrance},
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/scrape-errors?service=first").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"scrape_errors": jsonmatch.Array{errorInGermany},
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/scrape-errors?domain_uuid=uuid-for-france").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"scrape_errors": jsonmatch.Array{errorInFrance},
+	})
+}
+
+func TestV2ArchivedCommitmentList(t *testing.T) {
+	s := test.NewSetup(t,
+		test.WithConfig(resourceReportConfigJSON),
+		test.WithPersistedServiceInfo("first", test.DefaultLiquidServiceInfo("First")),
+		test.WithPersistedServiceInfo("second", test.DefaultLiquidServiceInfo("Second")),
+		test.WithInitialDiscovery,
+		test.WithEmptyResourceRecordsAsNeeded,
+	)
+
+	s.TokenValidator.Enforcer.AllowCluster = false
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowCluster = true
+
+	// initially, the archive is empty
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"archived_commitments": jsonmatch.Array{},
+	})
+
+	// archived commitments may refer to projects that do not exist anymore
+	committedForOneYear := must.Return(limesresources.ParseCommitmentDuration("1 year"))
+	now := s.Clock.Now()
+	s.MustDBInsert(&db.ArchivedProjectCommitment{
+		ID:                  1,
+		UUID:                "00000000-0000-0000-0000-000000000001",
+		DomainUUID:          "uuid-for-germany",
+		ProjectUUID:         "uuid-for-berlin",
+		ServiceType:         "first",
+		ResourceName:        "capacity",
+		AvailabilityZone:    "az-one",
+		Status:              liquid.CommitmentStatusExpired,
+		Amount:              10,
+		Duration:            committedForOneYear,
+		CreatedAt:           now.Add(-400 * 24 * time.Hour),
+		CreatorUUID:         "dummy",
+		CreatorName:         "dummy",
+		ConfirmedAt:         Some(now.Add(-400 * 24 * time.Hour)),
+		ExpiresAt:           now.Add(-35 * 24 * time.Hour),
+		UpdatedAt:           now.Add(-35 * 24 * time.Hour),
+		CreationContextJSON: json.RawMessage(`{"reason":"create"}`),
+		ArchivedAt:          now,
+	})
+	s.MustDBInsert(&db.ArchivedProjectCommitment{
+		ID:                  2,
+		UUID:                "00000000-0000-0000-0000-000000000002",
+		DomainUUID:          "uuid-for-atlantis",
+		ProjectUUID:         "uuid-for-poseidonis",
+		ServiceType:         "second",
+		ResourceName:        "things",
+		AvailabilityZone:    "any",
+		Status:              util.CommitmentStatusDeleted,
+		Amount:              5,
+		Duration:            committedForOneYear,
+		CreatedAt:           now.Add(-200 * 24 * time.Hour),
+		CreatorUUID:         "dummy",
+		CreatorName:         "dummy",
+		ConfirmBy:           Some(now.Add(-100 * 24 * time.Hour)),
+		ExpiresAt:           now.Add(165 * 24 * time.Hour),
+		DeletedAt:           Some(now.Add(-190 * 24 * time.Hour)),
+		UpdatedAt:           now.Add(-190 * 24 * time.Hour),
+		CreationContextJSON: json.RawMessage(`{"reason":"create"}`),
+		ArchivedAt:          now,
+	})
+
+	archivedInBerlin := jsonmatch.Object{
+		"uuid":              "00000000-0000-0000-0000-000000000001",
+		"amount":            10,
+		"duration":          "1 year",
+		"domain_id":         "uuid-for-germany",
+		"project_id":        "uuid-for-berlin",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "expired",
+		"created_at":        now.Add(-400 * 24 * time.Hour).Unix(),
+		"creator_uuid":      "dummy",
+		"creator_name":      "dummy",
+		"confirmed_at":      now.Add(-400 * 24 * time.Hour).Unix(),
+		"expires_at":        now.Add(-35 * 24 * time.Hour).Unix(),
+		"updated_at":        now.Add(-35 * 24 * time.Hour).Unix(),
+		"archived_at":       now.Unix(),
+	}
+	archivedInPoseidonis := jsonmatch.Object{
+		"uuid":              "00000000-0000-0000-0000-000000000002",
+		"amount":            5,
+		"duration":          "1 year",
+		"domain_id":         "uuid-for-atlantis",
+		"project_id":        "uuid-for-poseidonis",
+		"service_type":      "second",
+		"resource_name":     "things",
+		"availability_zone": "any",
+		"status":            "deleted",
+		"created_at":        now.Add(-200 * 24 * time.Hour).Unix(),
+		"creator_uuid":      "dummy",
+		"creator_name":      "dummy",
+		"confirm_by":        now.Add(-100 * 24 * time.Hour).Unix(),
+		"expires_at":        now.Add(165 * 24 * time.Hour).Unix(),
+		"deleted_at":        now.Add(-190 * 24 * time.Hour).Unix(),
+		"updated_at":        now.Add(-190 * 24 * time.Hour).Unix(),
+		"archived_at":       now.Unix(),
+	}
+
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"archived_commitments": jsonmatch.Array{archivedInBerlin, archivedInPoseidonis},
+	})
+
+	// filtering
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments?uuid=00000000-0000-0000-0000-000000000002").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"archived_commitments": jsonmatch.Array{archivedInPoseidonis},
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments?project_uuid=uuid-for-berlin").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"archived_commitments": jsonmatch.Array{archivedInBerlin},
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments?domain_uuid=uuid-for-atlantis").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"archived_commitments": jsonmatch.Array{archivedInPoseidonis},
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments?service=first&resource=capacity&az=az-one").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"archived_commitments": jsonmatch.Array{archivedInBerlin},
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments?service=first&az=any").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"archived_commitments": jsonmatch.Array{},
+	})
+
+	// commitments are ordered by archival time first, so a commitment that was archived earlier comes first despite its higher ID
+	s.MustDBInsert(&db.ArchivedProjectCommitment{
+		ID:                  3,
+		UUID:                "00000000-0000-0000-0000-000000000003",
+		DomainUUID:          "uuid-for-germany",
+		ProjectUUID:         "uuid-for-dresden",
+		ServiceType:         "first",
+		ResourceName:        "things",
+		AvailabilityZone:    "any",
+		Status:              liquid.CommitmentStatusSuperseded,
+		Amount:              1,
+		Duration:            committedForOneYear,
+		CreatedAt:           now.Add(-100 * 24 * time.Hour),
+		CreatorUUID:         "dummy",
+		CreatorName:         "dummy",
+		ConfirmedAt:         Some(now.Add(-100 * 24 * time.Hour)),
+		ExpiresAt:           now.Add(265 * 24 * time.Hour),
+		SupersededAt:        Some(now.Add(-80 * 24 * time.Hour)),
+		UpdatedAt:           now.Add(-80 * 24 * time.Hour),
+		CreationContextJSON: json.RawMessage(`{"reason":"create"}`),
+		ArchivedAt:          now.Add(-24 * time.Hour),
+	})
+	archivedInDresden := jsonmatch.Object{
+		"uuid":              "00000000-0000-0000-0000-000000000003",
+		"amount":            1,
+		"duration":          "1 year",
+		"domain_id":         "uuid-for-germany",
+		"project_id":        "uuid-for-dresden",
+		"service_type":      "first",
+		"resource_name":     "things",
+		"availability_zone": "any",
+		"status":            "superseded",
+		"created_at":        now.Add(-100 * 24 * time.Hour).Unix(),
+		"creator_uuid":      "dummy",
+		"creator_name":      "dummy",
+		"confirmed_at":      now.Add(-100 * 24 * time.Hour).Unix(),
+		"expires_at":        now.Add(265 * 24 * time.Hour).Unix(),
+		"superseded_at":     now.Add(-80 * 24 * time.Hour).Unix(),
+		"updated_at":        now.Add(-80 * 24 * time.Hour).Unix(),
+		"archived_at":       now.Add(-24 * time.Hour).Unix(),
+	}
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"archived_commitments": jsonmatch.Array{archivedInDresden, archivedInBerlin, archivedInPoseidonis},
+	})
+
+	// filtering by time ranges
+	formatTime := func(ts time.Time) string { return url.QueryEscape(ts.Format(time.RFC3339)) }
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments?min_archived_at="+formatTime(now.Add(-time.Hour))).ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"archived_commitments": jsonmatch.Array{archivedInBerlin, archivedInPoseidonis},
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments?max_archived_at="+formatTime(now.Add(-time.Hour))).ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"archived_commitments": jsonmatch.Array{archivedInDresden},
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments?min_expires_at="+formatTime(now)+"&max_expires_at="+formatTime(now.Add(200*24*time.Hour))).ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"archived_commitments": jsonmatch.Array{archivedInPoseidonis},
+	})
+
+	// pagination
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments?limit=2").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"archived_commitments": jsonmatch.Array{archivedInDresden, archivedInBerlin},
+		"next":                 "/resources/v2/admin/archived-commitments?limit=2&marker=00000000-0000-0000-0000-000000000001",
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments?limit=2&marker=00000000-0000-0000-0000-000000000001").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"archived_commitments": jsonmatch.Array{archivedInPoseidonis},
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments?limit=1&marker=00000000-0000-0000-0000-000000000003&service=second").ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"archived_commitments": jsonmatch.Array{archivedInPoseidonis},
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments?limit=0").
+		ExpectText(t, http.StatusBadRequest, "limit must be greater than zero\n")
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/admin/archived-commitments?marker=00000000-0000-0000-0000-000000000099").
+		ExpectText(t, http.StatusBadRequest, "no archived commitment found for marker \"00000000-0000-0000-0000-000000000099\"\n")
+}
@@ -0,0 +1,52 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/sapcc/go-api-declarations/opts"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/respondwith"
+
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+	"github.com/sapcc/limes/internal/api/reports_v2"
+)
+
+var errProjectUUIDMissing = errors.New("query parameter project_uuid is required")
+
+// handleGetResourcesAvailability handles GET /resources/v2/availability.
+func (p *v2Provider) handleGetResourcesAvailability(r *http.Request, token *gopherpolicy.Token) (resourcesv2.AvailabilityGetResponse, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/availability")
+	none := resourcesv2.AvailabilityGetResponse{}
+
+	options, err := opts.ParseQueryString[common.AvailabilityReportOpts](r.URL.Query())
+	if err != nil {
+		return none, err
+	}
+	projectUUID, ok := options.ProjectUUID.Unpack()
+	if !ok {
+		return none, respondwith.CustomStatus(http.StatusBadRequest, errProjectUUIDMissing)
+	}
+	dbDomain, dbProject, err := p.checkProjectAccess(token, projectUUID, "v2:project:report_single")
+	if err != nil {
+		return none, err
+	}
+
+	filter, err := reports_v2.FilterFromResourceOpts(p.Cluster, common.ResourceReportOpts{
+		GenericReportOpts: common.GenericReportOpts{
+			Area:        options.Area,
+			ServiceType: options.ServiceType,
+			Category:    options.Category,
+		},
+		ResourceName: options.ResourceName,
+	})
+	if err != nil {
+		return none, err
+	}
+	return reports_v2.GetAvailability(p.Cluster, filter, dbDomain, dbProject)
+}
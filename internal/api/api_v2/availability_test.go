@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sapcc/go-bits/httptest"
+	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/jsonmatch"
+
+	"github.com/sapcc/limes/internal/test"
+	"github.com/sapcc/limes/internal/test/common_fixtures"
+)
+
+var availabilityConfigJSON = string(must.Return(httptest.NewJQModifiableJSONString(test.RemoveCommentsFromJSON(`
+	{
+		"liquids": {
+			"first": {
+				"area": "first",
+				"commitment_behavior_per_resource": [
+					{
+						"key": "capacity",
+						"value": {
+							"durations_per_domain": [{"key": ".*", "value": ["1 hour", "2 hours"]}],
+							"until_percent": 80
+						}
+					},
+					{
+						"key": "things",
+						"value": {
+							"durations_per_domain": [{"key": "germany", "value": ["1 hour"]}]
+						}
+					}
+				]
+			},
+			"second": {
+				"area": "second",
+				"commitment_behavior_per_resource": []
+			}
+		}
+	}`), "availabilityConfigJSON").
+	ModifyWithVariable(".discovery = $ref", common_fixtures.DiscoveryBerlinDresdenParis).
+	ModifyWithVariable(".areas = $ref", common_fixtures.AreasFirstSecond).
+	ModifyWithVariable(".availability_zones = $ref", common_fixtures.AZsOneTwo).
+	MarshalJSON()))
+
+func TestV2ResourceAvailability(t *testing.T) {
+	s := test.NewSetup(t,
+		test.WithConfig(availabilityConfigJSON),
+		test.WithPersistedServiceInfo("first", test.DefaultLiquidServiceInfo("First")),
+		test.WithPersistedServiceInfo("second", test.DefaultLiquidServiceInfo("Second")),
+		test.WithInitialDiscovery,
+		test.WithEmptyResourceRecordsAsNeeded,
+	)
+
+	// capacity: az-one has 100 (80 committable), az-two has 50 (40 committable)
+	s.MustDBExec(`UPDATE az_resources SET raw_capacity = 100 WHERE id = $1`, s.GetAZResourceID("first", "capacity", "az-one"))
+	s.MustDBExec(`UPDATE az_resources SET raw_capacity = 50 WHERE id = $1`, s.GetAZResourceID("first", "capacity", "az-two"))
+
+	// usage: berlin uses 10 in az-one, dresden uses 20 in az-two, berlin uses 2 things
+	s.MustDBExec(`UPDATE project_az_resources SET usage = 10 WHERE project_id = $1 AND az_resource_id = $2`,
+		s.GetProjectID("berlin"), s.GetAZResourceID("first", "capacity", "az-one"))
+	s.MustDBExec(`UPDATE project_az_resources SET usage = 20 WHERE project_id = $1 AND az_resource_id = $2`,
+		s.GetProjectID("dresden"), s.GetAZResourceID("first", "capacity", "az-two"))
+	s.MustDBExec(`UPDATE project_az_resources SET usage = 2 WHERE project_id = $1 AND az_resource_id = $2`,
+		s.GetProjectID("berlin"), s.GetAZResourceID("first", "things", "any"))
+
+	// the project is required
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/availability").
+		ExpectText(t, http.StatusBadRequest, "query parameter project_uuid is required\n")
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/availability?project_uuid=does-not-exist").
+		ExpectText(t, http.StatusNotFound, "no such project (UUID = does-not-exist)\n")
+	s.TokenValidator.Enforcer.AllowReportSingle = false
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/availability?project_uuid=uuid-for-berlin").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowReportSingle = true
+
+	// berlin can commit unused capacity up to until_percent in AZ-aware resources (in az-one, this includes its own usage),
+	// and its own usage in flat resources without capacity
+	expectedBerlin := jsonmatch.Object{
+		"service_areas": jsonmatch.Object{
+			"first": jsonmatch.Object{
+				"services": jsonmatch.Object{
+					"first": jsonmatch.Object{
+						"categories": jsonmatch.Object{
+							"first": jsonmatch.Object{
+								"resources": jsonmatch.Object{
+									"things": jsonmatch.Object{
+										"availability_zones": jsonmatch.Object{
+											"any": jsonmatch.Object{"committable": 2},
+										},
+									},
+								},
+							},
+							"foo_category": jsonmatch.Object{
+								"resources": jsonmatch.Object{
+									"capacity": jsonmatch.Object{
+										"availability_zones": jsonmatch.Object{
+											"az-one": jsonmatch.Object{"committable": 80},
+											"az-two": jsonmatch.Object{"committable": 20},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/availability?project_uuid=uuid-for-berlin").
+		ExpectJSON(t, http.StatusOK, expectedBerlin)
+
+	// filters restrict the set of reported resources
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/availability?project_uuid=uuid-for-berlin&resource=capacity").
+		ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+			"service_areas": jsonmatch.Object{
+				"first": jsonmatch.Object{
+					"services": jsonmatch.Object{
+						"first": jsonmatch.Object{
+							"categories": jsonmatch.Object{
+								"foo_category": jsonmatch.Object{
+									"resources": jsonmatch.Object{
+										"capacity": jsonmatch.Object{
+											"availability_zones": jsonmatch.Object{
+												"az-one": jsonmatch.Object{"committable": 80},
+												"az-two": jsonmatch.Object{"committable": 20},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+
+	// paris is not in a domain where first/things accepts commitments;
+	// in az-one, the usage of berlin reduces what paris can commit
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/availability?project_uuid=uuid-for-paris").
+		ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+			"service_areas": jsonmatch.Object{
+				"first": jsonmatch.Object{
+					"services": jsonmatch.Object{
+						"first": jsonmatch.Object{
+							"categories": jsonmatch.Object{
+								"foo_category": jsonmatch.Object{
+									"resources": jsonmatch.Object{
+										"capacity": jsonmatch.Object{
+											"availability_zones": jsonmatch.Object{
+												"az-one": jsonmatch.Object{"committable": 70},
+												"az-two": jsonmatch.Object{"committable": 20},
+											},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		})
+
+	// forbidden resources do not accept commitments
+	s.MustDBExec(`UPDATE project_resources SET forbidden = TRUE WHERE project_id = $1 AND resource_id = $2`,
+		s.GetProjectID("berlin"), s.GetResourceID("first", "things"))
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/availability?project_uuid=uuid-for-berlin&resource=things").
+		ExpectJSON(t, http.StatusOK, jsonmatch.Object{"service_areas": jsonmatch.Object{}})
+}
@@ -10,10 +10,10 @@ import (
 	"github.com/sapcc/go-bits/gopherpolicy"
 	"github.com/sapcc/go-bits/httpapi"
 
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	ratesv2 "github.com/sapcc/limes/apideclarations/apiv2/rates"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
 	"github.com/sapcc/limes/internal/api/reports_v2"
-	"github.com/sapcc/limes/internal/apideclarations/apiv2/common"
-	ratesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/rates"
-	resourcesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/resources"
 )
 
 // handleGetResourcesCluster handles GET /resources/v2/cluster.
@@ -29,11 +29,19 @@ func (p *v2Provider) handleGetResourcesCluster(r *http.Request, token *gopherpol
 	if err != nil {
 		return none, err
 	}
-	_, err = reports_v2.FilterFromResourceOpts(p.Cluster, options.ResourceReportOpts)
+	filter, err := reports_v2.FilterFromResourceOpts(p.Cluster, options.ResourceReportOpts)
 	if err != nil {
 		return none, err
 	}
-	return none, nil
+	err = p.checkReportVersion(r, token, reports_v2.Scope{})
+	if err != nil {
+		return none, err
+	}
+	result, err := reports_v2.GetClusterResources(p.Cluster, token, p.timeNow(), filter, options)
+	if err != nil {
+		return none, err
+	}
+	return result, nil
 }
 
 // handleGetRatesCluster handles GET /rates/v2/cluster.
@@ -53,6 +61,10 @@ func (p *v2Provider) handleGetRatesCluster(r *http.Request, token *gopherpolicy.
 	if err != nil {
 		return none, err
 	}
+	err = p.checkReportVersion(r, token, reports_v2.Scope{})
+	if err != nil {
+		return none, err
+	}
 	result, err := reports_v2.GetClusterRates(p.Cluster, token, filter, options)
 	if err != nil {
 		return none, err
@@ -4,15 +4,20 @@
 package api_v2_test
 
 import (
+	"encoding/json"
 	"net/http"
 	"testing"
+	"time"
 
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
 	"github.com/sapcc/go-api-declarations/liquid"
 	"github.com/sapcc/go-bits/httptest"
 	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/jsonmatch"
 
 	. "go.xyrillian.de/gg/option"
 
+	"github.com/sapcc/limes/internal/db"
 	"github.com/sapcc/limes/internal/test"
 	"github.com/sapcc/limes/internal/test/common_fixtures"
 )
@@ -44,6 +49,140 @@ var rateReportConfigJSON = string(must.Return(httptest.NewJQModifiableJSONString
 	ModifyWithVariable(".availability_zones = $ref", common_fixtures.AZsOneTwo).
 	MarshalJSON()))
 
+var resourceReportConfigJSON = string(must.Return(httptest.NewJQModifiableJSONString(test.RemoveCommentsFromJSON(`
+	{
+		"liquids": {
+			"first": {
+				"area": "first",
+				"commitment_behavior_per_resource": []
+			},
+			"second": {
+				"area": "second",
+				"commitment_behavior_per_resource": []
+			}
+		},
+		"resource_behavior": [
+			{"resource": "first/capacity", "overcommit_factor": 1.5}
+		]
+	}`), "resourceReportConfigJSON").
+	ModifyWithVariable(".discovery = $ref", common_fixtures.DiscoveryBerlinDresdenParis).
+	ModifyWithVariable(".areas = $ref", common_fixtures.AreasFirstSecond).
+	ModifyWithVariable(".availability_zones = $ref", common_fixtures.AZsOneTwo).
+	MarshalJSON()))
+
+// setupResourceReportTest fills the DB with capacity, usage and commitment data
+// which is shared between the resource report tests on all levels.
+func setupResourceReportTest(t *testing.T) test.Setup {
+	t.Helper()
+	s := test.NewSetup(t,
+		test.WithConfig(resourceReportConfigJSON),
+		test.WithPersistedServiceInfo("first", test.DefaultLiquidServiceInfo("First")),
+		test.WithPersistedServiceInfo("second", test.DefaultLiquidServiceInfo("Second")),
+		test.WithInitialDiscovery,
+		test.WithEmptyResourceRecordsAsNeeded,
+	)
+	s.Clock.StepBy(time.Hour)
+
+	// capacity: az-one has 100 (150 after overcommit), az-two has 200 (300 after overcommit)
+	s.MustDBExec(`UPDATE az_resources SET raw_capacity = 100, usage = 30, subcapacities = '[{"name":"foo","capacity":100}]' WHERE id = $1`,
+		s.GetAZResourceID("first", "capacity", "az-one"))
+	s.MustDBExec(`UPDATE az_resources SET raw_capacity = 200 WHERE id = $1`,
+		s.GetAZResourceID("first", "capacity", "az-two"))
+	s.MustDBExec(`UPDATE services SET scraped_at = $1 WHERE type = 'first'`, s.Clock.Now())
+
+	// usage: berlin uses 10 in az-one, dresden uses 20 in az-two, paris uses 2 things
+	s.MustDBExec(`UPDATE project_az_resources SET usage = 10 WHERE project_id = $1 AND az_resource_id = $2`,
+		s.GetProjectID("berlin"), s.GetAZResourceID("first", "capacity", "az-one"))
+	s.MustDBExec(`UPDATE project_az_resources SET usage = 20 WHERE project_id = $1 AND az_resource_id = $2`,
+		s.GetProjectID("dresden"), s.GetAZResourceID("first", "capacity", "az-two"))
+	s.MustDBExec(`UPDATE project_az_resources SET usage = 2 WHERE project_id = $1 AND az_resource_id = $2`,
+		s.GetProjectID("paris"), s.GetAZResourceID("first", "things", "any"))
+
+	// commitments: berlin has 15 confirmed in az-one, dresden has 5 planned in az-two
+	committedForOneYear := must.Return(limesresources.ParseCommitmentDuration("1 year"))
+	s.MustDBInsert(&db.ProjectCommitment{
+		UUID:                "00000000-0000-0000-0000-000000000001",
+		ProjectID:           s.GetProjectID("berlin"),
+		AZResourceID:        s.GetAZResourceID("first", "capacity", "az-one"),
+		Amount:              15,
+		Duration:            committedForOneYear,
+		CreatedAt:           s.Clock.Now(),
+		CreatorUUID:         "dummy",
+		CreatorName:         "dummy",
+		ConfirmedAt:         Some(s.Clock.Now()),
+		ExpiresAt:           committedForOneYear.AddTo(s.Clock.Now()),
+		CreationContextJSON: json.RawMessage(`{}`),
+		Status:              liquid.CommitmentStatusConfirmed,
+	})
+	s.MustDBInsert(&db.ProjectCommitment{
+		UUID:                "00000000-0000-0000-0000-000000000002",
+		ProjectID:           s.GetProjectID("dresden"),
+		AZResourceID:        s.GetAZResourceID("first", "capacity", "az-two"),
+		Amount:              5,
+		Duration:            committedForOneYear,
+		CreatedAt:           s.Clock.Now(),
+		CreatorUUID:         "dummy",
+		CreatorName:         "dummy",
+		ConfirmBy:           Some(s.Clock.Now().Add(24 * time.Hour)),
+		ExpiresAt:           committedForOneYear.AddTo(s.Clock.Now().Add(24 * time.Hour)),
+		CreationContextJSON: json.RawMessage(`{}`),
+		Status:              liquid.CommitmentStatusPlanned,
+	})
+	return s
+}
+
+func TestV2ClusterResourceReport(t *testing.T) {
+	s := setupResourceReportTest(t)
+	fixturePath := "./fixtures/resource-cluster.json"
+
+	s.TokenValidator.Enforcer.AllowReportSingle = false
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/cluster").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowReportSingle = true
+
+	// the maximum result set includes all optional fields
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/cluster?with=commitment_stats&with=timing&with=subcapacities").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "all options"))
+
+	// without any options
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/cluster").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "no params").
+			Modify("del(.cluster_report.service_areas[].services[].scraped_at)").
+			Modify("del(.cluster_report.service_areas[].services[].categories[].resources[].availability_zones[].subcapacities)").
+			Modify("del(.cluster_report.service_areas[].services[].categories[].resources[].availability_zones[] | .committed, .committed_confirmed_unutilized, .uncommitted_usage)"))
+
+	// with info, the info report is filtered in the same way as the report
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/cluster?with=info&area=first&with=timing").ExpectJSON(t, http.StatusOK,
+		jsonmatch.Object{
+			"info":           jsonmatch.Irrelevant(),
+			"cluster_report": jsonmatch.Irrelevant(),
+		})
+
+	// filtering
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/cluster?with=timing&area=second").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "area filter").
+			Modify("del(.cluster_report.service_areas.first)").
+			Modify("del(.cluster_report.service_areas[].services[].categories[].resources[].availability_zones[].subcapacities)").
+			Modify("del(.cluster_report.service_areas[].services[].categories[].resources[].availability_zones[] | .committed, .committed_confirmed_unutilized, .uncommitted_usage)"))
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/cluster?with=timing&service=first&resource=things").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "resource filter").
+			Modify("del(.cluster_report.service_areas.second)").
+			Modify("del(.cluster_report.service_areas.first.services.first.categories.foo_category)").
+			Modify("del(.cluster_report.service_areas[].services[].categories[].resources[].availability_zones[] | .committed_confirmed_unutilized, .uncommitted_usage)"))
+
+	// scraped_at filter: second was never scraped, so it does not appear
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/cluster?with=timing&min_scraped_at=1970-01-01T00:00:00Z").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "min_scraped_at filter").
+			Modify("del(.cluster_report.service_areas.second)").
+			Modify("del(.cluster_report.service_areas[].services[].categories[].resources[].availability_zones[].subcapacities)").
+			Modify("del(.cluster_report.service_areas[].services[].categories[].resources[].availability_zones[] | .committed, .committed_confirmed_unutilized, .uncommitted_usage)"))
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/cluster?with=timing&min_scraped_at=1970-01-01T00:00:00Z&max_scraped_at=1970-01-01T02:00:00Z").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "min_scraped_at and max_scraped_at filter").
+			Modify("del(.cluster_report.service_areas.second)").
+			Modify("del(.cluster_report.service_areas[].services[].categories[].resources[].availability_zones[].subcapacities)").
+			Modify("del(.cluster_report.service_areas[].services[].categories[].resources[].availability_zones[] | .committed, .committed_confirmed_unutilized, .uncommitted_usage)"))
+}
+
 func TestV2ClusterRateReport(t *testing.T) {
 	srvInfoFirst := test.DefaultLiquidServiceInfo("First")
 	srvInfoFirst.Rates = map[liquid.RateName]liquid.RateInfo{
@@ -0,0 +1,269 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/audittools"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/logg"
+	"github.com/sapcc/go-bits/must"
+	"github.com/sapcc/go-bits/respondwith"
+	. "go.xyrillian.de/gg/option"
+
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+	"github.com/sapcc/limes/internal/audit"
+	"github.com/sapcc/limes/internal/core"
+	"github.com/sapcc/limes/internal/datamodel"
+	"github.com/sapcc/limes/internal/db"
+)
+
+// handlePostCommitmentAmend handles POST /resources/v2/commitments/:uuid/amend.
+//
+// The additional amount is first placed in a separate commitment, which is confirmed like a new commitment
+// (including the consumption of transferable commitments). Then this commitment and the original commitment
+// are superseded by the amended commitment, in the same way as in a merge.
+func (p *v2Provider) handlePostCommitmentAmend(r *http.Request, token *gopherpolicy.Token) (resourcesv2.CommitmentOperationResponse, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/commitments/:uuid/amend")
+	var (
+		none resourcesv2.CommitmentOperationResponse // used on error return paths only
+		ctx  = r.Context()
+		sis  = p.Cluster.SIC.GetSnapshot()
+		now  = p.timeNow()
+	)
+
+	// parse request
+	req, err := parseRequestBodyAs[resourcesv2.CommitmentAmendRequest](r)
+	if err != nil {
+		return none, err
+	}
+	uuid := liquid.CommitmentUUID(mux.Vars(r)["uuid"])
+
+	// validate request contents
+	c, path, dbDomain, dbProject, err := p.checkCommitmentAccess(token, uuid, "v2:project:commitment_create")
+	if err != nil {
+		return none, err
+	}
+	err = checkCommitmentIsReplaceable(c, "amend")
+	if err != nil {
+		return none, err
+	}
+	if c.Status != liquid.CommitmentStatusConfirmed {
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errAmendUnconfirmed)
+	}
+	if c.RenewContextJSON.IsSome() {
+		// the renewal would continue from the original expiration date, which does not fit an amended commitment
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errAmendRenewed)
+	}
+	azResource, behavior, err := p.validateCommittability(path, dbDomain, dbProject, c.Duration, sis)
+	if err != nil {
+		return none, err
+	}
+	if req.AdditionalAmount == 0 {
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errEmptyAmount)
+	}
+	expiresAt := c.ExpiresAt
+	if behavior.RestartExpiryOnAmend {
+		expiresAt = c.Duration.AddTo(now)
+	}
+
+	// prepare the commitment for the additional amount:
+	// like in handlePostNewCommitment, it needs to be inserted as "pending" initially to avoid confusing the TCC
+	creationContextJSON, err := json.Marshal(db.CommitmentWorkflowContext{Reason: db.CommitmentReasonAmend})
+	if err != nil {
+		return none, err
+	}
+	additionalCommitment := db.ProjectCommitment{
+		UUID:                datamodel.GenerateProjectCommitmentUUID(),
+		ProjectID:           dbProject.ID,
+		AZResourceID:        azResource.ID,
+		Amount:              req.AdditionalAmount,
+		Duration:            c.Duration,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+		CreatorUUID:         token.UserUUID(),
+		CreatorName:         fmt.Sprintf("%s@%s", token.UserName(), token.UserDomainName()),
+		ConfirmBy:           None[time.Time](),
+		ConfirmedAt:         None[time.Time](), // will be set below
+		ExpiresAt:           expiresAt,
+		CreationContextJSON: json.RawMessage(creationContextJSON),
+		Status:              liquid.CommitmentStatusPending,
+	}
+
+	var (
+		auditEvents       []audittools.Event
+		amendedCommitment db.ProjectCommitment
+	)
+	err = withinDryRunnableTx(p.DB, req.DryRun, func(tx db.Interface) error {
+		err := tx.Insert(&additionalCommitment)
+		if err != nil {
+			return err
+		}
+
+		// confirm the additional amount like a new commitment, which might consume transferable commitments
+		mailTemplate := None[core.MailTemplate]()
+		if mailConfig, exists := p.Cluster.Config.MailNotifications.Unpack(); exists && !req.DryRun {
+			mailTemplate = Some(mailConfig.Templates.TransferredCommitments)
+		}
+		tcc, err := datamodel.NewTransferableCommitmentCache(tx, p.Cluster, sis, path, now, datamodel.GenerateProjectCommitmentUUID, datamodel.GenerateTransferToken, mailTemplate)
+		if err != nil {
+			return err
+		}
+		auditContext := audit.Context{UserIdentity: token, Request: r}
+		resp, err := tcc.CanConfirmWithTransfers(ctx, additionalCommitment, dbProject, dbDomain, true, req.DryRun, auditContext, cadf.UpdateAction)
+		if err != nil {
+			return err
+		}
+		err = analyzeCommitmentChangeResponse(resp, now)
+		if err != nil {
+			return err
+		}
+		if !req.DryRun {
+			auditEvents = append(auditEvents, tcc.RetrieveAuditEvents()...)
+		}
+		err = tcc.GenerateTransferMails(p.Cluster.BehaviorForResourcePath(path.Resource()).IdentityInV1API)
+		if err != nil {
+			return err
+		}
+		additionalCommitment.Status = liquid.CommitmentStatusConfirmed
+		additionalCommitment.ConfirmedAt = Some(now)
+		_, err = tx.Update(&additionalCommitment)
+		if err != nil {
+			return err
+		}
+
+		// replace the original commitment and the additional commitment with the amended commitment
+		stats, err := getCommitmentStats(tx, dbProject.ID, azResource.ID)
+		if err != nil {
+			return err
+		}
+		replacedCommitments := []db.ProjectCommitment{c, additionalCommitment}
+		creationContext := db.CommitmentWorkflowContext{Reason: db.CommitmentReasonAmend}
+		for _, rc := range replacedCommitments {
+			creationContext.RelatedCommitmentIDs = append(creationContext.RelatedCommitmentIDs, rc.ID)
+			creationContext.RelatedCommitmentUUIDs = append(creationContext.RelatedCommitmentUUIDs, rc.UUID)
+		}
+		creationContextJSON, err := json.Marshal(creationContext)
+		if err != nil {
+			return err
+		}
+		amendedCommitment = db.ProjectCommitment{
+			UUID:                datamodel.GenerateProjectCommitmentUUID(),
+			ProjectID:           dbProject.ID,
+			AZResourceID:        azResource.ID,
+			Amount:              c.Amount + req.AdditionalAmount,
+			Duration:            c.Duration,
+			CreatedAt:           now,
+			UpdatedAt:           now,
+			CreatorUUID:         token.UserUUID(),
+			CreatorName:         fmt.Sprintf("%s@%s", token.UserName(), token.UserDomainName()),
+			ConfirmedAt:         Some(now),
+			ExpiresAt:           expiresAt,
+			CreationContextJSON: json.RawMessage(creationContextJSON),
+			Status:              liquid.CommitmentStatusConfirmed,
+		}
+		err = tx.Insert(&amendedCommitment)
+		if err != nil {
+			return err
+		}
+
+		liquidCommitments := []liquid.Commitment{
+			{
+				UUID:      amendedCommitment.UUID,
+				OldStatus: None[liquid.CommitmentStatus](),
+				NewStatus: Some(amendedCommitment.Status),
+				Amount:    amendedCommitment.Amount,
+				ConfirmBy: amendedCommitment.ConfirmBy,
+				ExpiresAt: amendedCommitment.ExpiresAt,
+			},
+		}
+		for _, rc := range replacedCommitments {
+			liquidCommitments = append(liquidCommitments, liquid.Commitment{
+				UUID:      rc.UUID,
+				OldStatus: Some(rc.Status),
+				NewStatus: Some(liquid.CommitmentStatusSuperseded),
+				Amount:    rc.Amount,
+				ConfirmBy: rc.ConfirmBy,
+				ExpiresAt: rc.ExpiresAt,
+			})
+		}
+		ccr := liquid.CommitmentChangeRequest{
+			DryRun:      req.DryRun,
+			AZ:          path.AvailabilityZone,
+			InfoVersion: must.BeOK(sis.GetServiceForType(path.ServiceType)).LiquidVersion,
+			ByProject: map[liquid.ProjectUUID]liquid.ProjectCommitmentChangeset{
+				dbProject.UUID: {
+					ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(dbProject, dbDomain),
+					ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
+						path.ResourceName: {
+							TotalConfirmedBefore:  stats.TotalConfirmed,
+							TotalConfirmedAfter:   stats.TotalConfirmed,
+							TotalGuaranteedBefore: stats.TotalGuaranteed,
+							TotalGuaranteedAfter:  stats.TotalGuaranteed,
+							Commitments:           liquidCommitments,
+						},
+					},
+				},
+			},
+		}
+		resp, err = datamodel.DelegateChangeCommitments(ctx, p.Cluster, ccr, sis, path.ServiceType, tx)
+		if err != nil {
+			return err
+		}
+		if ccr.RequiresConfirmation() {
+			err = analyzeCommitmentChangeResponse(resp, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, rc := range replacedCommitments {
+			err = supersedeCommitment(tx, &rc, db.CommitmentReasonAmend, []db.ProjectCommitment{amendedCommitment}, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !req.DryRun {
+			auditEvents = append(auditEvents, audit.CommitmentEventTarget{
+				CommitmentChangeRequest: ccr,
+			}.ReplicateForAllProjectsWithDefaults(audittools.Event{
+				Time:       now,
+				Request:    r,
+				User:       token,
+				ReasonCode: http.StatusOK,
+				Action:     cadf.UpdateAction,
+			})...)
+		}
+		return nil
+	}) // `tx` is committed here
+	if err != nil {
+		return none, err
+	}
+	for _, event := range auditEvents {
+		p.auditor.Record(event)
+	}
+
+	// trigger a capacity scrape in order to ApplyComputedProjectQuota based on the increased commitment
+	if !req.DryRun {
+		_, err := p.DB.Exec(`UPDATE services SET next_scrape_at = $1 WHERE type = $2`, now, path.ServiceType)
+		if err != nil {
+			logg.Error("could not trigger a new capacity scrape after amending commitment %s: %s", c.UUID, err.Error())
+		}
+	}
+
+	return resourcesv2.CommitmentOperationResponse{
+		Commitments: []resourcesv2.Commitment{
+			p.convertReplacementCommitmentToDisplayForm(token, amendedCommitment, path, dbDomain, dbProject, req.DryRun),
+		},
+	}, nil
+}
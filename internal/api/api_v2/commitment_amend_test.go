@@ -0,0 +1,156 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/easypg"
+	"github.com/sapcc/go-bits/httptest"
+	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/assert"
+	"go.xyrillian.de/gg/jsonmatch"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/internal/db"
+)
+
+func TestV2CommitmentAmend(t *testing.T) {
+	// "bigcapacity" is like "capacity", but restarts the commitment duration on amendments
+	s := setupBigCapacityTest(t)
+	firstCapacityAZOne := s.GetAZResourceID("first", "capacity", "az-one")
+	firstBigCapacityAZOne := s.GetAZResourceID("first", "bigcapacity", "az-one")
+	s.MustDBExec(`UPDATE az_resources SET raw_capacity = 20 WHERE id = $1`, firstCapacityAZOne)
+
+	// berlin has 10 confirmed on each resource, plus 5 planned on "capacity"
+	committedForOneHour := must.Return(limesresources.ParseCommitmentDuration("1 hour"))
+	insertCommitment := func(uuid liquid.CommitmentUUID, azResourceID db.AZResourceID, amount uint64, status liquid.CommitmentStatus) {
+		c := db.ProjectCommitment{
+			UUID:                uuid,
+			ProjectID:           s.GetProjectID("berlin"),
+			AZResourceID:        azResourceID,
+			Amount:              amount,
+			Duration:            committedForOneHour,
+			CreatedAt:           s.Clock.Now(),
+			UpdatedAt:           s.Clock.Now(),
+			CreatorUUID:         "dummy",
+			CreatorName:         "dummy",
+			ConfirmedAt:         Some(s.Clock.Now()),
+			ExpiresAt:           committedForOneHour.AddTo(s.Clock.Now()),
+			CreationContextJSON: json.RawMessage(`{}`),
+			Status:              status,
+		}
+		if status == liquid.CommitmentStatusPlanned {
+			c.ConfirmedAt = None[time.Time]()
+			c.ConfirmBy = Some(s.Clock.Now().Add(time.Hour))
+			c.ExpiresAt = committedForOneHour.AddTo(s.Clock.Now().Add(time.Hour))
+		}
+		s.MustDBInsert(&c)
+	}
+	insertCommitment("00000000-0000-0000-0000-000000000001", firstCapacityAZOne, 10, liquid.CommitmentStatusConfirmed)
+	insertCommitment("00000000-0000-0000-0000-000000000002", firstBigCapacityAZOne, 10, liquid.CommitmentStatusConfirmed)
+	insertCommitment("00000000-0000-0000-0000-000000000003", firstCapacityAZOne, 5, liquid.CommitmentStatusPlanned)
+	originalExpiresAt := committedForOneHour.AddTo(s.Clock.Now())
+
+	// amendments happen a while after the original commitments were created, so that restarting the duration makes a difference
+	s.Clock.StepBy(10 * time.Minute)
+	tr, tr0 := easypg.NewTracker(t, s.DB.Db)
+	tr0.Ignore()
+
+	// amending requires permission to create commitments in the commitment's project
+	s.TokenValidator.Enforcer.AllowCommitmentCreate = false
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/amend",
+		httptest.WithJSONBody(map[string]any{"additional_amount": 5}),
+	).ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowCommitmentCreate = true
+
+	// validation errors
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/does-not-exist/amend",
+		httptest.WithJSONBody(map[string]any{"additional_amount": 5}),
+	).ExpectText(t, http.StatusNotFound, "no such commitment\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/amend",
+		httptest.WithJSONBody(map[string]any{"additional_amount": 0}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "amount of committed resource must be greater than zero\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000003/amend",
+		httptest.WithJSONBody(map[string]any{"additional_amount": 5}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "only confirmed commitments may be amended\n")
+
+	// commitments that have already been renewed cannot be amended, regardless of whether the amendment restarts the duration
+	s.MustDBExec(`UPDATE project_commitments SET renew_context_json = '{"reason":"renew","related_ids":[99],"related_uuids":["00000000-0000-0000-0000-000000000099"]}' WHERE id IN (1, 2)`)
+	for _, uuid := range []string{"00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000002"} {
+		s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/"+uuid+"/amend",
+			httptest.WithJSONBody(map[string]any{"additional_amount": 5}),
+		).ExpectText(t, http.StatusUnprocessableEntity, "commitments that have already been renewed may not be amended\n")
+	}
+	s.MustDBExec(`UPDATE project_commitments SET renew_context_json = NULL WHERE id IN (1, 2)`)
+
+	// the additional amount is subject to the same capacity check as a new commitment:
+	// on 20 capacity, berlin already has 10 committed, so another 15 do not fit (this uses up ID 4)
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/amend",
+		httptest.WithJSONBody(map[string]any{"additional_amount": 15}),
+	).ExpectText(t, http.StatusConflict, "not enough capacity!\n")
+	tr.DBChanges().AssertEmpty()
+	s.Auditor.ExpectEvents(t /*, nothing */)
+
+	// amended commitments count as newly created by the user who amended them (i.e. createdByAlice)
+	onBigCapacity := jsonmatch.Object{"resource_name": "bigcapacity"}
+
+	// dry run does not have any side effects (this uses up IDs 5 and 6)
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/amend",
+		httptest.WithJSONBody(map[string]any{"dry_run": true, "additional_amount": 5}),
+	).ExpectJSON(t, http.StatusOK, jsonmatch.Object{"commitments": jsonmatch.Array{
+		expectedCommitmentJSON("00000000-0000-0000-0000-000000000000", 15, "1 hour", s.Clock.Now(), originalExpiresAt, createdByAlice),
+	}})
+	tr.DBChanges().AssertEmpty()
+	s.Auditor.ExpectEvents(t /*, nothing */)
+
+	// successful amendment: the additional amount is confirmed in a separate commitment (ID 7),
+	// which is then superseded together with the original commitment by the amended commitment (ID 8)
+	var amendedUUID string
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/amend",
+		httptest.WithJSONBody(map[string]any{"additional_amount": 5}),
+	).ExpectJSON(t, http.StatusOK, jsonmatch.Object{"commitments": jsonmatch.Array{
+		expectedCommitmentJSON(jsonmatch.CaptureField(&amendedUUID), 15, "1 hour", s.Clock.Now(), originalExpiresAt, createdByAlice),
+	}})
+	assert.Equal(t, len(s.Auditor.RecordedEvents()), 2)
+	additionalUUID := must.Return(s.DB.SelectStr(`SELECT uuid FROM project_commitments WHERE id = 7`))
+
+	tr.DBChanges().AssertEqualf(`
+		UPDATE project_commitments SET status = 'superseded', superseded_at = %[1]d, supersede_context_json = '{"reason": "amend", "related_ids": [8], "related_uuids": ["%[5]s"]}', updated_at = %[1]d WHERE id = 1 AND uuid = '00000000-0000-0000-0000-000000000001' AND transfer_token = NULL;
+		INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, superseded_at, creation_context_json, supersede_context_json, updated_at) VALUES (7, '%[4]s', 1, %[3]d, 'superseded', 5, '1 hour', %[1]d, 'uuid-for-alice', 'alice@Default', %[1]d, %[2]d, %[1]d, '{"reason": "amend"}', '{"reason": "amend", "related_ids": [8], "related_uuids": ["%[5]s"]}', %[1]d);
+		INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, creation_context_json, updated_at) VALUES (8, '%[5]s', 1, %[3]d, 'confirmed', 15, '1 hour', %[1]d, 'uuid-for-alice', 'alice@Default', %[1]d, %[2]d, '{"reason": "amend", "related_ids": [1, 7], "related_uuids": ["00000000-0000-0000-0000-000000000001", "%[4]s"]}', %[1]d);
+		UPDATE services SET next_scrape_at = %[1]d WHERE id = 1 AND type = 'first' AND liquid_version = 1;
+	`,
+		s.Clock.Now().Unix(), originalExpiresAt.Unix(), firstCapacityAZOne,
+		additionalUUID, amendedUUID,
+	)
+
+	// superseded commitments cannot be amended again
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/amend",
+		httptest.WithJSONBody(map[string]any{"additional_amount": 5}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "cannot amend a commitment in status \"superseded\"\n")
+
+	// on "bigcapacity", the amended commitment runs for its full duration starting from now
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000002/amend",
+		httptest.WithJSONBody(map[string]any{"additional_amount": 20}),
+	).ExpectJSON(t, http.StatusOK, jsonmatch.Object{"commitments": jsonmatch.Array{
+		expectedCommitmentJSON(jsonmatch.CaptureField(&amendedUUID), 30, "1 hour", s.Clock.Now(), committedForOneHour.AddTo(s.Clock.Now()), createdByAlice, onBigCapacity),
+	}})
+	assert.Equal(t, len(s.Auditor.RecordedEvents()), 2)
+	additionalUUID = must.Return(s.DB.SelectStr(`SELECT uuid FROM project_commitments WHERE id = 9`))
+
+	tr.DBChanges().AssertEqualf(`
+		UPDATE project_commitments SET status = 'superseded', superseded_at = %[1]d, supersede_context_json = '{"reason": "amend", "related_ids": [10], "related_uuids": ["%[5]s"]}', updated_at = %[1]d WHERE id = 2 AND uuid = '00000000-0000-0000-0000-000000000002' AND transfer_token = NULL;
+		INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, superseded_at, creation_context_json, supersede_context_json, updated_at) VALUES (9, '%[4]s', 1, %[3]d, 'superseded', 20, '1 hour', %[1]d, 'uuid-for-alice', 'alice@Default', %[1]d, %[2]d, %[1]d, '{"reason": "amend"}', '{"reason": "amend", "related_ids": [10], "related_uuids": ["%[5]s"]}', %[1]d);
+		INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, creation_context_json, updated_at) VALUES (10, '%[5]s', 1, %[3]d, 'confirmed', 30, '1 hour', %[1]d, 'uuid-for-alice', 'alice@Default', %[1]d, %[2]d, '{"reason": "amend", "related_ids": [2, 9], "related_uuids": ["00000000-0000-0000-0000-000000000002", "%[4]s"]}', %[1]d);
+	`,
+		s.Clock.Now().Unix(), committedForOneHour.AddTo(s.Clock.Now()).Unix(), firstBigCapacityAZOne,
+		additionalUUID, amendedUUID,
+	)
+}
@@ -0,0 +1,352 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/go-api-declarations/limes"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/audittools"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/logg"
+	"github.com/sapcc/go-bits/must"
+	"github.com/sapcc/go-bits/respondwith"
+	. "go.xyrillian.de/gg/option"
+
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+	"github.com/sapcc/limes/internal/audit"
+	"github.com/sapcc/limes/internal/core"
+	"github.com/sapcc/limes/internal/datamodel"
+	"github.com/sapcc/limes/internal/db"
+)
+
+const (
+	// maxCommitmentsPerBatch limits the size of a single POST /resources/v2/commitments/batch request.
+	maxCommitmentsPerBatch = 100
+	// maxBatchRequestSize is the request body size limit for POST /resources/v2/commitments/batch.
+	// It comfortably fits maxCommitmentsPerBatch requests of realistic size.
+	maxBatchRequestSize = 64 << 10
+)
+
+// commitmentBatchItem holds the validated state for a single entry of a batch commitment request.
+type commitmentBatchItem struct {
+	Request    resourcesv2.CommitmentRequest
+	Path       db.AZResourcePath
+	AZResource db.AZResource
+	Domain     db.Domain
+	Project    db.Project
+	Commitment db.ProjectCommitment
+}
+
+// commitmentBatchGroupKey identifies a group of batch items whose capacity is checked jointly in a single CommitmentChangeRequest.
+type commitmentBatchGroupKey struct {
+	ServiceType db.ServiceType
+	AZ          limes.AvailabilityZone
+}
+
+// handlePostCommitmentBatch handles POST /resources/v2/commitments/batch.
+func (p *v2Provider) handlePostCommitmentBatch(r *http.Request, token *gopherpolicy.Token) (resourcesv2.CommitmentOperationResponse, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/commitments/batch")
+	var (
+		none resourcesv2.CommitmentOperationResponse // used on error return paths only
+		ctx  = r.Context()
+		sis  = p.Cluster.SIC.GetSnapshot()
+		now  = p.timeNow()
+	)
+
+	// parse request
+	req, err := parseRequestBodyWithSizeLimitAs[resourcesv2.CommitmentBatchRequest](r, maxBatchRequestSize)
+	if err != nil {
+		return none, err
+	}
+	if len(req.Commitments) == 0 {
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errEmptyBatch)
+	}
+	if len(req.Commitments) > maxCommitmentsPerBatch {
+		err := fmt.Errorf("cannot create more than %d commitments in one batch", maxCommitmentsPerBatch)
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, err)
+	}
+
+	// validate all requested commitments before creating any of them
+	type projectScope struct {
+		Domain  db.Domain
+		Project db.Project
+	}
+	scopes := make(map[liquid.ProjectUUID]projectScope)
+	items := make([]commitmentBatchItem, len(req.Commitments))
+	for idx, cr := range req.Commitments {
+		if cr.DryRun {
+			return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errDryRunInBatch)
+		}
+		scope, exists := scopes[cr.ProjectUUID]
+		if !exists {
+			dbDomain, dbProject, err := p.checkProjectAccess(token, cr.ProjectUUID, "v2:project:commitment_create")
+			if err != nil {
+				return none, err
+			}
+			scope = projectScope{dbDomain, dbProject}
+			scopes[cr.ProjectUUID] = scope
+		}
+		azResource, err := p.validateCommitmentRequest(cr, scope.Domain, scope.Project, sis, now)
+		if err != nil {
+			return none, err
+		}
+		c, err := prepareNewCommitment(cr, azResource, scope.Project, token, now)
+		if err != nil {
+			return none, err
+		}
+		items[idx] = commitmentBatchItem{
+			Request:    cr,
+			Path:       azResourcePathOfRequest(cr),
+			AZResource: azResource,
+			Domain:     scope.Domain,
+			Project:    scope.Project,
+			Commitment: c,
+		}
+	}
+
+	var auditEvents []audittools.Event
+	err = withinDryRunnableTx(p.DB, req.DryRun, func(tx db.Interface) error {
+		// all commitments are inserted in their preliminary status (see prepareNewCommitment),
+		// such that the capacity checks below do not count them as existing commitments
+		for idx := range items {
+			err := tx.Insert(&items[idx].Commitment)
+			if err != nil {
+				return err
+			}
+		}
+
+		// confirmed commitments are checked like in handlePostNewCommitment because they might consume other commitments;
+		// all other commitments are checked jointly per service type and AZ
+		ccrsByGroup, err := buildBatchCommitmentChangeRequests(tx, items, sis, req.DryRun)
+		if err != nil {
+			return err
+		}
+		groups := slices.SortedFunc(func(yield func(commitmentBatchGroupKey) bool) {
+			for key := range ccrsByGroup {
+				if !yield(key) {
+					return
+				}
+			}
+		}, func(lhs, rhs commitmentBatchGroupKey) int {
+			return cmp.Or(cmp.Compare(lhs.ServiceType, rhs.ServiceType), cmp.Compare(lhs.AZ, rhs.AZ))
+		})
+		var confirmedPaths []db.AZResourcePath
+		for _, item := range items {
+			if item.Request.Status == liquid.CommitmentStatusConfirmed && !slices.Contains(confirmedPaths, item.Path) {
+				confirmedPaths = append(confirmedPaths, item.Path)
+			}
+		}
+
+		// When the batch needs several capacity checks, run all of them as dry runs first.
+		// This way, no liquid gets to apply changes for its part of the batch if another part is going to be rejected.
+		if len(groups)+len(confirmedPaths) > 1 && !req.DryRun {
+			for _, key := range groups {
+				ccr := ccrsByGroup[key]
+				ccr.DryRun = true
+				err := checkBatchCommitmentChangeRequest(ctx, p.Cluster, ccr, sis, key.ServiceType, tx, now)
+				if err != nil {
+					return err
+				}
+			}
+			_, err := p.confirmBatchCommitmentsWithTransfers(r, token, tx, items, confirmedPaths, sis, true, now)
+			if err != nil {
+				return err
+			}
+		}
+		for _, key := range groups {
+			err := checkBatchCommitmentChangeRequest(ctx, p.Cluster, ccrsByGroup[key], sis, key.ServiceType, tx, now)
+			if err != nil {
+				return err
+			}
+		}
+		transferAuditEvents, err := p.confirmBatchCommitmentsWithTransfers(r, token, tx, items, confirmedPaths, sis, req.DryRun, now)
+		if err != nil {
+			return err
+		}
+
+		// update status (as mentioned before, some commitments had to be inserted in a preliminary status)
+		for idx := range items {
+			item := &items[idx]
+			if item.Commitment.Status == item.Request.Status {
+				continue
+			}
+			item.Commitment.Status = item.Request.Status
+			if item.Request.Status == liquid.CommitmentStatusConfirmed {
+				item.Commitment.ConfirmedAt = Some(now)
+			}
+			_, err := tx.Update(&item.Commitment)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !req.DryRun {
+			for _, key := range groups {
+				auditEvents = append(auditEvents, audit.CommitmentEventTarget{
+					CommitmentChangeRequest: ccrsByGroup[key],
+				}.ReplicateForAllProjectsWithDefaults(audittools.Event{
+					Time:       now,
+					Request:    r,
+					User:       token,
+					ReasonCode: http.StatusCreated,
+					Action:     cadf.CreateAction,
+				})...)
+			}
+			auditEvents = append(auditEvents, transferAuditEvents...)
+		}
+		return nil
+	}) // `tx` is committed here
+	if err != nil {
+		return none, err
+	}
+	for _, event := range auditEvents {
+		p.auditor.Record(event)
+	}
+
+	// trigger a capacity scrape in order to ApplyComputedProjectQuota based on the new commitments
+	if !req.DryRun {
+		var serviceTypes []db.ServiceType
+		for _, item := range items {
+			if item.Commitment.Status == liquid.CommitmentStatusConfirmed && !slices.Contains(serviceTypes, item.Path.ServiceType) {
+				serviceTypes = append(serviceTypes, item.Path.ServiceType)
+			}
+		}
+		for _, serviceType := range serviceTypes {
+			_, err := p.DB.Exec(`UPDATE services SET next_scrape_at = $1 WHERE type = $2`, now, serviceType)
+			if err != nil {
+				logg.Error("could not trigger a new capacity scrape for %s after creating a batch of commitments: %s", serviceType, err.Error())
+			}
+		}
+	}
+
+	// the commitments are listed in the same order as in the request
+	result := resourcesv2.CommitmentOperationResponse{
+		Commitments: make([]resourcesv2.Commitment, len(items)),
+	}
+	for idx, item := range items {
+		result.Commitments[idx] = p.convertReplacementCommitmentToDisplayForm(token, item.Commitment, item.Path, item.Domain, item.Project, req.DryRun)
+	}
+	return result, nil
+}
+
+// buildBatchCommitmentChangeRequests groups the commitments of a batch by service type and AZ,
+// and builds a CommitmentChangeRequest for each group.
+// This ensures that all commitments in the same AZ resource are checked jointly against the available capacity.
+// Confirmed commitments are skipped since they are checked by confirmBatchCommitmentsWithTransfers instead.
+func buildBatchCommitmentChangeRequests(tx db.Interface, items []commitmentBatchItem, sis core.ServiceInfoSnapshot, dryRun bool) (map[commitmentBatchGroupKey]liquid.CommitmentChangeRequest, error) {
+	result := make(map[commitmentBatchGroupKey]liquid.CommitmentChangeRequest)
+	for _, item := range items {
+		if item.Request.Status == liquid.CommitmentStatusConfirmed {
+			continue
+		}
+		key := commitmentBatchGroupKey{item.Path.ServiceType, item.Path.AvailabilityZone}
+		ccr, exists := result[key]
+		if !exists {
+			ccr = liquid.CommitmentChangeRequest{
+				DryRun:      dryRun,
+				AZ:          key.AZ,
+				InfoVersion: must.BeOK(sis.GetServiceForType(key.ServiceType)).LiquidVersion,
+				ByProject:   make(map[liquid.ProjectUUID]liquid.ProjectCommitmentChangeset),
+			}
+		}
+
+		pcs, exists := ccr.ByProject[item.Project.UUID]
+		if !exists {
+			pcs = liquid.ProjectCommitmentChangeset{
+				ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(item.Project, item.Domain),
+				ByResource:      make(map[liquid.ResourceName]liquid.ResourceCommitmentChangeset),
+			}
+		}
+
+		rcs, exists := pcs.ByResource[item.Path.ResourceName]
+		if !exists {
+			stats, err := getCommitmentStats(tx, item.Project.ID, item.AZResource.ID)
+			if err != nil {
+				return nil, err
+			}
+			rcs = liquid.ResourceCommitmentChangeset{
+				TotalConfirmedBefore:  stats.TotalConfirmed,
+				TotalConfirmedAfter:   stats.TotalConfirmed,
+				TotalGuaranteedBefore: stats.TotalGuaranteed,
+				TotalGuaranteedAfter:  stats.TotalGuaranteed,
+			}
+		}
+		if item.Request.Status == liquid.CommitmentStatusGuaranteed {
+			rcs.TotalGuaranteedAfter += item.Commitment.Amount
+		}
+		rcs.Commitments = append(rcs.Commitments, liquid.Commitment{
+			UUID:      item.Commitment.UUID,
+			OldStatus: None[liquid.CommitmentStatus](),
+			NewStatus: Some(item.Request.Status),
+			Amount:    item.Commitment.Amount,
+			ConfirmBy: item.Commitment.ConfirmBy,
+			ExpiresAt: item.Commitment.ExpiresAt,
+		})
+
+		pcs.ByResource[item.Path.ResourceName] = rcs
+		ccr.ByProject[item.Project.UUID] = pcs
+		result[key] = ccr
+	}
+	return result, nil
+}
+
+// confirmBatchCommitmentsWithTransfers checks the confirmed commitments of a batch in the same way as handlePostNewCommitment,
+// using one TransferableCommitmentCache per AZ resource, such that they might consume commitments that are marked for transfer.
+// Like in handlePostNewCommitment, updating the status of the confirmed commitments is left to the caller.
+func (p *v2Provider) confirmBatchCommitmentsWithTransfers(r *http.Request, token *gopherpolicy.Token, tx db.Interface, items []commitmentBatchItem, paths []db.AZResourcePath, sis core.ServiceInfoSnapshot, dryRun bool, now time.Time) ([]audittools.Event, error) {
+	var auditEvents []audittools.Event
+	auditContext := audit.Context{UserIdentity: token, Request: r}
+	for _, path := range paths {
+		mailTemplate := None[core.MailTemplate]()
+		if mailConfig, exists := p.Cluster.Config.MailNotifications.Unpack(); exists && !dryRun {
+			mailTemplate = Some(mailConfig.Templates.TransferredCommitments)
+		}
+		tcc, err := datamodel.NewTransferableCommitmentCache(tx, p.Cluster, sis, path, now, datamodel.GenerateProjectCommitmentUUID, datamodel.GenerateTransferToken, mailTemplate)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range items {
+			if item.Path != path || item.Request.Status != liquid.CommitmentStatusConfirmed {
+				continue
+			}
+			resp, err := tcc.CanConfirmWithTransfers(r.Context(), item.Commitment, item.Project, item.Domain, true, dryRun, auditContext, cadf.CreateAction)
+			if err != nil {
+				return nil, err
+			}
+			err = analyzeCommitmentChangeResponse(resp, now)
+			if err != nil {
+				return nil, err
+			}
+		}
+		if !dryRun {
+			auditEvents = append(auditEvents, tcc.RetrieveAuditEvents()...)
+		}
+		err = tcc.GenerateTransferMails(p.Cluster.BehaviorForResourcePath(path.Resource()).IdentityInV1API)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return auditEvents, nil
+}
+
+// checkBatchCommitmentChangeRequest delegates a CommitmentChangeRequest and converts a negative response into an API error.
+func checkBatchCommitmentChangeRequest(ctx context.Context, cluster *core.Cluster, ccr liquid.CommitmentChangeRequest, sis core.ServiceInfoSnapshot, serviceType db.ServiceType, tx db.Interface, now time.Time) error {
+	resp, err := datamodel.DelegateChangeCommitments(ctx, cluster, ccr, sis, serviceType, tx)
+	if err != nil {
+		return err
+	}
+	if ccr.RequiresConfirmation() {
+		return analyzeCommitmentChangeResponse(resp, now)
+	}
+	return nil
+}
@@ -0,0 +1,409 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/easypg"
+	"github.com/sapcc/go-bits/httptest"
+	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/assert"
+	"go.xyrillian.de/gg/jsonmatch"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/internal/datamodel"
+	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/internal/test"
+)
+
+func setupCommitmentBatchTest(t *testing.T) (test.Setup, *easypg.Tracker) {
+	t.Helper()
+	s := test.NewSetup(t,
+		test.WithConfig(commitmentCreateConfigJSON),
+		test.WithPersistedServiceInfo("first", test.DefaultLiquidServiceInfo("First")),
+		test.WithPersistedServiceInfo("second", test.DefaultLiquidServiceInfo("Second")),
+		test.WithInitialDiscovery,
+		test.WithEmptyResourceRecordsAsNeeded,
+	)
+	s.Clock.StepBy(time.Hour) // to detect later that services.next_scrape_at was moved to NOW() after adding confirmed commitments
+
+	// set up some capacity in first/capacity, and allocate some of it with usage
+	firstCapacityID := s.GetResourceID("first", "capacity")
+	for az, capacity := range map[liquid.AvailabilityZone]uint64{
+		"az-one": 20,
+		"az-two": 30,
+		"total":  50,
+	} {
+		s.MustDBExec(`UPDATE az_resources SET raw_capacity = $1 WHERE az = $2 AND resource_id = $3`,
+			capacity, az, firstCapacityID)
+	}
+	dresdenID := s.GetProjectID("dresden")
+	firstCapacityOneID := s.GetAZResourceID("first", "capacity", "az-one")
+	s.MustDBExec(`UPDATE project_az_resources SET usage = $1 WHERE project_id = $2 AND az_resource_id = $3`,
+		10, dresdenID, firstCapacityOneID)
+
+	tr, tr0 := easypg.NewTracker(t, s.DB.Db)
+	tr0.Ignore()
+	return s, tr
+}
+
+func TestCommitmentBatchCreate(t *testing.T) {
+	ctx := t.Context()
+	s, tr := setupCommitmentBatchTest(t)
+	const oneDay time.Duration = 24 * time.Hour
+
+	request := map[string]any{
+		"commitments": []map[string]any{
+			{
+				"amount":            1,
+				"duration":          "1 hour",
+				"project_id":        "uuid-for-berlin",
+				"service_type":      "first",
+				"resource_name":     "capacity",
+				"availability_zone": "az-one",
+				"status":            "pending",
+			},
+			{
+				"amount":            5,
+				"duration":          "1 hour",
+				"project_id":        "uuid-for-berlin",
+				"service_type":      "first",
+				"resource_name":     "capacity",
+				"availability_zone": "az-one",
+				"status":            "confirmed",
+			},
+			{
+				"amount":            5,
+				"duration":          "1 hour",
+				"project_id":        "uuid-for-dresden",
+				"service_type":      "first",
+				"resource_name":     "capacity",
+				"availability_zone": "az-two",
+				"status":            "confirmed",
+			},
+			{
+				"amount":            2,
+				"duration":          "1 hour",
+				"project_id":        "uuid-for-berlin",
+				"service_type":      "first",
+				"resource_name":     "things",
+				"availability_zone": "any",
+				"status":            "planned",
+				"confirm_by":        s.Clock.Now().Add(10 * oneDay).Unix(),
+			},
+		},
+	}
+	var uuids [4]string
+	expectedCommitments := func(dryRun bool) jsonmatch.Array {
+		uuidField := func(idx int) any {
+			if dryRun {
+				return "00000000-0000-0000-0000-000000000000"
+			}
+			return jsonmatch.CaptureField(&uuids[idx])
+		}
+		return jsonmatch.Array{
+			jsonmatch.Object{
+				"uuid":              uuidField(0),
+				"amount":            1,
+				"duration":          "1 hour",
+				"project_id":        "uuid-for-berlin",
+				"service_type":      "first",
+				"resource_name":     "capacity",
+				"availability_zone": "az-one",
+				"status":            "pending",
+				"created_at":        s.Clock.Now().Unix(),
+				"creator_uuid":      "uuid-for-alice",
+				"creator_name":      "alice@Default",
+				"can_be_deleted":    true,
+				"confirm_by":        s.Clock.Now().Unix(),
+				"expires_at":        s.Clock.Now().Add(1 * time.Hour).Unix(),
+				"updated_at":        s.Clock.Now().Unix(),
+			},
+			jsonmatch.Object{
+				"uuid":              uuidField(1),
+				"amount":            5,
+				"duration":          "1 hour",
+				"project_id":        "uuid-for-berlin",
+				"service_type":      "first",
+				"resource_name":     "capacity",
+				"availability_zone": "az-one",
+				"status":            "confirmed",
+				"created_at":        s.Clock.Now().Unix(),
+				"creator_uuid":      "uuid-for-alice",
+				"creator_name":      "alice@Default",
+				"can_be_deleted":    true,
+				"confirmed_at":      s.Clock.Now().Unix(),
+				"expires_at":        s.Clock.Now().Add(1 * time.Hour).Unix(),
+				"updated_at":        s.Clock.Now().Unix(),
+			},
+			jsonmatch.Object{
+				"uuid":              uuidField(2),
+				"amount":            5,
+				"duration":          "1 hour",
+				"project_id":        "uuid-for-dresden",
+				"service_type":      "first",
+				"resource_name":     "capacity",
+				"availability_zone": "az-two",
+				"status":            "confirmed",
+				"created_at":        s.Clock.Now().Unix(),
+				"creator_uuid":      "uuid-for-alice",
+				"creator_name":      "alice@Default",
+				"can_be_deleted":    true,
+				"confirmed_at":      s.Clock.Now().Unix(),
+				"expires_at":        s.Clock.Now().Add(1 * time.Hour).Unix(),
+				"updated_at":        s.Clock.Now().Unix(),
+			},
+			jsonmatch.Object{
+				"uuid":              uuidField(3),
+				"amount":            2,
+				"duration":          "1 hour",
+				"project_id":        "uuid-for-berlin",
+				"service_type":      "first",
+				"resource_name":     "things",
+				"availability_zone": "any",
+				"status":            "planned",
+				"created_at":        s.Clock.Now().Unix(),
+				"creator_uuid":      "uuid-for-alice",
+				"creator_name":      "alice@Default",
+				"can_be_deleted":    true,
+				"confirm_by":        s.Clock.Now().Add(10 * oneDay).Unix(),
+				"expires_at":        s.Clock.Now().Add(10*oneDay + 1*time.Hour).Unix(),
+				"updated_at":        s.Clock.Now().Unix(),
+			},
+		}
+	}
+
+	// dry run does not have any side effects
+	dryrunRequest := map[string]any{"dry_run": true, "commitments": request["commitments"]}
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/batch", httptest.WithJSONBody(dryrunRequest)).
+		ExpectJSON(t, http.StatusCreated, jsonmatch.Object{"commitments": expectedCommitments(true)})
+	s.Auditor.ExpectEvents(t, nil...)
+	tr.DBChanges().AssertEmpty()
+
+	// actual run creates all commitments in the order of the request (the dry run used up IDs 1 through 4)
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/batch", httptest.WithJSONBody(request)).
+		ExpectJSON(t, http.StatusCreated, jsonmatch.Object{"commitments": expectedCommitments(false)})
+	tr.DBChanges().AssertEqualf(`
+			INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirm_by, expires_at, creation_context_json, updated_at) VALUES (5, '%[1]s', 1, 2, 'pending', 1, '1 hour', %[5]d, 'uuid-for-alice', 'alice@Default', %[5]d, %[6]d, '{"reason": "create"}', %[5]d);
+			INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, creation_context_json, updated_at) VALUES (6, '%[2]s', 1, 2, 'confirmed', 5, '1 hour', %[5]d, 'uuid-for-alice', 'alice@Default', %[5]d, %[6]d, '{"reason": "create"}', %[5]d);
+			INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, creation_context_json, updated_at) VALUES (7, '%[3]s', 2, 3, 'confirmed', 5, '1 hour', %[5]d, 'uuid-for-alice', 'alice@Default', %[5]d, %[6]d, '{"reason": "create"}', %[5]d);
+			INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirm_by, expires_at, creation_context_json, updated_at) VALUES (8, '%[4]s', 1, 6, 'planned', 2, '1 hour', %[5]d, 'uuid-for-alice', 'alice@Default', %[7]d, %[8]d, '{"reason": "create"}', %[5]d);
+			UPDATE services SET next_scrape_at = %[5]d WHERE id = 1 AND type = 'first' AND liquid_version = 1;
+		`,
+		uuids[0], uuids[1], uuids[2], uuids[3],
+		s.Clock.Now().Unix(),
+		s.Clock.Now().Add(1*time.Hour).Unix(),
+		s.Clock.Now().Add(10*oneDay).Unix(),
+		s.Clock.Now().Add(10*oneDay+1*time.Hour).Unix(),
+	)
+
+	// there is one audit event per project for each AZ for the unconfirmed commitments (in the order "any", "az-one"),
+	// followed by one audit event per confirmed commitment (in the order "az-one", "az-two")
+	events := s.Auditor.RecordedEvents()
+	assert.Equal(t, len(events), 4)
+	assert.Equal(t, events[0].Target.ProjectID, "uuid-for-berlin")
+	assert.Equal(t, events[1].Target.ProjectID, "uuid-for-berlin")
+	assert.Equal(t, events[2].Target.ProjectID, "uuid-for-berlin")
+	assert.Equal(t, events[3].Target.ProjectID, "uuid-for-dresden")
+	for _, event := range events {
+		assert.Equal(t, event.Action, "create")
+		assert.Equal(t, event.Reason.ReasonCode, "201")
+		assert.Equal(t, event.RequestPath, "/resources/v2/commitments/batch")
+	}
+}
+
+func TestCommitmentBatchCreateWithTransfer(t *testing.T) {
+	ctx := t.Context()
+	s, tr := setupCommitmentBatchTest(t)
+
+	// dresden has a commitment that is marked for public transfer
+	s.MustDBInsert(&db.ProjectCommitment{
+		UUID:                "00000000-0000-0000-0000-000000000001",
+		ProjectID:           s.GetProjectID("dresden"),
+		AZResourceID:        s.GetAZResourceID("first", "capacity", "az-one"),
+		Amount:              3,
+		Duration:            must.Return(limesresources.ParseCommitmentDuration("1 hour")),
+		CreatedAt:           s.Clock.Now(),
+		UpdatedAt:           s.Clock.Now(),
+		CreatorUUID:         "dummy",
+		CreatorName:         "dummy",
+		ConfirmedAt:         Some(s.Clock.Now()),
+		ExpiresAt:           s.Clock.Now().Add(1 * time.Hour),
+		CreationContextJSON: json.RawMessage(`{}`),
+		Status:              liquid.CommitmentStatusConfirmed,
+		TransferStatus:      limesresources.CommitmentTransferStatusPublic,
+		TransferToken:       Some("dummy-token"),
+		TransferStartedAt:   Some(s.Clock.Now()),
+	})
+	tr.DBChanges().Ignore()
+
+	commitments := []map[string]any{
+		{
+			"amount":            5,
+			"duration":          "1 hour",
+			"project_id":        "uuid-for-berlin",
+			"service_type":      "first",
+			"resource_name":     "capacity",
+			"availability_zone": "az-one",
+			"status":            "confirmed",
+		},
+		{
+			"amount":            1,
+			"duration":          "1 hour",
+			"project_id":        "uuid-for-berlin",
+			"service_type":      "first",
+			"resource_name":     "capacity",
+			"availability_zone": "az-one",
+			"status":            "pending",
+		},
+	}
+
+	// dry run does not consume the transferable commitment
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/batch", httptest.WithJSONBody(map[string]any{"dry_run": true, "commitments": commitments})).
+		ExpectStatus(t, http.StatusCreated)
+	s.Auditor.ExpectEvents(t, nil...)
+	tr.DBChanges().AssertEmpty()
+
+	// the confirmed commitment consumes the transferable commitment in the same way as on POST /resources/v2/commitments/new,
+	// including the mail notification to the previous owner (the dry run used up IDs 2 and 3)
+	var uuids [2]string
+	pendingCommitment := expectedCommitmentJSON(jsonmatch.CaptureField(&uuids[1]), 1, "1 hour", s.Clock.Now(), s.Clock.Now().Add(1*time.Hour), createdByAlice,
+		jsonmatch.Object{"status": "pending", "confirm_by": s.Clock.Now().Unix()})
+	delete(pendingCommitment, "confirmed_at")
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/batch", httptest.WithJSONBody(map[string]any{"commitments": commitments})).
+		ExpectJSON(t, http.StatusCreated, jsonmatch.Object{"commitments": jsonmatch.Array{
+			expectedCommitmentJSON(jsonmatch.CaptureField(&uuids[0]), 5, "1 hour", s.Clock.Now(), s.Clock.Now().Add(1*time.Hour), createdByAlice),
+			pendingCommitment,
+		}})
+	tr.DBChanges().AssertEqualf(`
+			DELETE FROM project_commitments WHERE id = 1 AND uuid = '00000000-0000-0000-0000-000000000001' AND transfer_token = 'dummy-token';
+			INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, superseded_at, creation_context_json, supersede_context_json, updated_at) VALUES (1, '00000000-0000-0000-0000-000000000001', 2, 2, 'superseded', 3, '1 hour', %[3]d, 'dummy', 'dummy', %[3]d, %[4]d, %[3]d, '{}', '{"reason": "consume", "related_ids": [4], "related_uuids": ["%[1]s"]}', %[3]d);
+			INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, creation_context_json, updated_at) VALUES (4, '%[1]s', 1, 2, 'confirmed', 5, '1 hour', %[3]d, 'uuid-for-alice', 'alice@Default', %[3]d, %[4]d, '{"reason": "create"}', %[3]d);
+			INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirm_by, expires_at, creation_context_json, updated_at) VALUES (5, '%[2]s', 1, 2, 'pending', 1, '1 hour', %[3]d, 'uuid-for-alice', 'alice@Default', %[3]d, %[4]d, '{"reason": "create"}', %[3]d);
+			INSERT INTO project_mail_notifications (id, project_id, subject, body, next_submission_at) VALUES (1, 2, 'Transferred!', 'Hello', %[3]d);
+			UPDATE services SET next_scrape_at = %[3]d WHERE id = 1 AND type = 'first' AND liquid_version = 1;
+		`,
+		uuids[0], uuids[1],
+		s.Clock.Now().Unix(),
+		s.Clock.Now().Add(1*time.Hour).Unix(),
+	)
+
+	// the audit trail shows the pending commitment, then the confirmed commitment and its consumption from the POV of both projects
+	events := s.Auditor.RecordedEvents()
+	assert.Equal(t, len(events), 3)
+	assert.Equal(t, events[0].Target.ProjectID, "uuid-for-berlin")
+	assert.Equal(t, events[0].Action, cadf.CreateAction)
+	assert.Equal(t, events[1].Target.ProjectID, "uuid-for-berlin")
+	assert.Equal(t, events[1].Action, cadf.CreateAction)
+	assert.Equal(t, events[1].Reason.ReasonCode, "201")
+	assert.Equal(t, events[2].Target.ProjectID, "uuid-for-dresden")
+	assert.Equal(t, events[2].Action, datamodel.ConsumeAction)
+	assert.Equal(t, events[2].Reason.ReasonCode, "200")
+}
+
+func TestCommitmentBatchCreateErrors(t *testing.T) {
+	ctx := t.Context()
+	s, tr := setupCommitmentBatchTest(t)
+
+	validCommitment := map[string]any{
+		"amount":            1,
+		"duration":          "1 hour",
+		"project_id":        "uuid-for-berlin",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "pending",
+	}
+	expectError := func(commitments []map[string]any, expect func(r httptest.Response)) {
+		t.Helper()
+		for _, dryRun := range []bool{true, false} {
+			request := map[string]any{"dry_run": dryRun, "commitments": commitments}
+			s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/batch", httptest.WithJSONBody(request)).Expect(expect)
+		}
+		s.Auditor.ExpectEvents(t, nil...)
+		tr.DBChanges().AssertEmpty() // since both requests failed
+	}
+
+	// empty batch
+	expectError(nil, func(r httptest.Response) {
+		r.ExpectText(t, http.StatusUnprocessableEntity, "at least one commitment must be given\n")
+	})
+
+	// dry_run can only be given for the whole batch
+	expectError([]map[string]any{
+		{
+			"dry_run":           true,
+			"amount":            1,
+			"duration":          "1 hour",
+			"project_id":        "uuid-for-berlin",
+			"service_type":      "first",
+			"resource_name":     "capacity",
+			"availability_zone": "az-one",
+			"status":            "pending",
+		},
+	}, func(r httptest.Response) {
+		r.ExpectText(t, http.StatusUnprocessableEntity, "dry_run may only be set for the whole batch, not for individual commitments\n")
+	})
+
+	// one invalid entry makes the whole batch fail
+	expectError([]map[string]any{
+		validCommitment,
+		{
+			"amount":            1,
+			"duration":          "1 hour",
+			"project_id":        "uuid-for-berlin",
+			"service_type":      "first",
+			"resource_name":     "nonexistent",
+			"availability_zone": "az-one",
+			"status":            "pending",
+		},
+	}, func(r httptest.Response) {
+		r.ExpectText(t, http.StatusNotFound, "no such resource\n")
+	})
+
+	// permission checks apply to each project in the batch
+	s.TokenValidator.Enforcer.AllowCommitmentCreate = false
+	expectError([]map[string]any{validCommitment}, func(r httptest.Response) {
+		r.ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	})
+	s.TokenValidator.Enforcer.AllowCommitmentCreate = true
+
+	// on 20 capacity in az-one with 10 used by dresden, each of these commitments would fit on its own
+	// (berlin: 9 + 10 <= 20, dresden: max(12, 10) <= 20), but they do not fit together (9 + 12 > 20)
+	for _, dryRun := range []bool{true, false} {
+		s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/batch", httptest.WithJSONBody(map[string]any{
+			"dry_run": dryRun,
+			"commitments": []map[string]any{
+				validCommitment,
+				{
+					"amount":            9,
+					"duration":          "1 hour",
+					"project_id":        "uuid-for-berlin",
+					"service_type":      "first",
+					"resource_name":     "capacity",
+					"availability_zone": "az-one",
+					"status":            "confirmed",
+				},
+				{
+					"amount":            12,
+					"duration":          "1 hour",
+					"project_id":        "uuid-for-dresden",
+					"service_type":      "first",
+					"resource_name":     "capacity",
+					"availability_zone": "az-one",
+					"status":            "confirmed",
+				},
+			},
+		})).ExpectText(t, http.StatusConflict, "not enough capacity!\n")
+	}
+	s.Auditor.ExpectEvents(t, nil...)
+	tr.DBChanges().AssertEmpty() // in particular, the valid pending commitment was not created either
+}
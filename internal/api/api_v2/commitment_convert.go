@@ -0,0 +1,264 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/audittools"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/must"
+	"github.com/sapcc/go-bits/respondwith"
+	. "go.xyrillian.de/gg/option"
+
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+	"github.com/sapcc/limes/internal/audit"
+	"github.com/sapcc/limes/internal/datamodel"
+	"github.com/sapcc/limes/internal/db"
+)
+
+// handlePostCommitmentConvert handles POST /resources/v2/commitments/:uuid/convert.
+func (p *v2Provider) handlePostCommitmentConvert(r *http.Request, token *gopherpolicy.Token) (resourcesv2.CommitmentOperationResponse, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/commitments/:uuid/convert")
+	var (
+		none resourcesv2.CommitmentOperationResponse // used on error return paths only
+		sis  = p.Cluster.SIC.GetSnapshot()
+		now  = p.timeNow()
+	)
+
+	// parse request
+	req, err := parseRequestBodyAs[resourcesv2.CommitmentConvertRequest](r)
+	if err != nil {
+		return none, err
+	}
+	uuid := liquid.CommitmentUUID(mux.Vars(r)["uuid"])
+
+	// validate request contents
+	c, sourcePath, dbDomain, dbProject, err := p.checkCommitmentAccess(token, uuid, "v2:project:commitment_create")
+	if err != nil {
+		return none, err
+	}
+	sourceAZResource, ok := sis.GetAZResourceForPath(sourcePath)
+	if !ok {
+		return none, respondwith.CustomStatus(http.StatusNotFound, errNoSuchResource)
+	}
+	err = checkCommitmentIsReplaceable(c, "convert")
+	if err != nil {
+		return none, err
+	}
+	targetPath := db.AZResourcePath{
+		ServiceType:      req.TargetServiceType,
+		ResourceName:     req.TargetResourceName,
+		AvailabilityZone: sourcePath.AvailabilityZone,
+	}
+	if targetPath == sourcePath {
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errConversionToSameResource)
+	}
+	targetAZResource, targetBehavior, err := p.validateCommittability(targetPath, dbDomain, dbProject, c.Duration, sis)
+	if err != nil {
+		return none, err
+	}
+	sourceBehavior := p.Cluster.CommitmentBehaviorForResourcePath(sourcePath.Resource()).ForDomain(dbDomain.Name)
+	sourceResource := must.BeOK(sis.GetResourceForPath(sourcePath.Resource()))
+	targetResource := must.BeOK(sis.GetResourceForPath(targetPath.Resource())) // existence was checked by validateCommittability
+	rate, ok := sourceBehavior.GetConversionRateTo(targetBehavior).Unpack()
+	if !ok || sourceResource.Unit != targetResource.Unit {
+		err := fmt.Errorf("commitment is not convertible into resource %s", targetPath.Resource().String())
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, err)
+	}
+	if req.SourceAmount == 0 {
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errEmptyAmount)
+	}
+	if req.SourceAmount > c.Amount {
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errAmountExceedsCommitment)
+	}
+	if req.SourceAmount%rate.FromAmount != 0 {
+		err := fmt.Errorf("source_amount must be a multiple of %d for this conversion", rate.FromAmount)
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, err)
+	}
+	targetAmount := (req.SourceAmount / rate.FromAmount) * rate.ToAmount
+	if req.TargetAmount != targetAmount {
+		err := fmt.Errorf("target_amount does not match the conversion rate: expected %d, but got %d", targetAmount, req.TargetAmount)
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, err)
+	}
+
+	var (
+		auditEvents         []audittools.Event
+		remainingCommitment Option[db.ProjectCommitment]
+		convertedCommitment db.ProjectCommitment
+	)
+	err = withinDryRunnableTx(p.DB, req.DryRun, func(tx db.Interface) error {
+		sourceStats, err := getCommitmentStats(tx, dbProject.ID, sourceAZResource.ID)
+		if err != nil {
+			return err
+		}
+		targetStats, err := getCommitmentStats(tx, dbProject.ID, targetAZResource.ID)
+		if err != nil {
+			return err
+		}
+		sourceStatsAfter := sourceStats
+		targetStatsAfter := targetStats
+		switch c.Status {
+		case liquid.CommitmentStatusConfirmed:
+			sourceStatsAfter.TotalConfirmed -= req.SourceAmount
+			targetStatsAfter.TotalConfirmed += targetAmount
+		case liquid.CommitmentStatusGuaranteed:
+			sourceStatsAfter.TotalGuaranteed -= req.SourceAmount
+			targetStatsAfter.TotalGuaranteed += targetAmount
+		}
+
+		// the unconverted part of the commitment (if any) stays on the source resource
+		var newCommitments []db.ProjectCommitment
+		sourceCommitments := []liquid.Commitment{
+			{
+				UUID:      c.UUID,
+				OldStatus: Some(c.Status),
+				NewStatus: Some(liquid.CommitmentStatusSuperseded),
+				Amount:    c.Amount,
+				ConfirmBy: c.ConfirmBy,
+				ExpiresAt: c.ExpiresAt,
+			},
+		}
+		if req.SourceAmount < c.Amount {
+			rc, err := datamodel.BuildSplitCommitment(c, c.Amount-req.SourceAmount, now, datamodel.GenerateProjectCommitmentUUID)
+			if err != nil {
+				return err
+			}
+			err = tx.Insert(&rc)
+			if err != nil {
+				return err
+			}
+			sourceCommitments = append(sourceCommitments, liquid.Commitment{
+				UUID:      rc.UUID,
+				OldStatus: None[liquid.CommitmentStatus](),
+				NewStatus: Some(rc.Status),
+				Amount:    rc.Amount,
+				ConfirmBy: rc.ConfirmBy,
+				ExpiresAt: rc.ExpiresAt,
+			})
+			remainingCommitment = Some(rc)
+			newCommitments = append(newCommitments, rc)
+		}
+
+		// the converted part is placed on the target resource
+		creationContextJSON, err := json.Marshal(db.CommitmentWorkflowContext{
+			Reason:                 db.CommitmentReasonConvert,
+			RelatedCommitmentIDs:   []db.ProjectCommitmentID{c.ID},
+			RelatedCommitmentUUIDs: []liquid.CommitmentUUID{c.UUID},
+		})
+		if err != nil {
+			return err
+		}
+		convertedCommitment = db.ProjectCommitment{
+			UUID:                datamodel.GenerateProjectCommitmentUUID(),
+			ProjectID:           c.ProjectID,
+			AZResourceID:        targetAZResource.ID,
+			Amount:              targetAmount,
+			Duration:            c.Duration,
+			CreatedAt:           now,
+			UpdatedAt:           now,
+			CreatorUUID:         c.CreatorUUID,
+			CreatorName:         c.CreatorName,
+			ConfirmBy:           c.ConfirmBy,
+			ConfirmedAt:         c.ConfirmedAt,
+			ExpiresAt:           c.ExpiresAt,
+			CreationContextJSON: json.RawMessage(creationContextJSON),
+			Status:              c.Status,
+			NotifyOnConfirm:     c.NotifyOnConfirm,
+		}
+		err = tx.Insert(&convertedCommitment)
+		if err != nil {
+			return err
+		}
+		newCommitments = append(newCommitments, convertedCommitment)
+
+		ccr := liquid.CommitmentChangeRequest{
+			DryRun:      req.DryRun,
+			AZ:          sourcePath.AvailabilityZone,
+			InfoVersion: must.BeOK(sis.GetServiceForType(sourcePath.ServiceType)).LiquidVersion,
+			ByProject: map[liquid.ProjectUUID]liquid.ProjectCommitmentChangeset{
+				dbProject.UUID: {
+					ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(dbProject, dbDomain),
+					ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
+						sourcePath.ResourceName: {
+							TotalConfirmedBefore:  sourceStats.TotalConfirmed,
+							TotalConfirmedAfter:   sourceStatsAfter.TotalConfirmed,
+							TotalGuaranteedBefore: sourceStats.TotalGuaranteed,
+							TotalGuaranteedAfter:  sourceStatsAfter.TotalGuaranteed,
+							Commitments:           sourceCommitments,
+						},
+						targetPath.ResourceName: {
+							TotalConfirmedBefore:  targetStats.TotalConfirmed,
+							TotalConfirmedAfter:   targetStatsAfter.TotalConfirmed,
+							TotalGuaranteedBefore: targetStats.TotalGuaranteed,
+							TotalGuaranteedAfter:  targetStatsAfter.TotalGuaranteed,
+							Commitments: []liquid.Commitment{
+								{
+									UUID:      convertedCommitment.UUID,
+									OldStatus: None[liquid.CommitmentStatus](),
+									NewStatus: Some(convertedCommitment.Status),
+									Amount:    convertedCommitment.Amount,
+									ConfirmBy: convertedCommitment.ConfirmBy,
+									ExpiresAt: convertedCommitment.ExpiresAt,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		resp, err := datamodel.DelegateChangeCommitments(r.Context(), p.Cluster, ccr, sis, sourcePath.ServiceType, tx)
+		if err != nil {
+			return err
+		}
+		if ccr.RequiresConfirmation() {
+			err = analyzeCommitmentChangeResponse(resp, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = supersedeCommitment(tx, &c, db.CommitmentReasonConvert, newCommitments, now)
+		if err != nil {
+			return err
+		}
+
+		if !req.DryRun {
+			auditEvents = audit.CommitmentEventTarget{
+				CommitmentChangeRequest: ccr,
+			}.ReplicateForAllProjectsWithDefaults(audittools.Event{
+				Time:       now,
+				Request:    r,
+				User:       token,
+				ReasonCode: http.StatusOK,
+				Action:     cadf.UpdateAction,
+			})
+		}
+		return nil
+	}) // `tx` is committed here
+	if err != nil {
+		return none, err
+	}
+	for _, event := range auditEvents {
+		p.auditor.Record(event)
+	}
+
+	// the converted commitment is listed first, followed by the remainder on the source resource (if any)
+	result := resourcesv2.CommitmentOperationResponse{
+		Commitments: []resourcesv2.Commitment{
+			p.convertReplacementCommitmentToDisplayForm(token, convertedCommitment, targetPath, dbDomain, dbProject, req.DryRun),
+		},
+	}
+	if rc, ok := remainingCommitment.Unpack(); ok {
+		result.Commitments = append(result.Commitments,
+			p.convertReplacementCommitmentToDisplayForm(token, rc, sourcePath, dbDomain, dbProject, req.DryRun))
+	}
+	return result, nil
+}
@@ -0,0 +1,110 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/easypg"
+	"github.com/sapcc/go-bits/httptest"
+	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/assert"
+	"go.xyrillian.de/gg/jsonmatch"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/internal/db"
+)
+
+func TestV2CommitmentConvert(t *testing.T) {
+	s := setupBigCapacityTest(t)
+
+	// berlin has 10 confirmed in az-one
+	committedForOneHour := must.Return(limesresources.ParseCommitmentDuration("1 hour"))
+	s.MustDBInsert(&db.ProjectCommitment{
+		UUID:                "00000000-0000-0000-0000-000000000001",
+		ProjectID:           s.GetProjectID("berlin"),
+		AZResourceID:        s.GetAZResourceID("first", "capacity", "az-one"),
+		Amount:              10,
+		Duration:            committedForOneHour,
+		CreatedAt:           s.Clock.Now(),
+		UpdatedAt:           s.Clock.Now(),
+		CreatorUUID:         "dummy",
+		CreatorName:         "dummy",
+		ConfirmedAt:         Some(s.Clock.Now()),
+		ExpiresAt:           committedForOneHour.AddTo(s.Clock.Now()),
+		CreationContextJSON: json.RawMessage(`{}`),
+		Status:              liquid.CommitmentStatusConfirmed,
+	})
+
+	tr, tr0 := easypg.NewTracker(t, s.DB.Db)
+	tr0.Ignore()
+
+	// converting requires permission to create commitments in the commitment's project
+	s.TokenValidator.Enforcer.AllowCommitmentCreate = false
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/convert",
+		httptest.WithJSONBody(map[string]any{"target_service_type": "first", "target_resource_name": "bigcapacity", "source_amount": 4, "target_amount": 2}),
+	).ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowCommitmentCreate = true
+
+	// validation errors
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/convert",
+		httptest.WithJSONBody(map[string]any{"target_service_type": "first", "target_resource_name": "capacity", "source_amount": 4, "target_amount": 4}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "commitment cannot be converted into its own resource\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/convert",
+		httptest.WithJSONBody(map[string]any{"target_service_type": "first", "target_resource_name": "things", "source_amount": 4, "target_amount": 4}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "commitments are not enabled for this resource\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/convert",
+		httptest.WithJSONBody(map[string]any{"target_service_type": "first", "target_resource_name": "bigcapacity", "source_amount": 12, "target_amount": 6}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "amount may not exceed the amount of the commitment\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/convert",
+		httptest.WithJSONBody(map[string]any{"target_service_type": "first", "target_resource_name": "bigcapacity", "source_amount": 3, "target_amount": 1}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "source_amount must be a multiple of 2 for this conversion\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/convert",
+		httptest.WithJSONBody(map[string]any{"target_service_type": "first", "target_resource_name": "bigcapacity", "source_amount": 4, "target_amount": 3}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "target_amount does not match the conversion rate: expected 2, but got 3\n")
+
+	now := s.Clock.Now()
+	expiresAt := committedForOneHour.AddTo(now)
+	onBigCapacity := jsonmatch.Object{"resource_name": "bigcapacity"}
+
+	// dry run does not have any side effects
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/convert",
+		httptest.WithJSONBody(map[string]any{"dry_run": true, "target_service_type": "first", "target_resource_name": "bigcapacity", "source_amount": 4, "target_amount": 2}),
+	).ExpectJSON(t, http.StatusOK, jsonmatch.Object{"commitments": jsonmatch.Array{
+		expectedCommitmentJSON("00000000-0000-0000-0000-000000000000", 2, "1 hour", now, expiresAt, onBigCapacity),
+		expectedCommitmentJSON("00000000-0000-0000-0000-000000000000", 6, "1 hour", now, expiresAt),
+	}})
+	tr.DBChanges().AssertEmpty()
+	s.Auditor.ExpectEvents(t /*, nothing */)
+
+	// successful conversion of part of the commitment
+	var convertedUUID, remainingUUID string
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/convert",
+		httptest.WithJSONBody(map[string]any{"target_service_type": "first", "target_resource_name": "bigcapacity", "source_amount": 4, "target_amount": 2}),
+	).ExpectJSON(t, http.StatusOK, jsonmatch.Object{"commitments": jsonmatch.Array{
+		expectedCommitmentJSON(jsonmatch.CaptureField(&convertedUUID), 2, "1 hour", now, expiresAt, onBigCapacity),
+		expectedCommitmentJSON(jsonmatch.CaptureField(&remainingUUID), 6, "1 hour", now, expiresAt),
+	}})
+	assert.Equal(t, len(s.Auditor.RecordedEvents()), 1)
+
+	// the original commitment is superseded (IDs 2 and 3 were used up by the dry run)
+	tr.DBChanges().AssertEqualf(`
+		UPDATE project_commitments SET status = 'superseded', superseded_at = %[1]d, supersede_context_json = '{"reason": "convert", "related_ids": [4, 5], "related_uuids": ["%[5]s", "%[6]s"]}' WHERE id = 1 AND uuid = '00000000-0000-0000-0000-000000000001' AND transfer_token = NULL;
+		INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, creation_context_json, updated_at) VALUES (4, '%[5]s', 1, %[3]d, 'confirmed', 6, '1 hour', %[1]d, 'dummy', 'dummy', %[1]d, %[2]d, '{"reason": "split", "related_ids": [1], "related_uuids": ["00000000-0000-0000-0000-000000000001"]}', %[1]d);
+		INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, creation_context_json, updated_at) VALUES (5, '%[6]s', 1, %[4]d, 'confirmed', 2, '1 hour', %[1]d, 'dummy', 'dummy', %[1]d, %[2]d, '{"reason": "convert", "related_ids": [1], "related_uuids": ["00000000-0000-0000-0000-000000000001"]}', %[1]d);
+	`,
+		s.Clock.Now().Unix(), committedForOneHour.AddTo(s.Clock.Now()).Unix(),
+		s.GetAZResourceID("first", "capacity", "az-one"), s.GetAZResourceID("first", "bigcapacity", "az-one"),
+		remainingUUID, convertedUUID,
+	)
+
+	// superseded commitments cannot be converted again
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/convert",
+		httptest.WithJSONBody(map[string]any{"target_service_type": "first", "target_resource_name": "bigcapacity", "source_amount": 4, "target_amount": 2}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "cannot convert a commitment in status \"superseded\"\n")
+}
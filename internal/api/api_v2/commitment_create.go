@@ -22,7 +22,7 @@ import (
 	. "go.xyrillian.de/gg/option"
 	"go.xyrillian.de/gg/options"
 
-	resourcesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/resources"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
 	"github.com/sapcc/limes/internal/audit"
 	"github.com/sapcc/limes/internal/core"
 	"github.com/sapcc/limes/internal/datamodel"
@@ -43,16 +43,7 @@ func (p *v2Provider) handlePostNewCommitment(r *http.Request, token *gopherpolic
 	if err != nil {
 		return none, err
 	}
-	path := db.AZResourcePath{
-		ServiceType:      req.ServiceType,
-		ResourceName:     req.ResourceName,
-		AvailabilityZone: req.AvailabilityZone,
-	}
-	attrs := commitmentStatusAttributes{
-		Status:          req.Status,
-		ConfirmBy:       options.Map(req.ConfirmBy, util.FromUnixEncodedTime),
-		NotifyOnConfirm: req.NotifyOnConfirm,
-	}
+	path := azResourcePathOfRequest(req)
 	now := p.timeNow()
 
 	// validate request contents
@@ -60,51 +51,16 @@ func (p *v2Provider) handlePostNewCommitment(r *http.Request, token *gopherpolic
 	if err != nil {
 		return none, err
 	}
-	azResource, behavior, err := p.validateCommittability(path, dbDomain, dbProject, req.Duration, sis)
-	if err != nil {
-		return none, err
-	}
-	if req.Amount == 0 {
-		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errEmptyAmount)
-	}
-	err = p.validateStatusAttributesOnNewCommitment(attrs, behavior, now)
+	azResource, err := p.validateCommitmentRequest(req, dbDomain, dbProject, sis, now)
 	if err != nil {
 		return none, err
 	}
 
 	// prepare commitment and commitment request
-	creationContextJSON, err := json.Marshal(db.CommitmentWorkflowContext{Reason: db.CommitmentReasonCreate})
+	c, err := prepareNewCommitment(req, azResource, dbProject, token, now)
 	if err != nil {
 		return none, err
 	}
-	c := db.ProjectCommitment{
-		UUID:                datamodel.GenerateProjectCommitmentUUID(),
-		AZResourceID:        azResource.ID,
-		ProjectID:           dbProject.ID,
-		Amount:              req.Amount,
-		Duration:            req.Duration,
-		CreatedAt:           now,
-		UpdatedAt:           now,
-		CreatorUUID:         token.UserUUID(),
-		CreatorName:         fmt.Sprintf("%s@%s", token.UserName(), token.UserDomainName()),
-		ConfirmBy:           None[time.Time](), // may be set below
-		ConfirmedAt:         None[time.Time](), // may be set below
-		ExpiresAt:           req.Duration.AddTo(attrs.ConfirmBy.UnwrapOr(now)),
-		CreationContextJSON: json.RawMessage(creationContextJSON),
-		Status:              req.Status,
-		NotifyOnConfirm:     req.NotifyOnConfirm,
-	}
-	switch c.Status {
-	case liquid.CommitmentStatusConfirmed:
-		// special case: need to insert this with a different status initially;
-		// otherwise TransferableCommitmentCache will count it as an existing
-		// confirmed commitment when gathering stats (will change this later)
-		c.Status = liquid.CommitmentStatusPending
-	case liquid.CommitmentStatusPending:
-		c.ConfirmBy = Some(now)
-	default:
-		c.ConfirmBy = attrs.ConfirmBy
-	}
 
 	// using a transaction because we need to insert the commitment first to get
 	// its ID (for use in the SupersedeContext of consumed commitments),
@@ -139,7 +95,7 @@ func (p *v2Provider) handlePostNewCommitment(r *http.Request, token *gopherpolic
 			if err != nil {
 				return err
 			}
-			err = analyzeCommitmentChangeResponse(resp)
+			err = analyzeCommitmentChangeResponse(resp, now)
 			if err != nil {
 				return err
 			}
@@ -159,6 +115,10 @@ func (p *v2Provider) handlePostNewCommitment(r *http.Request, token *gopherpolic
 				return err
 			}
 		} else {
+			totalGuaranteedAfter := stats.TotalGuaranteed
+			if req.Status == liquid.CommitmentStatusGuaranteed {
+				totalGuaranteedAfter += c.Amount
+			}
 			ccr := liquid.CommitmentChangeRequest{
 				DryRun:      req.DryRun,
 				AZ:          path.AvailabilityZone,
@@ -171,12 +131,12 @@ func (p *v2Provider) handlePostNewCommitment(r *http.Request, token *gopherpolic
 								TotalConfirmedBefore:  stats.TotalConfirmed,
 								TotalConfirmedAfter:   stats.TotalConfirmed,
 								TotalGuaranteedBefore: stats.TotalGuaranteed,
-								TotalGuaranteedAfter:  stats.TotalGuaranteed, // TODO: change when introducing "guaranteed" commitments
+								TotalGuaranteedAfter:  totalGuaranteedAfter,
 								Commitments: []liquid.Commitment{
 									{
 										UUID:      c.UUID,
 										OldStatus: None[liquid.CommitmentStatus](),
-										NewStatus: Some(c.Status),
+										NewStatus: Some(req.Status),
 										Amount:    c.Amount,
 										ConfirmBy: c.ConfirmBy,
 										ExpiresAt: c.ExpiresAt,
@@ -192,7 +152,16 @@ func (p *v2Provider) handlePostNewCommitment(r *http.Request, token *gopherpolic
 				return err
 			}
 			if ccr.RequiresConfirmation() {
-				err = analyzeCommitmentChangeResponse(resp)
+				err = analyzeCommitmentChangeResponse(resp, now)
+				if err != nil {
+					return err
+				}
+			}
+
+			// update status (as mentioned before, we had to insert guaranteed commitments as "planned" initially)
+			if c.Status != req.Status {
+				c.Status = req.Status
+				_, err = tx.Update(&c)
 				if err != nil {
 					return err
 				}
@@ -230,7 +199,7 @@ func (p *v2Provider) handlePostNewCommitment(r *http.Request, token *gopherpolic
 		}
 	}
 
-	canBeDeleted := datamodel.CanDeleteCommitment(token, c, p.timeNow)
+	canBeDeleted := p.canDeleteCommitment(token, c, dbDomain, dbProject)
 	result := convertCommitmentToDisplayForm(c, path, dbProject, canBeDeleted)
 	if req.DryRun {
 		result.UUID = "00000000-0000-0000-0000-000000000000"
@@ -238,6 +207,83 @@ func (p *v2Provider) handlePostNewCommitment(r *http.Request, token *gopherpolic
 	return result, nil
 }
 
+// azResourcePathOfRequest returns the AZ resource that a requested commitment belongs to.
+func azResourcePathOfRequest(req resourcesv2.CommitmentRequest) db.AZResourcePath {
+	return db.AZResourcePath{
+		ServiceType:      req.ServiceType,
+		ResourceName:     req.ResourceName,
+		AvailabilityZone: req.AvailabilityZone,
+	}
+}
+
+// validateCommitmentRequest validates the contents of a request to create a new commitment in the given project.
+// On success, the AZ resource for the new commitment is returned.
+func (p *v2Provider) validateCommitmentRequest(req resourcesv2.CommitmentRequest, dbDomain db.Domain, dbProject db.Project, sis core.ServiceInfoSnapshot, now time.Time) (db.AZResource, error) {
+	azResource, behavior, err := p.validateCommittability(azResourcePathOfRequest(req), dbDomain, dbProject, req.Duration, sis)
+	if err != nil {
+		return db.AZResource{}, err
+	}
+	if req.Amount == 0 {
+		return db.AZResource{}, respondwith.CustomStatus(http.StatusUnprocessableEntity, errEmptyAmount)
+	}
+	attrs := commitmentStatusAttributes{
+		Status:          req.Status,
+		ConfirmBy:       options.Map(req.ConfirmBy, util.FromUnixEncodedTime),
+		NotifyOnConfirm: req.NotifyOnConfirm,
+	}
+	err = p.validateStatusAttributesOnNewCommitment(attrs, behavior, now)
+	if err != nil {
+		return db.AZResource{}, err
+	}
+	return azResource, nil
+}
+
+// prepareNewCommitment builds the database record for a new commitment from a validated request.
+//
+// Commitments requested in status "confirmed" or "guaranteed" are prepared in a preliminary status instead.
+// The caller needs to insert the commitment in this status, and move it into the requested status once the capacity check has passed.
+func prepareNewCommitment(req resourcesv2.CommitmentRequest, azResource db.AZResource, dbProject db.Project, token *gopherpolicy.Token, now time.Time) (db.ProjectCommitment, error) {
+	creationContextJSON, err := json.Marshal(db.CommitmentWorkflowContext{Reason: db.CommitmentReasonCreate})
+	if err != nil {
+		return db.ProjectCommitment{}, err
+	}
+	confirmBy := options.Map(req.ConfirmBy, util.FromUnixEncodedTime)
+	c := db.ProjectCommitment{
+		UUID:                datamodel.GenerateProjectCommitmentUUID(),
+		AZResourceID:        azResource.ID,
+		ProjectID:           dbProject.ID,
+		Amount:              req.Amount,
+		Duration:            req.Duration,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+		CreatorUUID:         token.UserUUID(),
+		CreatorName:         fmt.Sprintf("%s@%s", token.UserName(), token.UserDomainName()),
+		ConfirmBy:           None[time.Time](), // may be set below
+		ConfirmedAt:         None[time.Time](), // may be set below
+		ExpiresAt:           req.Duration.AddTo(confirmBy.UnwrapOr(now)),
+		CreationContextJSON: json.RawMessage(creationContextJSON),
+		Status:              req.Status,
+		NotifyOnConfirm:     req.NotifyOnConfirm,
+	}
+	switch c.Status {
+	case liquid.CommitmentStatusConfirmed:
+		// special case: need to insert this with a different status initially;
+		// otherwise TransferableCommitmentCache will count it as an existing
+		// confirmed commitment when gathering stats (will change this later)
+		c.Status = liquid.CommitmentStatusPending
+	case liquid.CommitmentStatusGuaranteed:
+		// same special case as above: the capacity check must not count this commitment
+		// as an existing guaranteed commitment (will change this later)
+		c.Status = liquid.CommitmentStatusPlanned
+		c.ConfirmBy = confirmBy
+	case liquid.CommitmentStatusPending:
+		c.ConfirmBy = Some(now)
+	default:
+		c.ConfirmBy = confirmBy
+	}
+	return c, nil
+}
+
 // withinDryRunnableTx starts a transaction such that the type system helps enforce that a dry run is not accidentally committed:
 // Within `action`, `tx` is only a generic interface handle that does not allow calling `tx.Commit()` directly.
 func withinDryRunnableTx(dbm *gorp.DbMap, dryRun bool, action func(tx db.Interface) error) error {
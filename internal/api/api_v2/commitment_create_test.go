@@ -375,6 +375,77 @@ func TestCommitmentCreateBasic(t *testing.T) {
 				s.Clock.Now().Unix(),
 				s.Clock.Now().Add(1*time.Hour).Unix(),
 			)
+
+			// "guaranteed" commitments also require capacity to be present, and reserve it until their confirm_by date
+			var uuid4 string
+			createCommitmentAndExpectSuccess(t, s, tr, manager == "liquid", map[string]any{
+				"amount":            4,
+				"duration":          "1 hour",
+				"project_id":        "uuid-for-berlin",
+				"service_type":      "first",
+				"resource_name":     "capacity",
+				"availability_zone": "az-two",
+				"status":            "guaranteed",
+				"confirm_by":        s.Clock.Now().Add(oneDay).Unix(),
+			}, jsonmatch.Object{
+				"uuid":              jsonmatch.CaptureField(&uuid4),
+				"amount":            4,
+				"duration":          "1 hour",
+				"project_id":        "uuid-for-berlin",
+				"service_type":      "first",
+				"resource_name":     "capacity",
+				"availability_zone": "az-two",
+				"status":            "guaranteed",
+				"created_at":        s.Clock.Now().Unix(),
+				"creator_uuid":      "uuid-for-alice",
+				"creator_name":      "alice@Default",
+				"can_be_deleted":    true,
+				"confirm_by":        s.Clock.Now().Add(oneDay).Unix(),
+				"expires_at":        s.Clock.Now().Add(oneDay + 1*time.Hour).Unix(),
+				"updated_at":        s.Clock.Now().Unix(),
+			}, func() cadf.Resource {
+				return cadf.Resource{
+					TypeURI:     "service/resources/commitment",
+					ID:          uuid4,
+					DomainID:    "uuid-for-germany",
+					DomainName:  "germany",
+					ProjectID:   "uuid-for-berlin",
+					ProjectName: "berlin",
+					Attachments: []cadf.Attachment{must.Return(cadf.NewJSONAttachment("payload", map[string]any{
+						"az":          "az-two",
+						"dryRun":      false,
+						"infoVersion": 1,
+						"byProject": map[string]map[string]any{
+							"uuid-for-berlin": {
+								"byResource": map[string]map[string]any{
+									"capacity": {
+										"totalConfirmedBefore":  3,
+										"totalConfirmedAfter":   3,
+										"totalGuaranteedBefore": 0,
+										"totalGuaranteedAfter":  4,
+										"commitments": []map[string]any{{
+											"amount":    4,
+											"confirmBy": s.Clock.Now().Add(oneDay).UTC().Format(time.RFC3339),
+											"expiresAt": s.Clock.Now().Add(oneDay + 1*time.Hour).UTC().Format(time.RFC3339),
+											"newStatus": "guaranteed",
+											"oldStatus": nil,
+											"uuid":      uuid4,
+										}},
+									},
+								},
+							},
+						},
+					}))},
+				}
+			})
+			tr.DBChanges().AssertEqualf(`
+			INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirm_by, expires_at, creation_context_json, updated_at) VALUES (8, '%[1]s', 1, 3, 'guaranteed', 4, '1 hour', %[2]d, 'uuid-for-alice', 'alice@Default', %[3]d, %[4]d, '{"reason": "create"}', %[2]d);
+		`,
+				uuid4,
+				s.Clock.Now().Unix(),
+				s.Clock.Now().Add(oneDay).Unix(),
+				s.Clock.Now().Add(oneDay+1*time.Hour).Unix(),
+			)
 		})
 	}
 }
@@ -548,7 +619,7 @@ func TestCommitmentCreateValidationErrors(t *testing.T) {
 	})
 
 	// invalid choice of status
-	for _, status := range []string{"guaranteed", "active", "superseded", "expired", "deleted", "unknown"} {
+	for _, status := range []string{"active", "superseded", "expired", "deleted", "unknown"} {
 		createCommitmentAndExpectError(t, s, tr, map[string]any{
 			"amount":            1,
 			"duration":          "1 hour",
@@ -563,7 +634,7 @@ func TestCommitmentCreateValidationErrors(t *testing.T) {
 	}
 
 	// invalid presence/absence of confirm_by for chosen state
-	for _, status := range []string{"planned"} { // TODO: add "guaranteed" here
+	for _, status := range []string{"planned", "guaranteed"} {
 		createCommitmentAndExpectError(t, s, tr, map[string]any{
 			"amount":            1,
 			"duration":          "1 hour",
@@ -708,7 +779,7 @@ func TestCommitmentCreateRejectedByLiquid(t *testing.T) {
 		"availability_zone": "az-one",
 		"status":            "confirmed",
 	})).
-		ExpectHeader(t, "Retry-After", s.Clock.Now().Add(1*time.Hour).Format(time.RFC1123)).
+		ExpectHeader(t, "Retry-After", s.Clock.Now().Add(1*time.Hour).UTC().Format(http.TimeFormat)).
 		ExpectText(t, http.StatusConflict, "could not purchase new capacity, Sammy bought it all\n")
 }
 
@@ -755,5 +826,56 @@ func TestCommitmentCreateRejectedByLimes(t *testing.T) {
 		"resource_name":     "capacity",
 		"availability_zone": "az-one",
 		"status":            "confirmed",
+	})).
+		ExpectHeader(t, "Retry-After", ""). // not set because the next capacity scrape is already due
+		ExpectText(t, http.StatusConflict, "not enough capacity!\n")
+
+	// since capacity is only reassessed by the next capacity scrape, clients are advised to retry after that
+	nextScrapeAt := s.Clock.Now().Add(15 * time.Minute)
+	s.MustDBExec(`UPDATE services SET next_scrape_at = $1 WHERE type = $2`, nextScrapeAt, "first")
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/new", httptest.WithJSONBody(map[string]any{
+		"amount":            15,
+		"duration":          "1 hour",
+		"project_id":        "uuid-for-berlin",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "confirmed",
+	})).
+		ExpectHeader(t, "Retry-After", nextScrapeAt.UTC().Format(http.TimeFormat)).
+		ExpectText(t, http.StatusConflict, "not enough capacity!\n")
+
+	// "guaranteed" commitments are subject to the same capacity check...
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/new", httptest.WithJSONBody(map[string]any{
+		"amount":            15,
+		"duration":          "1 hour",
+		"project_id":        "uuid-for-berlin",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "guaranteed",
+		"confirm_by":        s.Clock.Now().Add(1 * time.Hour).Unix(),
+	})).ExpectText(t, http.StatusConflict, "not enough capacity!\n")
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/new", httptest.WithJSONBody(map[string]any{
+		"amount":            5,
+		"duration":          "1 hour",
+		"project_id":        "uuid-for-berlin",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "guaranteed",
+		"confirm_by":        s.Clock.Now().Add(1 * time.Hour).Unix(),
+	})).ExpectStatus(t, http.StatusCreated)
+
+	// ...and the capacity reserved by them is not available to other commitments anymore
+	// (without the guaranteed commitment, this would fit: 5 committed by berlin + 11 committed by dresden <= 20)
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/new", httptest.WithJSONBody(map[string]any{
+		"amount":            11,
+		"duration":          "1 hour",
+		"project_id":        "uuid-for-dresden",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "confirmed",
 	})).ExpectText(t, http.StatusConflict, "not enough capacity!\n")
 }
@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sapcc/go-api-declarations/cadf"
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/audittools"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/must"
+	"github.com/sapcc/go-bits/respondwith"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/internal/audit"
+	"github.com/sapcc/limes/internal/datamodel"
+	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/internal/util"
+)
+
+// handleDeleteCommitment handles DELETE /resources/v2/commitments/:uuid.
+func (p *v2Provider) handleDeleteCommitment(r *http.Request, token *gopherpolicy.Token) (struct{}, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/commitments/:uuid")
+	var (
+		none struct{} // there is no response body
+		sis  = p.Cluster.SIC.GetSnapshot()
+		now  = p.timeNow()
+	)
+
+	uuid := liquid.CommitmentUUID(mux.Vars(r)["uuid"])
+	c, path, dbDomain, dbProject, err := p.checkCommitmentAccess(token, uuid, "v2:project:report_single")
+	if err != nil {
+		return none, err
+	}
+	azResource, ok := sis.GetAZResourceForPath(path)
+	if !ok {
+		return none, respondwith.CustomStatus(http.StatusNotFound, errNoSuchResource)
+	}
+
+	// like in the v1 API, commitments that have already ended their lifecycle are treated as nonexistent
+	if slices.Contains([]liquid.CommitmentStatus{liquid.CommitmentStatusExpired, liquid.CommitmentStatusSuperseded}, c.Status) {
+		return none, respondwith.CustomStatus(http.StatusNotFound, errNoSuchCommitment)
+	}
+	if !p.canDeleteCommitment(token, c, dbDomain, dbProject) {
+		return none, respondwith.CustomStatus(http.StatusForbidden, errCommitmentNotDeletable)
+	}
+
+	var auditEvents []audittools.Event
+	err = withinDryRunnableTx(p.DB, false, func(tx db.Interface) error {
+		stats, err := getCommitmentStats(tx, dbProject.ID, azResource.ID)
+		if err != nil {
+			return err
+		}
+		totalConfirmedAfter := stats.TotalConfirmed
+		totalGuaranteedAfter := stats.TotalGuaranteed
+		switch c.Status {
+		case liquid.CommitmentStatusConfirmed:
+			totalConfirmedAfter -= c.Amount
+		case liquid.CommitmentStatusGuaranteed:
+			totalGuaranteedAfter -= c.Amount
+		}
+
+		ccr := liquid.CommitmentChangeRequest{
+			AZ:          path.AvailabilityZone,
+			InfoVersion: must.BeOK(sis.GetServiceForType(path.ServiceType)).LiquidVersion,
+			ByProject: map[liquid.ProjectUUID]liquid.ProjectCommitmentChangeset{
+				dbProject.UUID: {
+					ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(dbProject, dbDomain),
+					ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
+						path.ResourceName: {
+							TotalConfirmedBefore:  stats.TotalConfirmed,
+							TotalConfirmedAfter:   totalConfirmedAfter,
+							TotalGuaranteedBefore: stats.TotalGuaranteed,
+							TotalGuaranteedAfter:  totalGuaranteedAfter,
+							Commitments: []liquid.Commitment{
+								{
+									UUID:      c.UUID,
+									OldStatus: Some(c.Status),
+									NewStatus: None[liquid.CommitmentStatus](),
+									Amount:    c.Amount,
+									ConfirmBy: c.ConfirmBy,
+									ExpiresAt: c.ExpiresAt,
+								},
+							},
+						},
+					},
+				},
+			},
+		}
+		_, err = datamodel.DelegateChangeCommitments(r.Context(), p.Cluster, ccr, sis, path.ServiceType, tx)
+		if err != nil {
+			return err
+		}
+
+		// perform deletion
+		c.Status = util.CommitmentStatusDeleted
+		c.DeletedAt = Some(now)
+		c.UpdatedAt = now
+		c.TransferStatus = limesresources.CommitmentTransferStatusNone
+		c.TransferToken = None[string]()
+		c.TransferStartedAt = None[time.Time]()
+		_, err = tx.Update(&c)
+		if err != nil {
+			return err
+		}
+
+		auditEvents = audit.CommitmentEventTarget{
+			CommitmentChangeRequest: ccr,
+		}.ReplicateForAllProjectsWithDefaults(audittools.Event{
+			Time:       now,
+			Request:    r,
+			User:       token,
+			ReasonCode: http.StatusNoContent,
+			Action:     cadf.DeleteAction,
+		})
+		return nil
+	}) // `tx` is committed here
+	if err != nil {
+		return none, err
+	}
+
+	for _, event := range auditEvents {
+		p.auditor.Record(event)
+	}
+	return none, nil
+}
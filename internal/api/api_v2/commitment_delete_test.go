@@ -0,0 +1,93 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/go-bits/easypg"
+	"github.com/sapcc/go-bits/must"
+)
+
+func TestV2CommitmentDelete(t *testing.T) {
+	s := setupResourceReportTest(t)
+	const oneYear = 365 * 24 * time.Hour
+	createdAt := s.Clock.Now()
+	s.Clock.StepBy(48 * time.Hour) // to leave the grace period in which commitments can be deleted by their creators
+
+	tr, tr0 := easypg.NewTracker(t, s.DB.Db)
+	tr0.Ignore()
+
+	// deleting requires access to the project
+	s.TokenValidator.Enforcer.AllowReportSingle = false
+	s.Handler.RespondTo(s.Ctx, "DELETE /resources/v2/commitments/00000000-0000-0000-0000-000000000001").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowReportSingle = true
+
+	// deleting requires the specific permission to delete commitments
+	s.TokenValidator.Enforcer.AllowUncommit = false
+	s.Handler.RespondTo(s.Ctx, "DELETE /resources/v2/commitments/00000000-0000-0000-0000-000000000001").
+		ExpectText(t, http.StatusForbidden, "commitment can only be deleted within the deletion grace period after its creation\n")
+	s.TokenValidator.Enforcer.AllowUncommit = true
+	tr.DBChanges().AssertEmpty()
+	s.Auditor.ExpectEvents(t, nil...)
+
+	// successful deletion
+	s.Handler.RespondTo(s.Ctx, "DELETE /resources/v2/commitments/00000000-0000-0000-0000-000000000001").
+		ExpectStatus(t, http.StatusNoContent)
+	tr.DBChanges().AssertEqualf(`
+		UPDATE project_commitments SET status = 'deleted', deleted_at = %[1]d, updated_at = %[1]d WHERE id = 1 AND uuid = '00000000-0000-0000-0000-000000000001' AND transfer_token = NULL;
+	`, s.Clock.Now().Unix())
+	s.Auditor.ExpectEvents(t, cadf.Event{
+		Action:      "delete",
+		Outcome:     "success",
+		Reason:      cadf.Reason{ReasonType: "HTTP", ReasonCode: "204"},
+		RequestPath: "/resources/v2/commitments/00000000-0000-0000-0000-000000000001",
+		Target: cadf.Resource{
+			TypeURI:     "service/resources/commitment",
+			ID:          "00000000-0000-0000-0000-000000000001",
+			DomainID:    "uuid-for-germany",
+			DomainName:  "germany",
+			ProjectID:   "uuid-for-berlin",
+			ProjectName: "berlin",
+			Attachments: []cadf.Attachment{must.Return(cadf.NewJSONAttachment("payload", map[string]any{
+				"az":          "az-one",
+				"dryRun":      false,
+				"infoVersion": 1,
+				"byProject": map[string]map[string]any{
+					"uuid-for-berlin": {
+						"byResource": map[string]map[string]any{
+							"capacity": {
+								"totalConfirmedBefore":  15,
+								"totalConfirmedAfter":   0,
+								"totalGuaranteedBefore": 0,
+								"totalGuaranteedAfter":  0,
+								"commitments": []map[string]any{{
+									"amount":    15,
+									"expiresAt": createdAt.Add(oneYear).UTC().Format(time.RFC3339),
+									"newStatus": nil,
+									"oldStatus": "confirmed",
+									"uuid":      "00000000-0000-0000-0000-000000000001",
+								}},
+							},
+						},
+					},
+				},
+			}))},
+		},
+	})
+
+	// deleted commitments are gone
+	s.Handler.RespondTo(s.Ctx, "DELETE /resources/v2/commitments/00000000-0000-0000-0000-000000000001").
+		ExpectText(t, http.StatusNotFound, "no such commitment\n")
+
+	// commitments that ended their lifecycle cannot be deleted (like in v1, they are reported as nonexistent)
+	s.MustDBExec(`UPDATE project_commitments SET status = 'superseded', superseded_at = $1 WHERE uuid = $2`,
+		s.Clock.Now(), "00000000-0000-0000-0000-000000000002")
+	s.Handler.RespondTo(s.Ctx, "DELETE /resources/v2/commitments/00000000-0000-0000-0000-000000000002").
+		ExpectText(t, http.StatusNotFound, "no such commitment\n")
+}
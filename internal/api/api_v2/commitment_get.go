@@ -0,0 +1,30 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/httpapi"
+
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+)
+
+// handleGetCommitment handles GET /resources/v2/commitments/:uuid.
+func (p *v2Provider) handleGetCommitment(r *http.Request, token *gopherpolicy.Token) (resourcesv2.Commitment, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/commitments/:uuid")
+	none := resourcesv2.Commitment{}
+
+	uuid := liquid.CommitmentUUID(mux.Vars(r)["uuid"])
+	c, path, dbDomain, dbProject, err := p.checkCommitmentAccess(token, uuid, "v2:project:report_single")
+	if err != nil {
+		return none, err
+	}
+
+	canBeDeleted := p.canDeleteCommitment(token, c, dbDomain, dbProject)
+	return convertCommitmentToDisplayForm(c, path, dbProject, canBeDeleted), nil
+}
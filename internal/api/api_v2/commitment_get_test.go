@@ -0,0 +1,74 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"go.xyrillian.de/gg/jsonmatch"
+)
+
+func TestV2CommitmentGet(t *testing.T) {
+	s := setupResourceReportTest(t)
+	s.MustDBExec(`UPDATE project_commitments SET updated_at = created_at`)
+
+	const oneYear = 365 * 24 * time.Hour
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/00000000-0000-0000-0000-000000000001").
+		ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+			"uuid":              "00000000-0000-0000-0000-000000000001",
+			"amount":            15,
+			"duration":          "1 year",
+			"project_id":        "uuid-for-berlin",
+			"service_type":      "first",
+			"resource_name":     "capacity",
+			"availability_zone": "az-one",
+			"status":            "confirmed",
+			"created_at":        s.Clock.Now().Unix(),
+			"creator_uuid":      "dummy",
+			"creator_name":      "dummy",
+			"can_be_deleted":    true,
+			"confirmed_at":      s.Clock.Now().Unix(),
+			"expires_at":        s.Clock.Now().Add(oneYear).Unix(),
+			"updated_at":        s.Clock.Now().Unix(),
+		})
+
+	// can_be_deleted depends on the user's permissions
+	s.TokenValidator.Enforcer.AllowUncommit = false
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/00000000-0000-0000-0000-000000000001").
+		ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+			"uuid":              "00000000-0000-0000-0000-000000000001",
+			"amount":            15,
+			"duration":          "1 year",
+			"project_id":        "uuid-for-berlin",
+			"service_type":      "first",
+			"resource_name":     "capacity",
+			"availability_zone": "az-one",
+			"status":            "confirmed",
+			"created_at":        s.Clock.Now().Unix(),
+			"creator_uuid":      "dummy",
+			"creator_name":      "dummy",
+			"confirmed_at":      s.Clock.Now().Unix(),
+			"expires_at":        s.Clock.Now().Add(oneYear).Unix(),
+			"updated_at":        s.Clock.Now().Unix(),
+		})
+	s.TokenValidator.Enforcer.AllowUncommit = true
+
+	// access to the commitment requires access to its project
+	s.TokenValidator.Enforcer.AllowReportSingle = false
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/00000000-0000-0000-0000-000000000001").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/does-not-exist").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowReportSingle = true
+
+	// deleted commitments cannot be shown
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/does-not-exist").
+		ExpectText(t, http.StatusNotFound, "no such commitment\n")
+	s.MustDBExec(`UPDATE project_commitments SET status = 'deleted', deleted_at = $1 WHERE uuid = $2`,
+		s.Clock.Now(), "00000000-0000-0000-0000-000000000001")
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/00000000-0000-0000-0000-000000000001").
+		ExpectText(t, http.StatusNotFound, "no such commitment\n")
+}
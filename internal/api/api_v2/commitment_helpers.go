@@ -4,6 +4,7 @@
 package api_v2
 
 import (
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -14,6 +15,7 @@ import (
 	"github.com/sapcc/go-api-declarations/limes"
 	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
 	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/gopherpolicy"
 	"github.com/sapcc/go-bits/must"
 	"github.com/sapcc/go-bits/respondwith"
 	"github.com/sapcc/go-bits/sqlext"
@@ -21,26 +23,42 @@ import (
 	. "go.xyrillian.de/gg/option"
 	"go.xyrillian.de/gg/options"
 
-	resourcesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/resources"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
 	"github.com/sapcc/limes/internal/core"
+	"github.com/sapcc/limes/internal/datamodel"
 	"github.com/sapcc/limes/internal/db"
 	"github.com/sapcc/limes/internal/util"
 )
 
 var (
+	errAmendRenewed              = errors.New("commitments that have already been renewed may not be amended")
+	errAmendUnconfirmed          = errors.New("only confirmed commitments may be amended")
+	errAmountExceedsCommitment   = errors.New("amount may not exceed the amount of the commitment")
 	errAZMustNotBeAny            = errors.New(`resource is AZ-aware, so the AZ may not be set to "any"`)
 	errAZMustBeAny               = errors.New(`resource does not accept AZ-aware commitments, so the AZ must be set to "any"`)
+	errCommitmentNotDeletable    = errors.New("commitment can only be deleted within the deletion grace period after its creation")
 	errCommitmentsDisabled       = errors.New("commitments are not enabled for this resource")
 	errConfirmByInPast           = errors.New("confirm_by may not be set in the past")
 	errConfirmByMissing          = errors.New("confirm_by must be set for the requested initial commitment status")
 	errConfirmByNotAllowed       = errors.New("confirm_by may not be set for the requested initial commitment status")
+	errConversionToSameResource  = errors.New("commitment cannot be converted into its own resource")
+	errDryRunInBatch             = errors.New("dry_run may only be set for the whole batch, not for individual commitments")
+	errEmptyBatch                = errors.New("at least one commitment must be given")
 	errEmptyAmount               = errors.New("amount of committed resource must be greater than zero")
 	errInvalidInitialStatus      = errors.New("initial commitment status value is invalid")
+	errMergeAcrossResources      = errors.New("all commitments must be in the same project, resource and AZ")
+	errMergeDuplicates           = errors.New("commitments cannot be merged with themselves")
+	errMergeUnconfirmed          = errors.New("only confirmed commitments may be merged")
+	errNoSuchCommitment          = errors.New("no such commitment")
 	errNoSuchAZ                  = errors.New("no such availability zone")
 	errNoSuchResource            = errors.New("no such resource")
 	errNoSuchService             = errors.New("no such service")
 	errNotifyOnConfirmNotAllowed = errors.New("notify_on_confirm may not be set for commitments with immediate confirmation")
 	errResourceForbidden         = errors.New("resource is not enabled in this project")
+	errSplitAmountTooLarge       = errors.New("amount must be smaller than the amount of the commitment")
+	errTransferIntoSameProject   = errors.New("commitment is already located in the target project")
+	errTransferStatusUnchanged   = errors.New("transfer_status is already set to the requested value")
+	errTransferTokenMissing      = errors.New("missing Transfer-Token header")
 )
 
 func convertCommitmentToDisplayForm(c db.ProjectCommitment, path db.AZResourcePath, project db.Project, canBeDeleted bool) resourcesv2.Commitment {
@@ -68,6 +86,127 @@ func convertCommitmentToDisplayForm(c db.ProjectCommitment, path db.AZResourcePa
 	}
 }
 
+var findCommitmentByUUIDQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+	SELECT pc.*
+	  FROM project_commitments pc
+	 WHERE pc.uuid = $1 AND pc.status != {{util.CommitmentStatusDeleted}}
+`))
+
+// checkCommitmentAccess loads the commitment with the given UUID and checks access to its project using the given policy rule.
+// Soft-deleted commitments are treated as nonexistent.
+// On success, returns the commitment, its AZ resource path and the database records for its project and domain.
+func (p *v2Provider) checkCommitmentAccess(t *gopherpolicy.Token, uuid liquid.CommitmentUUID, policyRule string) (_ db.ProjectCommitment, _ db.AZResourcePath, _ db.Domain, _ db.Project, err error) {
+	// NOTE: Like checkProjectAccess, this obfuscates "commitment not found"
+	// errors to users without successful authorization.
+	var c db.ProjectCommitment
+	err = p.DB.SelectOne(&c, findCommitmentByUUIDQuery, uuid)
+	switch {
+	case err == nil:
+		// continue below
+	case errors.Is(err, sql.ErrNoRows):
+		t.Context.Request = map[string]string{
+			"domain_uuid":  "unknown",
+			"project_uuid": "unknown",
+		}
+		err = t.Enforce(policyRule)
+		if err == nil {
+			err = respondwith.CustomStatus(http.StatusNotFound, errNoSuchCommitment)
+		}
+		return
+	default:
+		return
+	}
+
+	var projectUUID liquid.ProjectUUID
+	err = p.DB.QueryRow(`SELECT uuid FROM projects WHERE id = $1`, c.ProjectID).Scan(&projectUUID)
+	if err != nil {
+		return
+	}
+	dbDomain, dbProject, err := p.checkProjectAccess(t, projectUUID, policyRule)
+	if err != nil {
+		return
+	}
+
+	var path db.AZResourcePath
+	err = p.DB.QueryRow(`SELECT path FROM az_resources WHERE id = $1`, c.AZResourceID).Scan(&path)
+	if err != nil {
+		return
+	}
+	return c, path, dbDomain, dbProject, nil
+}
+
+// getAZResourcePaths returns the paths of all AZ resources known to the ServiceInfoCache, indexed by ID.
+func (p *v2Provider) getAZResourcePaths() map[db.AZResourceID]db.AZResourcePath {
+	result := make(map[db.AZResourceID]db.AZResourcePath)
+	for serviceType, azResourcesByName := range p.Cluster.SIC.GetSnapshot().GetAZResources() {
+		for resourceName, azResourcesByAZ := range azResourcesByName {
+			for az, azResource := range azResourcesByAZ {
+				result[azResource.ID] = db.AZResourcePath{ServiceType: serviceType, ResourceName: resourceName, AvailabilityZone: az}
+			}
+		}
+	}
+	return result
+}
+
+// canDeleteCommitment wraps datamodel.CanDeleteCommitment.
+// The policy rules checked therein are shared with the v1 API, so the policy context must be filled with the v1 API's key names.
+func (p *v2Provider) canDeleteCommitment(t *gopherpolicy.Token, c db.ProjectCommitment, dbDomain db.Domain, dbProject db.Project) bool {
+	t.Context.Request = map[string]string{
+		"domain_uuid":  dbDomain.UUID,
+		"project_uuid": string(dbProject.UUID),
+		"domain_id":    dbDomain.UUID,
+		"project_id":   string(dbProject.UUID),
+	}
+	return datamodel.CanDeleteCommitment(t, c, p.timeNow)
+}
+
+// checkCommitmentIsReplaceable checks that the given commitment can be superseded by new commitments
+// as part of an operation like "split" or "merge". The operation name is used in error messages.
+func checkCommitmentIsReplaceable(c db.ProjectCommitment, operation string) error {
+	// commitments that have already ended their lifecycle cannot be changed anymore
+	if slices.Contains([]liquid.CommitmentStatus{liquid.CommitmentStatusExpired, liquid.CommitmentStatusSuperseded}, c.Status) {
+		err := fmt.Errorf("cannot %s a commitment in status %q", operation, c.Status)
+		return respondwith.CustomStatus(http.StatusUnprocessableEntity, err)
+	}
+	// commitments in transfer must not change under the feet of the receiving side
+	if c.TransferStatus != limesresources.CommitmentTransferStatusNone {
+		err := fmt.Errorf("cannot %s a commitment that is marked for transfer", operation)
+		return respondwith.CustomStatus(http.StatusUnprocessableEntity, err)
+	}
+	return nil
+}
+
+// supersedeCommitment moves the given commitment into status "superseded",
+// recording the commitments that replace it in its SupersedeContextJSON.
+func supersedeCommitment(tx db.Interface, c *db.ProjectCommitment, reason db.CommitmentReason, replacements []db.ProjectCommitment, now time.Time) error {
+	supersedeContext := db.CommitmentWorkflowContext{Reason: reason}
+	for _, r := range replacements {
+		supersedeContext.RelatedCommitmentIDs = append(supersedeContext.RelatedCommitmentIDs, r.ID)
+		supersedeContext.RelatedCommitmentUUIDs = append(supersedeContext.RelatedCommitmentUUIDs, r.UUID)
+	}
+	buf, err := json.Marshal(supersedeContext)
+	if err != nil {
+		return err
+	}
+	c.Status = liquid.CommitmentStatusSuperseded
+	c.SupersededAt = Some(now)
+	c.SupersedeContextJSON = Some(json.RawMessage(buf))
+	c.UpdatedAt = now
+	_, err = tx.Update(c)
+	return err
+}
+
+// convertReplacementCommitmentToDisplayForm is like convertCommitmentToDisplayForm,
+// but for commitments that were created by an operation that supports dry runs.
+func (p *v2Provider) convertReplacementCommitmentToDisplayForm(t *gopherpolicy.Token, c db.ProjectCommitment, path db.AZResourcePath, dbDomain db.Domain, dbProject db.Project, dryRun bool) resourcesv2.Commitment {
+	canBeDeleted := p.canDeleteCommitment(t, c, dbDomain, dbProject)
+	result := convertCommitmentToDisplayForm(c, path, dbProject, canBeDeleted)
+	if dryRun {
+		result.UUID = "00000000-0000-0000-0000-000000000000"
+	}
+	return result
+}
+
 // validateCommittability checks that the AZ resource identified by `path`:
 //   - exists in the given project scope, and
 //   - allows commitments of the specified duration.
@@ -147,7 +286,7 @@ type commitmentStatusAttributes struct {
 // NotifyOnConfirm fields of a CommitmentRequest.
 func (p *v2Provider) validateStatusAttributesOnNewCommitment(attrs commitmentStatusAttributes, behavior core.ScopedCommitmentBehavior, now time.Time) error {
 	switch attrs.Status {
-	case liquid.CommitmentStatusPlanned: // TODO: add "guaranteed" here when adding support for it
+	case liquid.CommitmentStatusPlanned, liquid.CommitmentStatusGuaranteed:
 		if attrs.ConfirmBy.IsNone() {
 			return respondwith.CustomStatus(http.StatusUnprocessableEntity, errConfirmByMissing)
 		}
@@ -194,14 +333,15 @@ func getCommitmentStats(dbi db.Interface, projectID db.ProjectID, azResourceID d
 }
 
 // analyzeCommitmentChangeResponse converts a CommitmentChangeResponse into an API error unless the response is positive.
-func analyzeCommitmentChangeResponse(resp liquid.CommitmentChangeResponse) error {
+// A RetryAt in the past (e.g. when the next capacity scrape is overdue) is not reported to the client.
+func analyzeCommitmentChangeResponse(resp liquid.CommitmentChangeResponse, now time.Time) error {
 	if resp.RejectionReason == "" {
 		return nil
 	}
 	err := errors.New(resp.RejectionReason)
-	if retryAt, exists := resp.RetryAt.Unpack(); exists {
+	if retryAt, exists := resp.RetryAt.Unpack(); exists && retryAt.After(now) {
 		return respondwith.CustomStatus(http.StatusConflict, err,
-			respondwith.CustomHeader("Retry-After", retryAt.Format(time.RFC1123)),
+			respondwith.CustomHeader("Retry-After", retryAt.UTC().Format(http.TimeFormat)),
 		)
 	} else {
 		return respondwith.CustomStatus(http.StatusConflict, err)
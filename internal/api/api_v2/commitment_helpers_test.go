@@ -0,0 +1,103 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/sapcc/go-bits/httptest"
+	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/jsonmatch"
+
+	"github.com/sapcc/limes/internal/test"
+	"github.com/sapcc/limes/internal/test/common_fixtures"
+)
+
+// This config is shared by tests for operations that move a commitment between resources or change its duration.
+// "bigcapacity" is like "capacity", but one unit of it is worth two units of "capacity",
+// and amending a commitment on it restarts the commitment duration.
+var commitmentBigCapacityConfigJSON = string(must.Return(httptest.NewJQModifiableJSONString(test.RemoveCommentsFromJSON(`
+	{
+		"liquids": {
+			"first": {
+				"area": "first",
+				"commitment_behavior_per_resource": [
+					{
+						"key": "capacity",
+						"value": {
+							"durations_per_domain": [{"key": ".*", "value": ["1 hour"]}],
+							"conversion_rule": {"identifier": "flavor", "weight": 2}
+						}
+					},
+					{
+						"key": "bigcapacity",
+						"value": {
+							"durations_per_domain": [{"key": ".*", "value": ["1 hour"]}],
+							"conversion_rule": {"identifier": "flavor", "weight": 4},
+							"restart_expiry_on_amend": true
+						}
+					}
+				]
+			},
+			"second": {
+				"area": "second",
+				"commitment_behavior_per_resource": []
+			}
+		}
+	}`), "commitmentBigCapacityConfigJSON").
+	ModifyWithVariable(".discovery = $ref", common_fixtures.DiscoveryBerlinDresdenParis).
+	ModifyWithVariable(".areas = $ref", common_fixtures.AreasFirstSecond).
+	ModifyWithVariable(".availability_zones = $ref", common_fixtures.AZsOneTwo).
+	MarshalJSON()))
+
+// setupBigCapacityTest prepares a test with commitmentBigCapacityConfigJSON.
+// There is enough capacity for commitments on "bigcapacity" in az-one.
+func setupBigCapacityTest(t *testing.T) test.Setup {
+	srvInfo := test.DefaultLiquidServiceInfo("First")
+	srvInfo.Resources["bigcapacity"] = srvInfo.Resources["capacity"]
+	s := test.NewSetup(t,
+		test.WithConfig(commitmentBigCapacityConfigJSON),
+		test.WithPersistedServiceInfo("first", srvInfo),
+		test.WithPersistedServiceInfo("second", test.DefaultLiquidServiceInfo("Second")),
+		test.WithInitialDiscovery,
+		test.WithEmptyResourceRecordsAsNeeded,
+	)
+	s.MustDBExec(`UPDATE az_resources SET raw_capacity = 100 WHERE id = $1`, s.GetAZResourceID("first", "bigcapacity", "az-one"))
+	return s
+}
+
+// createdByAlice can be given to expectedCommitmentJSON for commitments that were created through the API by the test user.
+var createdByAlice = jsonmatch.Object{
+	"creator_uuid": "uuid-for-alice",
+	"creator_name": "alice@Default",
+}
+
+// expectedCommitmentJSON builds the full JSON representation of a confirmed commitment on first/capacity in az-one of project berlin,
+// which was created and confirmed by the "dummy" user at createdAt and has not been updated since.
+// Fields that differ from these defaults can be given in overrides.
+func expectedCommitmentJSON(uuid any, amount uint64, duration string, createdAt, expiresAt time.Time, overrides ...jsonmatch.Object) jsonmatch.Object {
+	result := jsonmatch.Object{
+		"uuid":              uuid,
+		"amount":            amount,
+		"duration":          duration,
+		"project_id":        "uuid-for-berlin",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "confirmed",
+		"created_at":        createdAt.Unix(),
+		"creator_uuid":      "dummy",
+		"creator_name":      "dummy",
+		"can_be_deleted":    true,
+		"confirmed_at":      createdAt.Unix(),
+		"expires_at":        expiresAt.Unix(),
+		"updated_at":        createdAt.Unix(),
+	}
+	for _, o := range overrides {
+		maps.Copy(result, o)
+	}
+	return result
+}
@@ -0,0 +1,313 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/gorilla/mux"
+	"github.com/lib/pq"
+	"github.com/sapcc/go-api-declarations/limes"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/sqlext"
+	. "go.xyrillian.de/gg/option"
+
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+	"github.com/sapcc/limes/internal/db"
+)
+
+// handleGetCommitmentLineage handles GET /resources/v2/commitments/:uuid/lineage.
+func (p *v2Provider) handleGetCommitmentLineage(r *http.Request, token *gopherpolicy.Token) (resourcesv2.CommitmentLineageResponse, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/commitments/:uuid/lineage")
+	none := resourcesv2.CommitmentLineageResponse{}
+
+	uuid := liquid.CommitmentUUID(mux.Vars(r)["uuid"])
+	c, path, dbDomain, dbProject, err := p.checkCommitmentAccess(token, uuid, "v2:project:report_single")
+	if err != nil {
+		return none, err
+	}
+
+	w := commitmentLineageWalker{
+		p:               p,
+		token:           token,
+		azResourcePaths: p.getAZResourcePaths(),
+		commitments:     map[liquid.CommitmentUUID]db.ProjectCommitment{c.UUID: c},
+		projects:        map[db.ProjectID]Option[commitmentLineageProject]{c.ProjectID: Some(commitmentLineageProject{dbDomain, dbProject})},
+		relations:       make(map[commitmentRelationKey]db.CommitmentReason),
+	}
+	ancestors, err := w.walk(c, true)
+	if err != nil {
+		return none, err
+	}
+	descendants, err := w.walk(c, false)
+	if err != nil {
+		return none, err
+	}
+
+	canBeDeleted := p.canDeleteCommitment(token, c, dbDomain, dbProject)
+	result := resourcesv2.CommitmentLineageResponse{
+		Commitment:  convertCommitmentToDisplayForm(c, path, dbProject, canBeDeleted),
+		Ancestors:   w.convertToDisplayForm(ancestors),
+		Descendants: w.convertToDisplayForm(descendants),
+		Relations:   make([]resourcesv2.CommitmentRelation, 0, len(w.relations)),
+	}
+	for key, reason := range w.relations {
+		parent := w.commitments[key.Parent]
+		child := w.commitments[key.Child]
+		createdAt := parent.SupersededAt.UnwrapOr(child.CreatedAt)
+		if reason == db.CommitmentReasonRenew {
+			createdAt = child.CreatedAt
+		}
+		result.Relations = append(result.Relations, resourcesv2.CommitmentRelation{
+			ParentUUID: key.Parent,
+			ChildUUID:  key.Child,
+			Reason:     resourcesv2.CommitmentRelationReason(reason),
+			CreatedAt:  limes.UnixEncodedTime{Time: createdAt},
+		})
+	}
+	slices.SortFunc(result.Relations, func(lhs, rhs resourcesv2.CommitmentRelation) int {
+		return cmp.Or(
+			lhs.CreatedAt.Compare(rhs.CreatedAt.Time),
+			cmp.Compare(lhs.ParentUUID, rhs.ParentUUID),
+			cmp.Compare(lhs.ChildUUID, rhs.ChildUUID),
+		)
+	})
+	return result, nil
+}
+
+// Finds all commitments that could be directly related to any of the commitments in $1:
+// those listed in $2 (which comes from the workflow contexts of the commitments in $1),
+// and those whose own workflow contexts refer to any of the commitments in $1.
+// The project and domain of each commitment are returned alongside, for the visibility check.
+var findRelatedCommitmentsQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+	SELECT pc.*, p.uuid AS project_uuid, p.name AS project_name, p.parent_uuid AS project_parent_uuid,
+	       d.id AS domain_id, d.uuid AS domain_uuid, d.name AS domain_name
+	  FROM project_commitments pc
+	  JOIN projects p ON p.id = pc.project_id
+	  JOIN domains d ON d.id = p.domain_id
+	 WHERE pc.status != {{util.CommitmentStatusDeleted}}
+	   AND (pc.uuid = ANY($2)
+	     OR jsonb_exists_any(pc.creation_context_json->'related_uuids', $1)
+	     OR jsonb_exists_any(pc.supersede_context_json->'related_uuids', $1)
+	     OR jsonb_exists_any(pc.renew_context_json->'related_uuids', $1))
+	 ORDER BY pc.id
+`))
+
+// commitmentLineageCandidate is a result row of findRelatedCommitmentsQuery.
+type commitmentLineageCandidate struct {
+	db.ProjectCommitment
+	ProjectUUID       liquid.ProjectUUID `db:"project_uuid"`
+	ProjectName       string             `db:"project_name"`
+	ProjectParentUUID string             `db:"project_parent_uuid"`
+	DomainID          db.DomainID        `db:"domain_id"`
+	DomainUUID        string             `db:"domain_uuid"`
+	DomainName        string             `db:"domain_name"`
+}
+
+// commitmentLineageWalker holds the state for traversing the graph of commitments
+// that is described by the workflow contexts of all commitments.
+type commitmentLineageWalker struct {
+	p               *v2Provider
+	token           *gopherpolicy.Token
+	azResourcePaths map[db.AZResourceID]db.AZResourcePath
+	// all commitments that were visited so far, including the one where the walk started
+	commitments map[liquid.CommitmentUUID]db.ProjectCommitment
+	// None for projects that the user is not allowed to see
+	projects  map[db.ProjectID]Option[commitmentLineageProject]
+	relations map[commitmentRelationKey]db.CommitmentReason
+}
+
+type commitmentLineageProject struct {
+	Domain  db.Domain
+	Project db.Project
+}
+
+type commitmentRelationKey struct {
+	Parent liquid.CommitmentUUID
+	Child  liquid.CommitmentUUID
+}
+
+// walk returns all ancestors (if `upwards`) or all descendants (otherwise) of the given commitment, sorted by ID.
+// The graph is traversed breadth-first, with one query per generation of commitments.
+func (w *commitmentLineageWalker) walk(start db.ProjectCommitment, upwards bool) ([]db.ProjectCommitment, error) {
+	var result []db.ProjectCommitment
+	isVisited := map[liquid.CommitmentUUID]bool{start.UUID: true}
+	frontier := []db.ProjectCommitment{start}
+	for len(frontier) > 0 {
+		neighbors, err := w.findNeighbors(frontier, upwards)
+		if err != nil {
+			return nil, err
+		}
+		frontier = nil
+		for _, n := range neighbors {
+			if isVisited[n.UUID] {
+				continue
+			}
+			isVisited[n.UUID] = true
+			w.commitments[n.UUID] = n
+			result = append(result, n)
+			frontier = append(frontier, n)
+		}
+	}
+
+	slices.SortFunc(result, func(lhs, rhs db.ProjectCommitment) int {
+		return cmp.Compare(lhs.ID, rhs.ID)
+	})
+	return result, nil
+}
+
+// findNeighbors returns the visible parents (if `upwards`) or children (otherwise) of the given commitments,
+// and records the respective relations.
+func (w *commitmentLineageWalker) findNeighbors(frontier []db.ProjectCommitment, upwards bool) ([]db.ProjectCommitment, error) {
+	var (
+		frontierUUIDs pq.StringArray
+		relatedUUIDs  pq.StringArray
+	)
+	isInFrontier := make(map[liquid.CommitmentUUID]bool, len(frontier))
+	frontierRelations := make(map[commitmentRelationKey]db.CommitmentReason)
+	for _, c := range frontier {
+		frontierUUIDs = append(frontierUUIDs, string(c.UUID))
+		isInFrontier[c.UUID] = true
+
+		ownRelations, err := parseCommitmentRelations(c)
+		if err != nil {
+			return nil, err
+		}
+		for key, reason := range ownRelations {
+			if upwards && key.Child == c.UUID {
+				relatedUUIDs = append(relatedUUIDs, string(key.Parent))
+				frontierRelations[key] = reason
+			}
+			if !upwards && key.Parent == c.UUID {
+				relatedUUIDs = append(relatedUUIDs, string(key.Child))
+				frontierRelations[key] = reason
+			}
+		}
+	}
+
+	var candidates []commitmentLineageCandidate
+	_, err := w.p.DB.Select(&candidates, findRelatedCommitmentsQuery, frontierUUIDs, relatedUUIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []db.ProjectCommitment
+	for _, candidate := range candidates {
+		candidateRelations, err := parseCommitmentRelations(candidate.ProjectCommitment)
+		if err != nil {
+			return nil, err
+		}
+
+		// the relation may be recorded on either side (e.g. "consume" is only recorded in the supersede context of the parent)
+		relations := make(map[commitmentRelationKey]db.CommitmentReason)
+		for key, reason := range candidateRelations {
+			if upwards && key.Parent == candidate.UUID && isInFrontier[key.Child] {
+				relations[key] = reason
+			}
+			if !upwards && key.Child == candidate.UUID && isInFrontier[key.Parent] {
+				relations[key] = reason
+			}
+		}
+		for key, reason := range frontierRelations {
+			if (upwards && key.Parent == candidate.UUID) || (!upwards && key.Child == candidate.UUID) {
+				relations[key] = reason
+			}
+		}
+		if len(relations) == 0 || !w.isVisible(candidate) {
+			continue
+		}
+		for key, reason := range relations {
+			if _, exists := w.relations[key]; !exists {
+				w.relations[key] = reason
+			}
+		}
+		result = append(result, candidate.ProjectCommitment)
+	}
+	return result, nil
+}
+
+// isVisible checks whether the user is allowed to see the given commitment.
+// Commitments on resources that are not known to the ServiceInfoCache are treated as invisible.
+func (w *commitmentLineageWalker) isVisible(c commitmentLineageCandidate) bool {
+	if _, exists := w.azResourcePaths[c.AZResourceID]; !exists {
+		return false
+	}
+
+	project, exists := w.projects[c.ProjectID]
+	if !exists {
+		info := commitmentLineageProject{
+			Domain: db.Domain{
+				ID:   c.DomainID,
+				Name: c.DomainName,
+				UUID: c.DomainUUID,
+			},
+			Project: db.Project{
+				ID:         c.ProjectID,
+				DomainID:   c.DomainID,
+				Name:       c.ProjectName,
+				UUID:       c.ProjectUUID,
+				ParentUUID: c.ProjectParentUUID,
+			},
+		}
+
+		w.token.Context.Request = map[string]string{
+			"domain_uuid":  info.Domain.UUID,
+			"project_uuid": string(info.Project.UUID),
+		}
+		if w.token.Check("v2:project:report_single") {
+			project = Some(info)
+		} else {
+			project = None[commitmentLineageProject]()
+		}
+		w.projects[c.ProjectID] = project
+	}
+	return project.IsSome()
+}
+
+// convertToDisplayForm converts commitments that have been deemed visible by isVisible().
+func (w *commitmentLineageWalker) convertToDisplayForm(commitments []db.ProjectCommitment) []resourcesv2.Commitment {
+	result := make([]resourcesv2.Commitment, 0, len(commitments))
+	for _, c := range commitments {
+		info := w.projects[c.ProjectID].UnwrapOrPanic("convertToDisplayForm called for invisible commitment")
+		canBeDeleted := w.p.canDeleteCommitment(w.token, c, info.Domain, info.Project)
+		result = append(result, convertCommitmentToDisplayForm(c, w.azResourcePaths[c.AZResourceID], info.Project, canBeDeleted))
+	}
+	return result
+}
+
+// parseCommitmentRelations returns all relations that are recorded in the workflow contexts of the given commitment.
+func parseCommitmentRelations(c db.ProjectCommitment) (map[commitmentRelationKey]db.CommitmentReason, error) {
+	result := make(map[commitmentRelationKey]db.CommitmentReason)
+
+	var creationContext db.CommitmentWorkflowContext
+	err := json.Unmarshal(c.CreationContextJSON, &creationContext)
+	if err != nil {
+		return nil, fmt.Errorf("while parsing creation context of commitment %s: %w", c.UUID, err)
+	}
+	for _, parentUUID := range creationContext.RelatedCommitmentUUIDs {
+		result[commitmentRelationKey{Parent: parentUUID, Child: c.UUID}] = creationContext.Reason
+	}
+
+	for contextName, buf := range map[string]Option[json.RawMessage]{"supersede": c.SupersedeContextJSON, "renew": c.RenewContextJSON} {
+		buf, exists := buf.Unpack()
+		if !exists {
+			continue
+		}
+		var wc db.CommitmentWorkflowContext
+		err := json.Unmarshal(buf, &wc)
+		if err != nil {
+			return nil, fmt.Errorf("while parsing %s context of commitment %s: %w", contextName, c.UUID, err)
+		}
+		for _, childUUID := range wc.RelatedCommitmentUUIDs {
+			result[commitmentRelationKey{Parent: c.UUID, Child: childUUID}] = wc.Reason
+		}
+	}
+	return result, nil
+}
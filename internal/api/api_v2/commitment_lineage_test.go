@@ -0,0 +1,175 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/jsonmatch"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/internal/db"
+)
+
+func TestV2CommitmentLineage(t *testing.T) {
+	s := setupResourceReportTest(t)
+	committedForOneYear := must.Return(limesresources.ParseCommitmentDuration("1 year"))
+	const oneYear = 365 * 24 * time.Hour
+	now := s.Clock.Now()
+
+	// build the following lineage:
+	//   - commitment 16 in dresden was consumed when commitment 01 in berlin was confirmed,
+	//   - commitment 01 was split into commitments 11 and 12,
+	//   - commitments 11 and 12 were merged into commitment 13,
+	//   - commitment 13 was renewed into commitment 14.
+	s.MustDBExec(`UPDATE project_commitments SET status = $1, superseded_at = $2, supersede_context_json = $3 WHERE uuid = $4`,
+		liquid.CommitmentStatusSuperseded, now,
+		`{"reason":"split","related_uuids":["00000000-0000-0000-0000-000000000011","00000000-0000-0000-0000-000000000012"]}`,
+		"00000000-0000-0000-0000-000000000001")
+	insertCommitment := func(uuid liquid.CommitmentUUID, projectName string, amount uint64, status liquid.CommitmentStatus, creationContext string, modify func(*db.ProjectCommitment)) {
+		c := db.ProjectCommitment{
+			UUID:                uuid,
+			ProjectID:           s.GetProjectID(projectName),
+			AZResourceID:        s.GetAZResourceID("first", "capacity", "az-one"),
+			Amount:              amount,
+			Duration:            committedForOneYear,
+			CreatedAt:           now,
+			CreatorUUID:         "dummy",
+			CreatorName:         "dummy",
+			ConfirmedAt:         Some(now),
+			ExpiresAt:           committedForOneYear.AddTo(now),
+			CreationContextJSON: json.RawMessage(creationContext),
+			Status:              status,
+		}
+		if status == liquid.CommitmentStatusSuperseded {
+			c.SupersededAt = Some(now)
+		}
+		if modify != nil {
+			modify(&c)
+		}
+		s.MustDBInsert(&c)
+	}
+	insertCommitment("00000000-0000-0000-0000-000000000011", "berlin", 10, liquid.CommitmentStatusSuperseded,
+		`{"reason":"split","related_uuids":["00000000-0000-0000-0000-000000000001"]}`,
+		func(c *db.ProjectCommitment) {
+			c.SupersedeContextJSON = Some(json.RawMessage(`{"reason":"merge","related_uuids":["00000000-0000-0000-0000-000000000013"]}`))
+		})
+	insertCommitment("00000000-0000-0000-0000-000000000012", "berlin", 5, liquid.CommitmentStatusSuperseded,
+		`{"reason":"split","related_uuids":["00000000-0000-0000-0000-000000000001"]}`,
+		func(c *db.ProjectCommitment) {
+			c.SupersedeContextJSON = Some(json.RawMessage(`{"reason":"merge","related_uuids":["00000000-0000-0000-0000-000000000013"]}`))
+		})
+	insertCommitment("00000000-0000-0000-0000-000000000013", "berlin", 15, liquid.CommitmentStatusConfirmed,
+		`{"reason":"merge","related_uuids":["00000000-0000-0000-0000-000000000011","00000000-0000-0000-0000-000000000012"]}`,
+		func(c *db.ProjectCommitment) {
+			c.RenewContextJSON = Some(json.RawMessage(`{"reason":"renew","related_uuids":["00000000-0000-0000-0000-000000000014"]}`))
+		})
+	s.Clock.StepBy(time.Hour)
+	insertCommitment("00000000-0000-0000-0000-000000000014", "berlin", 15, liquid.CommitmentStatusPlanned,
+		`{"reason":"renew","related_uuids":["00000000-0000-0000-0000-000000000013"]}`,
+		func(c *db.ProjectCommitment) {
+			c.CreatedAt = s.Clock.Now()
+			c.ConfirmedAt = None[time.Time]()
+			c.ConfirmBy = Some(now.Add(oneYear))
+			c.ExpiresAt = committedForOneYear.AddTo(now.Add(oneYear))
+		})
+	// the "consume" relation is only recorded on the consumed commitment
+	insertCommitment("00000000-0000-0000-0000-000000000016", "dresden", 15, liquid.CommitmentStatusSuperseded,
+		`{"reason":"create"}`,
+		func(c *db.ProjectCommitment) {
+			c.SupersedeContextJSON = Some(json.RawMessage(`{"reason":"consume","related_uuids":["00000000-0000-0000-0000-000000000001"]}`))
+		})
+	s.MustDBExec(`UPDATE project_commitments SET updated_at = created_at`)
+
+	// for simplicity, make "can_be_deleted" false everywhere
+	s.TokenValidator.Enforcer.AllowUncommit = false
+
+	superseded := jsonmatch.Object{"status": "superseded"}
+	expected01 := expectedCommitmentJSON("00000000-0000-0000-0000-000000000001", 15, "1 year", now, now.Add(oneYear), superseded)
+	expected11 := expectedCommitmentJSON("00000000-0000-0000-0000-000000000011", 10, "1 year", now, now.Add(oneYear), superseded)
+	expected12 := expectedCommitmentJSON("00000000-0000-0000-0000-000000000012", 5, "1 year", now, now.Add(oneYear), superseded)
+	expected13 := expectedCommitmentJSON("00000000-0000-0000-0000-000000000013", 15, "1 year", now, now.Add(oneYear))
+	expected13["was_renewed"] = true
+	expected14 := expectedCommitmentJSON("00000000-0000-0000-0000-000000000014", 15, "1 year", s.Clock.Now(), now.Add(2*oneYear), jsonmatch.Object{
+		"status":     "planned",
+		"confirm_by": now.Add(oneYear).Unix(),
+	})
+	delete(expected14, "confirmed_at")
+	expected16 := expectedCommitmentJSON("00000000-0000-0000-0000-000000000016", 15, "1 year", now, now.Add(oneYear),
+		superseded, jsonmatch.Object{"project_id": "uuid-for-dresden"})
+	for _, expected := range []jsonmatch.Object{expected01, expected11, expected12, expected13, expected14, expected16} {
+		delete(expected, "can_be_deleted")
+	}
+
+	expectedRelation := func(parentUUID, childUUID, reason string, createdAt time.Time) jsonmatch.Object {
+		return jsonmatch.Object{
+			"parent_id":  parentUUID,
+			"child_id":   childUUID,
+			"reason":     reason,
+			"created_at": createdAt.Unix(),
+		}
+	}
+	relation16to01 := expectedRelation("00000000-0000-0000-0000-000000000016", "00000000-0000-0000-0000-000000000001", "consume", now)
+	relation01to11 := expectedRelation("00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000011", "split", now)
+	relation01to12 := expectedRelation("00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000012", "split", now)
+	relation11to13 := expectedRelation("00000000-0000-0000-0000-000000000011", "00000000-0000-0000-0000-000000000013", "merge", now)
+	relation12to13 := expectedRelation("00000000-0000-0000-0000-000000000012", "00000000-0000-0000-0000-000000000013", "merge", now)
+	relation13to14 := expectedRelation("00000000-0000-0000-0000-000000000013", "00000000-0000-0000-0000-000000000014", "renew", s.Clock.Now())
+
+	// lineage in the middle of the graph: ancestors are followed across projects, siblings are not shown
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/00000000-0000-0000-0000-000000000011/lineage").
+		ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+			"commitment":  expected11,
+			"ancestors":   jsonmatch.Array{expected01, expected16},
+			"descendants": jsonmatch.Array{expected13, expected14},
+			"relations":   jsonmatch.Array{relation01to11, relation11to13, relation16to01, relation13to14},
+		})
+
+	// lineage at the end of the graph
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/00000000-0000-0000-0000-000000000014/lineage").
+		ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+			"commitment":  expected14,
+			"ancestors":   jsonmatch.Array{expected01, expected11, expected12, expected13, expected16},
+			"descendants": jsonmatch.Array{},
+			"relations": jsonmatch.Array{
+				relation01to11, relation01to12, relation11to13, relation12to13, relation16to01, relation13to14,
+			},
+		})
+
+	// commitments in projects that the user cannot see are not shown
+	s.TokenValidator.Enforcer.RejectProjectUUID = "uuid-for-dresden"
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/00000000-0000-0000-0000-000000000001/lineage").
+		ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+			"commitment":  expected01,
+			"ancestors":   jsonmatch.Array{},
+			"descendants": jsonmatch.Array{expected11, expected12, expected13, expected14},
+			"relations": jsonmatch.Array{
+				relation01to11, relation01to12, relation11to13, relation12to13, relation13to14,
+			},
+		})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/00000000-0000-0000-0000-000000000016/lineage").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.RejectProjectUUID = ""
+
+	// deleted commitments are not part of the lineage
+	s.MustDBExec(`UPDATE project_commitments SET status = 'deleted', deleted_at = $1 WHERE uuid = $2`,
+		s.Clock.Now(), "00000000-0000-0000-0000-000000000014")
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/00000000-0000-0000-0000-000000000013/lineage").
+		ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+			"commitment":  expected13,
+			"ancestors":   jsonmatch.Array{expected01, expected11, expected12, expected16},
+			"descendants": jsonmatch.Array{},
+			"relations": jsonmatch.Array{
+				relation01to11, relation01to12, relation11to13, relation12to13, relation16to01,
+			},
+		})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/00000000-0000-0000-0000-000000000014/lineage").
+		ExpectText(t, http.StatusNotFound, "no such commitment\n")
+}
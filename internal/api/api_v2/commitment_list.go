@@ -0,0 +1,168 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/lib/pq"
+	"github.com/sapcc/go-api-declarations/opts"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/respondwith"
+	"github.com/sapcc/go-bits/sqlext"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+	"github.com/sapcc/limes/internal/api/reports_v2"
+	"github.com/sapcc/limes/internal/db"
+)
+
+var listCommitmentsQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+	SELECT pc.*, azr.path
+	FROM project_commitments pc
+	JOIN az_resources azr
+	ON azr.id = pc.az_resource_id
+	JOIN projects p
+	ON p.id = pc.project_id
+	WHERE ($1::TEXT IS NULL OR azr.az = $1)
+	AND (($2::TEXT[] IS NULL AND pc.status NOT IN ({{liquid.CommitmentStatusSuperseded}}, {{liquid.CommitmentStatusExpired}})) OR pc.status = ANY($2))
+	AND ($3::TEXT IS NULL OR pc.transfer_status = $3)
+	AND ($4::TIMESTAMPTZ IS NULL OR pc.expires_at >= $4)
+	AND ($5::TIMESTAMPTZ IS NULL OR pc.expires_at <= $5)
+	AND pc.status != {{util.CommitmentStatusDeleted}}
+	AND {{azr.resource_id = ANY($resource_id)}}
+	AND {{p.domain_id = $domain_id}}
+	AND {{p.id = $project_id}}
+	ORDER BY pc.id
+`))
+
+var listCommitmentProjectsQuery = sqlext.SimplifyWhitespace(`
+	SELECT p.id, p.uuid, d.uuid
+	FROM projects p
+	JOIN domains d
+	ON d.id = p.domain_id
+	WHERE {{d.id = $domain_id}}
+	AND {{p.id = $project_id}}
+`)
+
+// handleGetCommitments handles GET /resources/v2/commitments.
+func (p *v2Provider) handleGetCommitments(r *http.Request, token *gopherpolicy.Token) (resourcesv2.CommitmentListResponse, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/commitments")
+	none := resourcesv2.CommitmentListResponse{}
+
+	options, err := opts.ParseQueryString[common.CommitmentListOpts](r.URL.Query())
+	if err != nil {
+		return none, err
+	}
+	scope, err := p.checkCommitmentListAccess(token, options)
+	if err != nil {
+		return none, err
+	}
+	filter, err := reports_v2.FilterFromResourceOpts(p.Cluster, common.ResourceReportOpts{
+		GenericReportOpts: common.GenericReportOpts{ServiceType: options.ServiceType},
+		ResourceName:      options.ResourceName,
+	})
+	if err != nil {
+		return none, err
+	}
+
+	// collect project metadata within the scope (this also ensures that we only show commitments of known projects)
+	type projectInfo struct {
+		Project db.Project
+		Domain  db.Domain
+	}
+	projects := make(map[db.ProjectID]projectInfo)
+	query, args := scope.ExpandScopeFilters(listCommitmentProjectsQuery)
+	err = sqlext.ForeachRow(p.DB, query, args, func(rows *sql.Rows) error {
+		var info projectInfo
+		err := rows.Scan(&info.Project.ID, &info.Project.UUID, &info.Domain.UUID)
+		projects[info.Project.ID] = info
+		return err
+	})
+	if err != nil {
+		return none, err
+	}
+
+	// list matching commitments
+	var statuses pq.StringArray
+	for _, status := range options.Status {
+		statuses = append(statuses, string(status))
+	}
+	query, args = filter.ExpandServiceFilters(listCommitmentsQuery,
+		options.AvailabilityZone, statuses, options.TransferStatus, options.MinExpiresAt, options.MaxExpiresAt)
+	query, args = scope.ExpandScopeFilters(query, args...)
+	// (the AZ resource path is taken from the join in the query, since the filter only reports on the resource level)
+	var rows []struct {
+		db.ProjectCommitment
+		Path db.AZResourcePath `db:"path"`
+	}
+	_, err = p.DB.Select(&rows, query, args...)
+	if err != nil {
+		return none, err
+	}
+
+	result := resourcesv2.CommitmentListResponse{Commitments: make([]resourcesv2.Commitment, 0, len(rows))}
+	for _, row := range rows {
+		c := row.ProjectCommitment
+		info, exists := projects[c.ProjectID]
+		if !exists {
+			// defense in depth (the DB should not change that much between the queries)
+			continue
+		}
+
+		canBeDeleted := p.canDeleteCommitment(token, c, info.Domain, info.Project)
+		result.Commitments = append(result.Commitments, convertCommitmentToDisplayForm(c, row.Path, info.Project, canBeDeleted))
+	}
+	return result, nil
+}
+
+// checkCommitmentListAccess authorizes a request to GET /resources/v2/commitments
+// and returns the scope in which commitments may be listed.
+//   - With project_uuid, this requires the same access as the report for a single project.
+//   - With domain_uuid or without scope restriction, this requires the same access as the report for multiple projects.
+func (p *v2Provider) checkCommitmentListAccess(token *gopherpolicy.Token, options common.CommitmentListOpts) (reports_v2.Scope, error) {
+	projectUUID, hasProjectUUID := options.ProjectUUID.Unpack()
+	domainUUID, hasDomainUUID := options.DomainUUID.Unpack()
+
+	switch {
+	case hasProjectUUID && hasDomainUUID:
+		return reports_v2.Scope{}, respondwith.CustomStatus(http.StatusBadRequest, errors.New("query domain_uuid cannot be set, when query project_uuid is set"))
+
+	case hasProjectUUID:
+		dbDomain, dbProject, err := p.checkProjectAccess(token, projectUUID, "v2:project:report_single")
+		if err != nil {
+			return reports_v2.Scope{}, err
+		}
+		return reports_v2.Scope{Domain: Some(dbDomain), Project: Some(dbProject)}, nil
+
+	case hasDomainUUID:
+		// NOTE: The authorization happens before the domain lookup, to avoid leaking the existence of domains to unauthorized users.
+		token.Context.Request = map[string]string{"domain_uuid": domainUUID}
+		err := token.Enforce("v2:project:report_multiple")
+		if err != nil {
+			return reports_v2.Scope{}, err
+		}
+		var dbDomain db.Domain
+		err = p.DB.SelectOne(&dbDomain, `SELECT * FROM domains WHERE uuid = $1`, domainUUID)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return reports_v2.Scope{}, respondwith.CustomStatus(http.StatusNotFound, fmt.Errorf("no such domain (UUID = %s)", domainUUID))
+		case err != nil:
+			return reports_v2.Scope{}, err
+		}
+		return reports_v2.Scope{Domain: Some(dbDomain)}, nil
+
+	default:
+		err := token.Enforce("v2:project:report_multiple")
+		if err != nil {
+			return reports_v2.Scope{}, err
+		}
+		return reports_v2.Scope{}, nil
+	}
+}
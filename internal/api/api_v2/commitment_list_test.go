@@ -0,0 +1,147 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/jsonmatch"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/internal/db"
+)
+
+func TestV2CommitmentList(t *testing.T) {
+	s := setupResourceReportTest(t)
+
+	// on top of the shared setup, paris has a superseded commitment for things, and the planned commitment of dresden is up for transfer
+	committedForOneYear := must.Return(limesresources.ParseCommitmentDuration("1 year"))
+	s.MustDBInsert(&db.ProjectCommitment{
+		UUID:                "00000000-0000-0000-0000-000000000003",
+		ProjectID:           s.GetProjectID("paris"),
+		AZResourceID:        s.GetAZResourceID("first", "things", "any"),
+		Amount:              2,
+		Duration:            committedForOneYear,
+		CreatedAt:           s.Clock.Now(),
+		CreatorUUID:         "dummy",
+		CreatorName:         "dummy",
+		ConfirmedAt:         Some(s.Clock.Now()),
+		ExpiresAt:           committedForOneYear.AddTo(s.Clock.Now()),
+		SupersededAt:        Some(s.Clock.Now()),
+		CreationContextJSON: json.RawMessage(`{}`),
+		Status:              liquid.CommitmentStatusSuperseded,
+	})
+	s.MustDBExec(`UPDATE project_commitments SET transfer_status = 'public', transfer_token = 'dummy-token', transfer_started_at = $1 WHERE uuid = $2`,
+		s.Clock.Now(), "00000000-0000-0000-0000-000000000002")
+	s.MustDBExec(`UPDATE project_commitments SET updated_at = created_at`)
+
+	const oneYear = 365 * 24 * time.Hour
+	commitmentBerlin := jsonmatch.Object{
+		"uuid":              "00000000-0000-0000-0000-000000000001",
+		"amount":            15,
+		"duration":          "1 year",
+		"project_id":        "uuid-for-berlin",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "confirmed",
+		"created_at":        s.Clock.Now().Unix(),
+		"creator_uuid":      "dummy",
+		"creator_name":      "dummy",
+		"can_be_deleted":    true,
+		"confirmed_at":      s.Clock.Now().Unix(),
+		"expires_at":        s.Clock.Now().Add(oneYear).Unix(),
+		"updated_at":        s.Clock.Now().Unix(),
+	}
+	commitmentDresden := jsonmatch.Object{
+		"uuid":              "00000000-0000-0000-0000-000000000002",
+		"amount":            5,
+		"duration":          "1 year",
+		"project_id":        "uuid-for-dresden",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-two",
+		"status":            "planned",
+		"transfer_status":   "public",
+		"transfer_token":    "dummy-token",
+		"created_at":        s.Clock.Now().Unix(),
+		"creator_uuid":      "dummy",
+		"creator_name":      "dummy",
+		"can_be_deleted":    true,
+		"confirm_by":        s.Clock.Now().Add(24 * time.Hour).Unix(),
+		"expires_at":        s.Clock.Now().Add(24*time.Hour + oneYear).Unix(),
+		"updated_at":        s.Clock.Now().Unix(),
+	}
+	commitmentParis := jsonmatch.Object{
+		"uuid":              "00000000-0000-0000-0000-000000000003",
+		"amount":            2,
+		"duration":          "1 year",
+		"project_id":        "uuid-for-paris",
+		"service_type":      "first",
+		"resource_name":     "things",
+		"availability_zone": "any",
+		"status":            "superseded",
+		"created_at":        s.Clock.Now().Unix(),
+		"creator_uuid":      "dummy",
+		"creator_name":      "dummy",
+		"can_be_deleted":    true,
+		"confirmed_at":      s.Clock.Now().Unix(),
+		"expires_at":        s.Clock.Now().Add(oneYear).Unix(),
+		"updated_at":        s.Clock.Now().Unix(),
+	}
+	expectCommitments := func(path string, commitments ...jsonmatch.Object) {
+		t.Helper()
+		list := make(jsonmatch.Array, len(commitments))
+		for idx, c := range commitments {
+			list[idx] = c
+		}
+		s.Handler.RespondTo(s.Ctx, "GET "+path).ExpectJSON(t, http.StatusOK, jsonmatch.Object{"commitments": list})
+	}
+
+	// without filters, superseded and expired commitments are not shown
+	expectCommitments("/resources/v2/commitments", commitmentBerlin, commitmentDresden)
+
+	// filter by scope
+	expectCommitments("/resources/v2/commitments?project_uuid=uuid-for-berlin", commitmentBerlin)
+	expectCommitments("/resources/v2/commitments?domain_uuid=uuid-for-germany", commitmentBerlin, commitmentDresden)
+	expectCommitments("/resources/v2/commitments?domain_uuid=uuid-for-france")
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments?domain_uuid=uuid-for-germany&project_uuid=uuid-for-berlin").
+		ExpectText(t, http.StatusBadRequest, "query domain_uuid cannot be set, when query project_uuid is set\n")
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments?project_uuid=does-not-exist").
+		ExpectText(t, http.StatusNotFound, "no such project (UUID = does-not-exist)\n")
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments?domain_uuid=does-not-exist").
+		ExpectText(t, http.StatusNotFound, "no such domain (UUID = does-not-exist)\n")
+
+	// filter by resource and AZ
+	expectCommitments("/resources/v2/commitments?service=first&resource=capacity&az=az-two", commitmentDresden)
+	expectCommitments("/resources/v2/commitments?resource=things")
+
+	// filter by status
+	expectCommitments("/resources/v2/commitments?status=superseded", commitmentParis)
+	expectCommitments("/resources/v2/commitments?status=confirmed&status=superseded", commitmentBerlin, commitmentParis)
+	expectCommitments("/resources/v2/commitments?transfer_status=public", commitmentDresden)
+
+	// filter by expiry date
+	expectCommitments("/resources/v2/commitments?min_expires_at=1971-01-01T12:00:00Z", commitmentDresden)
+	expectCommitments("/resources/v2/commitments?max_expires_at=1971-01-01T12:00:00Z", commitmentBerlin)
+	expectCommitments("/resources/v2/commitments?min_expires_at=1971-01-01T00:00:00Z&max_expires_at=1971-01-01T12:00:00Z", commitmentBerlin)
+	expectCommitments("/resources/v2/commitments?min_expires_at=1971-01-01T12:00:00Z&max_expires_at=1971-01-02T00:00:00Z")
+
+	// authorization
+	s.TokenValidator.Enforcer.AllowReportMultiple = false
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments?domain_uuid=uuid-for-germany").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	expectCommitments("/resources/v2/commitments?project_uuid=uuid-for-berlin", commitmentBerlin)
+	s.TokenValidator.Enforcer.AllowReportSingle = false
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments?project_uuid=uuid-for-berlin").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+}
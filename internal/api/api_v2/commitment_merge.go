@@ -0,0 +1,203 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/audittools"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/must"
+	"github.com/sapcc/go-bits/respondwith"
+	. "go.xyrillian.de/gg/option"
+
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+	"github.com/sapcc/limes/internal/audit"
+	"github.com/sapcc/limes/internal/datamodel"
+	"github.com/sapcc/limes/internal/db"
+)
+
+// handlePostCommitmentMerge handles POST /resources/v2/commitments/merge.
+func (p *v2Provider) handlePostCommitmentMerge(r *http.Request, token *gopherpolicy.Token) (resourcesv2.CommitmentOperationResponse, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/commitments/merge")
+	var (
+		none resourcesv2.CommitmentOperationResponse // used on error return paths only
+		sis  = p.Cluster.SIC.GetSnapshot()
+		now  = p.timeNow()
+	)
+
+	// parse request
+	req, err := parseRequestBodyAs[resourcesv2.CommitmentMergeRequest](r)
+	if err != nil {
+		return none, err
+	}
+	if len(req.CommitmentUUIDs) < 2 {
+		err := fmt.Errorf("merging requires at least two commitments, but %d were given", len(req.CommitmentUUIDs))
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, err)
+	}
+	if len(slices.Compact(slices.Sorted(slices.Values(req.CommitmentUUIDs)))) != len(req.CommitmentUUIDs) {
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errMergeDuplicates)
+	}
+
+	// validate request contents
+	var (
+		commitments []db.ProjectCommitment
+		path        db.AZResourcePath
+		dbDomain    db.Domain
+		dbProject   db.Project
+	)
+	for idx, uuid := range req.CommitmentUUIDs {
+		c, cPath, cDomain, cProject, err := p.checkCommitmentAccess(token, uuid, "v2:project:commitment_create")
+		if err != nil {
+			return none, err
+		}
+		if idx == 0 {
+			path, dbDomain, dbProject = cPath, cDomain, cProject
+		} else if cPath != path || cProject.ID != dbProject.ID {
+			return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errMergeAcrossResources)
+		}
+		err = checkCommitmentIsReplaceable(c, "merge")
+		if err != nil {
+			return none, err
+		}
+		if c.Status != liquid.CommitmentStatusConfirmed {
+			return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errMergeUnconfirmed)
+		}
+		commitments = append(commitments, c)
+	}
+	azResource, ok := sis.GetAZResourceForPath(path)
+	if !ok {
+		return none, respondwith.CustomStatus(http.StatusNotFound, errNoSuchResource)
+	}
+
+	// prepare merged commitment: it covers the total amount until the latest expiration date of all merged commitments
+	mergedCommitment := db.ProjectCommitment{
+		UUID:         datamodel.GenerateProjectCommitmentUUID(),
+		ProjectID:    dbProject.ID,
+		AZResourceID: azResource.ID,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		CreatorUUID:  token.UserUUID(),
+		CreatorName:  fmt.Sprintf("%s@%s", token.UserName(), token.UserDomainName()),
+		ConfirmedAt:  Some(now),
+		ExpiresAt:    time.Time{}, // filled below
+		Status:       liquid.CommitmentStatusConfirmed,
+	}
+	creationContext := db.CommitmentWorkflowContext{Reason: db.CommitmentReasonMerge}
+	for _, c := range commitments {
+		mergedCommitment.Amount += c.Amount
+		if c.ExpiresAt.After(mergedCommitment.ExpiresAt) {
+			mergedCommitment.ExpiresAt = c.ExpiresAt
+			mergedCommitment.Duration = c.Duration
+		}
+		creationContext.RelatedCommitmentIDs = append(creationContext.RelatedCommitmentIDs, c.ID)
+		creationContext.RelatedCommitmentUUIDs = append(creationContext.RelatedCommitmentUUIDs, c.UUID)
+	}
+	creationContextJSON, err := json.Marshal(creationContext)
+	if err != nil {
+		return none, err
+	}
+	mergedCommitment.CreationContextJSON = json.RawMessage(creationContextJSON)
+
+	var auditEvents []audittools.Event
+	err = withinDryRunnableTx(p.DB, req.DryRun, func(tx db.Interface) error {
+		stats, err := getCommitmentStats(tx, dbProject.ID, azResource.ID)
+		if err != nil {
+			return err
+		}
+		err = tx.Insert(&mergedCommitment)
+		if err != nil {
+			return err
+		}
+
+		liquidCommitments := []liquid.Commitment{
+			{
+				UUID:      mergedCommitment.UUID,
+				OldStatus: None[liquid.CommitmentStatus](),
+				NewStatus: Some(mergedCommitment.Status),
+				Amount:    mergedCommitment.Amount,
+				ConfirmBy: mergedCommitment.ConfirmBy,
+				ExpiresAt: mergedCommitment.ExpiresAt,
+			},
+		}
+		for _, c := range commitments {
+			liquidCommitments = append(liquidCommitments, liquid.Commitment{
+				UUID:      c.UUID,
+				OldStatus: Some(c.Status),
+				NewStatus: Some(liquid.CommitmentStatusSuperseded),
+				Amount:    c.Amount,
+				ConfirmBy: c.ConfirmBy,
+				ExpiresAt: c.ExpiresAt,
+			})
+		}
+		ccr := liquid.CommitmentChangeRequest{
+			DryRun:      req.DryRun,
+			AZ:          path.AvailabilityZone,
+			InfoVersion: must.BeOK(sis.GetServiceForType(path.ServiceType)).LiquidVersion,
+			ByProject: map[liquid.ProjectUUID]liquid.ProjectCommitmentChangeset{
+				dbProject.UUID: {
+					ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(dbProject, dbDomain),
+					ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
+						path.ResourceName: {
+							TotalConfirmedBefore:  stats.TotalConfirmed,
+							TotalConfirmedAfter:   stats.TotalConfirmed,
+							TotalGuaranteedBefore: stats.TotalGuaranteed,
+							TotalGuaranteedAfter:  stats.TotalGuaranteed,
+							Commitments:           liquidCommitments,
+						},
+					},
+				},
+			},
+		}
+		resp, err := datamodel.DelegateChangeCommitments(r.Context(), p.Cluster, ccr, sis, path.ServiceType, tx)
+		if err != nil {
+			return err
+		}
+		if ccr.RequiresConfirmation() {
+			err = analyzeCommitmentChangeResponse(resp, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, c := range commitments {
+			err = supersedeCommitment(tx, &c, db.CommitmentReasonMerge, []db.ProjectCommitment{mergedCommitment}, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		if !req.DryRun {
+			auditEvents = audit.CommitmentEventTarget{
+				CommitmentChangeRequest: ccr,
+			}.ReplicateForAllProjectsWithDefaults(audittools.Event{
+				Time:       now,
+				Request:    r,
+				User:       token,
+				ReasonCode: http.StatusOK,
+				Action:     cadf.UpdateAction,
+			})
+		}
+		return nil
+	}) // `tx` is committed here
+	if err != nil {
+		return none, err
+	}
+	for _, event := range auditEvents {
+		p.auditor.Record(event)
+	}
+
+	return resourcesv2.CommitmentOperationResponse{
+		Commitments: []resourcesv2.Commitment{
+			p.convertReplacementCommitmentToDisplayForm(token, mergedCommitment, path, dbDomain, dbProject, req.DryRun),
+		},
+	}, nil
+}
@@ -0,0 +1,99 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/easypg"
+	"github.com/sapcc/go-bits/httptest"
+	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/assert"
+	"go.xyrillian.de/gg/jsonmatch"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/internal/db"
+)
+
+func TestV2CommitmentMerge(t *testing.T) {
+	s := setupResourceReportTest(t)
+	s.MustDBExec(`UPDATE project_commitments SET updated_at = created_at`)
+
+	// in addition to the fixture, berlin has another confirmed commitment in az-one that runs for longer
+	committedForTwoYears := must.Return(limesresources.ParseCommitmentDuration("2 years"))
+	s.MustDBInsert(&db.ProjectCommitment{
+		UUID:                "00000000-0000-0000-0000-000000000003",
+		ProjectID:           s.GetProjectID("berlin"),
+		AZResourceID:        s.GetAZResourceID("first", "capacity", "az-one"),
+		Amount:              5,
+		Duration:            committedForTwoYears,
+		CreatedAt:           s.Clock.Now(),
+		UpdatedAt:           s.Clock.Now(),
+		CreatorUUID:         "dummy",
+		CreatorName:         "dummy",
+		ConfirmedAt:         Some(s.Clock.Now()),
+		ExpiresAt:           committedForTwoYears.AddTo(s.Clock.Now()),
+		CreationContextJSON: json.RawMessage(`{}`),
+		Status:              liquid.CommitmentStatusConfirmed,
+	})
+
+	tr, tr0 := easypg.NewTracker(t, s.DB.Db)
+	tr0.Ignore()
+
+	// validation errors
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/merge",
+		httptest.WithJSONBody(map[string]any{"commitment_ids": []string{"00000000-0000-0000-0000-000000000001"}}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "merging requires at least two commitments, but 1 were given\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/merge",
+		httptest.WithJSONBody(map[string]any{"commitment_ids": []string{"00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000001"}}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "commitments cannot be merged with themselves\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/merge",
+		httptest.WithJSONBody(map[string]any{"commitment_ids": []string{"00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000002"}}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "all commitments must be in the same project, resource and AZ\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/merge",
+		httptest.WithJSONBody(map[string]any{"commitment_ids": []string{"00000000-0000-0000-0000-000000000001", "does-not-exist"}}),
+	).ExpectText(t, http.StatusNotFound, "no such commitment\n")
+
+	// merging requires permission to create commitments in the project
+	s.TokenValidator.Enforcer.AllowCommitmentCreate = false
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/merge",
+		httptest.WithJSONBody(map[string]any{"commitment_ids": []string{"00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000003"}}),
+	).ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowCommitmentCreate = true
+
+	// dry run does not have any side effects
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/merge",
+		httptest.WithJSONBody(map[string]any{"dry_run": true, "commitment_ids": []string{"00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000003"}}),
+	).ExpectJSON(t, http.StatusOK, jsonmatch.Object{"commitments": jsonmatch.Array{
+		expectedCommitmentJSON("00000000-0000-0000-0000-000000000000", 20, "2 years", s.Clock.Now(), committedForTwoYears.AddTo(s.Clock.Now()), createdByAlice),
+	}})
+	tr.DBChanges().AssertEmpty()
+	s.Auditor.ExpectEvents(t /*, nothing */)
+
+	// successful merge
+	var mergedUUID string
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/merge",
+		httptest.WithJSONBody(map[string]any{"commitment_ids": []string{"00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000003"}}),
+	).ExpectJSON(t, http.StatusOK, jsonmatch.Object{"commitments": jsonmatch.Array{
+		expectedCommitmentJSON(jsonmatch.CaptureField(&mergedUUID), 20, "2 years", s.Clock.Now(), committedForTwoYears.AddTo(s.Clock.Now()), createdByAlice),
+	}})
+	assert.Equal(t, len(s.Auditor.RecordedEvents()), 1)
+
+	// the merged commitments are superseded (ID 4 was used up by the dry run)
+	tr.DBChanges().AssertEqualf(`
+		UPDATE project_commitments SET status = 'superseded', superseded_at = %[1]d, supersede_context_json = '{"reason": "merge", "related_ids": [5], "related_uuids": ["%[4]s"]}' WHERE id = 1 AND uuid = '00000000-0000-0000-0000-000000000001' AND transfer_token = NULL;
+		UPDATE project_commitments SET status = 'superseded', superseded_at = %[1]d, supersede_context_json = '{"reason": "merge", "related_ids": [5], "related_uuids": ["%[4]s"]}' WHERE id = 3 AND uuid = '00000000-0000-0000-0000-000000000003' AND transfer_token = NULL;
+		INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, creation_context_json, updated_at) VALUES (5, '%[4]s', 1, %[3]d, 'confirmed', 20, '2 years', %[1]d, 'uuid-for-alice', 'alice@Default', %[1]d, %[2]d, '{"reason": "merge", "related_ids": [1, 3], "related_uuids": ["00000000-0000-0000-0000-000000000001", "00000000-0000-0000-0000-000000000003"]}', %[1]d);
+	`,
+		s.Clock.Now().Unix(), committedForTwoYears.AddTo(s.Clock.Now()).Unix(), s.GetAZResourceID("first", "capacity", "az-one"), mergedUUID)
+
+	// superseded commitments cannot be merged again
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/merge",
+		httptest.WithJSONBody(map[string]any{"commitment_ids": []string{mergedUUID, "00000000-0000-0000-0000-000000000003"}}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "cannot merge a commitment in status \"superseded\"\n")
+}
@@ -0,0 +1,164 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/audittools"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/must"
+	"github.com/sapcc/go-bits/respondwith"
+	. "go.xyrillian.de/gg/option"
+
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+	"github.com/sapcc/limes/internal/audit"
+	"github.com/sapcc/limes/internal/datamodel"
+	"github.com/sapcc/limes/internal/db"
+)
+
+// handlePostCommitmentSplit handles POST /resources/v2/commitments/:uuid/split.
+func (p *v2Provider) handlePostCommitmentSplit(r *http.Request, token *gopherpolicy.Token) (resourcesv2.CommitmentOperationResponse, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/commitments/:uuid/split")
+	var (
+		none resourcesv2.CommitmentOperationResponse // used on error return paths only
+		sis  = p.Cluster.SIC.GetSnapshot()
+		now  = p.timeNow()
+	)
+
+	// parse request
+	req, err := parseRequestBodyAs[resourcesv2.CommitmentSplitRequest](r)
+	if err != nil {
+		return none, err
+	}
+	uuid := liquid.CommitmentUUID(mux.Vars(r)["uuid"])
+
+	// validate request contents
+	c, path, dbDomain, dbProject, err := p.checkCommitmentAccess(token, uuid, "v2:project:commitment_create")
+	if err != nil {
+		return none, err
+	}
+	azResource, ok := sis.GetAZResourceForPath(path)
+	if !ok {
+		return none, respondwith.CustomStatus(http.StatusNotFound, errNoSuchResource)
+	}
+	err = checkCommitmentIsReplaceable(c, "split")
+	if err != nil {
+		return none, err
+	}
+	if req.Amount == 0 {
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errEmptyAmount)
+	}
+	if req.Amount >= c.Amount {
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errSplitAmountTooLarge)
+	}
+
+	var (
+		auditEvents    []audittools.Event
+		newCommitments []db.ProjectCommitment
+	)
+	err = withinDryRunnableTx(p.DB, req.DryRun, func(tx db.Interface) error {
+		stats, err := getCommitmentStats(tx, dbProject.ID, azResource.ID)
+		if err != nil {
+			return err
+		}
+
+		// the split-off part is listed first, followed by the remainder
+		for _, amount := range []uint64{req.Amount, c.Amount - req.Amount} {
+			nc, err := datamodel.BuildSplitCommitment(c, amount, now, datamodel.GenerateProjectCommitmentUUID)
+			if err != nil {
+				return err
+			}
+			err = tx.Insert(&nc)
+			if err != nil {
+				return err
+			}
+			newCommitments = append(newCommitments, nc)
+		}
+
+		liquidCommitments := []liquid.Commitment{
+			{
+				UUID:      c.UUID,
+				OldStatus: Some(c.Status),
+				NewStatus: Some(liquid.CommitmentStatusSuperseded),
+				Amount:    c.Amount,
+				ConfirmBy: c.ConfirmBy,
+				ExpiresAt: c.ExpiresAt,
+			},
+		}
+		for _, nc := range newCommitments {
+			liquidCommitments = append(liquidCommitments, liquid.Commitment{
+				UUID:      nc.UUID,
+				OldStatus: None[liquid.CommitmentStatus](),
+				NewStatus: Some(nc.Status),
+				Amount:    nc.Amount,
+				ConfirmBy: nc.ConfirmBy,
+				ExpiresAt: nc.ExpiresAt,
+			})
+		}
+		ccr := liquid.CommitmentChangeRequest{
+			DryRun:      req.DryRun,
+			AZ:          path.AvailabilityZone,
+			InfoVersion: must.BeOK(sis.GetServiceForType(path.ServiceType)).LiquidVersion,
+			ByProject: map[liquid.ProjectUUID]liquid.ProjectCommitmentChangeset{
+				dbProject.UUID: {
+					ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(dbProject, dbDomain),
+					ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
+						path.ResourceName: {
+							TotalConfirmedBefore:  stats.TotalConfirmed,
+							TotalConfirmedAfter:   stats.TotalConfirmed,
+							TotalGuaranteedBefore: stats.TotalGuaranteed,
+							TotalGuaranteedAfter:  stats.TotalGuaranteed,
+							Commitments:           liquidCommitments,
+						},
+					},
+				},
+			},
+		}
+		resp, err := datamodel.DelegateChangeCommitments(r.Context(), p.Cluster, ccr, sis, path.ServiceType, tx)
+		if err != nil {
+			return err
+		}
+		if ccr.RequiresConfirmation() {
+			err = analyzeCommitmentChangeResponse(resp, now)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = supersedeCommitment(tx, &c, db.CommitmentReasonSplit, newCommitments, now)
+		if err != nil {
+			return err
+		}
+
+		if !req.DryRun {
+			auditEvents = audit.CommitmentEventTarget{
+				CommitmentChangeRequest: ccr,
+			}.ReplicateForAllProjectsWithDefaults(audittools.Event{
+				Time:       now,
+				Request:    r,
+				User:       token,
+				ReasonCode: http.StatusOK,
+				Action:     cadf.UpdateAction,
+			})
+		}
+		return nil
+	}) // `tx` is committed here
+	if err != nil {
+		return none, err
+	}
+	for _, event := range auditEvents {
+		p.auditor.Record(event)
+	}
+
+	result := resourcesv2.CommitmentOperationResponse{Commitments: make([]resourcesv2.Commitment, len(newCommitments))}
+	for idx, nc := range newCommitments {
+		result.Commitments[idx] = p.convertReplacementCommitmentToDisplayForm(token, nc, path, dbDomain, dbProject, req.DryRun)
+	}
+	return result, nil
+}
@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sapcc/go-bits/easypg"
+	"github.com/sapcc/go-bits/httptest"
+	"go.xyrillian.de/gg/assert"
+	"go.xyrillian.de/gg/jsonmatch"
+)
+
+func TestV2CommitmentSplit(t *testing.T) {
+	s := setupResourceReportTest(t)
+	const oneYear = 365 * 24 * time.Hour
+
+	tr, tr0 := easypg.NewTracker(t, s.DB.Db)
+	tr0.Ignore()
+
+	// splitting requires permission to create commitments in the commitment's project
+	s.TokenValidator.Enforcer.AllowCommitmentCreate = false
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/split",
+		httptest.WithJSONBody(map[string]any{"amount": 10}),
+	).ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowCommitmentCreate = true
+
+	// validation errors
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/split",
+		httptest.WithJSONBody(map[string]any{"amount": 0}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "amount of committed resource must be greater than zero\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/split",
+		httptest.WithJSONBody(map[string]any{"amount": 15}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "amount must be smaller than the amount of the commitment\n")
+
+	// dry run does not have any side effects
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/split",
+		httptest.WithJSONBody(map[string]any{"amount": 10, "dry_run": true}),
+	).ExpectJSON(t, http.StatusOK, jsonmatch.Object{"commitments": jsonmatch.Array{
+		expectedCommitmentJSON("00000000-0000-0000-0000-000000000000", 10, "1 year", s.Clock.Now(), s.Clock.Now().Add(oneYear)),
+		expectedCommitmentJSON("00000000-0000-0000-0000-000000000000", 5, "1 year", s.Clock.Now(), s.Clock.Now().Add(oneYear)),
+	}})
+	tr.DBChanges().AssertEmpty()
+	s.Auditor.ExpectEvents(t /*, nothing */)
+
+	// successful split
+	var uuid1, uuid2 string
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/split",
+		httptest.WithJSONBody(map[string]any{"amount": 10}),
+	).ExpectJSON(t, http.StatusOK, jsonmatch.Object{"commitments": jsonmatch.Array{
+		expectedCommitmentJSON(jsonmatch.CaptureField(&uuid1), 10, "1 year", s.Clock.Now(), s.Clock.Now().Add(oneYear)),
+		expectedCommitmentJSON(jsonmatch.CaptureField(&uuid2), 5, "1 year", s.Clock.Now(), s.Clock.Now().Add(oneYear)),
+	}})
+	assert.Equal(t, len(s.Auditor.RecordedEvents()), 1)
+
+	// the new commitments record the lineage in both directions (IDs 3 and 4 were used up by the dry run)
+	tr.DBChanges().AssertEqualf(`
+		UPDATE project_commitments SET status = 'superseded', superseded_at = %[1]d, supersede_context_json = '{"reason": "split", "related_ids": [5, 6], "related_uuids": ["%[4]s", "%[5]s"]}', updated_at = %[1]d WHERE id = 1 AND uuid = '00000000-0000-0000-0000-000000000001' AND transfer_token = NULL;
+		INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, creation_context_json, updated_at) VALUES (5, '%[4]s', 1, %[3]d, 'confirmed', 10, '1 year', %[1]d, 'dummy', 'dummy', %[1]d, %[2]d, '{"reason": "split", "related_ids": [1], "related_uuids": ["00000000-0000-0000-0000-000000000001"]}', %[1]d);
+		INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, creation_context_json, updated_at) VALUES (6, '%[5]s', 1, %[3]d, 'confirmed', 5, '1 year', %[1]d, 'dummy', 'dummy', %[1]d, %[2]d, '{"reason": "split", "related_ids": [1], "related_uuids": ["00000000-0000-0000-0000-000000000001"]}', %[1]d);
+	`,
+		s.Clock.Now().Unix(), s.Clock.Now().Add(oneYear).Unix(), s.GetAZResourceID("first", "capacity", "az-one"), uuid1, uuid2)
+
+	// the original commitment is superseded and cannot be split again
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/split",
+		httptest.WithJSONBody(map[string]any{"amount": 10}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "cannot split a commitment in status \"superseded\"\n")
+
+	// commitments in transfer cannot be split
+	s.MustDBExec(`UPDATE project_commitments SET transfer_status = 'unlisted', transfer_token = 'dummy-token', transfer_started_at = $1 WHERE uuid = $2`,
+		s.Clock.Now(), uuid1)
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/"+uuid1+"/split",
+		httptest.WithJSONBody(map[string]any{"amount": 5}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "cannot split a commitment that is marked for transfer\n")
+}
@@ -0,0 +1,435 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sapcc/go-api-declarations/cadf"
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/audittools"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/must"
+	"github.com/sapcc/go-bits/respondwith"
+	"github.com/sapcc/go-bits/sqlext"
+	. "go.xyrillian.de/gg/option"
+
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+	"github.com/sapcc/limes/internal/audit"
+	"github.com/sapcc/limes/internal/core"
+	"github.com/sapcc/limes/internal/datamodel"
+	"github.com/sapcc/limes/internal/db"
+)
+
+var (
+	findCommitmentByTransferTokenQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+		SELECT * FROM project_commitments
+		 WHERE transfer_token = $1 AND transfer_status != {{limesresources.CommitmentTransferStatusNone}}
+		   AND status NOT IN ({{liquid.CommitmentStatusSuperseded}}, {{liquid.CommitmentStatusExpired}}, {{util.CommitmentStatusDeleted}})
+	`))
+	findCommitmentByUUIDAndTransferTokenQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+		SELECT * FROM project_commitments
+		 WHERE uuid = $1 AND transfer_token = $2 AND transfer_status != {{limesresources.CommitmentTransferStatusNone}}
+		   AND status NOT IN ({{liquid.CommitmentStatusSuperseded}}, {{liquid.CommitmentStatusExpired}}, {{util.CommitmentStatusDeleted}})
+	`))
+)
+
+// handlePostCommitmentStartTransfer handles POST /resources/v2/commitments/:uuid/start-transfer.
+func (p *v2Provider) handlePostCommitmentStartTransfer(r *http.Request, token *gopherpolicy.Token) (resourcesv2.Commitment, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/commitments/:uuid/start-transfer")
+	var (
+		none resourcesv2.Commitment // used on error return paths only
+		sis  = p.Cluster.SIC.GetSnapshot()
+		now  = p.timeNow()
+	)
+
+	// parse request
+	req, err := parseRequestBodyAs[resourcesv2.CommitmentTransferRequest](r)
+	if err != nil {
+		return none, err
+	}
+	uuid := liquid.CommitmentUUID(mux.Vars(r)["uuid"])
+
+	// validate request contents
+	c, path, dbDomain, dbProject, err := p.checkCommitmentAccess(token, uuid, "v2:project:commitment_create")
+	if err != nil {
+		return none, err
+	}
+	azResource, ok := sis.GetAZResourceForPath(path)
+	if !ok {
+		return none, respondwith.CustomStatus(http.StatusNotFound, errNoSuchResource)
+	}
+	acceptableTransferStatuses := []limesresources.CommitmentTransferStatus{
+		limesresources.CommitmentTransferStatusUnlisted,
+		limesresources.CommitmentTransferStatusPublic,
+		// None is allowed in order to withdraw an existing offer for a commitment transfer
+		limesresources.CommitmentTransferStatusNone,
+	}
+	if !slices.Contains(acceptableTransferStatuses, req.TransferStatus) {
+		buf := must.Return(json.Marshal(acceptableTransferStatuses)) // panic on error is acceptable here, marshals should never fail
+		msg := "invalid transfer_status; acceptable values: " + string(buf)
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errors.New(msg))
+	}
+	if c.TransferStatus == req.TransferStatus {
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errTransferStatusUnchanged)
+	}
+	isWithdrawal := req.TransferStatus == limesresources.CommitmentTransferStatusNone
+	if !isWithdrawal {
+		// commitments that have already ended their lifecycle cannot be offered anymore
+		if slices.Contains([]liquid.CommitmentStatus{liquid.CommitmentStatusExpired, liquid.CommitmentStatusSuperseded}, c.Status) {
+			err := fmt.Errorf("cannot transfer a commitment in status %q", c.Status)
+			return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, err)
+		}
+		if req.Amount == 0 {
+			return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errEmptyAmount)
+		}
+		if req.Amount > c.Amount {
+			return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errAmountExceedsCommitment)
+		}
+	}
+
+	// when moving into CommitmentTransferStatusNone, the token is cleared;
+	// otherwise a new token is generated and filled in for the transfer
+	transferToken := None[string]()
+	transferStartedAt := None[time.Time]()
+	if !isWithdrawal {
+		transferToken = Some(datamodel.GenerateTransferToken())
+		transferStartedAt = Some(now)
+	}
+
+	var auditEvents []audittools.Event
+	err = withinDryRunnableTx(p.DB, false, func(tx db.Interface) error {
+		stats, err := getCommitmentStats(tx, dbProject.ID, azResource.ID)
+		if err != nil {
+			return err
+		}
+
+		// if the commitment is not split, the CCR is only used for the audit trail
+		ccr := liquid.CommitmentChangeRequest{
+			AZ:          path.AvailabilityZone,
+			InfoVersion: must.BeOK(sis.GetServiceForType(path.ServiceType)).LiquidVersion,
+			ByProject: map[liquid.ProjectUUID]liquid.ProjectCommitmentChangeset{
+				dbProject.UUID: {
+					ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(dbProject, dbDomain),
+					ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
+						path.ResourceName: {
+							TotalConfirmedBefore:  stats.TotalConfirmed,
+							TotalConfirmedAfter:   stats.TotalConfirmed,
+							TotalGuaranteedBefore: stats.TotalGuaranteed,
+							TotalGuaranteedAfter:  stats.TotalGuaranteed,
+						},
+					},
+				},
+			},
+		}
+		rcc := ccr.ByProject[dbProject.UUID].ByResource[path.ResourceName]
+		cac := make(map[liquid.CommitmentUUID]audit.CommitmentAttributeChangeset)
+
+		if isWithdrawal || req.Amount == c.Amount {
+			// mark the whole commitment for transfer (or withdraw the existing offer)
+			rcc.Commitments = []liquid.Commitment{
+				{
+					UUID:      c.UUID,
+					OldStatus: Some(c.Status),
+					NewStatus: Some(c.Status),
+					Amount:    c.Amount,
+					ConfirmBy: c.ConfirmBy,
+					ExpiresAt: c.ExpiresAt,
+				},
+			}
+			ccr.ByProject[dbProject.UUID].ByResource[path.ResourceName] = rcc
+			cac[c.UUID] = audit.CommitmentAttributeChangeset{
+				OldTransferStatus: c.TransferStatus,
+				NewTransferStatus: req.TransferStatus,
+			}
+
+			c.TransferStatus = req.TransferStatus
+			c.TransferToken = transferToken
+			c.TransferStartedAt = transferStartedAt
+			c.UpdatedAt = now
+			_, err = tx.Update(&c)
+			if err != nil {
+				return err
+			}
+		} else {
+			// split the commitment and mark only the requested part for transfer
+			transferCommitment, err := datamodel.BuildSplitCommitment(c, req.Amount, now, datamodel.GenerateProjectCommitmentUUID)
+			if err != nil {
+				return err
+			}
+			transferCommitment.TransferStatus = req.TransferStatus
+			transferCommitment.TransferToken = transferToken
+			transferCommitment.TransferStartedAt = transferStartedAt
+			remainingCommitment, err := datamodel.BuildSplitCommitment(c, c.Amount-req.Amount, now, datamodel.GenerateProjectCommitmentUUID)
+			if err != nil {
+				return err
+			}
+			err = tx.Insert(&transferCommitment)
+			if err != nil {
+				return err
+			}
+			err = tx.Insert(&remainingCommitment)
+			if err != nil {
+				return err
+			}
+
+			rcc.Commitments = []liquid.Commitment{
+				{
+					UUID:      c.UUID,
+					OldStatus: Some(c.Status),
+					NewStatus: Some(liquid.CommitmentStatusSuperseded),
+					Amount:    c.Amount,
+					ConfirmBy: c.ConfirmBy,
+					ExpiresAt: c.ExpiresAt,
+				},
+				{
+					UUID:      transferCommitment.UUID,
+					OldStatus: None[liquid.CommitmentStatus](),
+					NewStatus: Some(transferCommitment.Status),
+					Amount:    transferCommitment.Amount,
+					ConfirmBy: transferCommitment.ConfirmBy,
+					ExpiresAt: transferCommitment.ExpiresAt,
+				},
+				{
+					UUID:      remainingCommitment.UUID,
+					OldStatus: None[liquid.CommitmentStatus](),
+					NewStatus: Some(remainingCommitment.Status),
+					Amount:    remainingCommitment.Amount,
+					ConfirmBy: remainingCommitment.ConfirmBy,
+					ExpiresAt: remainingCommitment.ExpiresAt,
+				},
+			}
+			ccr.ByProject[dbProject.UUID].ByResource[path.ResourceName] = rcc
+			cac[transferCommitment.UUID] = audit.CommitmentAttributeChangeset{
+				OldTransferStatus: limesresources.CommitmentTransferStatusNone,
+				NewTransferStatus: req.TransferStatus,
+			}
+			if c.TransferStatus != limesresources.CommitmentTransferStatusNone {
+				// a previous offer for the whole commitment ends together with the commitment itself
+				cac[c.UUID] = audit.CommitmentAttributeChangeset{
+					OldTransferStatus: c.TransferStatus,
+					NewTransferStatus: limesresources.CommitmentTransferStatusNone,
+				}
+			}
+
+			resp, err := datamodel.DelegateChangeCommitments(r.Context(), p.Cluster, ccr, sis, path.ServiceType, tx)
+			if err != nil {
+				return err
+			}
+			if ccr.RequiresConfirmation() {
+				err = analyzeCommitmentChangeResponse(resp, now)
+				if err != nil {
+					return err
+				}
+			}
+
+			supersedeContextJSON, err := json.Marshal(db.CommitmentWorkflowContext{
+				Reason:                 db.CommitmentReasonSplit,
+				RelatedCommitmentIDs:   []db.ProjectCommitmentID{transferCommitment.ID, remainingCommitment.ID},
+				RelatedCommitmentUUIDs: []liquid.CommitmentUUID{transferCommitment.UUID, remainingCommitment.UUID},
+			})
+			if err != nil {
+				return err
+			}
+			c.Status = liquid.CommitmentStatusSuperseded
+			c.SupersededAt = Some(now)
+			c.SupersedeContextJSON = Some(json.RawMessage(supersedeContextJSON))
+			c.TransferStatus = limesresources.CommitmentTransferStatusNone
+			c.TransferToken = None[string]()
+			c.TransferStartedAt = None[time.Time]()
+			c.UpdatedAt = now
+			_, err = tx.Update(&c)
+			if err != nil {
+				return err
+			}
+
+			// the response shows the part of the commitment that was marked for transfer
+			c = transferCommitment
+		}
+
+		auditEvents = audit.CommitmentEventTarget{
+			CommitmentChangeRequest:       ccr,
+			CommitmentAttributeChangesets: cac,
+		}.ReplicateForAllProjectsWithDefaults(audittools.Event{
+			Time:       now,
+			Request:    r,
+			User:       token,
+			ReasonCode: http.StatusOK,
+			Action:     cadf.UpdateAction,
+		})
+		return nil
+	}) // `tx` is committed here
+	if err != nil {
+		return none, err
+	}
+
+	for _, event := range auditEvents {
+		p.auditor.Record(event)
+	}
+
+	canBeDeleted := p.canDeleteCommitment(token, c, dbDomain, dbProject)
+	return convertCommitmentToDisplayForm(c, path, dbProject, canBeDeleted), nil
+}
+
+// handleGetCommitmentByTransferToken handles GET /resources/v2/commitments/by-transfer-token/:token.
+func (p *v2Provider) handleGetCommitmentByTransferToken(r *http.Request, token *gopherpolicy.Token) (resourcesv2.Commitment, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/commitments/by-transfer-token/:token")
+	none := resourcesv2.Commitment{}
+
+	// Knowledge of the transfer token is what authorizes access to the commitment,
+	// so it is enough to be a member of any project.
+	err := token.Enforce("v2:project:info")
+	if err != nil {
+		return none, err
+	}
+
+	// The token column is a unique key, so we expect only one result.
+	var c db.ProjectCommitment
+	err = p.DB.SelectOne(&c, findCommitmentByTransferTokenQuery, mux.Vars(r)["token"])
+	if errors.Is(err, sql.ErrNoRows) {
+		return none, respondwith.CustomStatus(http.StatusNotFound, errNoSuchCommitment)
+	} else if err != nil {
+		return none, err
+	}
+	dbDomain, dbProject, path, err := p.loadCommitmentLocation(c)
+	if err != nil {
+		return none, err
+	}
+
+	canBeDeleted := p.canDeleteCommitment(token, c, dbDomain, dbProject)
+	return convertCommitmentToDisplayForm(c, path, dbProject, canBeDeleted), nil
+}
+
+// handlePostCommitmentAcceptTransfer handles POST /resources/v2/commitments/:uuid/accept-transfer.
+func (p *v2Provider) handlePostCommitmentAcceptTransfer(r *http.Request, token *gopherpolicy.Token) (resourcesv2.Commitment, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/commitments/:uuid/accept-transfer")
+	var (
+		none resourcesv2.Commitment // used on error return paths only
+		sis  = p.Cluster.SIC.GetSnapshot()
+		now  = p.timeNow()
+	)
+
+	// parse request
+	req, err := parseRequestBodyAs[resourcesv2.CommitmentAcceptTransferRequest](r)
+	if err != nil {
+		return none, err
+	}
+	transferToken := r.Header.Get("Transfer-Token")
+	if transferToken == "" {
+		return none, respondwith.CustomStatus(http.StatusBadRequest, errTransferTokenMissing)
+	}
+	uuid := liquid.CommitmentUUID(mux.Vars(r)["uuid"])
+
+	// validate request contents
+	targetDomain, targetProject, err := p.checkProjectAccess(token, req.ProjectUUID, "v2:project:commitment_create")
+	if err != nil {
+		return none, err
+	}
+	var c db.ProjectCommitment
+	err = p.DB.SelectOne(&c, findCommitmentByUUIDAndTransferTokenQuery, uuid, transferToken)
+	if errors.Is(err, sql.ErrNoRows) {
+		return none, respondwith.CustomStatus(http.StatusNotFound, errNoSuchCommitment)
+	} else if err != nil {
+		return none, err
+	}
+	if c.ProjectID == targetProject.ID {
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errTransferIntoSameProject)
+	}
+	sourceDomain, sourceProject, path, err := p.loadCommitmentLocation(c)
+	if err != nil {
+		return none, err
+	}
+	_, _, err = p.validateCommittability(path, targetDomain, targetProject, c.Duration, sis)
+	if err != nil {
+		return none, err
+	}
+
+	var auditEvents []audittools.Event
+	err = withinDryRunnableTx(p.DB, false, func(tx db.Interface) error {
+		// the capacity check is shared with the consumption of transferable commitments
+		tcc, err := datamodel.NewTransferableCommitmentCache(tx, p.Cluster, sis, path, now, datamodel.GenerateProjectCommitmentUUID, datamodel.GenerateTransferToken, None[core.MailTemplate]())
+		if err != nil {
+			return err
+		}
+		ccr, resp, err := tcc.CanTransferCommitment(r.Context(), c, sourceProject, sourceDomain, targetProject, targetDomain)
+		if err != nil {
+			return err
+		}
+		err = analyzeCommitmentChangeResponse(resp, now)
+		if err != nil {
+			return err
+		}
+		cac := map[liquid.CommitmentUUID]audit.CommitmentAttributeChangeset{
+			c.UUID: {
+				OldTransferStatus: c.TransferStatus,
+				NewTransferStatus: limesresources.CommitmentTransferStatusNone,
+			},
+		}
+
+		// perform move
+		c.ProjectID = targetProject.ID
+		c.TransferStatus = limesresources.CommitmentTransferStatusNone
+		c.TransferToken = None[string]()
+		c.TransferStartedAt = None[time.Time]()
+		c.UpdatedAt = now
+		_, err = tx.Update(&c)
+		if err != nil {
+			return err
+		}
+
+		auditEvents = audit.CommitmentEventTarget{
+			CommitmentChangeRequest:       ccr,
+			CommitmentAttributeChangesets: cac,
+		}.ReplicateForAllProjectsWithDefaults(audittools.Event{
+			Time:       now,
+			Request:    r,
+			User:       token,
+			ReasonCode: http.StatusOK,
+			Action:     cadf.UpdateAction,
+		})
+		return nil
+	}) // `tx` is committed here
+	if err != nil {
+		return none, err
+	}
+
+	for _, event := range auditEvents {
+		p.auditor.Record(event)
+	}
+
+	canBeDeleted := p.canDeleteCommitment(token, c, targetDomain, targetProject)
+	return convertCommitmentToDisplayForm(c, path, targetProject, canBeDeleted), nil
+}
+
+// loadCommitmentLocation loads the database records for the project and domain of the given commitment, as well as its AZ resource path.
+// This is used when the commitment was not located through checkCommitmentAccess, e.g. because it was found by its transfer token.
+func (p *v2Provider) loadCommitmentLocation(c db.ProjectCommitment) (_ db.Domain, _ db.Project, _ db.AZResourcePath, err error) {
+	var (
+		dbDomain  db.Domain
+		dbProject db.Project
+		path      db.AZResourcePath
+	)
+	err = p.DB.SelectOne(&dbProject, `SELECT * FROM projects WHERE id = $1`, c.ProjectID)
+	if err != nil {
+		return
+	}
+	err = p.DB.SelectOne(&dbDomain, `SELECT * FROM domains WHERE id = $1`, dbProject.DomainID)
+	if err != nil {
+		return
+	}
+	err = p.DB.QueryRow(`SELECT path FROM az_resources WHERE id = $1`, c.AZResourceID).Scan(&path)
+	if err != nil {
+		return
+	}
+	return dbDomain, dbProject, path, nil
+}
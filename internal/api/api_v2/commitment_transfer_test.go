@@ -0,0 +1,331 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/httptest"
+	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/assert"
+	"go.xyrillian.de/gg/jsonmatch"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/internal/test"
+	"github.com/sapcc/limes/internal/test/common_fixtures"
+)
+
+var commitmentTransferConfigJSON = string(must.Return(httptest.NewJQModifiableJSONString(test.RemoveCommentsFromJSON(`
+	{
+		"liquids": {
+			"first": {
+				"area": "first",
+				"commitment_behavior_per_resource": [
+					{
+						"key": "capacity",
+						"value": {
+							"durations_per_domain": [{"key": "germany", "value": ["1 hour"]}]
+						}
+					}
+				]
+			},
+			"second": {
+				"area": "second",
+				"commitment_behavior_per_resource": []
+			}
+		}
+	}`), "commitmentTransferConfigJSON").
+	ModifyWithVariable(".discovery = $ref", common_fixtures.DiscoveryBerlinDresdenParis).
+	ModifyWithVariable(".areas = $ref", common_fixtures.AreasFirstSecond).
+	ModifyWithVariable(".availability_zones = $ref", common_fixtures.AZsOneTwo).
+	MarshalJSON()))
+
+func TestV2CommitmentTransfer(t *testing.T) {
+	s := test.NewSetup(t,
+		test.WithConfig(commitmentTransferConfigJSON),
+		test.WithPersistedServiceInfo("first", test.DefaultLiquidServiceInfo("First")),
+		test.WithPersistedServiceInfo("second", test.DefaultLiquidServiceInfo("Second")),
+		test.WithInitialDiscovery,
+		test.WithEmptyResourceRecordsAsNeeded,
+	)
+	s.MustDBExec(`UPDATE az_resources SET raw_capacity = 100 WHERE id = $1`, s.GetAZResourceID("first", "capacity", "az-one"))
+
+	// berlin has 10 confirmed in az-one
+	committedForOneHour := must.Return(limesresources.ParseCommitmentDuration("1 hour"))
+	s.MustDBInsert(&db.ProjectCommitment{
+		UUID:                "00000000-0000-0000-0000-000000000001",
+		ProjectID:           s.GetProjectID("berlin"),
+		AZResourceID:        s.GetAZResourceID("first", "capacity", "az-one"),
+		Amount:              10,
+		Duration:            committedForOneHour,
+		CreatedAt:           s.Clock.Now(),
+		UpdatedAt:           s.Clock.Now(),
+		CreatorUUID:         "dummy",
+		CreatorName:         "dummy",
+		ConfirmedAt:         Some(s.Clock.Now()),
+		ExpiresAt:           committedForOneHour.AddTo(s.Clock.Now()),
+		CreationContextJSON: json.RawMessage(`{}`),
+		Status:              liquid.CommitmentStatusConfirmed,
+	})
+
+	// offering a commitment requires permission to create commitments in its project
+	s.TokenValidator.Enforcer.AllowCommitmentCreate = false
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/start-transfer",
+		httptest.WithJSONBody(map[string]any{"amount": 10, "transfer_status": "unlisted"}),
+	).ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowCommitmentCreate = true
+
+	// validation errors
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/start-transfer",
+		httptest.WithJSONBody(map[string]any{"amount": 10, "transfer_status": "foo"}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "invalid transfer_status; acceptable values: [\"unlisted\",\"public\",\"\"]\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/start-transfer",
+		httptest.WithJSONBody(map[string]any{"amount": 10, "transfer_status": ""}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "transfer_status is already set to the requested value\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/start-transfer",
+		httptest.WithJSONBody(map[string]any{"amount": 0, "transfer_status": "unlisted"}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "amount of committed resource must be greater than zero\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/start-transfer",
+		httptest.WithJSONBody(map[string]any{"amount": 11, "transfer_status": "unlisted"}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "amount may not exceed the amount of the commitment\n")
+	s.Auditor.ExpectEvents(t /*, nothing */)
+
+	// offering part of the commitment splits it
+	var (
+		transferUUID  string
+		transferToken string
+	)
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/start-transfer",
+		httptest.WithJSONBody(map[string]any{"amount": 4, "transfer_status": "unlisted"}),
+	).ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"uuid":              jsonmatch.CaptureField(&transferUUID),
+		"amount":            4,
+		"duration":          "1 hour",
+		"project_id":        "uuid-for-berlin",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "confirmed",
+		"transfer_status":   "unlisted",
+		"transfer_token":    jsonmatch.CaptureField(&transferToken),
+		"created_at":        s.Clock.Now().Unix(),
+		"creator_uuid":      "dummy",
+		"creator_name":      "dummy",
+		"can_be_deleted":    true,
+		"confirmed_at":      s.Clock.Now().Unix(),
+		"expires_at":        committedForOneHour.AddTo(s.Clock.Now()).Unix(),
+		"updated_at":        s.Clock.Now().Unix(),
+	})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments?project_uuid=uuid-for-berlin").
+		ExpectJSON(t, http.StatusOK, jsonmatch.Object{"commitments": jsonmatch.Array{
+			jsonmatch.Object{
+				"uuid":              transferUUID,
+				"amount":            4,
+				"duration":          "1 hour",
+				"project_id":        "uuid-for-berlin",
+				"service_type":      "first",
+				"resource_name":     "capacity",
+				"availability_zone": "az-one",
+				"status":            "confirmed",
+				"transfer_status":   "unlisted",
+				"transfer_token":    transferToken,
+				"created_at":        s.Clock.Now().Unix(),
+				"creator_uuid":      "dummy",
+				"creator_name":      "dummy",
+				"can_be_deleted":    true,
+				"confirmed_at":      s.Clock.Now().Unix(),
+				"expires_at":        committedForOneHour.AddTo(s.Clock.Now()).Unix(),
+				"updated_at":        s.Clock.Now().Unix(),
+			},
+			jsonmatch.Object{
+				"uuid":              jsonmatch.Irrelevant(),
+				"amount":            6,
+				"duration":          "1 hour",
+				"project_id":        "uuid-for-berlin",
+				"service_type":      "first",
+				"resource_name":     "capacity",
+				"availability_zone": "az-one",
+				"status":            "confirmed",
+				"created_at":        s.Clock.Now().Unix(),
+				"creator_uuid":      "dummy",
+				"creator_name":      "dummy",
+				"can_be_deleted":    true,
+				"confirmed_at":      s.Clock.Now().Unix(),
+				"expires_at":        committedForOneHour.AddTo(s.Clock.Now()).Unix(),
+				"updated_at":        s.Clock.Now().Unix(),
+			},
+		}})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/00000000-0000-0000-0000-000000000001").
+		ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+			"uuid":              "00000000-0000-0000-0000-000000000001",
+			"amount":            10,
+			"duration":          "1 hour",
+			"project_id":        "uuid-for-berlin",
+			"service_type":      "first",
+			"resource_name":     "capacity",
+			"availability_zone": "az-one",
+			"status":            "superseded",
+			"created_at":        s.Clock.Now().Unix(),
+			"creator_uuid":      "dummy",
+			"creator_name":      "dummy",
+			"can_be_deleted":    true,
+			"confirmed_at":      s.Clock.Now().Unix(),
+			"expires_at":        committedForOneHour.AddTo(s.Clock.Now()).Unix(),
+			"updated_at":        s.Clock.Now().Unix(),
+		})
+	assert.Equal(t, len(s.Auditor.RecordedEvents()), 1) // one event for the project owning the split commitment
+
+	// superseded commitments cannot be offered anymore
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000001/start-transfer",
+		httptest.WithJSONBody(map[string]any{"amount": 10, "transfer_status": "unlisted"}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "cannot transfer a commitment in status \"superseded\"\n")
+
+	// the offered commitment can be found by its transfer token
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/by-transfer-token/"+transferToken).
+		ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+			"uuid":              transferUUID,
+			"amount":            4,
+			"duration":          "1 hour",
+			"project_id":        "uuid-for-berlin",
+			"service_type":      "first",
+			"resource_name":     "capacity",
+			"availability_zone": "az-one",
+			"status":            "confirmed",
+			"transfer_status":   "unlisted",
+			"transfer_token":    transferToken,
+			"created_at":        s.Clock.Now().Unix(),
+			"creator_uuid":      "dummy",
+			"creator_name":      "dummy",
+			"can_be_deleted":    true,
+			"confirmed_at":      s.Clock.Now().Unix(),
+			"expires_at":        committedForOneHour.AddTo(s.Clock.Now()).Unix(),
+			"updated_at":        s.Clock.Now().Unix(),
+		})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/by-transfer-token/wrong-token").
+		ExpectText(t, http.StatusNotFound, "no such commitment\n")
+
+	// accepting requires the transfer token and permission to create commitments in the target project
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/"+transferUUID+"/accept-transfer",
+		httptest.WithJSONBody(map[string]any{"project_id": "uuid-for-dresden"}),
+	).ExpectText(t, http.StatusBadRequest, "missing Transfer-Token header\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/"+transferUUID+"/accept-transfer",
+		httptest.WithJSONBody(map[string]any{"project_id": "uuid-for-dresden"}),
+		httptest.WithHeader("Transfer-Token", "wrong-token"),
+	).ExpectText(t, http.StatusNotFound, "no such commitment\n")
+	s.TokenValidator.Enforcer.AllowCommitmentCreate = false
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/"+transferUUID+"/accept-transfer",
+		httptest.WithJSONBody(map[string]any{"project_id": "uuid-for-dresden"}),
+		httptest.WithHeader("Transfer-Token", transferToken),
+	).ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowCommitmentCreate = true
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/"+transferUUID+"/accept-transfer",
+		httptest.WithJSONBody(map[string]any{"project_id": "uuid-for-berlin"}),
+		httptest.WithHeader("Transfer-Token", transferToken),
+	).ExpectText(t, http.StatusUnprocessableEntity, "commitment is already located in the target project\n")
+
+	// the target project must accept commitments of this duration
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/"+transferUUID+"/accept-transfer",
+		httptest.WithJSONBody(map[string]any{"project_id": "uuid-for-paris"}),
+		httptest.WithHeader("Transfer-Token", transferToken),
+	).ExpectText(t, http.StatusUnprocessableEntity, "commitments are not enabled for this resource\n")
+
+	// successful transfer
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/"+transferUUID+"/accept-transfer",
+		httptest.WithJSONBody(map[string]any{"project_id": "uuid-for-dresden"}),
+		httptest.WithHeader("Transfer-Token", transferToken),
+	).ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"uuid":              transferUUID,
+		"amount":            4,
+		"duration":          "1 hour",
+		"project_id":        "uuid-for-dresden",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "confirmed",
+		"created_at":        s.Clock.Now().Unix(),
+		"creator_uuid":      "dummy",
+		"creator_name":      "dummy",
+		"can_be_deleted":    true,
+		"confirmed_at":      s.Clock.Now().Unix(),
+		"expires_at":        committedForOneHour.AddTo(s.Clock.Now()).Unix(),
+		"updated_at":        s.Clock.Now().Unix(),
+	})
+	assert.Equal(t, len(s.Auditor.RecordedEvents()), 2) // one event each for the source and target project
+
+	// the transfer token cannot be used again
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/by-transfer-token/"+transferToken).
+		ExpectText(t, http.StatusNotFound, "no such commitment\n")
+
+	// when a commitment that is already offered as a whole gets split for a new offer,
+	// the previous offer ends together with the original commitment
+	s.MustDBInsert(&db.ProjectCommitment{
+		UUID:                "00000000-0000-0000-0000-000000000002",
+		ProjectID:           s.GetProjectID("berlin"),
+		AZResourceID:        s.GetAZResourceID("first", "capacity", "az-one"),
+		Amount:              10,
+		Duration:            committedForOneHour,
+		CreatedAt:           s.Clock.Now(),
+		UpdatedAt:           s.Clock.Now(),
+		CreatorUUID:         "dummy",
+		CreatorName:         "dummy",
+		ConfirmedAt:         Some(s.Clock.Now()),
+		ExpiresAt:           committedForOneHour.AddTo(s.Clock.Now()),
+		CreationContextJSON: json.RawMessage(`{}`),
+		Status:              liquid.CommitmentStatusConfirmed,
+	})
+	var previousTransferToken string
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000002/start-transfer",
+		httptest.WithJSONBody(map[string]any{"amount": 10, "transfer_status": "public"}),
+	).ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+		"uuid":              "00000000-0000-0000-0000-000000000002",
+		"amount":            10,
+		"duration":          "1 hour",
+		"project_id":        "uuid-for-berlin",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "confirmed",
+		"transfer_status":   "public",
+		"transfer_token":    jsonmatch.CaptureField(&previousTransferToken),
+		"created_at":        s.Clock.Now().Unix(),
+		"creator_uuid":      "dummy",
+		"creator_name":      "dummy",
+		"can_be_deleted":    true,
+		"confirmed_at":      s.Clock.Now().Unix(),
+		"expires_at":        committedForOneHour.AddTo(s.Clock.Now()).Unix(),
+		"updated_at":        s.Clock.Now().Unix(),
+	})
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000002/start-transfer",
+		httptest.WithJSONBody(map[string]any{"amount": 3, "transfer_status": "unlisted"}),
+	).ExpectStatus(t, http.StatusOK)
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/00000000-0000-0000-0000-000000000002").
+		ExpectJSON(t, http.StatusOK, jsonmatch.Object{
+			"uuid":              "00000000-0000-0000-0000-000000000002",
+			"amount":            10,
+			"duration":          "1 hour",
+			"project_id":        "uuid-for-berlin",
+			"service_type":      "first",
+			"resource_name":     "capacity",
+			"availability_zone": "az-one",
+			"status":            "superseded",
+			"created_at":        s.Clock.Now().Unix(),
+			"creator_uuid":      "dummy",
+			"creator_name":      "dummy",
+			"can_be_deleted":    true,
+			"confirmed_at":      s.Clock.Now().Unix(),
+			"expires_at":        committedForOneHour.AddTo(s.Clock.Now()).Unix(),
+			"updated_at":        s.Clock.Now().Unix(),
+		})
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/commitments/by-transfer-token/"+previousTransferToken).
+		ExpectText(t, http.StatusNotFound, "no such commitment\n")
+	s.Handler.RespondTo(s.Ctx, "POST /resources/v2/commitments/00000000-0000-0000-0000-000000000002/accept-transfer",
+		httptest.WithJSONBody(map[string]any{"project_id": "uuid-for-dresden"}),
+		httptest.WithHeader("Transfer-Token", previousTransferToken),
+	).ExpectText(t, http.StatusNotFound, "no such commitment\n")
+}
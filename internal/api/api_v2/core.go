@@ -6,12 +6,17 @@ package api_v2
 import (
 	"bytes"
 	"cmp"
+	"context"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"reflect"
+	"strings"
 	"time"
 
 	"github.com/go-gorp/gorp/v3"
@@ -20,9 +25,14 @@ import (
 	"github.com/sapcc/go-bits/audittools"
 	"github.com/sapcc/go-bits/gopherpolicy"
 	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/logg"
 	"github.com/sapcc/go-bits/respondwith"
 	. "go.xyrillian.de/gg/option"
 
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	ratesv2 "github.com/sapcc/limes/apideclarations/apiv2/rates"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+	"github.com/sapcc/limes/internal/api/reports_v2"
 	"github.com/sapcc/limes/internal/core"
 	"github.com/sapcc/limes/internal/db"
 )
@@ -57,20 +67,46 @@ func (p *v2Provider) AddTo(r *mux.Router) {
 	}
 
 	tv := p.tokenValidator
-	resRouter.Methods("GET").Path("/info").HandlerFunc(handlerFunc(http.StatusOK, tv, p.handleGetResourcesInfo))
-	resRouter.Methods("GET").Path("/cluster").HandlerFunc(handlerFunc(http.StatusOK, tv, p.handleGetResourcesCluster))
-	resRouter.Methods("GET").Path("/domains").HandlerFunc(handlerFunc(http.StatusOK, tv, p.handleGetResourcesDomains))
-	resRouter.Methods("GET").Path("/domains/{domain_uuid}").HandlerFunc(handlerFunc(http.StatusOK, tv, p.handleGetResourcesDomain))
-	resRouter.Methods("GET").Path("/projects").HandlerFunc(handlerFunc(http.StatusOK, tv, p.handleGetResourcesProjects))
-	resRouter.Methods("GET").Path("/projects/{project_uuid}").HandlerFunc(handlerFunc(http.StatusOK, tv, p.handleGetResourcesProject))
-	resRouter.Methods("POST").Path("/commitments/new").HandlerFunc(handlerFunc(http.StatusCreated, tv, p.handlePostNewCommitment))
-
-	ratesRouter.Methods("GET").Path("/info").HandlerFunc(handlerFunc(http.StatusOK, tv, p.handleGetRatesInfo))
-	ratesRouter.Methods("GET").Path("/cluster").HandlerFunc(handlerFunc(http.StatusOK, tv, p.handleGetRatesCluster))
-	ratesRouter.Methods("GET").Path("/domains").HandlerFunc(handlerFunc(http.StatusOK, tv, p.handleGetRatesDomains))
-	ratesRouter.Methods("GET").Path("/domains/{domain_uuid}").HandlerFunc(handlerFunc(http.StatusOK, tv, p.handleGetRatesDomain))
-	ratesRouter.Methods("GET").Path("/projects").HandlerFunc(handlerFunc(http.StatusOK, tv, p.handleGetRatesProjects))
-	ratesRouter.Methods("GET").Path("/projects/{project_uuid}").HandlerFunc(handlerFunc(http.StatusOK, tv, p.handleGetRatesProject))
+	resRouter.Methods("GET").Path("/info").Handler(handlerFunc(http.StatusOK, tv, p.handleGetResourcesInfo))
+	resRouter.Methods("GET").Path("/cluster").Handler(handlerFunc(http.StatusOK, tv, p.handleGetResourcesCluster).withQueryOpts(common.ClusterResourceReportOpts{}))
+	resRouter.Methods("GET").Path("/domains").Handler(handlerFunc(http.StatusOK, tv, p.handleGetResourcesDomains).withQueryOpts(common.DomainResourceReportOpts{}))
+	resRouter.Methods("GET").Path("/domains/{domain_uuid}").Handler(handlerFunc(http.StatusOK, tv, p.handleGetResourcesDomain).withQueryOpts(common.DomainResourceReportOpts{}))
+	resRouter.Methods("GET").Path("/projects").Handler(handlerFunc(http.StatusOK, tv, p.handleGetResourcesProjects).withQueryOpts(common.ProjectResourceReportOpts{}))
+	resRouter.Methods("GET").Path("/projects/{project_uuid}").Handler(handlerFunc(http.StatusOK, tv, p.handleGetResourcesProject).withQueryOpts(common.ProjectResourceReportOpts{}))
+	resRouter.Methods("PUT").Path("/projects/{project_uuid}/max-quota").Handler(handlerFunc(http.StatusNoContent, tv, p.handlePutProjectMaxQuota).withRequestBody(resourcesv2.ProjectMaxQuotaRequest{}))
+	resRouter.Methods("PUT").Path("/projects/{project_uuid}/forbid-autogrowth").Handler(handlerFunc(http.StatusNoContent, tv, p.handlePutProjectForbidAutogrowth).withRequestBody(resourcesv2.ProjectForbidAutogrowthRequest{}))
+	resRouter.Methods("GET").Path("/availability").Handler(handlerFunc(http.StatusOK, tv, p.handleGetResourcesAvailability).withQueryOpts(common.AvailabilityReportOpts{}))
+	resRouter.Methods("GET").Path("/admin/inconsistencies").Handler(handlerFunc(http.StatusOK, tv, p.handleGetInconsistencies).withQueryOpts(common.AdminReportOpts{}))
+	resRouter.Methods("GET").Path("/admin/scrape-errors").Handler(handlerFunc(http.StatusOK, tv, p.handleGetScrapeErrors).withQueryOpts(common.AdminReportOpts{}))
+	resRouter.Methods("GET").Path("/admin/archived-commitments").Handler(handlerFunc(http.StatusOK, tv, p.handleGetArchivedCommitments).withQueryOpts(common.ArchivedCommitmentListOpts{}))
+	resRouter.Methods("GET").Path("/commitments").Handler(handlerFunc(http.StatusOK, tv, p.handleGetCommitments).withQueryOpts(common.CommitmentListOpts{}))
+	resRouter.Methods("POST").Path("/commitments/new").Handler(handlerFunc(http.StatusCreated, tv, withIdempotencyKey(p, p.handlePostNewCommitment)).withRequestBody(resourcesv2.CommitmentRequest{}).withIdempotencyKeyHeader())
+	resRouter.Methods("POST").Path("/commitments/batch").Handler(handlerFunc(http.StatusCreated, tv, withIdempotencyKey(p, p.handlePostCommitmentBatch)).withRequestBody(resourcesv2.CommitmentBatchRequest{}).withIdempotencyKeyHeader())
+	resRouter.Methods("POST").Path("/commitments/merge").Handler(handlerFunc(http.StatusOK, tv, withIdempotencyKey(p, p.handlePostCommitmentMerge)).withRequestBody(resourcesv2.CommitmentMergeRequest{}).withIdempotencyKeyHeader())
+	resRouter.Methods("GET").Path("/commitments/{uuid}").Handler(handlerFunc(http.StatusOK, tv, p.handleGetCommitment))
+	resRouter.Methods("DELETE").Path("/commitments/{uuid}").Handler(handlerFunc(http.StatusNoContent, tv, withIdempotencyKey(p, p.handleDeleteCommitment)).withIdempotencyKeyHeader())
+	resRouter.Methods("GET").Path("/commitments/{uuid}/lineage").Handler(handlerFunc(http.StatusOK, tv, p.handleGetCommitmentLineage))
+	resRouter.Methods("POST").Path("/commitments/{uuid}/split").Handler(handlerFunc(http.StatusOK, tv, withIdempotencyKey(p, p.handlePostCommitmentSplit)).withRequestBody(resourcesv2.CommitmentSplitRequest{}).withIdempotencyKeyHeader())
+	resRouter.Methods("POST").Path("/commitments/{uuid}/convert").Handler(handlerFunc(http.StatusOK, tv, withIdempotencyKey(p, p.handlePostCommitmentConvert)).withRequestBody(resourcesv2.CommitmentConvertRequest{}).withIdempotencyKeyHeader())
+	resRouter.Methods("POST").Path("/commitments/{uuid}/amend").Handler(handlerFunc(http.StatusOK, tv, withIdempotencyKey(p, p.handlePostCommitmentAmend)).withRequestBody(resourcesv2.CommitmentAmendRequest{}).withIdempotencyKeyHeader())
+	resRouter.Methods("POST").Path("/commitments/{uuid}/start-transfer").Handler(handlerFunc(http.StatusOK, tv, withIdempotencyKey(p, p.handlePostCommitmentStartTransfer)).withRequestBody(resourcesv2.CommitmentTransferRequest{}).withIdempotencyKeyHeader())
+	resRouter.Methods("GET").Path("/commitments/by-transfer-token/{token}").Handler(handlerFunc(http.StatusOK, tv, p.handleGetCommitmentByTransferToken))
+	resRouter.Methods("POST").Path("/commitments/{uuid}/accept-transfer").Handler(handlerFunc(http.StatusOK, tv, withIdempotencyKey(p, p.handlePostCommitmentAcceptTransfer)).withRequestBody(resourcesv2.CommitmentAcceptTransferRequest{}).withIdempotencyKeyHeader().withHeader("Transfer-Token", true))
+
+	ratesRouter.Methods("GET").Path("/info").Handler(handlerFunc(http.StatusOK, tv, p.handleGetRatesInfo))
+	ratesRouter.Methods("GET").Path("/cluster").Handler(handlerFunc(http.StatusOK, tv, p.handleGetRatesCluster).withQueryOpts(common.ClusterRateReportOpts{}))
+	ratesRouter.Methods("GET").Path("/domains").Handler(handlerFunc(http.StatusOK, tv, p.handleGetRatesDomains).withQueryOpts(common.DomainRateReportOpts{}))
+	ratesRouter.Methods("GET").Path("/domains/{domain_uuid}").Handler(handlerFunc(http.StatusOK, tv, p.handleGetRatesDomain).withQueryOpts(common.DomainRateReportOpts{}))
+	ratesRouter.Methods("GET").Path("/projects").Handler(handlerFunc(http.StatusOK, tv, p.handleGetRatesProjects).withQueryOpts(common.ProjectRateReportOpts{}))
+	ratesRouter.Methods("GET").Path("/projects/{project_uuid}").Handler(handlerFunc(http.StatusOK, tv, p.handleGetRatesProject).withQueryOpts(common.ProjectRateReportOpts{}))
+	ratesRouter.Methods("PUT").Path("/projects/{project_uuid}").Handler(handlerFunc(http.StatusNoContent, tv, p.handlePutRatesProject).withRequestBody(ratesv2.ProjectRateLimitRequest{}))
+
+	// this is served without authentication, since it only describes the API itself
+	var openAPIHandler http.Handler = serveOpenAPIDocument(resRouter, ratesRouter)
+	if apiDomainNames, ok := p.DomainNames.Unpack(); ok {
+		openAPIHandler = EnforceDomainName(apiDomainNames.V2)(openAPIHandler)
+	}
+	r.Methods("GET").Path("/v2/openapi.json").Handler(openAPIHandler)
 }
 
 // Wrapper for request handlers that enforces a structure,
@@ -79,11 +115,17 @@ func (p *v2Provider) AddTo(r *mux.Router) {
 //
 // This wrapper also performs AuthN and provides a parsed token to the actual request handler.
 // The wrapper needs the token to decide whether to use error obfuscation.
-func handlerFunc[T any](successCode int, tv gopherpolicy.Validator, action func(*http.Request, *gopherpolicy.Token) (T, error)) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
+//
+// The returned endpoint also remembers the response type, which is used to generate the OpenAPI document.
+func handlerFunc[T any](successCode int, tv gopherpolicy.Validator, action func(*http.Request, *gopherpolicy.Token) (T, error)) endpoint {
+	serve := func(w http.ResponseWriter, r *http.Request) {
 		t := tv.CheckToken(r)
 		t.Context.Request = mux.Vars(r)
 
+		// request handlers for reports can fill this through checkReportVersion()
+		version := &responseVersion{}
+		r = r.WithContext(context.WithValue(r.Context(), responseVersionKey{}, version))
+
 		var (
 			resp T
 			err  error
@@ -99,6 +141,18 @@ func handlerFunc[T any](successCode int, tv gopherpolicy.Validator, action func(
 		}
 
 		if err != nil {
+			if errors.Is(err, errNotModified) {
+				w.Header().Set("ETag", version.ETag.UnwrapOr(""))
+				w.WriteHeader(http.StatusNotModified)
+				return
+			}
+
+			var bodyErr errorWithJSONBody
+			if errors.As(err, &bodyErr) {
+				respondwith.JSON(w, bodyErr.StatusCode(), bodyErr.ResponseBody())
+				return
+			}
+
 			// This is intentionally a double-negative: If the rule does not exist,
 			// Check() returns false, and we get the safe behavior of obfuscating everything.
 			if t.Check("v2:meta:no_error_obfuscation") {
@@ -109,24 +163,179 @@ func handlerFunc[T any](successCode int, tv gopherpolicy.Validator, action func(
 			return
 		}
 
-		if successCode == http.StatusNoContent {
+		switch {
+		case successCode == http.StatusNoContent:
 			w.WriteHeader(http.StatusNoContent)
-		} else {
+		case r.Method == http.MethodGet && successCode == http.StatusOK:
+			respondWithETag(w, r, resp, version.ETag)
+		default:
 			respondwith.JSON(w, successCode, resp)
 		}
 	}
+	return endpoint{
+		serve:        serve,
+		successCode:  successCode,
+		responseType: reflect.TypeFor[T](),
+	}
+}
+
+// endpoint is the http.Handler returned by handlerFunc.
+// Besides serving requests, it describes the endpoint's request and response types for the OpenAPI document.
+type endpoint struct {
+	serve        http.HandlerFunc
+	successCode  int
+	responseType reflect.Type
+	queryType    Option[reflect.Type]
+	requestType  Option[reflect.Type]
+	headers      []endpointHeader
+}
+
+// endpointHeader describes a request header that is interpreted by the request handler.
+type endpointHeader struct {
+	name     string
+	required bool
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (e endpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.serve(w, r)
+}
+
+// withQueryOpts declares the type that the request handler uses with opts.ParseQueryString().
+// This is only used for generating the OpenAPI document.
+func (e endpoint) withQueryOpts(opts any) endpoint {
+	e.queryType = Some(reflect.TypeOf(opts))
+	return e
+}
+
+// withRequestBody declares the type that the request handler uses with parseRequestBodyAs().
+// This is only used for generating the OpenAPI document.
+func (e endpoint) withRequestBody(body any) endpoint {
+	e.requestType = Some(reflect.TypeOf(body))
+	return e
+}
+
+// withHeader declares a request header that the request handler reads.
+// This is only used for generating the OpenAPI document.
+func (e endpoint) withHeader(name string, required bool) endpoint {
+	e.headers = append(e.headers, endpointHeader{name, required})
+	return e
+}
+
+// withIdempotencyKeyHeader declares the optional header that is read by withIdempotencyKey().
+// This is only used for generating the OpenAPI document.
+func (e endpoint) withIdempotencyKeyHeader() endpoint {
+	return e.withHeader("Idempotency-Key", false)
+}
+
+// respondWithETag is like respondwith.JSON with status 200, but it also sets an ETag.
+// If the ETag matches the If-None-Match header of the request, 304 (Not Modified) is returned without a response body.
+//
+// If the request handler has not provided an ETag through checkReportVersion(), the ETag is computed from the response body.
+// This does not avoid the cost of computing the response, but it saves bandwidth and parsing effort
+// for clients that poll the same endpoint over and over again.
+func respondWithETag(w http.ResponseWriter, r *http.Request, data any, knownETag Option[string]) {
+	buf, err := json.Marshal(data)
+	if err != nil {
+		// defense in depth: our response types should always be serializable
+		respondwith.ObfuscatedErrorText(w, fmt.Errorf("could not serialize response body: %w", err))
+		return
+	}
+	buf = append(buf, '\n') // for consistency with respondwith.JSON
+
+	etag, ok := knownETag.Unpack()
+	if !ok {
+		checksum := sha256.Sum256(buf)
+		etag = `"` + hex.EncodeToString(checksum[:16]) + `"`
+	}
+	w.Header().Set("ETag", etag)
+	if etagMatches(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(buf)
+	if err != nil {
+		logg.Error("could not write response body: %s", err.Error())
+	}
+}
+
+// responseVersionKey is the context key under which handlerFunc provides a *responseVersion to the request handler.
+type responseVersionKey struct{}
+
+// responseVersion is filled by checkReportVersion() to tell handlerFunc which ETag to use for the response.
+type responseVersion struct {
+	ETag Option[string]
+}
+
+// errNotModified is returned by checkReportVersion() when the client already has the current version of the report.
+// handlerFunc renders it as 304 (Not Modified).
+var errNotModified = errors.New("not modified")
+
+// checkReportVersion shall be called by request handlers for reports before building the report.
+// It derives the ETag of the report from reports_v2.GetReportVersion() instead of from the rendered report,
+// so that conditional requests can be answered without running the expensive report queries.
+//
+// If the data changes while the report is being built, the report will be newer than its ETag.
+// This is harmless since the client will receive the report again on its next conditional request.
+func (p *v2Provider) checkReportVersion(r *http.Request, token *gopherpolicy.Token, scope reports_v2.Scope) error {
+	dataVersion, err := reports_v2.GetReportVersion(p.DB, scope)
+	if err != nil {
+		return err
+	}
+
+	// besides the data, the report depends on the query options and on what the requesting user is allowed to see
+	checksum := sha256.Sum256([]byte(strings.Join([]string{
+		r.URL.Path, r.URL.RawQuery, token.UserUUID(), token.ProjectScopeUUID(), token.DomainScopeUUID(), dataVersion,
+	}, "\n")))
+	etag := `"` + hex.EncodeToString(checksum[:16]) + `"`
+
+	if version, ok := r.Context().Value(responseVersionKey{}).(*responseVersion); ok {
+		version.ETag = Some(etag)
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			return errNotModified
+		}
+	}
+	return nil
+}
+
+// etagMatches evaluates an If-None-Match header against the ETag of the current response.
+// As required by RFC 9110, section 13.1.2, the weak comparison function is used.
+func etagMatches(ifNoneMatch, etag string) bool {
+	for candidate := range strings.SplitSeq(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// errorWithJSONBody is an error that handlerFunc renders as a structured JSON response body instead of as plain text.
+// This is used when an error response needs to explain more than a single error message, e.g. one error per requested rate limit.
+type errorWithJSONBody interface {
+	error
+	StatusCode() int
+	ResponseBody() any
 }
 
 // parseRequestBodyAs unmarshals a JSON-encoded request body.
 func parseRequestBodyAs[T any](r *http.Request) (T, error) {
+	// To guard against complexity attacks using extremely large request bodies,
+	// we never read more than 8 KiB. Except for batch requests, there are no
+	// request types in the v2 API that could ever require more than that.
+	return parseRequestBodyWithSizeLimitAs[T](r, 8192)
+}
+
+// parseRequestBodyWithSizeLimitAs is like parseRequestBodyAs, but allows to choose a different maximum size for the request body.
+// This is only intended for batch requests, whose size scales with the number of items in the batch.
+func parseRequestBodyWithSizeLimitAs[T any](r *http.Request, maxRequestSize int) (T, error) {
 	// TODO: With how clever this function is now, it probably should be in go-bits.
 	var result T
 
-	// To guard against complexity attacks using extremely large request bodies,
-	// we never read more than 8 KiB. There are no request types in the v2 API
-	// that could ever require more than that.
-	const maxRequestSize = 8192
-	buf, err := io.ReadAll(io.LimitReader(r.Body, maxRequestSize))
+	buf, err := io.ReadAll(io.LimitReader(r.Body, int64(maxRequestSize)))
 	if err != nil {
 		return result, fmt.Errorf("while reading request body: %w", err)
 	}
@@ -8,8 +8,10 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/sapcc/go-bits/httptest"
+	"go.xyrillian.de/gg/assert"
 
 	"github.com/sapcc/limes/internal/api/api_v2"
 	"github.com/sapcc/limes/internal/test"
@@ -74,3 +76,69 @@ func TestDomainNameSeparation(t *testing.T) {
 		})
 	}
 }
+
+func TestConditionalRequests(t *testing.T) {
+	ctx := t.Context()
+	s := test.NewSetup(t,
+		test.WithConfig(commitmentCreateConfigJSON),
+		test.WithPersistedServiceInfo("first", test.DefaultLiquidServiceInfo("First")),
+		test.WithInitialDiscovery,
+		test.WithEmptyResourceRecordsAsNeeded,
+	)
+
+	// reports carry an ETag derived from the response body
+	var etag string
+	s.Handler.RespondTo(ctx, "GET /resources/v2/info").
+		CaptureHeader("ETag", &etag).
+		ExpectStatus(t, http.StatusOK)
+	assert.Equal(t, strings.HasPrefix(etag, `"`) && strings.HasSuffix(etag, `"`), true)
+
+	// the same report has the same ETag, so conditional requests do not receive the report again
+	for _, ifNoneMatch := range []string{etag, "W/" + etag, `"something-else", ` + etag, "*"} {
+		resp := s.Handler.RespondTo(ctx, "GET /resources/v2/info", httptest.WithHeader("If-None-Match", ifNoneMatch)).
+			ExpectHeader(t, "ETag", etag)
+		resp.ExpectStatus(t, http.StatusNotModified)
+		assert.Equal(t, resp.BodyString(), "")
+	}
+
+	// when the ETag does not match, the full report is returned
+	s.Handler.RespondTo(ctx, "GET /resources/v2/info", httptest.WithHeader("If-None-Match", `"something-else"`)).
+		ExpectHeader(t, "ETag", etag).
+		ExpectStatus(t, http.StatusOK)
+	s.Handler.RespondTo(ctx, "GET /resources/v2/cluster", httptest.WithHeader("If-None-Match", etag)).
+		ExpectStatus(t, http.StatusOK)
+
+	// reports derive their ETag from the underlying data, so conditional requests are answered without building the report
+	var reportETag string
+	s.Handler.RespondTo(ctx, "GET /resources/v2/projects/uuid-for-berlin").
+		CaptureHeader("ETag", &reportETag).
+		ExpectStatus(t, http.StatusOK)
+	resp := s.Handler.RespondTo(ctx, "GET /resources/v2/projects/uuid-for-berlin", httptest.WithHeader("If-None-Match", reportETag)).
+		ExpectHeader(t, "ETag", reportETag)
+	resp.ExpectStatus(t, http.StatusNotModified)
+	assert.Equal(t, resp.BodyString(), "")
+
+	// the ETag of a report depends on the query options...
+	s.Handler.RespondTo(ctx, "GET /resources/v2/projects/uuid-for-berlin?service=first", httptest.WithHeader("If-None-Match", reportETag)).
+		ExpectStatus(t, http.StatusOK)
+	// ...and on data that was changed by a scrape...
+	s.Clock.StepBy(time.Hour)
+	s.MustDBExec(`UPDATE project_services SET scraped_at = $1 WHERE project_id = $2`, s.Clock.Now(), s.GetProjectID("berlin"))
+	s.Handler.RespondTo(ctx, "GET /resources/v2/projects/uuid-for-berlin", httptest.WithHeader("If-None-Match", reportETag)).
+		CaptureHeader("ETag", &reportETag).
+		ExpectStatus(t, http.StatusOK)
+	// ...or without leaving a timestamp behind
+	s.MustDBExec(`UPDATE project_resources SET max_quota_from_outside_admin = 500 WHERE project_id = $1`, s.GetProjectID("berlin"))
+	s.Handler.RespondTo(ctx, "GET /resources/v2/projects/uuid-for-berlin", httptest.WithHeader("If-None-Match", reportETag)).
+		CaptureHeader("ETag", &reportETag).
+		ExpectStatus(t, http.StatusOK)
+	// but not on data outside of the report's scope
+	s.MustDBExec(`UPDATE project_services SET scraped_at = $1 WHERE project_id = $2`, s.Clock.Now(), s.GetProjectID("paris"))
+	s.Handler.RespondTo(ctx, "GET /resources/v2/projects/uuid-for-berlin", httptest.WithHeader("If-None-Match", reportETag)).
+		ExpectStatus(t, http.StatusNotModified)
+
+	// error responses do not carry an ETag
+	s.Handler.RespondTo(ctx, "GET /resources/v2/projects/does-not-exist", httptest.WithHeader("If-None-Match", "*")).
+		ExpectHeader(t, "ETag", "").
+		ExpectStatus(t, http.StatusNotFound)
+}
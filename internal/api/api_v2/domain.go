@@ -12,10 +12,10 @@ import (
 
 	. "go.xyrillian.de/gg/option"
 
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	ratesv2 "github.com/sapcc/limes/apideclarations/apiv2/rates"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
 	"github.com/sapcc/limes/internal/api/reports_v2"
-	"github.com/sapcc/limes/internal/apideclarations/apiv2/common"
-	ratesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/rates"
-	resourcesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/resources"
 )
 
 // handleGetResourcesDomains handles GET /resources/v2/domains.
@@ -27,7 +27,7 @@ func (p *v2Provider) handleGetResourcesDomains(r *http.Request, token *gopherpol
 	if err != nil {
 		return none, err
 	}
-	_, err = reports_v2.NewScope(false, r, None[string](), token, p.DB)
+	scope, err := reports_v2.NewScope(false, r, None[string](), token, p.DB)
 	if err != nil {
 		return none, err
 	}
@@ -35,11 +35,19 @@ func (p *v2Provider) handleGetResourcesDomains(r *http.Request, token *gopherpol
 	if err != nil {
 		return none, err
 	}
-	_, err = reports_v2.FilterFromResourceOpts(p.Cluster, options.ResourceReportOpts)
+	filter, err := reports_v2.FilterFromResourceOpts(p.Cluster, options.ResourceReportOpts)
+	if err != nil {
+		return none, err
+	}
+	err = p.checkReportVersion(r, token, scope)
+	if err != nil {
+		return none, err
+	}
+	result, err := reports_v2.GetDomainResources(p.Cluster, token, p.timeNow(), filter, options, scope)
 	if err != nil {
 		return none, err
 	}
-	return none, nil
+	return result, nil
 }
 
 // handleGetResourcesDomain handles GET /resources/v2/domains/:domain_uuid.
@@ -51,7 +59,7 @@ func (p *v2Provider) handleGetResourcesDomain(r *http.Request, token *gopherpoli
 	if err != nil {
 		return none, err
 	}
-	_, err = reports_v2.NewScope(false, r, None[string](), token, p.DB)
+	scope, err := reports_v2.NewScope(false, r, None[string](), token, p.DB)
 	if err != nil {
 		return none, err
 	}
@@ -59,11 +67,19 @@ func (p *v2Provider) handleGetResourcesDomain(r *http.Request, token *gopherpoli
 	if err != nil {
 		return none, err
 	}
-	_, err = reports_v2.FilterFromResourceOpts(p.Cluster, options.ResourceReportOpts)
+	filter, err := reports_v2.FilterFromResourceOpts(p.Cluster, options.ResourceReportOpts)
 	if err != nil {
 		return none, err
 	}
-	return none, nil
+	err = p.checkReportVersion(r, token, scope)
+	if err != nil {
+		return none, err
+	}
+	result, err := reports_v2.GetDomainResources(p.Cluster, token, p.timeNow(), filter, options, scope)
+	if err != nil {
+		return none, err
+	}
+	return result, nil
 }
 
 // handleGetRatesDomains handles GET /rates/v2/domains.
@@ -98,6 +114,10 @@ func (p *v2Provider) commonHandleGetRatesDomain(r *http.Request, token *gopherpo
 	if err != nil {
 		return none, err
 	}
+	err = p.checkReportVersion(r, token, scope)
+	if err != nil {
+		return none, err
+	}
 	result, err := reports_v2.GetDomainRates(p.Cluster, token, filter, options, scope)
 	if err != nil {
 		return none, err
@@ -15,6 +15,55 @@ import (
 	"github.com/sapcc/limes/internal/test"
 )
 
+func TestV2DomainResourceReport(t *testing.T) {
+	s := setupResourceReportTest(t)
+	fixturePath := "./fixtures/resource-domains.json"
+
+	s.TokenValidator.Enforcer.AllowReportMultiple = false
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/domains").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowReportMultiple = true
+	s.TokenValidator.Enforcer.AllowReportSingle = false
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/domains/uuid-for-france").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowReportSingle = true
+
+	// the maximum result set includes the commitment stats
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/domains?with=commitment_stats").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "with commitment stats"))
+
+	// without commitment stats
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/domains").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "no params").
+			Modify("del(.domains[].service_areas[].services[].categories[].resources[].availability_zones[] | .committed, .committed_confirmed_unutilized, .uncommitted_usage)"))
+
+	// one domain
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/domains/uuid-for-france?with=commitment_stats").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "domain filter france").
+			Modify(`del(.domains["uuid-for-germany"])`))
+	// the other domain
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/domains/uuid-for-germany?with=commitment_stats").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "domain filter germany").
+			Modify(`del(.domains["uuid-for-france"])`))
+
+	// filtering
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/domains?with=commitment_stats&area=first").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "area filter").
+			Modify("del(.domains[].service_areas.second)"))
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/domains?with=commitment_stats&category=foo_category").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "category filter").
+			Modify("del(.domains[].service_areas.first.services.first.categories.first)").
+			Modify("del(.domains[].service_areas.second.services.second.categories.second)"))
+
+	// scraped_at filter: no project has been scraped yet, so the report is empty
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/domains?min_scraped_at=1970-01-01T00:00:00Z").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "min_scraped_at filter").
+			Modify(".domains=null"))
+
+	// unknown domain
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/domains/does-not-exists").ExpectText(t, http.StatusNotFound, "no such domain (UUID = does-not-exists)\n")
+}
+
 func TestV2DomainRateReport(t *testing.T) {
 	srvInfoFirst := test.DefaultLiquidServiceInfo("First")
 	srvInfoFirst.Rates = map[liquid.RateName]liquid.RateInfo{
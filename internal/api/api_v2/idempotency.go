@@ -0,0 +1,171 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/logg"
+	"github.com/sapcc/go-bits/respondwith"
+	"github.com/sapcc/go-bits/sqlext"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/internal/db"
+)
+
+const (
+	// maxIdempotencyKeyLength limits the size of the Idempotency-Key header.
+	maxIdempotencyKeyLength = 255
+	// idempotencyKeyReservationTimeout is how long a request may hold the reservation of its Idempotency-Key
+	// without having completed. Afterwards, we assume that the request has been aborted (e.g. by a restart of
+	// the API process), and a retry of the request may take over the reservation.
+	idempotencyKeyReservationTimeout = 5 * time.Minute
+)
+
+var (
+	errIdempotencyKeyInProgress = errors.New("a request with the same Idempotency-Key is still being processed")
+	errIdempotencyKeyReused     = errors.New("this Idempotency-Key has already been used for a different request")
+)
+
+var (
+	// expired keys are deleted by the collector eventually, but may already be reused before that
+	idempotencyKeyReleaseExpiredQuery = `DELETE FROM idempotency_keys WHERE user_uuid = $1 AND key = $2 AND expires_at <= $3`
+	idempotencyKeyReserveQuery        = sqlext.SimplifyWhitespace(`
+		INSERT INTO idempotency_keys (user_uuid, key, request_hash, created_at, reserved_at, expires_at)
+		VALUES ($1, $2, $3, $4, $4, $5)
+		ON CONFLICT (user_uuid, key) DO UPDATE SET reserved_at = EXCLUDED.reserved_at
+		WHERE idempotency_keys.request_hash = EXCLUDED.request_hash
+		  AND idempotency_keys.response_json IS NULL
+		  AND idempotency_keys.reserved_at <= $6
+	`)
+	idempotencyKeyLookupQuery   = `SELECT request_hash, response_json FROM idempotency_keys WHERE user_uuid = $1 AND key = $2`
+	idempotencyKeyCompleteQuery = `UPDATE idempotency_keys SET response_json = $1 WHERE user_uuid = $2 AND key = $3 AND reserved_at = $4`
+	idempotencyKeyReleaseQuery  = `DELETE FROM idempotency_keys WHERE user_uuid = $1 AND key = $2 AND reserved_at = $3`
+)
+
+// withIdempotencyKey wraps the request handler of a commitment-mutating endpoint.
+// If the request carries an Idempotency-Key header, the handler is executed at most once per user and key:
+// The response of a successful execution is remembered, and returned again when the request is replayed.
+// Reusing the key for a request with a different method, path or body is an error.
+//
+// Failed requests are not remembered, so they can be retried with the same key.
+// The same goes for requests that do not complete within idempotencyKeyReservationTimeout.
+func withIdempotencyKey[T any](p *v2Provider, action func(*http.Request, *gopherpolicy.Token) (T, error)) func(*http.Request, *gopherpolicy.Token) (T, error) {
+	return func(r *http.Request, token *gopherpolicy.Token) (T, error) {
+		var none T // used on error return paths only
+		key := r.Header.Get("Idempotency-Key")
+		if key == "" {
+			return action(r, token)
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			err := fmt.Errorf("value of Idempotency-Key header may not be longer than %d bytes", maxIdempotencyKeyLength)
+			return none, respondwith.CustomStatus(http.StatusBadRequest, err)
+		}
+
+		requestHash, err := hashRequestForIdempotencyKey(r)
+		if err != nil {
+			return none, err
+		}
+		userUUID := token.UserUUID()
+		// the DB only stores timestamps with microsecond precision, but we need to match on `reserved_at` exactly below
+		now := p.timeNow().Truncate(time.Microsecond)
+		expiresAt := now.Add(p.Cluster.Config.IdempotencyKeyRetentionPeriod())
+
+		// try to reserve the key for this request (or take over the reservation of an identical request that has not completed in time)
+		_, err = p.DB.Exec(idempotencyKeyReleaseExpiredQuery, userUUID, key, now)
+		if err != nil {
+			return none, err
+		}
+		result, err := p.DB.Exec(idempotencyKeyReserveQuery, userUUID, key, requestHash, now, expiresAt, now.Add(-idempotencyKeyReservationTimeout))
+		if err != nil {
+			return none, err
+		}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return none, err
+		}
+		if rowsAffected == 0 {
+			return replayIdempotentRequest[T](p.DB, userUUID, key, requestHash)
+		}
+
+		// if our reservation was taken over in the meantime, completing or releasing it is left to the request that took it over
+		resp, err := action(r, token)
+		if err != nil {
+			_, dbErr := p.DB.Exec(idempotencyKeyReleaseQuery, userUUID, key, now)
+			if dbErr != nil {
+				logg.Error("could not release Idempotency-Key after failed request: %s", dbErr.Error())
+			}
+			return none, err
+		}
+
+		// since the request has already been executed, errors in remembering the response are not reported to the user
+		// (the key stays reserved until the reservation times out, so replays of the request will not be executed again before that)
+		respJSON, err := json.Marshal(resp)
+		if err == nil {
+			_, err = p.DB.Exec(idempotencyKeyCompleteQuery, string(respJSON), userUUID, key, now)
+		}
+		if err != nil {
+			logg.Error("could not remember response for Idempotency-Key: %s", err.Error())
+		}
+		return resp, nil
+	}
+}
+
+// hashRequestForIdempotencyKey computes a fingerprint of the request that is used
+// to recognize whether a request with a known Idempotency-Key is a replay.
+// The request body is restored afterwards, so that the request handler can still read it.
+func hashRequestForIdempotencyKey(r *http.Request) (string, error) {
+	// no endpoint accepts request bodies larger than this, so reading further is not necessary
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBatchRequestSize))
+	if err != nil {
+		return "", respondwith.CustomStatus(http.StatusBadRequest, fmt.Errorf("could not read request body: %w", err))
+	}
+	r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s %s\n", r.Method, r.URL.Path)
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// replayIdempotentRequest returns the remembered response for a request whose Idempotency-Key is already known.
+func replayIdempotentRequest[T any](dbi db.Interface, userUUID, key, requestHash string) (T, error) {
+	var (
+		none         T
+		storedHash   string
+		responseJSON Option[string]
+	)
+	err := dbi.QueryRow(idempotencyKeyLookupQuery, userUUID, key).Scan(&storedHash, &responseJSON)
+	if errors.Is(err, sql.ErrNoRows) {
+		// the original request failed concurrently and released the key again
+		return none, respondwith.CustomStatus(http.StatusConflict, errIdempotencyKeyInProgress)
+	}
+	if err != nil {
+		return none, err
+	}
+	if storedHash != requestHash {
+		return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, errIdempotencyKeyReused)
+	}
+	payload, ok := responseJSON.Unpack()
+	if !ok {
+		return none, respondwith.CustomStatus(http.StatusConflict, errIdempotencyKeyInProgress)
+	}
+
+	var resp T
+	err = json.Unmarshal([]byte(payload), &resp)
+	if err != nil {
+		return none, fmt.Errorf("could not decode remembered response for Idempotency-Key: %w", err)
+	}
+	return resp, nil
+}
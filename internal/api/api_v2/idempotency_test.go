@@ -0,0 +1,193 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sapcc/go-bits/easypg"
+	"github.com/sapcc/go-bits/httptest"
+	"go.xyrillian.de/gg/assert"
+	"go.xyrillian.de/gg/jsonmatch"
+
+	"github.com/sapcc/limes/internal/test"
+)
+
+func TestIdempotencyKeys(t *testing.T) {
+	ctx := t.Context()
+	s := test.NewSetup(t,
+		test.WithConfig(commitmentCreateConfigJSON),
+		test.WithPersistedServiceInfo("first", test.DefaultLiquidServiceInfo("First")),
+		test.WithPersistedServiceInfo("second", test.DefaultLiquidServiceInfo("Second")),
+		test.WithInitialDiscovery,
+		test.WithEmptyResourceRecordsAsNeeded,
+	)
+
+	tr, tr0 := easypg.NewTracker(t, s.DB.Db)
+	tr0.Ignore()
+
+	request := map[string]any{
+		"amount":            1,
+		"duration":          "1 hour",
+		"project_id":        "uuid-for-berlin",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "pending",
+	}
+	expected := func(uuid any) jsonmatch.Object {
+		return jsonmatch.Object{
+			"uuid":              uuid,
+			"amount":            1,
+			"duration":          "1 hour",
+			"project_id":        "uuid-for-berlin",
+			"service_type":      "first",
+			"resource_name":     "capacity",
+			"availability_zone": "az-one",
+			"status":            "pending",
+			"created_at":        s.Clock.Now().Unix(),
+			"creator_uuid":      "uuid-for-alice",
+			"creator_name":      "alice@Default",
+			"can_be_deleted":    true,
+			"confirm_by":        s.Clock.Now().Unix(),
+			"expires_at":        s.Clock.Now().Add(1 * time.Hour).Unix(),
+			"updated_at":        s.Clock.Now().Unix(),
+		}
+	}
+	createdAt := s.Clock.Now()
+
+	// first request with a new key is executed normally
+	var uuid1 string
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/new",
+		httptest.WithJSONBody(request),
+		httptest.WithHeader("Idempotency-Key", "first-key"),
+	).ExpectJSON(t, http.StatusCreated, expected(jsonmatch.CaptureField(&uuid1)))
+	s.Auditor.IgnoreEventsUntilNow()
+	tr.DBChanges().Ignore()
+
+	// replaying the request returns the original response without creating another commitment (even after some time has passed)
+	s.Clock.StepBy(10 * time.Minute)
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/new",
+		httptest.WithJSONBody(request),
+		httptest.WithHeader("Idempotency-Key", "first-key"),
+	).ExpectJSON(t, http.StatusCreated, jsonmatch.Object{
+		"uuid":              uuid1,
+		"amount":            1,
+		"duration":          "1 hour",
+		"project_id":        "uuid-for-berlin",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "pending",
+		"created_at":        createdAt.Unix(),
+		"creator_uuid":      "uuid-for-alice",
+		"creator_name":      "alice@Default",
+		"can_be_deleted":    true,
+		"confirm_by":        createdAt.Unix(),
+		"expires_at":        createdAt.Add(1 * time.Hour).Unix(),
+		"updated_at":        createdAt.Unix(),
+	})
+	s.Auditor.ExpectEvents(t, nil...)
+	tr.DBChanges().AssertEmpty()
+
+	// reusing the key for a different request is an error (this includes different endpoints)
+	otherRequest := map[string]any{
+		"amount":            2,
+		"duration":          "1 hour",
+		"project_id":        "uuid-for-berlin",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "pending",
+	}
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/new",
+		httptest.WithJSONBody(otherRequest),
+		httptest.WithHeader("Idempotency-Key", "first-key"),
+	).ExpectText(t, http.StatusUnprocessableEntity, "this Idempotency-Key has already been used for a different request\n")
+	s.Handler.RespondTo(ctx, "DELETE /resources/v2/commitments/"+uuid1,
+		httptest.WithHeader("Idempotency-Key", "first-key"),
+	).ExpectText(t, http.StatusUnprocessableEntity, "this Idempotency-Key has already been used for a different request\n")
+	s.Auditor.ExpectEvents(t, nil...)
+	tr.DBChanges().AssertEmpty()
+
+	// overly long keys are rejected
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/new",
+		httptest.WithJSONBody(otherRequest),
+		httptest.WithHeader("Idempotency-Key", strings.Repeat("x", 256)),
+	).ExpectText(t, http.StatusBadRequest, "value of Idempotency-Key header may not be longer than 255 bytes\n")
+	tr.DBChanges().AssertEmpty()
+
+	// failed requests are not remembered, so the key can be used again for a different request
+	invalidRequest := map[string]any{
+		"amount":            0,
+		"duration":          "1 hour",
+		"project_id":        "uuid-for-berlin",
+		"service_type":      "first",
+		"resource_name":     "capacity",
+		"availability_zone": "az-one",
+		"status":            "pending",
+	}
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/new",
+		httptest.WithJSONBody(invalidRequest),
+		httptest.WithHeader("Idempotency-Key", "second-key"),
+	).ExpectText(t, http.StatusUnprocessableEntity, "amount of committed resource must be greater than zero\n")
+	tr.DBChanges().AssertEmpty()
+
+	// this also works for endpoints without response body: replaying a deletion does not fail because the commitment is already gone
+	for range 2 {
+		s.Handler.RespondTo(ctx, "DELETE /resources/v2/commitments/"+uuid1,
+			httptest.WithHeader("Idempotency-Key", "second-key"),
+		).ExpectStatus(t, http.StatusNoContent)
+	}
+	assert.Equal(t, len(s.Auditor.RecordedEvents()), 1)
+	tr.DBChanges().Ignore()
+
+	// after the retention period, the key is forgotten and the request is executed again
+	s.Clock.StepBy(24 * time.Hour)
+	var uuid2 string
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/new",
+		httptest.WithJSONBody(request),
+		httptest.WithHeader("Idempotency-Key", "first-key"),
+	).ExpectJSON(t, http.StatusCreated, expected(jsonmatch.CaptureField(&uuid2)))
+	if uuid1 == uuid2 {
+		t.Error("expected a new commitment to be created after the Idempotency-Key has expired")
+	}
+
+	// a request that has not completed within the reservation timeout is assumed to have been aborted
+	var uuid3 string
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/new",
+		httptest.WithJSONBody(request),
+		httptest.WithHeader("Idempotency-Key", "third-key"),
+	).ExpectJSON(t, http.StatusCreated, expected(jsonmatch.CaptureField(&uuid3)))
+	s.MustDBExec(`UPDATE idempotency_keys SET response_json = NULL WHERE key = $1`, "third-key")
+	tr.DBChanges().Ignore()
+
+	// until then, replays are rejected because the original request might still complete...
+	s.Clock.StepBy(4 * time.Minute)
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/new",
+		httptest.WithJSONBody(request),
+		httptest.WithHeader("Idempotency-Key", "third-key"),
+	).ExpectText(t, http.StatusConflict, "a request with the same Idempotency-Key is still being processed\n")
+	tr.DBChanges().AssertEmpty()
+
+	// ...but afterwards, a replay takes over the reservation and is executed
+	s.Clock.StepBy(1 * time.Minute)
+	var uuid4 string
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/new",
+		httptest.WithJSONBody(request),
+		httptest.WithHeader("Idempotency-Key", "third-key"),
+	).ExpectJSON(t, http.StatusCreated, expected(jsonmatch.CaptureField(&uuid4)))
+	if uuid3 == uuid4 {
+		t.Error("expected a new commitment to be created after the reservation of the Idempotency-Key has timed out")
+	}
+
+	// the response of the request that took over the reservation is remembered as usual
+	s.Handler.RespondTo(ctx, "POST /resources/v2/commitments/new",
+		httptest.WithJSONBody(request),
+		httptest.WithHeader("Idempotency-Key", "third-key"),
+	).ExpectJSON(t, http.StatusCreated, expected(uuid4))
+}
@@ -9,9 +9,9 @@ import (
 	"github.com/sapcc/go-bits/gopherpolicy"
 	"github.com/sapcc/go-bits/httpapi"
 
+	ratesv2 "github.com/sapcc/limes/apideclarations/apiv2/rates"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
 	"github.com/sapcc/limes/internal/api/reports_v2"
-	ratesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/rates"
-	resourcesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/resources"
 )
 
 // handleGetResourcesInfo handles GET /resources/v2/info.
@@ -0,0 +1,497 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sapcc/go-api-declarations/limes"
+	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/logg"
+	"github.com/sapcc/go-bits/respondwith"
+	. "go.xyrillian.de/gg/option"
+)
+
+// The OpenAPI document is derived from the routes in AddTo() and the types in package apideclarations/apiv2.
+// It is not written by hand, so that clients in other languages can be generated from it without drifting away from the actual API.
+// Since the document only depends on the code, it is computed only once and then served from memory.
+
+type openAPIDocument struct {
+	OpenAPI    string                                 `json:"openapi"`
+	Info       openAPIInfo                            `json:"info"`
+	Paths      map[string]map[string]openAPIOperation `json:"paths"`
+	Components openAPIComponents                      `json:"components"`
+	Security   []map[string][]string                  `json:"security"`
+}
+
+type openAPIInfo struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+type openAPIComponents struct {
+	Schemas         map[string]*openAPISchema        `json:"schemas"`
+	SecuritySchemes map[string]openAPISecurityScheme `json:"securitySchemes"`
+}
+
+type openAPISecurityScheme struct {
+	Type string `json:"type"`
+	In   string `json:"in"`
+	Name string `json:"name"`
+}
+
+type openAPIOperation struct {
+	OperationID string                     `json:"operationId"`
+	Parameters  []openAPIParameter         `json:"parameters,omitempty"`
+	RequestBody Option[openAPIRequestBody] `json:"requestBody,omitzero"`
+	Responses   map[string]openAPIResponse `json:"responses"`
+}
+
+type openAPIParameter struct {
+	Name     string         `json:"name"`
+	In       string         `json:"in"`
+	Required bool           `json:"required,omitempty"`
+	Schema   *openAPISchema `json:"schema"`
+}
+
+type openAPIRequestBody struct {
+	Required bool                        `json:"required"`
+	Content  map[string]openAPIMediaType `json:"content"`
+}
+
+type openAPIResponse struct {
+	Description string                      `json:"description"`
+	Content     map[string]openAPIMediaType `json:"content,omitempty"`
+}
+
+type openAPIMediaType struct {
+	Schema *openAPISchema `json:"schema"`
+}
+
+type openAPISchema struct {
+	Ref                  string                    `json:"$ref,omitempty"`
+	Type                 string                    `json:"type,omitempty"`
+	Format               string                    `json:"format,omitempty"`
+	Minimum              Option[int]               `json:"minimum,omitzero"`
+	Enum                 []string                  `json:"enum,omitempty"`
+	Items                *openAPISchema            `json:"items,omitempty"`
+	Properties           map[string]*openAPISchema `json:"properties,omitempty"`
+	Required             []string                  `json:"required,omitempty"`
+	AdditionalProperties *openAPISchema            `json:"additionalProperties,omitempty"`
+	AnyOf                []*openAPISchema          `json:"anyOf,omitempty"`
+}
+
+// serveOpenAPIDocument returns the handler for "GET /v2/openapi.json".
+// The document describes all routes in the given routers that were created with handlerFunc.
+func serveOpenAPIDocument(routers ...*mux.Router) http.HandlerFunc {
+	renderDocument := sync.OnceValues(func() ([]byte, error) {
+		doc, err := buildOpenAPIDocument(routers...)
+		if err != nil {
+			return nil, err
+		}
+		// unlike our other responses, this one is indented since it is frequently read by humans
+		buf, err := json.MarshalIndent(doc, "", "  ")
+		return append(buf, '\n'), err
+	})
+	return func(w http.ResponseWriter, r *http.Request) {
+		httpapi.IdentifyEndpoint(r, "/v2/openapi.json")
+		buf, err := renderDocument()
+		if respondwith.ObfuscatedErrorText(w, err) {
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, err = w.Write(buf)
+		if err != nil {
+			logg.Error("could not write response body: %s", err.Error())
+		}
+	}
+}
+
+func buildOpenAPIDocument(routers ...*mux.Router) (openAPIDocument, error) {
+	doc := openAPIDocument{
+		OpenAPI: "3.1.0",
+		Info:    openAPIInfo{Title: "Limes v2 API", Version: "2"},
+		Paths:   make(map[string]map[string]openAPIOperation),
+		Components: openAPIComponents{
+			Schemas: make(map[string]*openAPISchema),
+			SecuritySchemes: map[string]openAPISecurityScheme{
+				"keystone": {Type: "apiKey", In: "header", Name: "X-Auth-Token"},
+			},
+		},
+		Security: []map[string][]string{{"keystone": {}}},
+	}
+	sb := openAPISchemaBuilder{Schemas: doc.Components.Schemas}
+
+	for _, router := range routers {
+		err := router.Walk(func(route *mux.Route, _ *mux.Router, _ []*mux.Route) error {
+			e, ok := route.GetHandler().(endpoint)
+			if !ok {
+				return nil
+			}
+			pathTemplate, err := route.GetPathTemplate()
+			if err != nil {
+				return err
+			}
+			methods, err := route.GetMethods()
+			if err != nil {
+				return err
+			}
+			for _, method := range methods {
+				op, err := sb.BuildOperation(method, pathTemplate, e)
+				if err != nil {
+					return fmt.Errorf("while describing %s %s: %w", method, pathTemplate, err)
+				}
+				if doc.Paths[pathTemplate] == nil {
+					doc.Paths[pathTemplate] = make(map[string]openAPIOperation)
+				}
+				doc.Paths[pathTemplate][strings.ToLower(method)] = op
+			}
+			return nil
+		})
+		if err != nil {
+			return openAPIDocument{}, err
+		}
+	}
+	return doc, nil
+}
+
+// openAPISchemaBuilder translates Go types into OpenAPI schemas.
+// Named struct types are collected in the components section of the document and referenced from there.
+type openAPISchemaBuilder struct {
+	Schemas map[string]*openAPISchema
+}
+
+var pathVariableRx = regexp.MustCompile(`\{([^}]+)\}`)
+
+// BuildOperation describes a single endpoint.
+func (sb openAPISchemaBuilder) BuildOperation(method, pathTemplate string, e endpoint) (openAPIOperation, error) {
+	op := openAPIOperation{
+		OperationID: buildOperationID(method, pathTemplate),
+		Responses: map[string]openAPIResponse{
+			"default": {
+				Description: "error message",
+				Content:     map[string]openAPIMediaType{"text/plain": {Schema: &openAPISchema{Type: "string"}}},
+			},
+		},
+	}
+
+	for _, match := range pathVariableRx.FindAllStringSubmatch(pathTemplate, -1) {
+		op.Parameters = append(op.Parameters, openAPIParameter{
+			Name:     match[1],
+			In:       "path",
+			Required: true,
+			Schema:   &openAPISchema{Type: "string"},
+		})
+	}
+	if queryType, ok := e.queryType.Unpack(); ok {
+		params, err := sb.buildQueryParameters(queryType)
+		if err != nil {
+			return openAPIOperation{}, err
+		}
+		op.Parameters = append(op.Parameters, params...)
+	}
+	for _, header := range e.headers {
+		op.Parameters = append(op.Parameters, openAPIParameter{
+			Name:     header.name,
+			In:       "header",
+			Required: header.required,
+			Schema:   &openAPISchema{Type: "string"},
+		})
+	}
+
+	if requestType, ok := e.requestType.Unpack(); ok {
+		schema, err := sb.BuildSchema(requestType)
+		if err != nil {
+			return openAPIOperation{}, err
+		}
+		op.RequestBody = Some(openAPIRequestBody{
+			Required: true,
+			Content:  map[string]openAPIMediaType{"application/json": {Schema: schema}},
+		})
+	}
+
+	status := strconv.Itoa(e.successCode)
+	if e.successCode == http.StatusNoContent {
+		op.Responses[status] = openAPIResponse{Description: http.StatusText(e.successCode)}
+	} else {
+		schema, err := sb.BuildSchema(e.responseType)
+		if err != nil {
+			return openAPIOperation{}, err
+		}
+		op.Responses[status] = openAPIResponse{
+			Description: http.StatusText(e.successCode),
+			Content:     map[string]openAPIMediaType{"application/json": {Schema: schema}},
+		}
+	}
+	if method == http.MethodGet && e.successCode == http.StatusOK {
+		// see respondWithETag()
+		op.Responses[strconv.Itoa(http.StatusNotModified)] = openAPIResponse{Description: http.StatusText(http.StatusNotModified)}
+	}
+	return op, nil
+}
+
+// buildOperationID derives an operation ID like "getResourcesProjectsByProjectUUID" from the method and path of an endpoint.
+// Client generators use this to name the methods that call the respective endpoint.
+func buildOperationID(method, pathTemplate string) string {
+	var sb strings.Builder
+	sb.WriteString(strings.ToLower(method))
+	for segment := range strings.SplitSeq(pathTemplate, "/") {
+		if segment == "" || segment == "v2" {
+			continue
+		}
+		if variable, ok := strings.CutPrefix(segment, "{"); ok {
+			sb.WriteString("By")
+			segment = strings.TrimSuffix(variable, "}")
+		}
+		for word := range strings.FieldsFuncSeq(segment, func(r rune) bool { return r == '-' || r == '_' }) {
+			if word == "uuid" {
+				sb.WriteString("UUID")
+			} else {
+				sb.WriteString(strings.ToUpper(word[:1]) + word[1:])
+			}
+		}
+	}
+	return sb.String()
+}
+
+// buildQueryParameters describes the query parameters accepted by opts.ParseQueryString() for the given struct type.
+func (sb openAPISchemaBuilder) buildQueryParameters(t reflect.Type) ([]openAPIParameter, error) {
+	var (
+		result     []openAPIParameter
+		flagValues = make(map[string][]string)
+	)
+	for _, field := range reflect.VisibleFields(t) {
+		tag := field.Tag.Get("q")
+		if tag == "" {
+			continue // embedded structs are not tagged, but their fields are visited separately
+		}
+		key, options, _ := strings.Cut(tag, ",")
+		var (
+			timeFormat = "RFC3339"
+			value      Option[string]
+			required   bool
+		)
+		for option := range strings.SplitSeq(options, ",") {
+			switch {
+			case strings.HasPrefix(option, "format:"):
+				timeFormat = strings.TrimPrefix(option, "format:")
+			case strings.HasPrefix(option, "value:"):
+				value = Some(strings.TrimPrefix(option, "value:"))
+			case option == "required":
+				required = true
+			}
+		}
+
+		// fields with "value:" are flags that are enabled by giving the respective value for the key, e.g. "?with=info&with=timing"
+		if value, ok := value.Unpack(); ok {
+			if _, exists := flagValues[key]; !exists {
+				result = append(result, openAPIParameter{Name: key, In: "query"})
+			}
+			flagValues[key] = append(flagValues[key], value)
+			continue
+		}
+
+		fieldType := unwrapOptionType(field.Type)
+		isSlice := fieldType.Kind() == reflect.Slice
+		if isSlice {
+			fieldType = fieldType.Elem()
+		}
+		var schema *openAPISchema
+		if fieldType == reflect.TypeFor[time.Time]() {
+			schema = timeSchemaForQueryFormat(timeFormat)
+		} else {
+			var err error
+			schema, err = sb.BuildSchema(fieldType)
+			if err != nil {
+				return nil, fmt.Errorf("in query parameter %q: %w", key, err)
+			}
+		}
+		if isSlice {
+			schema = &openAPISchema{Type: "array", Items: schema}
+		}
+		result = append(result, openAPIParameter{Name: key, In: "query", Required: required, Schema: schema})
+	}
+
+	for idx, param := range result {
+		if values, ok := flagValues[param.Name]; ok && param.Schema == nil {
+			slices.Sort(values)
+			result[idx].Schema = &openAPISchema{Type: "array", Items: &openAPISchema{Type: "string", Enum: values}}
+		}
+	}
+	return result, nil
+}
+
+// timeSchemaForQueryFormat describes a time.Time query parameter with the given "format:" option.
+func timeSchemaForQueryFormat(format string) *openAPISchema {
+	switch format {
+	case "Unix":
+		return &openAPISchema{Type: "integer", Format: "int64"}
+	case "DateOnly":
+		return &openAPISchema{Type: "string", Format: "date"}
+	case "DateTime":
+		return &openAPISchema{Type: "string"}
+	default:
+		return &openAPISchema{Type: "string", Format: "date-time"}
+	}
+}
+
+var (
+	anyOptionType       = reflect.TypeFor[interface{ IsSome() bool }]()
+	jsonMarshalerType   = reflect.TypeFor[json.Marshaler]()
+	textMarshalerType   = reflect.TypeFor[encoding.TextMarshaler]()
+	errUnsupportedInAPI = errors.New("type cannot be described in the OpenAPI document")
+)
+
+// unwrapOptionType returns T if the given type is Option[T], or the given type itself otherwise.
+func unwrapOptionType(t reflect.Type) reflect.Type {
+	if t.Kind() != reflect.Struct || !t.Implements(anyOptionType) {
+		return t
+	}
+	// there is no direct way to get T from Option[T] through reflection, but UnwrapOr() returns a T
+	method, _ := t.MethodByName("UnwrapOr")
+	return method.Type.Out(0)
+}
+
+// BuildSchema describes how values of the given type are serialized into JSON.
+func (sb openAPISchemaBuilder) BuildSchema(t reflect.Type) (*openAPISchema, error) {
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	// types with custom serialization
+	switch t {
+	case reflect.TypeFor[time.Time]():
+		return &openAPISchema{Type: "string", Format: "date-time"}, nil
+	case reflect.TypeFor[limes.UnixEncodedTime]():
+		return &openAPISchema{Type: "integer", Format: "int64"}, nil
+	case reflect.TypeFor[json.RawMessage]():
+		return &openAPISchema{}, nil // any JSON value
+	}
+	if inner := unwrapOptionType(t); inner != t {
+		schema, err := sb.BuildSchema(inner)
+		if err != nil {
+			return nil, err
+		}
+		return &openAPISchema{AnyOf: []*openAPISchema{schema, {Type: "null"}}}, nil
+	}
+	if t.Implements(jsonMarshalerType) || t.Implements(textMarshalerType) {
+		// e.g. limesresources.CommitmentDuration or limesrates.Window, which serialize into strings like "1 year" or "1m"
+		return &openAPISchema{Type: "string"}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &openAPISchema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return &openAPISchema{Type: "integer"}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &openAPISchema{Type: "integer", Minimum: Some(0)}, nil
+	case reflect.Float32, reflect.Float64:
+		return &openAPISchema{Type: "number"}, nil
+	case reflect.String:
+		return &openAPISchema{Type: "string"}, nil
+	case reflect.Interface:
+		return &openAPISchema{}, nil // any JSON value
+	case reflect.Slice, reflect.Array:
+		items, err := sb.BuildSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &openAPISchema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		values, err := sb.BuildSchema(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &openAPISchema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		if t.Name() == "" {
+			return sb.buildObjectSchema(t)
+		}
+		// named structs are shared between all places where they appear
+		name := path.Base(t.PkgPath()) + "." + t.Name()
+		ref := &openAPISchema{Ref: "#/components/schemas/" + name}
+		if _, exists := sb.Schemas[name]; exists {
+			return ref, nil
+		}
+		sb.Schemas[name] = &openAPISchema{} // placeholder to terminate recursion in self-referential types
+		schema, err := sb.buildObjectSchema(t)
+		if err != nil {
+			return nil, err
+		}
+		*sb.Schemas[name] = *schema
+		return ref, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", errUnsupportedInAPI, t.String())
+	}
+}
+
+// buildObjectSchema describes a struct type, following the rules of encoding/json.
+func (sb openAPISchemaBuilder) buildObjectSchema(t reflect.Type) (*openAPISchema, error) {
+	result := &openAPISchema{Type: "object", Properties: make(map[string]*openAPISchema)}
+	for field := range t.Fields() {
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+
+		// untagged embedded structs have their fields promoted into the containing object
+		if field.Anonymous && name == "" {
+			embeddedType := field.Type
+			if embeddedType.Kind() == reflect.Pointer {
+				embeddedType = embeddedType.Elem()
+			}
+			if embeddedType.Kind() == reflect.Struct {
+				embedded, err := sb.buildObjectSchema(embeddedType)
+				if err != nil {
+					return nil, err
+				}
+				for key, schema := range embedded.Properties {
+					result.Properties[key] = schema
+				}
+				result.Required = append(result.Required, embedded.Required...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+		isOptional := slices.ContainsFunc(strings.Split(options, ","), func(option string) bool {
+			return option == "omitempty" || option == "omitzero"
+		})
+		fieldType := field.Type
+		if isOptional {
+			// an Option[T] with "omitzero" is either a T or not present, but never null
+			fieldType = unwrapOptionType(fieldType)
+		} else {
+			result.Required = append(result.Required, name)
+		}
+
+		schema, err := sb.BuildSchema(fieldType)
+		if err != nil {
+			return nil, fmt.Errorf("in field %s.%s: %w", t.String(), field.Name, err)
+		}
+		result.Properties[name] = schema
+	}
+	slices.Sort(result.Required)
+	return result, nil
+}
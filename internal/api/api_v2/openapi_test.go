@@ -0,0 +1,51 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/httptest"
+	"go.xyrillian.de/gg/assert"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/internal/api/api_v2"
+	"github.com/sapcc/limes/internal/core"
+)
+
+func TestOpenAPIDocument(t *testing.T) {
+	// the OpenAPI document only depends on the code, so no database or configuration is needed
+	h := httptest.NewHandler(httpapi.Compose(
+		api_v2.NewV2API(&core.Cluster{}, None[api_v2.DomainNames](), nil, nil, time.Now),
+		httpapi.WithoutLogging(),
+	))
+
+	// when the API changes, update the fixture by running the test and moving "openapi.json.actual" into place
+	h.RespondTo(t.Context(), "GET /v2/openapi.json").
+		ExpectBodyAsInFixture(t, http.StatusOK, "fixtures/openapi.json")
+
+	// check explicitly that headers read by request handlers are documented
+	type parameter struct {
+		Name     string `json:"name"`
+		In       string `json:"in"`
+		Required bool   `json:"required"`
+	}
+	var doc struct {
+		Paths map[string]map[string]struct {
+			Parameters []parameter `json:"parameters"`
+		} `json:"paths"`
+	}
+	h.RespondTo(t.Context(), "GET /v2/openapi.json").CaptureJSON(&doc)
+	assert.Equal(t, doc.Paths["/resources/v2/commitments/new"]["post"].Parameters, []parameter{
+		{Name: "Idempotency-Key", In: "header", Required: false},
+	})
+	assert.Equal(t, doc.Paths["/resources/v2/commitments/{uuid}/accept-transfer"]["post"].Parameters, []parameter{
+		{Name: "uuid", In: "path", Required: true},
+		{Name: "Idempotency-Key", In: "header", Required: false},
+		{Name: "Transfer-Token", In: "header", Required: true},
+	})
+}
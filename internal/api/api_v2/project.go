@@ -10,10 +10,10 @@ import (
 	"github.com/sapcc/go-bits/gopherpolicy"
 	"github.com/sapcc/go-bits/httpapi"
 
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	ratesv2 "github.com/sapcc/limes/apideclarations/apiv2/rates"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
 	"github.com/sapcc/limes/internal/api/reports_v2"
-	"github.com/sapcc/limes/internal/apideclarations/apiv2/common"
-	ratesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/rates"
-	resourcesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/resources"
 )
 
 // handleGetResourcesProjects handles GET /resources/v2/projects.
@@ -26,7 +26,7 @@ func (p *v2Provider) handleGetResourcesProjects(r *http.Request, token *gopherpo
 	if err != nil {
 		return none, err
 	}
-	_, err = reports_v2.NewScope(true, r, options.DomainUUID, token, p.DB)
+	scope, err := reports_v2.NewScope(true, r, options.DomainUUID, token, p.DB)
 	if err != nil {
 		return none, err
 	}
@@ -34,11 +34,28 @@ func (p *v2Provider) handleGetResourcesProjects(r *http.Request, token *gopherpo
 	if err != nil {
 		return none, err
 	}
-	_, err = reports_v2.FilterFromResourceOpts(p.Cluster, options.ResourceReportOpts)
+	err = enforceProjectResourceReportOpts(token, options)
+	if err != nil {
+		return none, err
+	}
+	filter, err := reports_v2.FilterFromResourceOpts(p.Cluster, options.ResourceReportOpts)
+	if err != nil {
+		return none, err
+	}
+	err = p.checkReportVersion(r, token, scope)
+	if err != nil {
+		return none, err
+	}
+	page, err := reports_v2.NewProjectPage(p.DB, options.ProjectPaginationOpts, scope)
 	if err != nil {
 		return none, err
 	}
-	return none, nil
+	result, err := reports_v2.GetProjectResources(p.Cluster, token, p.timeNow(), filter, options, scope, page)
+	if err != nil {
+		return none, err
+	}
+	result.NextLink = page.NextLink(r.URL)
+	return result, nil
 }
 
 // handleGetResourcesProject handles GET /resources/v2/projects/:project_uuid.
@@ -51,7 +68,7 @@ func (p *v2Provider) handleGetResourcesProject(r *http.Request, token *gopherpol
 	if err != nil {
 		return none, err
 	}
-	_, err = reports_v2.NewScope(true, r, options.DomainUUID, token, p.DB)
+	scope, err := reports_v2.NewScope(true, r, options.DomainUUID, token, p.DB)
 	if err != nil {
 		return none, err
 	}
@@ -59,11 +76,46 @@ func (p *v2Provider) handleGetResourcesProject(r *http.Request, token *gopherpol
 	if err != nil {
 		return none, err
 	}
-	_, err = reports_v2.FilterFromResourceOpts(p.Cluster, options.ResourceReportOpts)
+	err = enforceProjectResourceReportOpts(token, options)
+	if err != nil {
+		return none, err
+	}
+	filter, err := reports_v2.FilterFromResourceOpts(p.Cluster, options.ResourceReportOpts)
+	if err != nil {
+		return none, err
+	}
+	err = p.checkReportVersion(r, token, scope)
+	if err != nil {
+		return none, err
+	}
+	page, err := reports_v2.NewProjectPage(p.DB, options.ProjectPaginationOpts, scope)
 	if err != nil {
 		return none, err
 	}
-	return none, nil
+	result, err := reports_v2.GetProjectResources(p.Cluster, token, p.timeNow(), filter, options, scope, page)
+	if err != nil {
+		return none, err
+	}
+	result.NextLink = page.NextLink(r.URL)
+	return result, nil
+}
+
+// enforceProjectResourceReportOpts checks the permissions for query options
+// which are only allowed for users with certain permissions.
+func enforceProjectResourceReportOpts(token *gopherpolicy.Token, options common.ProjectResourceReportOpts) error {
+	if options.WithTiming {
+		err := token.Enforce("v2:project:show_timing")
+		if err != nil {
+			return err
+		}
+	}
+	if options.WithSubresources {
+		err := token.Enforce("v2:project:show_subresources")
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // handleGetRatesProjects handles GET /rates/v2/projects.
@@ -99,9 +151,18 @@ func (p *v2Provider) commonHandleGetRatesProject(r *http.Request, token *gopherp
 	if err != nil {
 		return none, err
 	}
-	result, err := reports_v2.GetProjectRates(p.Cluster, token, filter, options, scope)
+	err = p.checkReportVersion(r, token, scope)
+	if err != nil {
+		return none, err
+	}
+	page, err := reports_v2.NewProjectPage(p.DB, options.ProjectPaginationOpts, scope)
+	if err != nil {
+		return none, err
+	}
+	result, err := reports_v2.GetProjectRates(p.Cluster, token, filter, options, scope, page)
 	if err != nil {
 		return none, err
 	}
+	result.NextLink = page.NextLink(r.URL)
 	return result, nil
 }
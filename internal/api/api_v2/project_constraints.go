@@ -0,0 +1,259 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"cmp"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/go-api-declarations/limes"
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/audittools"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/httpapi"
+	"github.com/sapcc/go-bits/logg"
+	"github.com/sapcc/go-bits/respondwith"
+	"github.com/sapcc/go-bits/sqlext"
+
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+	"github.com/sapcc/limes/internal/audit"
+	"github.com/sapcc/limes/internal/core"
+	"github.com/sapcc/limes/internal/datamodel"
+	"github.com/sapcc/limes/internal/db"
+)
+
+func (p *v2Provider) handlePutProjectMaxQuota(r *http.Request, token *gopherpolicy.Token) (struct{}, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/projects/:project_uuid/max-quota")
+	var (
+		none struct{} // there is no response body
+		sis  = p.Cluster.SIC.GetSnapshot()
+		now  = p.timeNow()
+	)
+
+	projectUUID := liquid.ProjectUUID(mux.Vars(r)["project_uuid"])
+	dbDomain, dbProject, err := p.checkProjectAccess(token, projectUUID, "v2:project:edit_as_outside_admin")
+	if err != nil {
+		return none, err
+	}
+	req, err := parseRequestBodyAs[resourcesv2.ProjectMaxQuotaRequest](r)
+	if err != nil {
+		return none, err
+	}
+
+	// validate request
+	requested := make(map[db.ResourcePath]*audit.MaxQuotaChange)
+	paths := sortedResourcePaths(req.MaxQuota)
+	for _, path := range paths {
+		resource, err := findResourceForConstraint(path, sis)
+		if err != nil {
+			return none, err
+		}
+		newValue := req.MaxQuota[path.ServiceType][path.ResourceName]
+		if newValue.IsSome() && !resource.HasQuota {
+			err := fmt.Errorf("resource %s/%s does not track quota", path.ServiceType, path.ResourceName)
+			return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, err)
+		}
+		requested[path] = &audit.MaxQuotaChange{NewValue: newValue}
+	}
+
+	// write requested values to DB
+	err = p.updateProjectResourceConstraints(dbProject, paths, sis, now,
+		func(res *db.ProjectResource, path db.ResourcePath) {
+			requestedChange := requested[path]
+			requestedChange.OldValue = res.MaxQuotaFromOutsideAdmin // remember for audit event
+			res.MaxQuotaFromOutsideAdmin = requestedChange.NewValue
+		})
+	if err != nil {
+		return none, err
+	}
+
+	// write audit trail
+	nm := core.BuildResourceNameMapping(p.Cluster, sis)
+	for _, path := range paths {
+		apiServiceType, apiResourceName := auditIdentityForResource(nm, path)
+		p.auditor.Record(audittools.Event{
+			Time:       now,
+			Request:    r,
+			User:       token,
+			ReasonCode: http.StatusNoContent,
+			Action:     cadf.UpdateAction,
+			Target: audit.MaxQuotaEventTarget{
+				DomainID:        dbDomain.UUID,
+				DomainName:      dbDomain.Name,
+				ProjectID:       dbProject.UUID,
+				ProjectName:     dbProject.Name,
+				ServiceType:     apiServiceType,
+				ResourceName:    apiResourceName,
+				RequestedChange: *requested[path],
+			},
+		})
+	}
+
+	return none, nil
+}
+
+func (p *v2Provider) handlePutProjectForbidAutogrowth(r *http.Request, token *gopherpolicy.Token) (struct{}, error) {
+	httpapi.IdentifyEndpoint(r, "/resources/v2/projects/:project_uuid/forbid-autogrowth")
+	var (
+		none struct{} // there is no response body
+		sis  = p.Cluster.SIC.GetSnapshot()
+		now  = p.timeNow()
+	)
+
+	projectUUID := liquid.ProjectUUID(mux.Vars(r)["project_uuid"])
+	dbDomain, dbProject, err := p.checkProjectAccess(token, projectUUID, "v2:project:edit")
+	if err != nil {
+		return none, err
+	}
+	req, err := parseRequestBodyAs[resourcesv2.ProjectForbidAutogrowthRequest](r)
+	if err != nil {
+		return none, err
+	}
+
+	// validate request
+	requested := make(map[db.ResourcePath]*audit.AutogrowthChange)
+	paths := sortedResourcePaths(req.ForbidAutogrowth)
+	for _, path := range paths {
+		resource, err := findResourceForConstraint(path, sis)
+		if err != nil {
+			return none, err
+		}
+		if !resource.HasQuota {
+			err := fmt.Errorf("resource %s/%s does not track quota", path.ServiceType, path.ResourceName)
+			return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, err)
+		}
+		behavior := p.Cluster.CommitmentBehaviorForResourcePath(path).ForDomain(dbDomain.Name)
+		if len(behavior.Durations) == 0 {
+			err := fmt.Errorf("resource %s/%s does not allow commitments", path.ServiceType, path.ResourceName)
+			return none, respondwith.CustomStatus(http.StatusUnprocessableEntity, err)
+		}
+		requested[path] = &audit.AutogrowthChange{ForbidAutogrowth: req.ForbidAutogrowth[path.ServiceType][path.ResourceName]}
+	}
+
+	// write requested values to DB
+	err = p.updateProjectResourceConstraints(dbProject, paths, sis, now,
+		func(res *db.ProjectResource, path db.ResourcePath) {
+			res.ForbidAutogrowth = requested[path].ForbidAutogrowth
+		})
+	if err != nil {
+		return none, err
+	}
+
+	// write audit trail
+	nm := core.BuildResourceNameMapping(p.Cluster, sis)
+	for _, path := range paths {
+		apiServiceType, apiResourceName := auditIdentityForResource(nm, path)
+		p.auditor.Record(audittools.Event{
+			Time:       now,
+			Request:    r,
+			User:       token,
+			ReasonCode: http.StatusNoContent,
+			Action:     cadf.UpdateAction,
+			Target: audit.AutogrowthEventTarget{
+				DomainID:         dbDomain.UUID,
+				DomainName:       dbDomain.Name,
+				ProjectID:        dbProject.UUID,
+				ProjectName:      dbProject.Name,
+				ServiceType:      apiServiceType,
+				ResourceName:     apiResourceName,
+				AutogrowthChange: *requested[path],
+			},
+		})
+	}
+
+	return none, nil
+}
+
+// sortedResourcePaths flattens the keys of a request payload that is grouped by service type and resource name.
+// The result is sorted to ensure that validation errors are reported deterministically.
+func sortedResourcePaths[T any](requested map[db.ServiceType]map[liquid.ResourceName]T) []db.ResourcePath {
+	var result []db.ResourcePath
+	for serviceType, requestedInService := range requested {
+		for resourceName := range requestedInService {
+			result = append(result, db.ResourcePath{ServiceType: serviceType, ResourceName: resourceName})
+		}
+	}
+	slices.SortFunc(result, func(lhs, rhs db.ResourcePath) int {
+		return cmp.Or(cmp.Compare(lhs.ServiceType, rhs.ServiceType), cmp.Compare(lhs.ResourceName, rhs.ResourceName))
+	})
+	return result
+}
+
+// auditIdentityForResource returns the identifiers for the given resource in audit events.
+// For consistency with audit events from the v1 API, the resource is identified by its v1 API identity.
+// If there is none, the native identifiers are used instead, since the event must not be dropped.
+func auditIdentityForResource(nm core.ResourceNameMapping, path db.ResourcePath) (limes.ServiceType, limesresources.ResourceName) {
+	apiServiceType, apiResourceName, exists := nm.MapToV1API(path.ServiceType, path.ResourceName)
+	if !exists {
+		return limes.ServiceType(path.ServiceType), limesresources.ResourceName(path.ResourceName)
+	}
+	return apiServiceType, apiResourceName
+}
+
+// findResourceForConstraint checks that a resource path from a request body refers to an existing resource.
+func findResourceForConstraint(path db.ResourcePath, sis core.ServiceInfoSnapshot) (db.Resource, error) {
+	_, ok := sis.GetServiceForType(path.ServiceType)
+	if !ok {
+		return db.Resource{}, respondwith.CustomStatus(http.StatusNotFound, fmt.Errorf("%w: %s", errNoSuchService, path.ServiceType))
+	}
+	resource, ok := sis.GetResourceForPath(path)
+	if !ok {
+		return db.Resource{}, respondwith.CustomStatus(http.StatusNotFound, fmt.Errorf("%w: %s/%s", errNoSuchResource, path.ServiceType, path.ResourceName))
+	}
+	return resource, nil
+}
+
+// updateProjectResourceConstraints applies `update` to the project_resources of the given project for all given resources.
+// Afterwards, the quota of these resources is recomputed to take the changed constraints into account.
+func (p *v2Provider) updateProjectResourceConstraints(dbProject db.Project, paths []db.ResourcePath, sis core.ServiceInfoSnapshot, now time.Time, update func(*db.ProjectResource, db.ResourcePath)) error {
+	var serviceTypes []db.ServiceType
+	for _, path := range paths {
+		if !slices.Contains(serviceTypes, path.ServiceType) {
+			serviceTypes = append(serviceTypes, path.ServiceType)
+		}
+	}
+
+	tx, err := p.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer sqlext.RollbackUnlessCommitted(tx)
+
+	for _, serviceType := range serviceTypes {
+		err := datamodel.ProjectResourceUpdate{
+			UpdateResource: func(res *db.ProjectResource, resName liquid.ResourceName) error {
+				path := db.ResourcePath{ServiceType: serviceType, ResourceName: resName}
+				if slices.Contains(paths, path) {
+					update(res, path)
+				}
+				return nil
+			},
+		}.Run(tx, dbProject, sis, serviceType)
+		if err != nil {
+			return err
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+
+	// the constraints have already been persisted at this point, so errors are only logged;
+	// the next capacity scrape will run ApplyComputedProjectQuota again anyway
+	for _, path := range paths {
+		resource, _ := sis.GetResourceForPath(path) // existence was checked during request validation
+		err := datamodel.ApplyComputedProjectQuota(path.ServiceType, resource, p.Cluster, now)
+		if err != nil {
+			logg.Error("could not apply computed project quota for %s/%s after changing quota constraints: %s",
+				path.ServiceType, path.ResourceName, err.Error())
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/easypg"
+	"github.com/sapcc/go-bits/httptest"
+	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/assert"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/internal/datamodel"
+	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/internal/test"
+	"github.com/sapcc/limes/internal/test/common_fixtures"
+)
+
+var projectConstraintsConfigJSON = string(must.Return(httptest.NewJQModifiableJSONString(test.RemoveCommentsFromJSON(`
+	{
+		"liquids": {
+			"first": {
+				"area": "first",
+				"commitment_behavior_per_resource": [
+					{
+						"key": "capacity",
+						"value": {
+							"durations_per_domain": [{"key": ".*", "value": ["1 hour"]}]
+						}
+					}
+				]
+			},
+			"second": {
+				"area": "second",
+				"commitment_behavior_per_resource": []
+			}
+		}
+	}`), "projectConstraintsConfigJSON").
+	ModifyWithVariable(".discovery = $ref", common_fixtures.DiscoveryBerlinDresdenParis).
+	ModifyWithVariable(".areas = $ref", common_fixtures.AreasFirstSecond).
+	ModifyWithVariable(".availability_zones = $ref", common_fixtures.AZsOneTwo).
+	MarshalJSON()))
+
+func setupProjectConstraintsTest(t *testing.T) test.Setup {
+	t.Helper()
+	s := test.NewSetup(t,
+		test.WithConfig(projectConstraintsConfigJSON),
+		test.WithPersistedServiceInfo("first", test.DefaultLiquidServiceInfo("First")),
+		test.WithPersistedServiceInfo("second", test.DefaultLiquidServiceInfo("Second")),
+		test.WithInitialDiscovery,
+		test.WithEmptyResourceRecordsAsNeeded,
+	)
+
+	// compute initial quotas, such that the tests only observe quota changes caused by the requests
+	sis := s.Cluster.SIC.GetSnapshot()
+	for _, resName := range []liquid.ResourceName{"capacity", "things"} {
+		res := must.BeOK(sis.GetResourceForPath(db.ResourcePath{ServiceType: "first", ResourceName: resName}))
+		must.SucceedT(t, datamodel.ApplyComputedProjectQuota("first", res, s.Cluster, s.Clock.Now()))
+	}
+	return s
+}
+
+func TestV2PutProjectMaxQuota(t *testing.T) {
+	s := setupProjectConstraintsTest(t)
+	tr, tr0 := easypg.NewTracker(t, s.DB.Db)
+	tr0.Ignore()
+
+	// happy case: set a non-null value for the first time, then update it
+	for _, value := range []uint64{500, 1000} {
+		s.Handler.RespondTo(s.Ctx, "PUT /resources/v2/projects/uuid-for-berlin/max-quota",
+			httptest.WithJSONBody(map[string]any{"max_quota": map[string]any{"first": map[string]any{"things": value}}}),
+		).ExpectStatus(t, http.StatusNoContent)
+		tr.DBChanges().AssertEqualf(`
+			UPDATE project_resources SET max_quota_from_outside_admin = %d WHERE id = 2 AND project_id = 1 AND resource_id = 2;
+		`, value)
+		events := s.Auditor.RecordedEvents()
+		assert.Equal(t, len(events), 1)
+		assert.Equal(t, events[0].Target.TypeURI, "service/first/things/max-quota")
+	}
+
+	// happy case: write a NULL value over both an existing NULL value and a non-NULL value
+	s.Handler.RespondTo(s.Ctx, "PUT /resources/v2/projects/uuid-for-berlin/max-quota",
+		httptest.WithJSONBody(map[string]any{"max_quota": map[string]any{"first": map[string]any{"capacity": nil, "things": nil}}}),
+	).ExpectStatus(t, http.StatusNoContent)
+	tr.DBChanges().AssertEqualf(`
+		UPDATE project_resources SET max_quota_from_outside_admin = NULL WHERE id = 2 AND project_id = 1 AND resource_id = 2;
+	`)
+	assert.Equal(t, len(s.Auditor.RecordedEvents()), 2)
+
+	// error case: insufficient permissions
+	s.TokenValidator.Enforcer.AllowEditMaxQuota = false
+	s.Handler.RespondTo(s.Ctx, "PUT /resources/v2/projects/uuid-for-berlin/max-quota",
+		httptest.WithJSONBody(map[string]any{"max_quota": map[string]any{"first": map[string]any{"things": 500}}}),
+	).ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowEditMaxQuota = true
+
+	// error cases: invalid service or resource
+	s.Handler.RespondTo(s.Ctx, "PUT /resources/v2/projects/uuid-for-berlin/max-quota",
+		httptest.WithJSONBody(map[string]any{"max_quota": map[string]any{"unknown": map[string]any{"things": 500}}}),
+	).ExpectText(t, http.StatusNotFound, "no such service: unknown\n")
+	s.Handler.RespondTo(s.Ctx, "PUT /resources/v2/projects/uuid-for-berlin/max-quota",
+		httptest.WithJSONBody(map[string]any{"max_quota": map[string]any{"first": map[string]any{"items": 500}}}),
+	).ExpectText(t, http.StatusNotFound, "no such resource: first/items\n")
+
+	// error case: resource does not track quota
+	s.MustDBExec("UPDATE resources SET has_quota = FALSE WHERE path = $1", "first/capacity")
+	must.SucceedT(t, s.Cluster.SIC.InvalidateService(Some(db.ServiceType("first"))))
+	tr.DBChanges().Ignore()
+	s.Handler.RespondTo(s.Ctx, "PUT /resources/v2/projects/uuid-for-berlin/max-quota",
+		httptest.WithJSONBody(map[string]any{"max_quota": map[string]any{"first": map[string]any{"capacity": 500}}}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "resource first/capacity does not track quota\n")
+
+	tr.DBChanges().AssertEmpty()
+	s.Auditor.ExpectEvents(t /*, nothing */)
+}
+
+func TestV2PutProjectForbidAutogrowth(t *testing.T) {
+	s := setupProjectConstraintsTest(t)
+	tr, tr0 := easypg.NewTracker(t, s.DB.Db)
+	tr0.Ignore()
+
+	// happy case: forbid autogrowth twice, only update the database once
+	for range 2 {
+		s.Handler.RespondTo(s.Ctx, "PUT /resources/v2/projects/uuid-for-berlin/forbid-autogrowth",
+			httptest.WithJSONBody(map[string]any{"forbid_autogrowth": map[string]any{"first": map[string]any{"capacity": true}}}),
+		).ExpectStatus(t, http.StatusNoContent)
+		assert.Equal(t, len(s.Auditor.RecordedEvents()), 1)
+	}
+	tr.DBChanges().AssertEqualf(`UPDATE project_resources SET forbid_autogrowth = TRUE WHERE id = 1 AND project_id = 1 AND resource_id = 1;`)
+
+	// happy case: allow autogrowth again
+	s.Handler.RespondTo(s.Ctx, "PUT /resources/v2/projects/uuid-for-berlin/forbid-autogrowth",
+		httptest.WithJSONBody(map[string]any{"forbid_autogrowth": map[string]any{"first": map[string]any{"capacity": false}}}),
+	).ExpectStatus(t, http.StatusNoContent)
+	tr.DBChanges().AssertEqualf(`UPDATE project_resources SET forbid_autogrowth = FALSE WHERE id = 1 AND project_id = 1 AND resource_id = 1;`)
+	assert.Equal(t, len(s.Auditor.RecordedEvents()), 1)
+
+	// error case: insufficient permissions
+	s.TokenValidator.Enforcer.AllowEdit = false
+	s.Handler.RespondTo(s.Ctx, "PUT /resources/v2/projects/uuid-for-berlin/forbid-autogrowth",
+		httptest.WithJSONBody(map[string]any{"forbid_autogrowth": map[string]any{"first": map[string]any{"capacity": true}}}),
+	).ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowEdit = true
+
+	// error case: malformed request
+	s.Handler.RespondTo(s.Ctx, "PUT /resources/v2/projects/uuid-for-berlin/forbid-autogrowth",
+		httptest.WithJSONBody(map[string]any{"forbid_autogrowth": map[string]any{"first": map[string]any{"capacity": "yes"}}}),
+	).ExpectStatus(t, http.StatusBadRequest)
+
+	// error case: resource does not accept commitments
+	s.Handler.RespondTo(s.Ctx, "PUT /resources/v2/projects/uuid-for-berlin/forbid-autogrowth",
+		httptest.WithJSONBody(map[string]any{"forbid_autogrowth": map[string]any{"first": map[string]any{"things": true}}}),
+	).ExpectText(t, http.StatusUnprocessableEntity, "resource first/things does not allow commitments\n")
+
+	// error case: unknown resource
+	s.Handler.RespondTo(s.Ctx, "PUT /resources/v2/projects/uuid-for-berlin/forbid-autogrowth",
+		httptest.WithJSONBody(map[string]any{"forbid_autogrowth": map[string]any{"first": map[string]any{"items": true}}}),
+	).ExpectText(t, http.StatusNotFound, "no such resource: first/items\n")
+
+	tr.DBChanges().AssertEmpty()
+	s.Auditor.ExpectEvents(t /*, nothing */)
+}
@@ -15,6 +15,78 @@ import (
 	"github.com/sapcc/limes/internal/test"
 )
 
+func TestV2ProjectResourceReport(t *testing.T) {
+	s := setupResourceReportTest(t)
+	fixturePath := "./fixtures/resource-projects.json"
+
+	// add some project specific data on top of the shared setup
+	berlinCapacityAZOne := s.GetAZResourceID("first", "capacity", "az-one")
+	s.MustDBExec(`UPDATE project_az_resources SET quota = 20, subresources = '[{"id":"foo","usage":10}]', historical_usage = '{"t":[1,2],"v":[5,12]}' WHERE project_id = $1 AND az_resource_id = $2`,
+		s.GetProjectID("berlin"), berlinCapacityAZOne)
+	s.MustDBExec(`UPDATE project_resources SET max_quota_from_outside_admin = 50, forbid_autogrowth = TRUE WHERE project_id = $1 AND resource_id = $2`,
+		s.GetProjectID("berlin"), s.GetResourceID("first", "capacity"))
+	s.MustDBExec(`UPDATE project_services SET scraped_at = $1 WHERE project_id = $2 AND service_id = $3`,
+		s.Clock.Now(), s.GetProjectID("paris"), s.GetServiceID("first"))
+
+	s.TokenValidator.Enforcer.AllowReportMultiple = false
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/projects").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowReportMultiple = true
+	s.TokenValidator.Enforcer.AllowReportSingle = false
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/projects/uuid-for-paris").
+		ExpectText(t, http.StatusForbidden, "Forbidden\n")
+	s.TokenValidator.Enforcer.AllowReportSingle = true
+
+	// the maximum result set includes all optional fields
+	allOptions := "with=commitment_stats&with=constraints&with=subresources&with=timing"
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/projects?"+allOptions).ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "all options"))
+
+	// without any options
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/projects").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "no params").
+			Modify("del(.domains[].projects[].service_areas[].services[].scraped_at)").
+			Modify("del(.domains[].projects[].service_areas[].services[].categories[].resources[] | .max_quota, .forbid_autogrowth)").
+			Modify("del(.domains[].projects[].service_areas[].services[].categories[].resources[].availability_zones[] | .committed, .subresources)"))
+
+	// one project
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/projects/uuid-for-berlin?"+allOptions).ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "project filter berlin").
+			Modify(`del(.domains["uuid-for-france"])`).
+			Modify(`del(.domains["uuid-for-germany"].projects["uuid-for-dresden"])`))
+
+	// one domain
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/projects?domain_uuid=uuid-for-france&"+allOptions).ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "domain filter france").
+			Modify(`del(.domains["uuid-for-germany"])`))
+
+	// pagination: projects are ordered by UUID (berlin, dresden, paris)
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/projects?limit=2&"+allOptions).ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "first page").
+			Modify(`del(.domains["uuid-for-france"])`).
+			Modify(`.next = "/resources/v2/projects?limit=2&marker=uuid-for-dresden&`+allOptions+`"`))
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/projects?limit=2&marker=uuid-for-dresden&"+allOptions).ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "last page").
+			Modify(`del(.domains["uuid-for-germany"])`))
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/projects?limit=0").
+		ExpectText(t, http.StatusBadRequest, "limit must be greater than zero\n")
+
+	// filtering
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/projects?area=first&resource=capacity&"+allOptions).ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "resource filter").
+			Modify("del(.domains[].projects[].service_areas.second)").
+			Modify("del(.domains[].projects[].service_areas.first.services.first.categories.first)"))
+
+	// scraped_at filter: only paris/first has been scraped
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/projects?min_scraped_at=1970-01-01T00:00:00Z&"+allOptions).ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "min_scraped_at filter").
+			Modify(`del(.domains["uuid-for-germany"])`).
+			Modify("del(.domains[].projects[].service_areas.second)"))
+
+	// unknown project
+	s.Handler.RespondTo(s.Ctx, "GET /resources/v2/projects/does-not-exists").ExpectText(t, http.StatusNotFound, "no such project (UUID = does-not-exists)\n")
+}
+
 func TestV2ProjectRateReport(t *testing.T) {
 	srvInfoFirst := test.DefaultLiquidServiceInfo("First")
 	srvInfoFirst.Rates = map[liquid.RateName]liquid.RateInfo{
@@ -109,6 +181,18 @@ func TestV2ProjectRateReport(t *testing.T) {
 			Modify("del(.domains[].projects[].service_areas.first.services.first.categories.first)").
 			Modify("del(.info.service_areas.first.services.first.categories.first)"))
 
+	// pagination: projects are ordered by UUID (berlin, dresden, paris)
+	s.Handler.RespondTo(s.Ctx, "GET /rates/v2/projects?limit=1&marker=uuid-for-berlin").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "middle page").
+			Modify("del(.info)").
+			Modify(`del(.domains["uuid-for-france"])`).
+			Modify(`del(.domains["uuid-for-germany"].projects["uuid-for-berlin"])`).
+			Modify(`.next = "/rates/v2/projects?limit=1&marker=uuid-for-dresden"`))
+	s.Handler.RespondTo(s.Ctx, "GET /rates/v2/projects?limit=1&marker=uuid-for-dresden").ExpectJSON(t, http.StatusOK,
+		httptest.NewJQModifiableJSONFixture(fixturePath, "last page").
+			Modify("del(.info)").
+			Modify(`del(.domains["uuid-for-germany"])`))
+
 	// no we add some rate limits to get the other 2 combinations (objects:update=limit only, object:delete=limit + usage)
 	objectsDeleteRateID := s.GetRateID("first", "objects:delete")
 	s.MustDBExec(`UPDATE project_rates SET rate_limit = 10, window_ns = 5000000000 WHERE rate_id IN ($1, $2)`, objectsDeleteRateID, objectsUpdateRateID)
@@ -0,0 +1,150 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gorilla/mux"
+	limesrates "github.com/sapcc/go-api-declarations/limes/rates"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/httpapi"
+	. "go.xyrillian.de/gg/option"
+
+	ratesv2 "github.com/sapcc/limes/apideclarations/apiv2/rates"
+	"github.com/sapcc/limes/internal/datamodel"
+	"github.com/sapcc/limes/internal/db"
+)
+
+// rateLimitValidationError is returned by PUT /rates/v2/projects/:project_uuid
+// when at least one of the requested rate limits is unacceptable.
+type rateLimitValidationError struct {
+	Status   int
+	Response ratesv2.ProjectRateLimitErrorResponse
+}
+
+// Error implements the builtin/error interface.
+func (e rateLimitValidationError) Error() string {
+	lines := make([]string, len(e.Response.UnacceptableRateLimits))
+	for idx, u := range e.Response.UnacceptableRateLimits {
+		lines[idx] = fmt.Sprintf("cannot change %s/%s rate limit: %s", u.ServiceType, u.RateName, u.Message)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// StatusCode implements the errorWithJSONBody interface.
+func (e rateLimitValidationError) StatusCode() int {
+	return e.Status
+}
+
+// ResponseBody implements the errorWithJSONBody interface.
+func (e rateLimitValidationError) ResponseBody() any {
+	return e.Response
+}
+
+func (p *v2Provider) handlePutRatesProject(r *http.Request, token *gopherpolicy.Token) (struct{}, error) {
+	httpapi.IdentifyEndpoint(r, "/rates/v2/projects/:project_uuid")
+	var (
+		none struct{} // there is no response body
+		now  = p.timeNow()
+	)
+
+	projectUUID := liquid.ProjectUUID(mux.Vars(r)["project_uuid"])
+	dbDomain, dbProject, err := p.checkProjectAccess(token, projectUUID, "v2:project:report_single")
+	if err != nil {
+		return none, err
+	}
+	req, err := parseRequestBodyAs[ratesv2.ProjectRateLimitRequest](r)
+	if err != nil {
+		return none, err
+	}
+
+	updater := datamodel.RateLimitUpdater{
+		Cluster: p.Cluster,
+		Domain:  &dbDomain,
+		Project: &dbProject,
+		CanSetRateLimit: func(serviceType db.ServiceType) bool {
+			token.Context.Request["service_type"] = string(serviceType)
+			return token.Check("v2:project:set_rate_limit")
+		},
+		Auditor: p.auditor,
+	}
+	requested := make(map[db.ServiceType]map[liquid.RateName]limesrates.RateLimitRequest, len(req.RateLimits))
+	for serviceType, requestedInService := range req.RateLimits {
+		requested[serviceType] = make(map[liquid.RateName]limesrates.RateLimitRequest, len(requestedInService))
+		for rateName, value := range requestedInService {
+			requested[serviceType][rateName] = limesrates.RateLimitRequest{Limit: value.Limit, Window: value.Window}
+		}
+	}
+
+	err = withinDryRunnableTx(p.DB, req.DryRun, func(tx db.Interface) error {
+		// validate inputs within the DB transaction, to ensure that we do not apply inconsistent values later
+		err := updater.ValidateRequests(requested, tx)
+		if err != nil {
+			return err
+		}
+		if verr, ok := buildRateLimitValidationError(updater).Unpack(); ok {
+			return verr
+		}
+		return updater.WriteRateLimits(tx)
+	})
+
+	// write audit trail for all attempted changes (but not for dry runs or for unexpected errors)
+	var verr rateLimitValidationError
+	switch {
+	case req.DryRun:
+		// dry runs do not change anything, so there is nothing to audit
+	case err == nil:
+		updater.CommitAuditTrailWithStatus(token, r, now, http.StatusNoContent)
+	case errors.As(err, &verr):
+		updater.CommitAuditTrailWithStatus(token, r, now, verr.Status)
+	}
+	return none, err
+}
+
+// buildRateLimitValidationError returns an error listing all unacceptable rate limits, or None if all changes are acceptable.
+func buildRateLimitValidationError(updater datamodel.RateLimitUpdater) Option[rateLimitValidationError] {
+	var (
+		unacceptable []ratesv2.UnacceptableRateLimit
+		statusCodes  []int
+	)
+	for _, serviceType := range slices.Sorted(maps.Keys(updater.Requests)) {
+		reqs := updater.Requests[serviceType]
+		for _, rateName := range slices.Sorted(maps.Keys(reqs)) {
+			verr := reqs[rateName].ValidationError
+			if verr == nil {
+				continue
+			}
+			unacceptable = append(unacceptable, ratesv2.UnacceptableRateLimit{
+				ServiceType: serviceType,
+				RateName:    rateName,
+				Status:      verr.Status,
+				Message:     verr.Message,
+			})
+			if !slices.Contains(statusCodes, verr.Status) {
+				statusCodes = append(statusCodes, verr.Status)
+			}
+		}
+	}
+	if len(unacceptable) == 0 {
+		return None[rateLimitValidationError]()
+	}
+
+	// when all errors have the same status, report that; otherwise use 422
+	// (Unprocessable Entity) as a reasonable overall default
+	status := http.StatusUnprocessableEntity
+	if len(statusCodes) == 1 {
+		status = statusCodes[0]
+	}
+	return Some(rateLimitValidationError{
+		Status:   status,
+		Response: ratesv2.ProjectRateLimitErrorResponse{UnacceptableRateLimits: unacceptable},
+	})
+}
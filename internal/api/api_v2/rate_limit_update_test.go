@@ -0,0 +1,155 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package api_v2_test
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	limesrates "github.com/sapcc/go-api-declarations/limes/rates"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/easypg"
+	"github.com/sapcc/go-bits/httptest"
+	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/jsonmatch"
+
+	"github.com/sapcc/limes/internal/audit"
+	"github.com/sapcc/limes/internal/test"
+)
+
+func TestV2PutProjectRateLimits(t *testing.T) {
+	srvInfoFirst := test.DefaultLiquidServiceInfo("First")
+	srvInfoFirst.Rates = map[liquid.RateName]liquid.RateInfo{
+		"objects:create": {DisplayName: "Object Creations", Topology: liquid.FlatTopology, HasUsage: true},
+		"objects:delete": {DisplayName: "Object Deletions", Unit: liquid.UnitMebibytes, Topology: liquid.FlatTopology, HasUsage: true},
+		"objects:update": {DisplayName: "Object Updates", Topology: liquid.FlatTopology, HasUsage: false},
+	}
+	s := test.NewSetup(t,
+		test.WithConfig(rateReportConfigJSON),
+		test.WithPersistedServiceInfo("first", srvInfoFirst),
+		test.WithPersistedServiceInfo("second", test.DefaultLiquidServiceInfo("Second")),
+		test.WithInitialDiscovery,
+		test.WithEmptyResourceRecordsAsNeeded,
+	)
+	tr, tr0 := easypg.NewTracker(t, s.DB.Db)
+	tr0.Ignore()
+
+	makeRequest := func(dryRun bool, rateLimits map[string]any) map[string]any {
+		return map[string]any{"dry_run": dryRun, "rate_limits": map[string]any{"first": rateLimits}}
+	}
+	makeEvent := func(reasonCode int, rateName string, payload audit.RateLimitChange) cadf.Event {
+		outcome := cadf.SuccessOutcome
+		if reasonCode >= http.StatusBadRequest {
+			outcome = cadf.FailureOutcome
+		}
+		return cadf.Event{
+			Action:      cadf.UpdateAction,
+			Outcome:     outcome,
+			Reason:      cadf.Reason{ReasonType: "HTTP", ReasonCode: strconv.Itoa(reasonCode)},
+			RequestPath: "/rates/v2/projects/uuid-for-berlin",
+			Target: cadf.Resource{
+				TypeURI:     fmt.Sprintf("service/first/%s/rates", rateName),
+				ID:          "uuid-for-berlin",
+				DomainID:    "uuid-for-germany",
+				DomainName:  "germany",
+				ProjectID:   "uuid-for-berlin",
+				ProjectName: "berlin",
+				Attachments: []cadf.Attachment{must.Return(cadf.NewJSONAttachment("payload", payload))},
+			},
+		}
+	}
+
+	// dry run does not have any side effects
+	s.Handler.RespondTo(s.Ctx, "PUT /rates/v2/projects/uuid-for-berlin",
+		httptest.WithJSONBody(makeRequest(true, map[string]any{"objects:create": map[string]any{"limit": 10, "window": "1m"}})),
+	).ExpectStatus(t, http.StatusNoContent)
+	tr.DBChanges().AssertEmpty()
+	s.Auditor.ExpectEvents(t /*, nothing */)
+
+	// happy case (ID 1 was used up by the dry run)
+	s.Handler.RespondTo(s.Ctx, "PUT /rates/v2/projects/uuid-for-berlin",
+		httptest.WithJSONBody(makeRequest(false, map[string]any{"objects:create": map[string]any{"limit": 10, "window": "1m"}})),
+	).ExpectStatus(t, http.StatusNoContent)
+	tr.DBChanges().AssertEqualf(`
+		INSERT INTO project_rates (id, project_id, rate_id, rate_limit, window_ns, usage_as_bigint) VALUES (2, 1, %d, 10, 60000000000, '0');
+	`, s.GetRateID("first", "objects:create"))
+	s.Auditor.ExpectEvents(t, makeEvent(http.StatusNoContent, "objects:create", audit.RateLimitChange{
+		Unit:      liquid.UnitPiece,
+		OldLimit:  5,
+		NewLimit:  10,
+		OldWindow: 1 * limesrates.WindowMinutes,
+		NewWindow: 1 * limesrates.WindowMinutes,
+	}))
+
+	// requesting the existing rate limit does not change anything
+	s.Handler.RespondTo(s.Ctx, "PUT /rates/v2/projects/uuid-for-berlin",
+		httptest.WithJSONBody(makeRequest(false, map[string]any{"objects:create": map[string]any{"limit": 10, "window": "1m"}})),
+	).ExpectStatus(t, http.StatusNoContent)
+	tr.DBChanges().AssertEmpty()
+	s.Auditor.ExpectEvents(t /*, nothing */)
+
+	// validation errors are reported per rate; if any rate limit is unacceptable, nothing is changed
+	s.Handler.RespondTo(s.Ctx, "PUT /rates/v2/projects/uuid-for-berlin",
+		httptest.WithJSONBody(makeRequest(false, map[string]any{
+			"objects:delete":  map[string]any{"limit": 10, "window": "1m"},
+			"objects:unknown": map[string]any{"limit": 10, "window": "1m"},
+			"objects:update":  map[string]any{"limit": 5, "window": "1s"},
+		})),
+	).ExpectJSON(t, http.StatusUnprocessableEntity, jsonmatch.Object{
+		"unacceptable_rate_limits": jsonmatch.Array{
+			jsonmatch.Object{"service_type": "first", "rate_name": "objects:delete", "status": 403, "message": "user is not allowed to create new rate limits"},
+			jsonmatch.Object{"service_type": "first", "rate_name": "objects:unknown", "status": 404, "message": "no such rate"},
+		},
+	})
+	tr.DBChanges().AssertEmpty()
+	s.Auditor.ExpectEvents(t,
+		makeEvent(http.StatusUnprocessableEntity, "objects:delete", audit.RateLimitChange{
+			NewLimit:     10,
+			NewWindow:    1 * limesrates.WindowMinutes,
+			RejectReason: "user is not allowed to create new rate limits",
+		}),
+		makeEvent(http.StatusUnprocessableEntity, "objects:update", audit.RateLimitChange{
+			Unit:         liquid.UnitPiece,
+			OldLimit:     2,
+			NewLimit:     5,
+			OldWindow:    1 * limesrates.WindowSeconds,
+			NewWindow:    1 * limesrates.WindowSeconds,
+			RejectReason: "cannot commit this because other values in this request are unacceptable",
+		}),
+		// objects:unknown does not exist, so it cannot be represented in the audit trail
+	)
+
+	// when all errors have the same status, that status is reported
+	s.TokenValidator.Enforcer.RejectServiceType = "first"
+	s.Handler.RespondTo(s.Ctx, "PUT /rates/v2/projects/uuid-for-berlin",
+		httptest.WithJSONBody(makeRequest(false, map[string]any{"objects:update": map[string]any{"limit": 5, "window": "1s"}})),
+	).ExpectJSON(t, http.StatusForbidden, jsonmatch.Object{
+		"unacceptable_rate_limits": jsonmatch.Array{
+			jsonmatch.Object{"service_type": "first", "rate_name": "objects:update", "status": 403, "message": `user is not allowed to set "first" rate limits`},
+		},
+	})
+	s.TokenValidator.Enforcer.RejectServiceType = ""
+	tr.DBChanges().AssertEmpty()
+	s.Auditor.ExpectEvents(t, makeEvent(http.StatusForbidden, "objects:update", audit.RateLimitChange{
+		Unit:         liquid.UnitPiece,
+		OldLimit:     2,
+		NewLimit:     5,
+		OldWindow:    1 * limesrates.WindowSeconds,
+		NewWindow:    1 * limesrates.WindowSeconds,
+		RejectReason: `user is not allowed to set "first" rate limits`,
+	}))
+
+	// the same validation is performed on dry runs, but without generating audit events
+	s.Handler.RespondTo(s.Ctx, "PUT /rates/v2/projects/uuid-for-berlin",
+		httptest.WithJSONBody(makeRequest(true, map[string]any{"objects:delete": map[string]any{"limit": 10, "window": "1m"}})),
+	).ExpectJSON(t, http.StatusForbidden, jsonmatch.Object{
+		"unacceptable_rate_limits": jsonmatch.Array{
+			jsonmatch.Object{"service_type": "first", "rate_name": "objects:delete", "status": 403, "message": "user is not allowed to create new rate limits"},
+		},
+	})
+	s.Auditor.ExpectEvents(t /*, nothing */)
+}
@@ -75,26 +75,28 @@ var (
 	`)
 
 	findAZResourceIDByLocationQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
-		SELECT azr.id, pr.forbidden IS NOT TRUE as resource_allows_commitments, COALESCE(total_confirmed, 0) as total_confirmed
+		SELECT azr.id, pr.forbidden IS NOT TRUE as resource_allows_commitments, COALESCE(total_confirmed, 0) as total_confirmed, COALESCE(total_guaranteed, 0) as total_guaranteed
 		FROM az_resources azr
 		JOIN resources r ON azr.resource_id = r.id
 		JOIN project_resources pr ON pr.resource_id = r.id
 		LEFT JOIN (
-			SELECT SUM(pc.amount) as total_confirmed
+			SELECT SUM(pc.amount) FILTER (WHERE pc.status = {{liquid.CommitmentStatusConfirmed}}) as total_confirmed,
+			       SUM(pc.amount) FILTER (WHERE pc.status = {{liquid.CommitmentStatusGuaranteed}}) as total_guaranteed
 			FROM az_resources azr
 			JOIN project_commitments pc ON azr.id = pc.az_resource_id
-			WHERE pc.project_id = $1 AND azr.path = $2 AND status = {{liquid.CommitmentStatusConfirmed}}
+			WHERE pc.project_id = $1 AND azr.path = $2
 		) pc ON 1=1
 		WHERE pr.project_id = $1 AND azr.path = $2
 	`))
 
 	findAZResourceLocationByIDQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
-		SELECT azr.path, COALESCE(pc.total_confirmed,0) AS total_confirmed
+		SELECT azr.path, COALESCE(pc.total_confirmed,0) AS total_confirmed, COALESCE(pc.total_guaranteed,0) AS total_guaranteed
 		FROM az_resources azr
 		LEFT JOIN (
-				SELECT SUM(amount) as total_confirmed
+				SELECT SUM(amount) FILTER (WHERE status = {{liquid.CommitmentStatusConfirmed}}) as total_confirmed,
+				       SUM(amount) FILTER (WHERE status = {{liquid.CommitmentStatusGuaranteed}}) as total_guaranteed
 				FROM project_commitments pc
-				WHERE az_resource_id = $1 AND project_id = $2 AND status = {{liquid.CommitmentStatusConfirmed}}
+				WHERE az_resource_id = $1 AND project_id = $2
 		) pc ON 1=1
 		WHERE azr.id = $1;
 	`))
@@ -169,6 +171,62 @@ func (p *v1Provider) GetProjectCommitments(w http.ResponseWriter, r *http.Reques
 	respondwith.JSON(w, http.StatusOK, map[string]any{"commitments": result})
 }
 
+// GetProjectCommitment handles GET /v1/domains/:domain_id/projects/:project_id/commitments/:id.
+func (p *v1Provider) GetProjectCommitment(w http.ResponseWriter, r *http.Request) {
+	httpapi.IdentifyEndpoint(r, "/v1/domains/:id/projects/:id/commitments/:id")
+	token := p.CheckToken(r)
+	if !token.Require(w, "project:show") {
+		return
+	}
+	dbDomain := p.FindDomainFromRequest(w, r)
+	if dbDomain == nil {
+		return
+	}
+	dbProject := p.FindProjectFromRequest(w, r, dbDomain)
+	if dbProject == nil {
+		return
+	}
+
+	// load commitment (like in GetProjectCommitments, commitments that have ended their lifecycle are not shown)
+	var dbCommitment db.ProjectCommitment
+	err := p.DB.SelectOne(&dbCommitment, findProjectCommitmentByIDQuery, mux.Vars(r)["id"], dbProject.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		http.Error(w, "no such commitment", http.StatusNotFound)
+		return
+	} else if respondwith.ObfuscatedErrorText(w, err) {
+		return
+	}
+	if slices.Contains([]liquid.CommitmentStatus{liquid.CommitmentStatusSuperseded, liquid.CommitmentStatusExpired, util.CommitmentStatusDeleted}, dbCommitment.Status) {
+		http.Error(w, "no such commitment", http.StatusNotFound)
+		return
+	}
+	var (
+		path            db.AZResourcePath
+		totalConfirmed  uint64
+		totalGuaranteed uint64
+	)
+	err = p.DB.QueryRow(findAZResourceLocationByIDQuery, dbCommitment.AZResourceID, dbProject.ID).
+		Scan(&path, &totalConfirmed, &totalGuaranteed)
+	if errors.Is(err, sql.ErrNoRows) {
+		// defense in depth: this should not happen because all the relevant tables are connected by FK constraints
+		http.Error(w, "no route to this commitment", http.StatusNotFound)
+		return
+	} else if respondwith.ObfuscatedErrorText(w, err) {
+		return
+	}
+	sis := p.Cluster.SIC.GetSnapshot()
+	resource, rExists := sis.GetResourceForPath(path.Resource())
+	if !rExists {
+		http.Error(w, "service or resource not found", http.StatusNotFound)
+		return
+	}
+
+	// the version headers allow the client to make subsequent modifications of this commitment conditional
+	c := datamodel.ConvertCommitmentToDisplayForm(dbCommitment, path.AvailabilityZone, p.Cluster.BehaviorForResourcePath(path.Resource()).IdentityInV1API, datamodel.CanDeleteCommitment(token, dbCommitment, p.timeNow), resource.Unit)
+	setCommitmentVersionHeaders(w, dbCommitment)
+	respondwith.JSON(w, http.StatusOK, map[string]any{"commitment": c})
+}
+
 // GetPublicCommitments handles GET /v1/public-commitments.
 func (p *v1Provider) GetPublicCommitments(w http.ResponseWriter, r *http.Request) {
 	httpapi.IdentifyEndpoint(r, "/v1/public-commitments")
@@ -391,9 +449,10 @@ func (p *v1Provider) CanConfirmNewProjectCommitment(w http.ResponseWriter, r *ht
 		azResourceID              db.AZResourceID
 		resourceAllowsCommitments bool
 		totalConfirmed            uint64
+		totalGuaranteed           uint64
 	)
 	err := p.DB.QueryRow(findAZResourceIDByLocationQuery, dbProject.ID, path).
-		Scan(&azResourceID, &resourceAllowsCommitments, &totalConfirmed)
+		Scan(&azResourceID, &resourceAllowsCommitments, &totalConfirmed, &totalGuaranteed)
 	if respondwith.ObfuscatedErrorText(w, err) {
 		return
 	}
@@ -445,7 +504,7 @@ func (p *v1Provider) CanConfirmNewProjectCommitment(w http.ResponseWriter, r *ht
 	if respondwith.ObfuscatedErrorText(w, err) {
 		return
 	}
-	result := !commitmentChangeRequestWasRejected(ccr, w, false)
+	result := !commitmentChangeRequestWasRejected(ccr, w, false, now)
 	respondwith.JSON(w, http.StatusOK, map[string]bool{"result": result})
 }
 
@@ -482,9 +541,10 @@ func (p *v1Provider) CreateProjectCommitment(w http.ResponseWriter, r *http.Requ
 		azResourceID              db.AZResourceID
 		resourceAllowsCommitments bool
 		totalConfirmed            uint64
+		totalGuaranteed           uint64
 	)
 	err := p.DB.QueryRow(findAZResourceIDByLocationQuery, dbProject.ID, path).
-		Scan(&azResourceID, &resourceAllowsCommitments, &totalConfirmed)
+		Scan(&azResourceID, &resourceAllowsCommitments, &totalConfirmed, &totalGuaranteed)
 	if respondwith.ObfuscatedErrorText(w, err) {
 		return
 	}
@@ -568,7 +628,7 @@ func (p *v1Provider) CreateProjectCommitment(w http.ResponseWriter, r *http.Requ
 		if respondwith.ObfuscatedErrorText(w, err) {
 			return
 		}
-		if commitmentChangeRequestWasRejected(result, w, true) {
+		if commitmentChangeRequestWasRejected(result, w, true, now) {
 			return
 		}
 
@@ -592,11 +652,10 @@ func (p *v1Provider) CreateProjectCommitment(w http.ResponseWriter, r *http.Requ
 					ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(*dbProject, *dbDomain),
 					ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
 						path.ResourceName: {
-							TotalConfirmedBefore: totalConfirmed,
-							TotalConfirmedAfter:  totalConfirmed,
-							// TODO: change when introducing "guaranteed" commitments
-							TotalGuaranteedBefore: 0,
-							TotalGuaranteedAfter:  0,
+							TotalConfirmedBefore:  totalConfirmed,
+							TotalConfirmedAfter:   totalConfirmed,
+							TotalGuaranteedBefore: totalGuaranteed,
+							TotalGuaranteedAfter:  totalGuaranteed,
 							Commitments: []liquid.Commitment{
 								{
 									UUID:      dbCommitment.UUID,
@@ -616,7 +675,7 @@ func (p *v1Provider) CreateProjectCommitment(w http.ResponseWriter, r *http.Requ
 		if respondwith.ObfuscatedErrorText(w, err) {
 			return
 		}
-		if ccr.RequiresConfirmation() && commitmentChangeRequestWasRejected(commitmentChangeResponse, w, true) {
+		if ccr.RequiresConfirmation() && commitmentChangeRequestWasRejected(commitmentChangeResponse, w, true, now) {
 			return
 		}
 		// TODO: change when introducing "guaranteed" commitments
@@ -724,11 +783,12 @@ func (p *v1Provider) MergeProjectCommitments(w http.ResponseWriter, r *http.Requ
 	}
 
 	var (
-		path           db.AZResourcePath
-		totalConfirmed uint64
+		path            db.AZResourcePath
+		totalConfirmed  uint64
+		totalGuaranteed uint64
 	)
 	err := p.DB.QueryRow(findAZResourceLocationByIDQuery, azResourceID, dbProject.ID).
-		Scan(&path, &totalConfirmed)
+		Scan(&path, &totalConfirmed, &totalGuaranteed)
 	if errors.Is(err, sql.ErrNoRows) {
 		http.Error(w, "no route to this commitment", http.StatusNotFound)
 		return
@@ -845,11 +905,10 @@ func (p *v1Provider) MergeProjectCommitments(w http.ResponseWriter, r *http.Requ
 				ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(*dbProject, *dbDomain),
 				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
 					path.ResourceName: {
-						TotalConfirmedBefore: totalConfirmed,
-						TotalConfirmedAfter:  totalConfirmed,
-						// TODO: change when introducing "guaranteed" commitments
-						TotalGuaranteedBefore: 0,
-						TotalGuaranteedAfter:  0,
+						TotalConfirmedBefore:  totalConfirmed,
+						TotalConfirmedAfter:   totalConfirmed,
+						TotalGuaranteedBefore: totalGuaranteed,
+						TotalGuaranteedAfter:  totalGuaranteed,
 						Commitments:           liquidCommitments,
 					},
 				},
@@ -884,9 +943,6 @@ func (p *v1Provider) MergeProjectCommitments(w http.ResponseWriter, r *http.Requ
 	respondwith.JSON(w, http.StatusAccepted, map[string]any{"commitment": c})
 }
 
-// As per the API spec, commitments can be renewed 90 days in advance at the earliest.
-const commitmentRenewalPeriod = 90 * 24 * time.Hour
-
 // RenewProjectCommitments handles POST /v1/domains/:domain_id/projects/:project_id/commitments/:id/renew.
 func (p *v1Provider) RenewProjectCommitments(w http.ResponseWriter, r *http.Request) {
 	httpapi.IdentifyEndpoint(r, "/v1/domains/:id/projects/:id/commitments/:id/renew")
@@ -912,6 +968,9 @@ func (p *v1Provider) RenewProjectCommitments(w http.ResponseWriter, r *http.Requ
 	} else if respondwith.ObfuscatedErrorText(w, err) {
 		return
 	}
+	if !checkCommitmentPreconditions(w, r, dbCommitment) {
+		return
+	}
 	now := p.timeNow()
 
 	// Check if commitment can be renewed
@@ -921,7 +980,7 @@ func (p *v1Provider) RenewProjectCommitments(w http.ResponseWriter, r *http.Requ
 	} else if now.After(dbCommitment.ExpiresAt) {
 		errs.Addf("invalid status %q", liquid.CommitmentStatusExpired)
 	}
-	if now.Before(dbCommitment.ExpiresAt.Add(-commitmentRenewalPeriod)) {
+	if now.Before(dbCommitment.ExpiresAt.Add(-core.CommitmentRenewalPeriod)) {
 		errs.Addf("renewal attempt too early")
 	}
 	if dbCommitment.RenewContextJSON.IsSome() {
@@ -940,13 +999,12 @@ func (p *v1Provider) RenewProjectCommitments(w http.ResponseWriter, r *http.Requ
 		return
 	}
 	defer sqlext.RollbackUnlessCommitted(tx)
+	if !lockCommitmentForUpdate(w, tx, dbCommitment) {
+		return
+	}
 
-	var (
-		path           db.AZResourcePath
-		totalConfirmed uint64
-	)
-	err = tx.QueryRow(findAZResourceLocationByIDQuery, dbCommitment.AZResourceID, dbProject.ID).
-		Scan(&path, &totalConfirmed)
+	var path db.AZResourcePath
+	err = tx.QueryRow(`SELECT path FROM az_resources WHERE id = $1`, dbCommitment.AZResourceID).Scan(&path)
 	if errors.Is(err, sql.ErrNoRows) {
 		http.Error(w, "no route to this commitment", http.StatusNotFound)
 		return
@@ -960,85 +1018,14 @@ func (p *v1Provider) RenewProjectCommitments(w http.ResponseWriter, r *http.Requ
 		http.Error(w, "service or resource not found", http.StatusNotFound)
 		return
 	}
-	service := must.BeOK(sis.GetServiceForType(path.ServiceType))
-
-	creationContext := db.CommitmentWorkflowContext{
-		Reason:                 db.CommitmentReasonRenew,
-		RelatedCommitmentIDs:   []db.ProjectCommitmentID{dbCommitment.ID},
-		RelatedCommitmentUUIDs: []liquid.CommitmentUUID{dbCommitment.UUID},
-	}
-	buf, err := json.Marshal(creationContext)
-	if respondwith.ObfuscatedErrorText(w, err) {
-		return
-	}
-	dbRenewedCommitment := db.ProjectCommitment{
-		UUID:                p.generateProjectCommitmentUUID(),
-		ProjectID:           dbProject.ID,
-		AZResourceID:        dbCommitment.AZResourceID,
-		Amount:              dbCommitment.Amount,
-		Duration:            dbCommitment.Duration,
-		CreatedAt:           now,
-		UpdatedAt:           now,
-		CreatorUUID:         token.UserUUID(),
-		CreatorName:         fmt.Sprintf("%s@%s", token.UserName(), token.UserDomainName()),
-		ConfirmBy:           Some(dbCommitment.ExpiresAt),
-		ExpiresAt:           dbCommitment.Duration.AddTo(dbCommitment.ExpiresAt),
-		Status:              liquid.CommitmentStatusPlanned,
-		CreationContextJSON: json.RawMessage(buf),
-	}
-
-	err = tx.Insert(&dbRenewedCommitment)
-	if respondwith.ObfuscatedErrorText(w, err) {
-		return
-	}
-
-	renewContext := db.CommitmentWorkflowContext{
-		Reason:                 db.CommitmentReasonRenew,
-		RelatedCommitmentIDs:   []db.ProjectCommitmentID{dbRenewedCommitment.ID},
-		RelatedCommitmentUUIDs: []liquid.CommitmentUUID{dbRenewedCommitment.UUID},
-	}
-	buf, err = json.Marshal(renewContext)
-	if respondwith.ObfuscatedErrorText(w, err) {
-		return
-	}
-	dbCommitment.UpdatedAt = now
-	dbCommitment.RenewContextJSON = Some(json.RawMessage(buf))
-	_, err = tx.Update(&dbCommitment)
-	if respondwith.ObfuscatedErrorText(w, err) {
-		return
-	}
-
-	// TODO: for now, this is CommitmentChangeRequest.RequiresConfirmation() = false, because totalConfirmed stays and guaranteed is not used yet.
-	// when we change this, we need to evaluate the response of the liquid
-	ccr := liquid.CommitmentChangeRequest{
-		AZ:          path.AvailabilityZone,
-		InfoVersion: service.LiquidVersion,
-		ByProject: map[liquid.ProjectUUID]liquid.ProjectCommitmentChangeset{
-			dbProject.UUID: {
-				ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(*dbProject, *dbDomain),
-				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
-					path.ResourceName: {
-						TotalConfirmedBefore: totalConfirmed,
-						TotalConfirmedAfter:  totalConfirmed,
-						// TODO: change when introducing "guaranteed" commitments
-						TotalGuaranteedBefore: 0,
-						TotalGuaranteedAfter:  0,
-						Commitments: []liquid.Commitment{
-							{
-								UUID:      dbRenewedCommitment.UUID,
-								OldStatus: None[liquid.CommitmentStatus](),
-								NewStatus: Some(liquid.CommitmentStatusPlanned),
-								Amount:    dbRenewedCommitment.Amount,
-								ConfirmBy: dbRenewedCommitment.ConfirmBy,
-								ExpiresAt: dbRenewedCommitment.ExpiresAt,
-							},
-						},
-					},
-				},
-			},
-		},
-	}
-	_, err = datamodel.DelegateChangeCommitments(r.Context(), p.Cluster, ccr, sis, service.Type, tx)
+	dbRenewedCommitment, ccr, err := datamodel.RenewCommitment(r.Context(), p.Cluster, tx, datamodel.CommitmentRenewal{
+		Commitment:  dbCommitment,
+		Project:     *dbProject,
+		Domain:      *dbDomain,
+		Path:        path,
+		CreatorUUID: token.UserUUID(),
+		CreatorName: fmt.Sprintf("%s@%s", token.UserName(), token.UserDomainName()),
+	}, sis, now, p.generateProjectCommitmentUUID)
 	if respondwith.ObfuscatedErrorText(w, err) {
 		return
 	}
@@ -1064,6 +1051,7 @@ func (p *v1Provider) RenewProjectCommitments(w http.ResponseWriter, r *http.Requ
 		p.auditor.Record(event)
 	}
 
+	setCommitmentVersionHeaders(w, dbRenewedCommitment)
 	respondwith.JSON(w, http.StatusAccepted, map[string]any{"commitment": c})
 }
 
@@ -1093,11 +1081,12 @@ func (p *v1Provider) DeleteProjectCommitment(w http.ResponseWriter, r *http.Requ
 		return
 	}
 	var (
-		path           db.AZResourcePath
-		totalConfirmed uint64
+		path            db.AZResourcePath
+		totalConfirmed  uint64
+		totalGuaranteed uint64
 	)
 	err = p.DB.QueryRow(findAZResourceLocationByIDQuery, dbCommitment.AZResourceID, dbProject.ID).
-		Scan(&path, &totalConfirmed)
+		Scan(&path, &totalConfirmed, &totalGuaranteed)
 	if errors.Is(err, sql.ErrNoRows) {
 		// defense in depth: this should not happen because all the relevant tables are connected by FK constraints
 		http.Error(w, "no route to this commitment", http.StatusNotFound)
@@ -1127,8 +1116,12 @@ func (p *v1Provider) DeleteProjectCommitment(w http.ResponseWriter, r *http.Requ
 	}
 
 	totalConfirmedAfter := totalConfirmed
-	if dbCommitment.Status == liquid.CommitmentStatusConfirmed {
+	totalGuaranteedAfter := totalGuaranteed
+	switch dbCommitment.Status {
+	case liquid.CommitmentStatusConfirmed:
 		totalConfirmedAfter -= dbCommitment.Amount
+	case liquid.CommitmentStatusGuaranteed:
+		totalGuaranteedAfter -= dbCommitment.Amount
 	}
 
 	ccr := liquid.CommitmentChangeRequest{
@@ -1139,11 +1132,10 @@ func (p *v1Provider) DeleteProjectCommitment(w http.ResponseWriter, r *http.Requ
 				ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(*dbProject, *dbDomain),
 				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
 					path.ResourceName: {
-						TotalConfirmedBefore: totalConfirmed,
-						TotalConfirmedAfter:  totalConfirmedAfter,
-						// TODO: change when introducing "guaranteed" commitments
-						TotalGuaranteedBefore: 0,
-						TotalGuaranteedAfter:  0,
+						TotalConfirmedBefore:  totalConfirmed,
+						TotalConfirmedAfter:   totalConfirmedAfter,
+						TotalGuaranteedBefore: totalGuaranteed,
+						TotalGuaranteedAfter:  totalGuaranteedAfter,
 						Commitments: []liquid.Commitment{
 							{
 								UUID:      dbCommitment.UUID,
@@ -1244,6 +1236,10 @@ func (p *v1Provider) StartCommitmentTransfer(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
+	if !checkCommitmentPreconditions(w, r, dbCommitment) {
+		return
+	}
+
 	// Deny requests which do not change the current transfer status.
 	if dbCommitment.TransferStatus == req.TransferStatus {
 		http.Error(w, "transfer_status is already set to desired value", http.StatusBadRequest)
@@ -1265,11 +1261,12 @@ func (p *v1Provider) StartCommitmentTransfer(w http.ResponseWriter, r *http.Requ
 	}
 
 	var (
-		path           db.AZResourcePath
-		totalConfirmed uint64
+		path            db.AZResourcePath
+		totalConfirmed  uint64
+		totalGuaranteed uint64
 	)
 	err = p.DB.QueryRow(findAZResourceLocationByIDQuery, dbCommitment.AZResourceID, dbProject.ID).
-		Scan(&path, &totalConfirmed)
+		Scan(&path, &totalConfirmed, &totalGuaranteed)
 	if errors.Is(err, sql.ErrNoRows) {
 		// defense in depth: this should not happen because all the relevant tables are connected by FK constraints
 		http.Error(w, "no route to this commitment", http.StatusNotFound)
@@ -1301,6 +1298,9 @@ func (p *v1Provider) StartCommitmentTransfer(w http.ResponseWriter, r *http.Requ
 		return
 	}
 	defer sqlext.RollbackUnlessCommitted(tx)
+	if !lockCommitmentForUpdate(w, tx, dbCommitment) {
+		return
+	}
 
 	// if not split, the ccr is just used for audit logging
 	ccr := liquid.CommitmentChangeRequest{
@@ -1311,11 +1311,10 @@ func (p *v1Provider) StartCommitmentTransfer(w http.ResponseWriter, r *http.Requ
 				ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(*dbProject, *dbDomain),
 				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
 					path.ResourceName: {
-						TotalConfirmedBefore: totalConfirmed,
-						TotalConfirmedAfter:  totalConfirmed,
-						// TODO: change when introducing "guaranteed" commitments
-						TotalGuaranteedBefore: 0,
-						TotalGuaranteedAfter:  0,
+						TotalConfirmedBefore:  totalConfirmed,
+						TotalConfirmedAfter:   totalConfirmed,
+						TotalGuaranteedBefore: totalGuaranteed,
+						TotalGuaranteedAfter:  totalGuaranteed,
 					},
 				},
 			},
@@ -1454,6 +1453,7 @@ func (p *v1Provider) StartCommitmentTransfer(w http.ResponseWriter, r *http.Requ
 	for _, event := range auditEvents {
 		p.auditor.Record(event)
 	}
+	setCommitmentVersionHeaders(w, dbCommitment)
 	respondwith.JSON(w, http.StatusAccepted, map[string]any{"commitment": c})
 }
 
@@ -1506,11 +1506,12 @@ func (p *v1Provider) GetCommitmentByTransferToken(w http.ResponseWriter, r *http
 	}
 
 	var (
-		path           db.AZResourcePath
-		totalConfirmed uint64
+		path            db.AZResourcePath
+		totalConfirmed  uint64
+		totalGuaranteed uint64
 	)
 	err = p.DB.QueryRow(findAZResourceLocationByIDQuery, dbCommitment.AZResourceID, dbCommitment.ProjectID).
-		Scan(&path, &totalConfirmed)
+		Scan(&path, &totalConfirmed, &totalGuaranteed)
 	if errors.Is(err, sql.ErrNoRows) {
 		// defense in depth: this should not happen because all the relevant tables are connected by FK constraints
 		http.Error(w, "location data not found.", http.StatusNotFound)
@@ -1566,11 +1567,12 @@ func (p *v1Provider) TransferCommitment(w http.ResponseWriter, r *http.Request)
 	}
 
 	var (
-		path                 db.AZResourcePath
-		sourceTotalConfirmed uint64
+		path                  db.AZResourcePath
+		sourceTotalConfirmed  uint64
+		sourceTotalGuaranteed uint64
 	)
 	err = p.DB.QueryRow(findAZResourceLocationByIDQuery, dbCommitment.AZResourceID, dbCommitment.ProjectID).
-		Scan(&path, &sourceTotalConfirmed)
+		Scan(&path, &sourceTotalConfirmed, &sourceTotalGuaranteed)
 
 	if errors.Is(err, sql.ErrNoRows) {
 		// defense in depth: this should not happen because all the relevant tables are connected by FK constraints
@@ -1605,9 +1607,10 @@ func (p *v1Provider) TransferCommitment(w http.ResponseWriter, r *http.Request)
 		azResourceID              db.AZResourceID
 		resourceAllowsCommitments bool
 		targetTotalConfirmed      uint64
+		targetTotalGuaranteed     uint64
 	)
 	err = p.DB.QueryRow(findAZResourceIDByLocationQuery, targetProject.ID, path).
-		Scan(&azResourceID, &resourceAllowsCommitments, &targetTotalConfirmed)
+		Scan(&azResourceID, &resourceAllowsCommitments, &targetTotalConfirmed, &targetTotalGuaranteed)
 	if respondwith.ObfuscatedErrorText(w, err) {
 		return
 	}
@@ -1627,9 +1630,15 @@ func (p *v1Provider) TransferCommitment(w http.ResponseWriter, r *http.Request)
 
 	sourceTotalConfirmedAfter := sourceTotalConfirmed
 	targetTotalConfirmedAfter := targetTotalConfirmed
-	if dbCommitment.Status == liquid.CommitmentStatusConfirmed {
+	sourceTotalGuaranteedAfter := sourceTotalGuaranteed
+	targetTotalGuaranteedAfter := targetTotalGuaranteed
+	switch dbCommitment.Status {
+	case liquid.CommitmentStatusConfirmed:
 		sourceTotalConfirmedAfter -= dbCommitment.Amount
 		targetTotalConfirmedAfter += dbCommitment.Amount
+	case liquid.CommitmentStatusGuaranteed:
+		sourceTotalGuaranteedAfter -= dbCommitment.Amount
+		targetTotalGuaranteedAfter += dbCommitment.Amount
 	}
 
 	// check move is allowed
@@ -1647,11 +1656,10 @@ func (p *v1Provider) TransferCommitment(w http.ResponseWriter, r *http.Request)
 				ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(sourceProject, sourceDomain),
 				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
 					path.ResourceName: {
-						TotalConfirmedBefore: sourceTotalConfirmed,
-						TotalConfirmedAfter:  sourceTotalConfirmedAfter,
-						// TODO: change when introducing "guaranteed" commitments
-						TotalGuaranteedBefore: 0,
-						TotalGuaranteedAfter:  0,
+						TotalConfirmedBefore:  sourceTotalConfirmed,
+						TotalConfirmedAfter:   sourceTotalConfirmedAfter,
+						TotalGuaranteedBefore: sourceTotalGuaranteed,
+						TotalGuaranteedAfter:  sourceTotalGuaranteedAfter,
 						Commitments: []liquid.Commitment{
 							{
 								UUID:      dbCommitment.UUID,
@@ -1669,11 +1677,10 @@ func (p *v1Provider) TransferCommitment(w http.ResponseWriter, r *http.Request)
 				ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(*targetProject, *targetDomain),
 				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
 					path.ResourceName: {
-						TotalConfirmedBefore: targetTotalConfirmed,
-						TotalConfirmedAfter:  targetTotalConfirmedAfter,
-						// TODO: change when introducing "guaranteed" commitments
-						TotalGuaranteedBefore: 0,
-						TotalGuaranteedAfter:  0,
+						TotalConfirmedBefore:  targetTotalConfirmed,
+						TotalConfirmedAfter:   targetTotalConfirmedAfter,
+						TotalGuaranteedBefore: targetTotalGuaranteed,
+						TotalGuaranteedAfter:  targetTotalGuaranteedAfter,
 						Commitments: []liquid.Commitment{
 							{
 								UUID:      dbCommitment.UUID,
@@ -1693,7 +1700,7 @@ func (p *v1Provider) TransferCommitment(w http.ResponseWriter, r *http.Request)
 	if respondwith.ObfuscatedErrorText(w, err) {
 		return
 	}
-	if commitmentChangeRequestWasRejected(commitmentChangeResponse, w, true) {
+	if commitmentChangeRequestWasRejected(commitmentChangeResponse, w, true, p.timeNow()) {
 		return
 	}
 
@@ -1841,12 +1848,16 @@ func (p *v1Provider) ConvertCommitment(w http.ResponseWriter, r *http.Request) {
 	} else if respondwith.ObfuscatedErrorText(w, err) {
 		return
 	}
+	if !checkCommitmentPreconditions(w, r, dbCommitment) {
+		return
+	}
 	var (
-		sourcePath           db.AZResourcePath
-		sourceTotalConfirmed uint64
+		sourcePath            db.AZResourcePath
+		sourceTotalConfirmed  uint64
+		sourceTotalGuaranteed uint64
 	)
 	err = p.DB.QueryRow(findAZResourceLocationByIDQuery, dbCommitment.AZResourceID, dbProject.ID).
-		Scan(&sourcePath, &sourceTotalConfirmed)
+		Scan(&sourcePath, &sourceTotalConfirmed, &sourceTotalGuaranteed)
 	if errors.Is(err, sql.ErrNoRows) {
 		// defense in depth: this should not happen because all the relevant tables are connected by FK constraints
 		http.Error(w, "no route to this commitment", http.StatusNotFound)
@@ -1929,14 +1940,18 @@ func (p *v1Provider) ConvertCommitment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 	defer sqlext.RollbackUnlessCommitted(tx)
+	if !lockCommitmentForUpdate(w, tx, dbCommitment) {
+		return
+	}
 
 	var (
 		targetAZResourceID        db.AZResourceID
 		resourceAllowsCommitments bool
 		targetTotalConfirmed      uint64
+		targetTotalGuaranteed     uint64
 	)
 	err = p.DB.QueryRow(findAZResourceIDByLocationQuery, dbProject.ID, targetPath).
-		Scan(&targetAZResourceID, &resourceAllowsCommitments, &targetTotalConfirmed)
+		Scan(&targetAZResourceID, &resourceAllowsCommitments, &targetTotalConfirmed, &targetTotalGuaranteed)
 	if respondwith.ObfuscatedErrorText(w, err) {
 		return
 	}
@@ -1995,6 +2010,12 @@ func (p *v1Provider) ConvertCommitment(w http.ResponseWriter, r *http.Request) {
 		sourceTotalConfirmedAfter -= req.SourceAmount
 		targetTotalConfirmedAfter += req.TargetAmount
 	}
+	sourceTotalGuaranteedAfter := sourceTotalGuaranteed
+	targetTotalGuaranteedAfter := targetTotalGuaranteed
+	if dbCommitment.Status == liquid.CommitmentStatusGuaranteed {
+		sourceTotalGuaranteedAfter -= req.SourceAmount
+		targetTotalGuaranteedAfter += req.TargetAmount
+	}
 
 	ccr := liquid.CommitmentChangeRequest{
 		AZ:          sourcePath.AvailabilityZone,
@@ -2004,19 +2025,17 @@ func (p *v1Provider) ConvertCommitment(w http.ResponseWriter, r *http.Request) {
 				ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(*dbProject, *dbDomain),
 				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
 					sourcePath.ResourceName: {
-						TotalConfirmedBefore: sourceTotalConfirmed,
-						TotalConfirmedAfter:  sourceTotalConfirmedAfter,
-						// TODO: change when introducing "guaranteed" commitments
-						TotalGuaranteedBefore: 0,
-						TotalGuaranteedAfter:  0,
+						TotalConfirmedBefore:  sourceTotalConfirmed,
+						TotalConfirmedAfter:   sourceTotalConfirmedAfter,
+						TotalGuaranteedBefore: sourceTotalGuaranteed,
+						TotalGuaranteedAfter:  sourceTotalGuaranteedAfter,
 						Commitments:           sourceCommitments,
 					},
 					targetPath.ResourceName: {
-						TotalConfirmedBefore: targetTotalConfirmed,
-						TotalConfirmedAfter:  targetTotalConfirmedAfter,
-						// TODO: change when introducing "guaranteed" commitments
-						TotalGuaranteedBefore: 0,
-						TotalGuaranteedAfter:  0,
+						TotalConfirmedBefore:  targetTotalConfirmed,
+						TotalConfirmedAfter:   targetTotalConfirmedAfter,
+						TotalGuaranteedBefore: targetTotalGuaranteed,
+						TotalGuaranteedAfter:  targetTotalGuaranteedAfter,
 						Commitments: []liquid.Commitment{
 							{
 								UUID:      convertedCommitment.UUID,
@@ -2038,7 +2057,7 @@ func (p *v1Provider) ConvertCommitment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// only check acceptance by liquid when old commitment was confirmed, unconfirmed commitments can be moved without acceptance
-	if ccr.RequiresConfirmation() && commitmentChangeRequestWasRejected(commitmentChangeResponse, w, true) {
+	if ccr.RequiresConfirmation() && commitmentChangeRequestWasRejected(commitmentChangeResponse, w, true, p.timeNow()) {
 		return
 	}
 
@@ -2102,6 +2121,7 @@ func (p *v1Provider) ConvertCommitment(w http.ResponseWriter, r *http.Request) {
 	for _, event := range auditEvents {
 		p.auditor.Record(event)
 	}
+	setCommitmentVersionHeaders(w, convertedCommitment)
 	respondwith.JSON(w, http.StatusAccepted, map[string]any{"commitment": c})
 }
 
@@ -2142,6 +2162,10 @@ func (p *v1Provider) UpdateCommitmentDuration(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if !checkCommitmentPreconditions(w, r, dbCommitment) {
+		return
+	}
+
 	now := p.timeNow()
 	if dbCommitment.ExpiresAt.Before(now) || dbCommitment.ExpiresAt.Equal(now) {
 		http.Error(w, "unable to process expired commitment", http.StatusForbidden)
@@ -2155,11 +2179,12 @@ func (p *v1Provider) UpdateCommitmentDuration(w http.ResponseWriter, r *http.Req
 	}
 
 	var (
-		path           db.AZResourcePath
-		totalConfirmed uint64
+		path            db.AZResourcePath
+		totalConfirmed  uint64
+		totalGuaranteed uint64
 	)
 	err = p.DB.QueryRow(findAZResourceLocationByIDQuery, dbCommitment.AZResourceID, dbProject.ID).
-		Scan(&path, &totalConfirmed)
+		Scan(&path, &totalConfirmed, &totalGuaranteed)
 	if errors.Is(err, sql.ErrNoRows) {
 		// defense in depth: this should not happen because all the relevant tables are connected by FK constraints
 		http.Error(w, "no route to this commitment", http.StatusNotFound)
@@ -2198,11 +2223,10 @@ func (p *v1Provider) UpdateCommitmentDuration(w http.ResponseWriter, r *http.Req
 				ProjectMetadata: datamodel.LiquidProjectMetadataFromDBProject(*dbProject, *dbDomain),
 				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
 					path.ResourceName: {
-						TotalConfirmedBefore: totalConfirmed,
-						TotalConfirmedAfter:  totalConfirmed,
-						// TODO: change when introducing "guaranteed" commitments
-						TotalGuaranteedBefore: 0,
-						TotalGuaranteedAfter:  0,
+						TotalConfirmedBefore:  totalConfirmed,
+						TotalConfirmedAfter:   totalConfirmed,
+						TotalGuaranteedBefore: totalGuaranteed,
+						TotalGuaranteedAfter:  totalGuaranteed,
 						Commitments: []liquid.Commitment{
 							{
 								UUID:         dbCommitment.UUID,
@@ -2224,14 +2248,26 @@ func (p *v1Provider) UpdateCommitmentDuration(w http.ResponseWriter, r *http.Req
 		return
 	}
 
+	if commitmentChangeRequestWasRejected(commitmentChangeResponse, w, true, now) {
+		return
+	}
+
+	tx, err := p.DB.Begin()
+	if respondwith.ObfuscatedErrorText(w, err) {
+		return
+	}
+	defer sqlext.RollbackUnlessCommitted(tx)
+	if !lockCommitmentForUpdate(w, tx, dbCommitment) {
+		return
+	}
 	dbCommitment.Duration = req.Duration
 	dbCommitment.ExpiresAt = newExpiresAt
 	dbCommitment.UpdatedAt = now
-	if commitmentChangeRequestWasRejected(commitmentChangeResponse, w, true) {
+	_, err = tx.Update(&dbCommitment)
+	if respondwith.ObfuscatedErrorText(w, err) {
 		return
 	}
-
-	_, err = p.DB.Update(&dbCommitment)
+	err = tx.Commit()
 	if respondwith.ObfuscatedErrorText(w, err) {
 		return
 	}
@@ -2251,15 +2287,17 @@ func (p *v1Provider) UpdateCommitmentDuration(w http.ResponseWriter, r *http.Req
 		p.auditor.Record(event)
 	}
 
+	setCommitmentVersionHeaders(w, dbCommitment)
 	respondwith.JSON(w, http.StatusOK, map[string]any{"commitment": c})
 }
 
-func commitmentChangeRequestWasRejected(response liquid.CommitmentChangeResponse, w http.ResponseWriter, withHTTPResponse bool) bool {
+func commitmentChangeRequestWasRejected(response liquid.CommitmentChangeResponse, w http.ResponseWriter, withHTTPResponse bool, now time.Time) bool {
 	if response.RejectionReason == "" {
 		return false
 	}
-	if retryAt, exists := response.RetryAt.Unpack(); exists {
-		w.Header().Set("Retry-After", retryAt.Format(time.RFC1123))
+	// a RetryAt in the past (e.g. when the next capacity scrape is overdue) does not tell the client anything useful
+	if retryAt, exists := response.RetryAt.Unpack(); exists && retryAt.After(now) {
+		w.Header().Set("Retry-After", retryAt.UTC().Format(http.TimeFormat))
 	}
 	if withHTTPResponse {
 		http.Error(w, response.RejectionReason, http.StatusConflict)
@@ -2309,3 +2347,60 @@ func (p *v1Provider) prepareClusterAdminCommitmentEditRequest(w http.ResponseWri
 	mux.Vars(r)["domain_id"] = request.DomainUUID
 	return true
 }
+
+// commitmentETag returns the ETag that identifies the current version of the given commitment.
+// It is reported alongside the commitment by endpoints that modify a single commitment,
+// and can be given in the If-Match header to make further modifications of this commitment conditional.
+func commitmentETag(c db.ProjectCommitment) string {
+	// UpdatedAt is rounded to microseconds in the same way as when it is stored in the DB
+	return fmt.Sprintf(`"%s-%d"`, c.UUID, c.UpdatedAt.Round(time.Microsecond).UnixMicro())
+}
+
+// setCommitmentVersionHeaders sets the ETag and Last-Modified headers for a response containing the given commitment.
+func setCommitmentVersionHeaders(w http.ResponseWriter, c db.ProjectCommitment) {
+	w.Header().Set("ETag", commitmentETag(c))
+	w.Header().Set("Last-Modified", c.UpdatedAt.UTC().Format(http.TimeFormat))
+}
+
+// checkCommitmentPreconditions evaluates the If-Match and If-Unmodified-Since headers of a request that modifies the given commitment.
+// If the commitment has been modified since the client last looked at it, a 412 (Precondition Failed) response is written.
+func checkCommitmentPreconditions(w http.ResponseWriter, r *http.Request, c db.ProjectCommitment) bool {
+	// as per RFC 9110, section 13.2.2, If-Unmodified-Since is ignored when If-Match is given
+	if ifMatch := r.Header.Get("If-Match"); ifMatch != "" {
+		etag := commitmentETag(c)
+		for candidate := range strings.SplitSeq(ifMatch, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || candidate == etag {
+				return true
+			}
+		}
+		http.Error(w, "commitment has been modified in the meantime", http.StatusPreconditionFailed)
+		return false
+	}
+
+	if ifUnmodifiedSince := r.Header.Get("If-Unmodified-Since"); ifUnmodifiedSince != "" {
+		// as per RFC 9110, section 13.1.4, invalid dates are ignored
+		t, err := http.ParseTime(ifUnmodifiedSince)
+		if err == nil && c.UpdatedAt.Truncate(time.Second).After(t) {
+			http.Error(w, "commitment has been modified in the meantime", http.StatusPreconditionFailed)
+			return false
+		}
+	}
+	return true
+}
+
+// lockCommitmentForUpdate locks the given commitment for the remainder of the transaction.
+// If the commitment has been modified since it was loaded, a 412 (Precondition Failed) response is written.
+// This ensures that concurrent modifications of the same commitment cannot overwrite each other.
+func lockCommitmentForUpdate(w http.ResponseWriter, tx db.Interface, c db.ProjectCommitment) bool {
+	var updatedAt time.Time
+	err := tx.QueryRow(`SELECT updated_at FROM project_commitments WHERE id = $1 FOR UPDATE`, c.ID).Scan(&updatedAt)
+	if respondwith.ObfuscatedErrorText(w, err) {
+		return false
+	}
+	if !updatedAt.Equal(c.UpdatedAt) {
+		http.Error(w, "commitment has been modified in the meantime", http.StatusPreconditionFailed)
+		return false
+	}
+	return true
+}
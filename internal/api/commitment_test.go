@@ -5,6 +5,7 @@ package api_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"maps"
 	"net/http"
 	"testing"
@@ -1995,6 +1996,111 @@ func TestTransferCommitmentForbiddenResource(t *testing.T) {
 	}.Check(t, s.Handler)
 }
 
+func Test_GuaranteedCommitmentTotals(t *testing.T) {
+	s := setupCommitmentTest(t, string(must.Return(testCommitmentsJSONWithoutMinConfirmDate.MarshalJSON())))
+
+	// berlin has two guaranteed commitments, and the first of them is ready for transfer
+	for idx, amount := range []uint64{10, 5} {
+		c := db.ProjectCommitment{
+			UUID:                test.GenerateDummyCommitmentUUID(uint64(idx + 1)),
+			ProjectID:           s.GetProjectID("berlin"),
+			AZResourceID:        s.GetAZResourceID("second", "capacity", "az-two"),
+			Amount:              amount,
+			Duration:            must.Return(limesresources.ParseCommitmentDuration("1 hour")),
+			CreatedAt:           s.Clock.Now(),
+			UpdatedAt:           s.Clock.Now(),
+			CreatorUUID:         "uuid-for-alice",
+			CreatorName:         "alice@Default",
+			ExpiresAt:           s.Clock.Now().Add(1 * time.Hour),
+			CreationContextJSON: json.RawMessage(`{"reason": "create"}`),
+			Status:              liquid.CommitmentStatusGuaranteed,
+		}
+		if idx == 0 {
+			c.TransferStatus = limesresources.CommitmentTransferStatusUnlisted
+			c.TransferToken = Some(test.GenerateDummyTransferToken(1))
+			c.TransferStartedAt = Some(s.Clock.Now())
+		}
+		s.MustDBInsert(&c)
+	}
+
+	// transferring a guaranteed commitment moves its amount between the guaranteed totals of both projects
+	oldassert.HTTPRequest{
+		Method:       http.MethodPost,
+		Path:         "/v1/domains/uuid-for-germany/projects/uuid-for-dresden/transfer-commitment/1",
+		Header:       map[string]string{"Transfer-Token": test.GenerateDummyTransferToken(1)},
+		ExpectStatus: http.StatusAccepted,
+	}.Check(t, s.Handler)
+	assert.Equal(t, s.LiquidClients["second"].LastCommitmentChangeRequest, liquid.CommitmentChangeRequest{
+		AZ:          "az-two",
+		InfoVersion: 1,
+		ByProject: map[liquid.ProjectUUID]liquid.ProjectCommitmentChangeset{
+			"uuid-for-berlin": {
+				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
+					"capacity": {
+						TotalGuaranteedBefore: 15,
+						TotalGuaranteedAfter:  5,
+						Commitments: []liquid.Commitment{
+							{
+								UUID:      test.GenerateDummyCommitmentUUID(1),
+								OldStatus: Some(liquid.CommitmentStatusGuaranteed),
+								NewStatus: None[liquid.CommitmentStatus](),
+								Amount:    10,
+								ExpiresAt: s.Clock.Now().Add(1 * time.Hour),
+							},
+						},
+					},
+				},
+			},
+			"uuid-for-dresden": {
+				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
+					"capacity": {
+						TotalGuaranteedBefore: 0,
+						TotalGuaranteedAfter:  10,
+						Commitments: []liquid.Commitment{
+							{
+								UUID:      test.GenerateDummyCommitmentUUID(1),
+								NewStatus: Some(liquid.CommitmentStatusGuaranteed),
+								Amount:    10,
+								ExpiresAt: s.Clock.Now().Add(1 * time.Hour),
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+
+	// deleting a guaranteed commitment removes its amount from the guaranteed total
+	oldassert.HTTPRequest{
+		Method:       http.MethodDelete,
+		Path:         "/v1/domains/uuid-for-germany/projects/uuid-for-berlin/commitments/2",
+		ExpectStatus: http.StatusNoContent,
+	}.Check(t, s.Handler)
+	assert.Equal(t, s.LiquidClients["second"].LastCommitmentChangeRequest, liquid.CommitmentChangeRequest{
+		AZ:          "az-two",
+		InfoVersion: 1,
+		ByProject: map[liquid.ProjectUUID]liquid.ProjectCommitmentChangeset{
+			"uuid-for-berlin": {
+				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
+					"capacity": {
+						TotalGuaranteedBefore: 5,
+						TotalGuaranteedAfter:  0,
+						Commitments: []liquid.Commitment{
+							{
+								UUID:      test.GenerateDummyCommitmentUUID(2),
+								OldStatus: Some(liquid.CommitmentStatusGuaranteed),
+								NewStatus: None[liquid.CommitmentStatus](),
+								Amount:    5,
+								ExpiresAt: s.Clock.Now().Add(1 * time.Hour),
+							},
+						},
+					},
+				},
+			},
+		},
+	})
+}
+
 func Test_GetCommitmentConversion(t *testing.T) {
 	s := setupCommitmentTest(t, string(must.Return(testConvertCommitmentsJSON.MarshalJSON())))
 
@@ -2508,6 +2614,114 @@ func Test_UpdateCommitmentDuration(t *testing.T) {
 	}.Check(t, s.Handler)
 }
 
+func Test_CommitmentPreconditions(t *testing.T) {
+	s := setupCommitmentTest(t, string(must.Return(testCommitmentsJSONWithoutMinConfirmDate.MarshalJSON())))
+
+	oldassert.HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/v1/domains/uuid-for-germany/projects/uuid-for-berlin/commitments/new",
+		Body: oldassert.JSONObject{
+			"commitment": oldassert.JSONObject{
+				"service_type":      "second",
+				"resource_name":     "capacity",
+				"availability_zone": "az-one",
+				"amount":            10,
+				"duration":          "1 hour",
+			},
+		},
+		ExpectStatus: http.StatusCreated,
+	}.Check(t, s.Handler)
+	createdAt := s.Clock.Now()
+	s.Clock.StepBy(30 * time.Minute)
+
+	// the current version of a commitment can be obtained without modifying it
+	oldassert.HTTPRequest{
+		Method: http.MethodGet,
+		Path:   "/v1/domains/uuid-for-germany/projects/uuid-for-berlin/commitments/1",
+		ExpectHeader: map[string]string{
+			"ETag":          fmt.Sprintf(`"00000000-0000-0000-0000-000000000001-%d"`, createdAt.UnixMicro()),
+			"Last-Modified": createdAt.UTC().Format(http.TimeFormat),
+		},
+		ExpectStatus: http.StatusOK,
+	}.Check(t, s.Handler)
+	oldassert.HTTPRequest{
+		Method:       http.MethodGet,
+		Path:         "/v1/domains/uuid-for-germany/projects/uuid-for-berlin/commitments/2",
+		ExpectBody:   oldassert.StringData("no such commitment\n"),
+		ExpectStatus: http.StatusNotFound,
+	}.Check(t, s.Handler)
+
+	// Negative: commitment was modified after the given time, or has a different ETag
+	oldassert.HTTPRequest{
+		Method:       http.MethodPost,
+		Path:         "/v1/domains/uuid-for-germany/projects/uuid-for-berlin/commitments/1/update-duration",
+		Header:       map[string]string{"If-Unmodified-Since": createdAt.Add(-1 * time.Hour).UTC().Format(http.TimeFormat)},
+		Body:         oldassert.JSONObject{"duration": "2 hours"},
+		ExpectBody:   oldassert.StringData("commitment has been modified in the meantime\n"),
+		ExpectStatus: http.StatusPreconditionFailed,
+	}.Check(t, s.Handler)
+	oldassert.HTTPRequest{
+		Method:       http.MethodPost,
+		Path:         "/v1/domains/uuid-for-germany/projects/uuid-for-berlin/commitments/1/update-duration",
+		Header:       map[string]string{"If-Match": `"00000000-0000-0000-0000-000000000001-0"`},
+		Body:         oldassert.JSONObject{"duration": "2 hours"},
+		ExpectBody:   oldassert.StringData("commitment has been modified in the meantime\n"),
+		ExpectStatus: http.StatusPreconditionFailed,
+	}.Check(t, s.Handler)
+
+	// Positive: commitment was not modified since the given time; the response contains the new version of the commitment
+	etag := fmt.Sprintf(`"00000000-0000-0000-0000-000000000001-%d"`, s.Clock.Now().UnixMicro())
+	oldassert.HTTPRequest{
+		Method: http.MethodPost,
+		Path:   "/v1/domains/uuid-for-germany/projects/uuid-for-berlin/commitments/1/update-duration",
+		Header: map[string]string{"If-Unmodified-Since": createdAt.UTC().Format(http.TimeFormat)},
+		Body:   oldassert.JSONObject{"duration": "2 hours"},
+		ExpectHeader: map[string]string{
+			"ETag":          etag,
+			"Last-Modified": s.Clock.Now().UTC().Format(http.TimeFormat),
+		},
+		ExpectStatus: http.StatusOK,
+	}.Check(t, s.Handler)
+
+	// Positive: If-Match with the ETag from the previous response
+	s.Clock.StepBy(1 * time.Minute)
+	oldassert.HTTPRequest{
+		Method:       http.MethodPost,
+		Path:         "/v1/domains/uuid-for-germany/projects/uuid-for-berlin/commitments/1/update-duration",
+		Header:       map[string]string{"If-Match": etag},
+		Body:         oldassert.JSONObject{"duration": "3 hours"},
+		ExpectStatus: http.StatusOK,
+	}.Check(t, s.Handler)
+
+	// Negative: replaying the same request fails because the ETag is outdated now
+	oldassert.HTTPRequest{
+		Method:       http.MethodPost,
+		Path:         "/v1/domains/uuid-for-germany/projects/uuid-for-berlin/commitments/1/update-duration",
+		Header:       map[string]string{"If-Match": etag},
+		Body:         oldassert.JSONObject{"duration": "3 hours"},
+		ExpectBody:   oldassert.StringData("commitment has been modified in the meantime\n"),
+		ExpectStatus: http.StatusPreconditionFailed,
+	}.Check(t, s.Handler)
+
+	// Positive: If-Match with a wildcard matches any version of the commitment
+	oldassert.HTTPRequest{
+		Method:       http.MethodPost,
+		Path:         "/v1/domains/uuid-for-germany/projects/uuid-for-berlin/commitments/1/update-duration",
+		Header:       map[string]string{"If-Match": "*"},
+		Body:         oldassert.JSONObject{"duration": "3 hours"},
+		ExpectStatus: http.StatusOK,
+	}.Check(t, s.Handler)
+
+	// commitments that have ended their lifecycle are not shown anymore
+	s.MustDBExec("UPDATE project_commitments SET status = $1 WHERE id = 1", liquid.CommitmentStatusSuperseded)
+	oldassert.HTTPRequest{
+		Method:       http.MethodGet,
+		Path:         "/v1/domains/uuid-for-germany/projects/uuid-for-berlin/commitments/1",
+		ExpectBody:   oldassert.StringData("no such commitment\n"),
+		ExpectStatus: http.StatusNotFound,
+	}.Check(t, s.Handler)
+}
+
 func Test_MergeCommitments(t *testing.T) {
 	s := setupCommitmentTest(t, string(must.Return(testCommitmentsJSONWithoutMinConfirmDate.MarshalJSON())))
 
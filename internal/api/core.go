@@ -139,6 +139,7 @@ func (p *v1Provider) AddTo(r *mux.Router) {
 	resRouter.Methods("POST").Path("/domains/{domain_id}/projects/{project_id}/commitments/new").HandlerFunc(p.CreateProjectCommitment)
 	resRouter.Methods("POST").Path("/domains/{domain_id}/projects/{project_id}/commitments/merge").HandlerFunc(p.MergeProjectCommitments)
 	resRouter.Methods("POST").Path("/domains/{domain_id}/projects/{project_id}/commitments/can-confirm").HandlerFunc(p.CanConfirmNewProjectCommitment)
+	resRouter.Methods("GET").Path("/domains/{domain_id}/projects/{project_id}/commitments/{id}").HandlerFunc(p.GetProjectCommitment)
 	resRouter.Methods("DELETE").Path("/domains/{domain_id}/projects/{project_id}/commitments/{id}").HandlerFunc(p.DeleteProjectCommitment)
 	resRouter.Methods("DELETE").Path("/commitments/{id}").HandlerFunc(p.DeleteProjectCommitmentAsCloudAdmin)
 	resRouter.Methods("POST").Path("/domains/{domain_id}/projects/{project_id}/commitments/{id}/renew").HandlerFunc(p.RenewProjectCommitments)
@@ -49,9 +49,9 @@ var everythingCommitment db.ProjectCommitment = db.ProjectCommitment{
 	TransferToken:     Some("transfer-token"),
 	TransferStartedAt: Some(time.Now()),
 
-	Status:                liquid.CommitmentStatusConfirmed,
-	NotifyOnConfirm:       true,
-	NotifiedForExpiration: true,
+	Status:                     liquid.CommitmentStatusConfirmed,
+	NotifyOnConfirm:            true,
+	ExpiryReminderNoticePeriod: Some(must.Return(limesresources.ParseCommitmentDuration("28 days"))),
 }
 
 // RenderMailTemplate handles GET /admin/mail/render
@@ -73,6 +73,14 @@ func (p *v1Provider) RenderMailTemplate(w http.ResponseWriter, r *http.Request)
 		"expiring_commitments":    mailConfig.Templates.ExpiringCommitments,
 		"transferred_commitments": mailConfig.Templates.TransferredCommitments,
 	}
+	if p.Cluster.Config.CommitmentAutoRenewal.IsSome() {
+		templates["renewed_commitments"] = mailConfig.Templates.RenewedCommitments
+	}
+	for _, reminder := range mailConfig.ExpiryReminders {
+		if template, ok := reminder.Template.Unpack(); ok {
+			templates[fmt.Sprintf("expiring_commitments (%s)", reminder.NoticePeriod.String())] = *template
+		}
+	}
 
 	dummyResource := core.AZResourceLocationV1{
 		ServiceType:      "foo-service",
@@ -4,7 +4,6 @@
 package api
 
 import (
-	"encoding/json"
 	"net/http"
 
 	"github.com/go-gorp/gorp/v3"
@@ -12,8 +11,8 @@ import (
 	"github.com/sapcc/go-bits/httpapi"
 	"github.com/sapcc/go-bits/respondwith"
 	"github.com/sapcc/go-bits/sqlext"
-	. "go.xyrillian.de/gg/option"
 
+	"github.com/sapcc/limes/internal/datamodel"
 	"github.com/sapcc/limes/internal/db"
 	"github.com/sapcc/limes/internal/reports"
 )
@@ -138,7 +137,7 @@ func (p *v1Provider) putOrSimulatePutProjectRates(w http.ResponseWriter, r *http
 		return
 	}
 
-	updater := RateLimitUpdater{
+	updater := datamodel.RateLimitUpdater{
 		Cluster: p.Cluster,
 		CanSetRateLimit: func(serviceType db.ServiceType) bool {
 			token.Context.Request["service_type"] = string(serviceType)
@@ -179,96 +178,18 @@ func (p *v1Provider) putOrSimulatePutProjectRates(w http.ResponseWriter, r *http
 
 	// stop now if we're only simulating
 	if simulate {
-		updater.WriteSimulationReport(w)
+		writeRateLimitSimulationReport(w, updater)
 		return
 	}
 
 	if !updater.IsValid() {
 		updater.CommitAuditTrail(token, r, requestTime)
-		updater.WritePutErrorResponse(w)
+		writeRateLimitPutErrorResponse(w, updater)
 		return
 	}
 
-	// get all project_rates and make them accessible quickly by ID
-	var projectRates []db.ProjectRate
-	_, err = tx.Select(&projectRates, `SELECT * FROM project_rates WHERE project_id = $1`, updater.Project.ID)
-	projectRateByClusterRateID := make(map[db.RateID]db.ProjectRate)
-	if respondwith.ErrorText(w, err) {
-		return
-	}
-	for _, rate := range projectRates {
-		projectRateByClusterRateID[rate.RateID] = rate
-	}
-
-	// check all services for resources to update
-	var services []db.Service
-	_, err = tx.Select(&services, `SELECT * FROM services ORDER BY type`)
-	if respondwith.ObfuscatedErrorText(w, err) {
-		return
-	}
-
-	// the db types do not have json tags, additionally the Window type serializes into a human readable format - not DB compatible.
-	type serializableProjectRate struct {
-		ProjectID db.ProjectID   `json:"project_id"`
-		RateID    db.RateID      `json:"rate_id"`
-		Limit     Option[uint64] `json:"rate_limit"` // None for rates that don't have a limit (just a usage)
-		Window    Option[uint64] `json:"window_ns"`  // None for rates that don't have a limit (just a usage)
-	}
-
-	var ratesToUpdate []serializableProjectRate
-	for _, srv := range services {
-		rateLimitRequests, exists := updater.Requests[srv.Type]
-		if !exists {
-			continue // no rate limits for this service
-		}
-		var rates []db.Rate
-		_, err = tx.Select(&rates, `SELECT * FROM rates ORDER BY NAME`)
-		if respondwith.ObfuscatedErrorText(w, err) {
-			return
-		}
-
-		for _, rate := range rates {
-			rateLimitRequest, exists := rateLimitRequests[rate.Name]
-			if !exists {
-				continue // no rate limit request for this rate
-			}
-			var projectRate serializableProjectRate
-			if existingRate, exists := projectRateByClusterRateID[rate.ID]; exists {
-				window, wExists := existingRate.Window.Unpack()
-				serializableWindow := None[uint64]()
-				if wExists {
-					serializableWindow = Some(uint64(window))
-				}
-				projectRate = serializableProjectRate{
-					ProjectID: existingRate.ProjectID,
-					RateID:    existingRate.RateID,
-					Limit:     existingRate.Limit,
-					Window:    serializableWindow,
-				}
-			} else {
-				projectRate = serializableProjectRate{
-					ProjectID: updater.Project.ID,
-					RateID:    rate.ID,
-				}
-			}
-			projectRate.Limit = Some(rateLimitRequest.NewLimit)
-			projectRate.Window = Some(uint64(rateLimitRequest.NewWindow))
-			ratesToUpdate = append(ratesToUpdate, projectRate)
-		}
-	}
 	// update the DB with the new rate limits
-	mergeStr := sqlext.SimplifyWhitespace(`
-		MERGE INTO project_rates pr
-		USING json_to_recordset($1::json) src (project_id BIGINT, rate_id BIGINT, rate_limit BIGINT, window_ns BIGINT)
-		ON src.project_id = pr.project_id AND src.rate_id = pr.rate_id
-		WHEN MATCHED THEN UPDATE SET rate_limit = src.rate_limit, window_ns = src.window_ns
-		WHEN NOT MATCHED BY TARGET THEN INSERT (project_id, rate_id, rate_limit, window_ns, usage_as_bigint) VALUES (src.project_id, src.rate_id, src.rate_limit, src.window_ns, 0)
-		WHEN NOT MATCHED BY SOURCE THEN DO NOTHING`)
-	buf, err := json.Marshal(ratesToUpdate)
-	if respondwith.ErrorText(w, err) {
-		return
-	}
-	_, err = tx.Exec(mergeStr, string(buf))
+	err = updater.WriteRateLimits(tx)
 	if respondwith.ObfuscatedErrorText(w, err) {
 		return
 	}
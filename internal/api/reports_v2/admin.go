@@ -0,0 +1,207 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package reports_v2
+
+import (
+	"cmp"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/sqlext"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+	"github.com/sapcc/limes/internal/core"
+	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/internal/util"
+)
+
+// Unlike in the v1 API, quotas are checked in every AZ:
+// For AZ-separated resources, quota is only tracked per AZ, so the az=total rows would never show any inconsistency.
+// Rows without quota (or without backend quota) drop out of the comparison because it evaluates to NULL.
+var overspentQuotaReportQuery = sqlext.SimplifyWhitespace(`
+	SELECT d.uuid, d.name, p.uuid, p.name, p.parent_uuid, azr.resource_id, azr.az, pazr.quota, pazr.usage
+	FROM project_az_resources pazr
+	JOIN az_resources azr
+	ON azr.id = pazr.az_resource_id
+	JOIN projects p
+	ON p.id = pazr.project_id
+	JOIN domains d
+	ON d.id = p.domain_id
+	WHERE pazr.usage > pazr.quota
+	AND ($1::TEXT IS NULL OR azr.az = $1)
+	AND {{azr.resource_id = ANY($resource_id)}}
+	AND {{d.id = $domain_id}}
+	ORDER BY d.name, p.name, azr.resource_id, azr.az
+`)
+
+var mismatchQuotaReportQuery = sqlext.SimplifyWhitespace(`
+	SELECT d.uuid, d.name, p.uuid, p.name, p.parent_uuid, azr.resource_id, azr.az, pazr.quota, pazr.backend_quota
+	FROM project_az_resources pazr
+	JOIN az_resources azr
+	ON azr.id = pazr.az_resource_id
+	JOIN projects p
+	ON p.id = pazr.project_id
+	JOIN domains d
+	ON d.id = p.domain_id
+	WHERE pazr.backend_quota != pazr.quota
+	AND ($1::TEXT IS NULL OR azr.az = $1)
+	AND {{azr.resource_id = ANY($resource_id)}}
+	AND {{d.id = $domain_id}}
+	ORDER BY d.name, p.name, azr.resource_id, azr.az
+`)
+
+var scrapeErrorReportQuery = sqlext.SimplifyWhitespace(`
+	SELECT d.uuid, d.name, p.uuid, p.name, p.parent_uuid, s.type, ps.checked_at, ps.scrape_error_message
+	FROM project_services ps
+	JOIN services s
+	ON s.id = ps.service_id
+	JOIN projects p
+	ON p.id = ps.project_id
+	JOIN domains d
+	ON d.id = p.domain_id
+	WHERE ps.scrape_error_message != ''
+	AND {{s.id = ANY($service_id)}}
+	AND {{d.id = $domain_id}}
+	ORDER BY d.name, p.name, s.type
+`)
+
+// GetInconsistencies returns a resourcesv2.InconsistenciesGetResponse.
+func GetInconsistencies(cluster *core.Cluster, filter Filter, scope Scope, options common.AdminReportOpts) (resourcesv2.InconsistenciesGetResponse, error) {
+	result := resourcesv2.InconsistenciesGetResponse{
+		// ensure that empty lists get serialized as `[]` rather than as `null`
+		OverspentQuotas: []resourcesv2.OverspentQuotaReport{},
+		MismatchQuotas:  []resourcesv2.MismatchQuotaReport{},
+	}
+	resourcesByID := getResourcesByID(filter)
+
+	query, args := filter.ExpandServiceFilters(overspentQuotaReportQuery, options.AvailabilityZone)
+	query, args = scope.ExpandScopeFilters(query, args...)
+	err := sqlext.ForeachRow(cluster.DB, query, args, func(rows *sql.Rows) error {
+		var (
+			report     resourcesv2.OverspentQuotaReport
+			resourceID db.ResourceID
+		)
+		err := rows.Scan(
+			&report.Project.DomainInfo.UUID, &report.Project.DomainInfo.Name,
+			&report.Project.UUID, &report.Project.Name, &report.Project.ParentUUID,
+			&resourceID, &report.AvailabilityZone, &report.Quota, &report.Usage,
+		)
+		if err != nil {
+			return err
+		}
+		resource, exists := resourcesByID[resourceID]
+		if !exists {
+			return nil
+		}
+		report.ServiceType = resource.ServiceType
+		report.ResourceName = resource.Name
+		report.Unit = resource.Unit
+		result.OverspentQuotas = append(result.OverspentQuotas, report)
+		return nil
+	})
+	if err != nil {
+		return result, err
+	}
+
+	query, args = filter.ExpandServiceFilters(mismatchQuotaReportQuery, options.AvailabilityZone)
+	query, args = scope.ExpandScopeFilters(query, args...)
+	err = sqlext.ForeachRow(cluster.DB, query, args, func(rows *sql.Rows) error {
+		var (
+			report     resourcesv2.MismatchQuotaReport
+			resourceID db.ResourceID
+		)
+		err := rows.Scan(
+			&report.Project.DomainInfo.UUID, &report.Project.DomainInfo.Name,
+			&report.Project.UUID, &report.Project.Name, &report.Project.ParentUUID,
+			&resourceID, &report.AvailabilityZone, &report.Quota, &report.BackendQuota,
+		)
+		if err != nil {
+			return err
+		}
+		resource, exists := resourcesByID[resourceID]
+		if !exists {
+			return nil
+		}
+		report.ServiceType = resource.ServiceType
+		report.ResourceName = resource.Name
+		report.Unit = resource.Unit
+		result.MismatchQuotas = append(result.MismatchQuotas, report)
+		return nil
+	})
+	return result, err
+}
+
+// resourceIdentity is what GetInconsistencies needs to know about a resource referenced by ID.
+type resourceIdentity struct {
+	ServiceType db.ServiceType
+	Name        liquid.ResourceName
+	Unit        liquid.Unit
+}
+
+func getResourcesByID(filter Filter) map[db.ResourceID]resourceIdentity {
+	result := make(map[db.ResourceID]resourceIdentity)
+	for serviceType, resources := range filter.GetResources() {
+		for name, resource := range resources {
+			result[resource.ID] = resourceIdentity{serviceType, name, resource.Unit}
+		}
+	}
+	return result
+}
+
+// GetScrapeErrors returns a resourcesv2.ScrapeErrorsGetResponse.
+func GetScrapeErrors(cluster *core.Cluster, filter Filter, scope Scope) (resourcesv2.ScrapeErrorsGetResponse, error) {
+	type errorKey struct {
+		ServiceType db.ServiceType
+		Message     string
+	}
+	// To avoid excessively large responses, identical errors in multiple projects are reported only once.
+	// Since rows are sorted by domain and project name, the first affected project is the one that is shown.
+	reportsByKey := make(map[errorKey]*resourcesv2.ScrapeErrorReport)
+
+	query, args := filter.ExpandServiceFilters(scrapeErrorReportQuery)
+	query, args = scope.ExpandScopeFilters(query, args...)
+	err := sqlext.ForeachRow(cluster.DB, query, args, func(rows *sql.Rows) error {
+		var (
+			report    resourcesv2.ScrapeErrorReport
+			checkedAt Option[time.Time]
+		)
+		err := rows.Scan(
+			&report.Project.DomainInfo.UUID, &report.Project.DomainInfo.Name,
+			&report.Project.UUID, &report.Project.Name, &report.Project.ParentUUID,
+			&report.ServiceType, &checkedAt, &report.Message,
+		)
+		if err != nil {
+			return err
+		}
+
+		key := errorKey{report.ServiceType, report.Message}
+		if existing, exists := reportsByKey[key]; exists {
+			existing.AffectedProjects++
+			return nil
+		}
+		if t, ok := checkedAt.Unpack(); ok {
+			report.CheckedAt = Some(util.IntoUnixEncodedTime(t))
+		}
+		report.AffectedProjects = 1
+		reportsByKey[key] = &report
+		return nil
+	})
+	if err != nil {
+		return resourcesv2.ScrapeErrorsGetResponse{}, err
+	}
+
+	// ensure that empty lists get serialized as `[]` rather than as `null`
+	result := resourcesv2.ScrapeErrorsGetResponse{ScrapeErrors: []resourcesv2.ScrapeErrorReport{}}
+	for _, report := range reportsByKey {
+		result.ScrapeErrors = append(result.ScrapeErrors, *report)
+	}
+	slices.SortFunc(result.ScrapeErrors, func(lhs, rhs resourcesv2.ScrapeErrorReport) int {
+		return cmp.Or(cmp.Compare(lhs.ServiceType, rhs.ServiceType), cmp.Compare(lhs.Message, rhs.Message))
+	})
+	return result, nil
+}
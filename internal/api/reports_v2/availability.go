@@ -0,0 +1,120 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package reports_v2
+
+import (
+	"database/sql"
+	"maps"
+	"slices"
+
+	"github.com/sapcc/go-api-declarations/limes"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/sqlext"
+
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
+	"github.com/sapcc/limes/internal/core"
+	"github.com/sapcc/limes/internal/datamodel"
+	"github.com/sapcc/limes/internal/db"
+)
+
+var forbiddenProjectResourcesQuery = sqlext.SimplifyWhitespace(`
+	SELECT pr.resource_id
+	FROM project_resources pr
+	WHERE pr.project_id = $1 AND pr.forbidden
+`)
+
+// GetAvailability returns a resourcesv2.AvailabilityGetResponse for the given project.
+// Only those resources are reported which accept new commitments in this project.
+func GetAvailability(cluster *core.Cluster, filter Filter, domain db.Domain, project db.Project) (resourcesv2.AvailabilityGetResponse, error) {
+	result := resourcesv2.AvailabilityGetResponse{Areas: make(map[string]resourcesv2.AvailabilityAreaReport)}
+	categories := filter.GetCategories()
+
+	isForbidden := make(map[db.ResourceID]bool)
+	err := sqlext.ForeachRow(cluster.DB, forbiddenProjectResourcesQuery, []any{project.ID}, func(rows *sql.Rows) error {
+		var resourceID db.ResourceID
+		err := rows.Scan(&resourceID)
+		isForbidden[resourceID] = true
+		return err
+	})
+	if err != nil {
+		return resourcesv2.AvailabilityGetResponse{}, err
+	}
+
+	services := filter.GetServices()
+	for _, serviceType := range slices.Sorted(maps.Keys(services)) {
+		area := cluster.Config.Liquids[serviceType].Area
+		// defense in depth: config should be in sync with serviceInfo
+		if area == "" {
+			continue
+		}
+		resources, _ := filter.GetResourcesForType(serviceType) // can have no resources
+		for _, resourceName := range slices.Sorted(maps.Keys(resources)) {
+			resource := resources[resourceName]
+			path := db.ResourcePath{ServiceType: serviceType, ResourceName: resourceName}
+
+			// these are the same conditions that POST /resources/v2/commitments/new checks
+			if isForbidden[resource.ID] {
+				continue
+			}
+			behavior := cluster.CommitmentBehaviorForResourcePath(path).ForDomain(domain.Name)
+			if len(behavior.Durations) == 0 {
+				continue
+			}
+
+			committableAmounts, err := datamodel.GetCommittableAmounts(path, project.ID, cluster, cluster.DB)
+			if err != nil {
+				return resourcesv2.AvailabilityGetResponse{}, err
+			}
+			resourceReport := resourcesv2.AvailabilityResourceReport{
+				AvailabilityZones: make(map[limes.AvailabilityZone]resourcesv2.AvailabilityAZReport),
+			}
+			for az, amount := range committableAmounts {
+				if !isCommittableAZ(cluster, resource, az) {
+					continue
+				}
+				resourceReport.AvailabilityZones[az] = resourcesv2.AvailabilityAZReport{Committable: amount}
+			}
+			if len(resourceReport.AvailabilityZones) == 0 {
+				continue
+			}
+
+			category := liquid.CategoryName(serviceType)
+			if categoryID, exists := resource.CategoryID.Unpack(); exists {
+				category = categories[categoryID].Name
+			}
+			setInAvailabilityReport(&result, area, serviceType, category, resourceName, resourceReport)
+		}
+	}
+	return result, nil
+}
+
+// isCommittableAZ checks whether POST /resources/v2/commitments/new accepts commitments in the given AZ of this resource.
+func isCommittableAZ(cluster *core.Cluster, resource db.Resource, az limes.AvailabilityZone) bool {
+	if resource.Topology == liquid.FlatTopology {
+		return az == limes.AvailabilityZoneAny
+	}
+	return slices.Contains(cluster.Config.AvailabilityZones, az)
+}
+
+// setInAvailabilityReport creates the higher level structs on the way to the nested
+// location of the resource in the report, if necessary, and places the resource report there.
+func setInAvailabilityReport(report *resourcesv2.AvailabilityGetResponse, area string, serviceType db.ServiceType, category liquid.CategoryName, resourceName liquid.ResourceName, value resourcesv2.AvailabilityResourceReport) {
+	// check area level
+	if _, exists := report.Areas[area]; !exists {
+		report.Areas[area] = resourcesv2.AvailabilityAreaReport{Services: make(map[db.ServiceType]resourcesv2.AvailabilityServiceReport)}
+	}
+	areaReport := report.Areas[area]
+
+	// check service level
+	if _, exists := areaReport.Services[serviceType]; !exists {
+		areaReport.Services[serviceType] = resourcesv2.AvailabilityServiceReport{Categories: make(map[liquid.CategoryName]resourcesv2.AvailabilityCategoryReport)}
+	}
+	serviceReport := areaReport.Services[serviceType]
+
+	// check category level
+	if _, exists := serviceReport.Categories[category]; !exists {
+		serviceReport.Categories[category] = resourcesv2.AvailabilityCategoryReport{Resources: make(map[liquid.ResourceName]resourcesv2.AvailabilityResourceReport)}
+	}
+	serviceReport.Categories[category].Resources[resourceName] = value
+}
@@ -5,21 +5,317 @@ package reports_v2
 
 import (
 	"database/sql"
+	"encoding/json"
 	"maps"
 	"slices"
+	"time"
 
+	"github.com/sapcc/go-api-declarations/limes"
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
 	"github.com/sapcc/go-api-declarations/liquid"
 	"github.com/sapcc/go-bits/sqlext"
 	. "go.xyrillian.de/gg/option"
 
 	"github.com/sapcc/go-bits/gopherpolicy"
 
-	"github.com/sapcc/limes/internal/apideclarations/apiv2/common"
-	ratesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/rates"
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	ratesv2 "github.com/sapcc/limes/apideclarations/apiv2/rates"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
 	"github.com/sapcc/limes/internal/core"
 	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/internal/util"
 )
 
+var clusterResourceReportQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+	WITH project_commitment_sums AS (
+		SELECT project_id, az_resource_id, SUM(amount) AS amount
+		FROM project_commitments
+		WHERE status = {{liquid.CommitmentStatusConfirmed}}
+		GROUP BY project_id, az_resource_id
+	)
+	SELECT azr.resource_id, azr.az, azr.raw_capacity, azr.usage, azr.subcapacities,
+		COALESCE(SUM(pazr.usage), 0) AS projects_usage,
+		COALESCE(SUM(COALESCE(pazr.physical_usage, pazr.usage)), 0) AS physical_usage,
+		COUNT(pazr.physical_usage) > 0 AS show_physical_usage,
+		COALESCE(SUM(GREATEST(0, COALESCE(pcs.amount, 0) - pazr.usage)), 0) AS committed_confirmed_unutilized,
+		COALESCE(SUM(GREATEST(0, pazr.usage - COALESCE(pcs.amount, 0))), 0) AS uncommitted_usage
+	FROM az_resources azr
+	LEFT OUTER JOIN project_az_resources pazr
+	ON pazr.az_resource_id = azr.id
+	LEFT OUTER JOIN project_commitment_sums pcs
+	ON pcs.az_resource_id = azr.id AND pcs.project_id = pazr.project_id
+	WHERE {{azr.resource_id = ANY($resource_id)}}
+	AND azr.az != {{liquid.AvailabilityZoneTotal}}
+	GROUP BY azr.id
+`))
+
+var clusterResourceCommitmentStatsQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+	SELECT azr.resource_id, azr.az, pc.status, pc.duration, SUM(pc.amount)
+	FROM project_commitments pc
+	JOIN az_resources azr
+	ON azr.id = pc.az_resource_id
+	WHERE {{azr.resource_id = ANY($resource_id)}}
+	AND pc.status IN ({{liquid.CommitmentStatusPlanned}}, {{liquid.CommitmentStatusPending}}, {{liquid.CommitmentStatusGuaranteed}}, {{liquid.CommitmentStatusConfirmed}})
+	GROUP BY azr.resource_id, azr.az, pc.status, pc.duration
+`))
+
+var clusterResourceScrapedAtQuery = sqlext.SimplifyWhitespace(`
+	SELECT s.type, s.scraped_at
+	FROM services s
+	WHERE {{s.id = ANY($service_id)}}
+`)
+
+// GetClusterResources returns a resourcesv2.ClusterGetResponse.
+func GetClusterResources(cluster *core.Cluster, token *gopherpolicy.Token, now time.Time, filter Filter, options common.ClusterResourceReportOpts) (resourcesv2.ClusterGetResponse, error) {
+	result := &resourcesv2.ClusterGetResponse{}
+
+	// fill info report
+	if options.WithInfo {
+		infoReport, err := GetResourcesInfo(cluster, token, now, filter)
+		if err != nil {
+			return *result, err
+		}
+		result.InfoReport = Some(infoReport)
+	}
+
+	// collect scraping timestamps, which are needed for both timing and the scraped_at filters
+	scrapedAtByServiceType := make(map[db.ServiceType]Option[time.Time])
+	query, args := filter.ExpandServiceFilters(clusterResourceScrapedAtQuery)
+	err := sqlext.ForeachRow(cluster.DB, query, args, func(rows *sql.Rows) error {
+		var (
+			serviceType db.ServiceType
+			scrapedAt   Option[time.Time]
+		)
+		err := rows.Scan(&serviceType, &scrapedAt)
+		if err != nil {
+			return err
+		}
+		scrapedAtByServiceType[serviceType] = scrapedAt
+		return nil
+	})
+	if err != nil {
+		return *result, err
+	}
+	isServiceIncluded := func(serviceType db.ServiceType) bool {
+		return isScrapedAtInRange(scrapedAtByServiceType[serviceType], options.ResourceReportOpts)
+	}
+
+	// first query: capacity and summed up project data per AZ
+	query, args = filter.ExpandServiceFilters(clusterResourceReportQuery)
+	err = sqlext.ForeachRow(cluster.DB, query, args, func(rows *sql.Rows) error {
+		var (
+			resourceID                   db.ResourceID
+			az                           limes.AvailabilityZone
+			rawCapacity                  uint64
+			overallUsage                 Option[uint64]
+			subcapacities                string
+			usage                        uint64
+			physicalUsage                uint64
+			showPhysicalUsage            bool
+			committedConfirmedUnutilized uint64
+			uncommittedUsage             uint64
+		)
+		err := rows.Scan(&resourceID, &az, &rawCapacity, &overallUsage, &subcapacities,
+			&usage, &physicalUsage, &showPhysicalUsage, &committedConfirmedUnutilized, &uncommittedUsage)
+		if err != nil {
+			return err
+		}
+		resourceReport, path, exists := findInClusterResourceReport(filter, cluster, result, resourceID, isServiceIncluded)
+		if !exists {
+			return nil
+		}
+
+		behavior := cluster.BehaviorForResource(path.ServiceType, path.ResourceName)
+		azReport := resourcesv2.ClusterAvailabilityZoneReport{
+			Capacity:     behavior.OvercommitFactor.ApplyTo(rawCapacity),
+			RawCapacity:  rawCapacity,
+			OverallUsage: overallUsage,
+			Usage:        usage,
+		}
+		if showPhysicalUsage {
+			azReport.PhysicalUsage = Some(physicalUsage)
+		}
+		if options.WithCommitmentStats {
+			azReport.CommittedConfirmedUnutilized = Some(committedConfirmedUnutilized)
+			azReport.UncommittedUsage = Some(uncommittedUsage)
+		}
+		if options.WithSubcapacities && subcapacities != "" && isSubcapacityAllowed(token, path) {
+			azReport.Subcapacities = json.RawMessage(subcapacities)
+		}
+		resourceReport.AvailabilityZones[az] = azReport
+		return nil
+	})
+	if err != nil {
+		return *result, err
+	}
+
+	// second query: commitment amounts grouped by status and duration
+	if options.WithCommitmentStats {
+		query, args = filter.ExpandServiceFilters(clusterResourceCommitmentStatsQuery)
+		err = sqlext.ForeachRow(cluster.DB, query, args, func(rows *sql.Rows) error {
+			var (
+				resourceID db.ResourceID
+				az         limes.AvailabilityZone
+				status     liquid.CommitmentStatus
+				duration   limesresources.CommitmentDuration
+				amount     uint64
+			)
+			err := rows.Scan(&resourceID, &az, &status, &duration, &amount)
+			if err != nil {
+				return err
+			}
+			resourceReport, _, exists := findInClusterResourceReport(filter, cluster, result, resourceID, isServiceIncluded)
+			if !exists {
+				return nil
+			}
+			azReport, exists := resourceReport.AvailabilityZones[az]
+			if !exists {
+				return nil
+			}
+			if azReport.Committed == nil {
+				azReport.Committed = make(map[liquid.CommitmentStatus]map[limesresources.CommitmentDuration]uint64)
+			}
+			if azReport.Committed[status] == nil {
+				azReport.Committed[status] = make(map[limesresources.CommitmentDuration]uint64)
+			}
+			azReport.Committed[status][duration] += amount
+			resourceReport.AvailabilityZones[az] = azReport
+			return nil
+		})
+		if err != nil {
+			return *result, err
+		}
+	}
+
+	// epilogue: perform some operations on the finished report
+	for _, areaReport := range result.ClusterReport.Areas {
+		for serviceType, serviceReport := range areaReport.Services {
+			if options.WithTiming {
+				if scrapedAt, ok := scrapedAtByServiceType[serviceType].Unpack(); ok {
+					serviceReport.ScrapedAt = Some(util.IntoUnixEncodedTime(scrapedAt))
+				}
+				areaReport.Services[serviceType] = serviceReport
+			}
+			for _, categoryReport := range serviceReport.Categories {
+				for _, resourceReport := range categoryReport.Resources {
+					removeEmptyClusterAZReports(resourceReport.AvailabilityZones)
+				}
+			}
+		}
+	}
+
+	return *result, nil
+}
+
+// isScrapedAtInRange checks the scraped_at timestamp of a service against the
+// min_scraped_at and max_scraped_at query options.
+func isScrapedAtInRange(scrapedAt Option[time.Time], opts common.ResourceReportOpts) bool {
+	if opts.MinScrapedAt.IsNone() && opts.MaxScrapedAt.IsNone() {
+		return true
+	}
+	t, ok := scrapedAt.Unpack()
+	if !ok {
+		return false
+	}
+	if minScrapedAt, ok := opts.MinScrapedAt.Unpack(); ok && t.Before(minScrapedAt) {
+		return false
+	}
+	if maxScrapedAt, ok := opts.MaxScrapedAt.Unpack(); ok && t.After(maxScrapedAt) {
+		return false
+	}
+	return true
+}
+
+// isSubcapacityAllowed checks whether the subcapacities of the given resource may be shown to the user.
+func isSubcapacityAllowed(token *gopherpolicy.Token, path db.ResourcePath) bool {
+	if token.Context.Request == nil {
+		token.Context.Request = make(map[string]string, 2)
+	}
+	token.Context.Request["service"] = string(path.ServiceType)
+	token.Context.Request["resource"] = string(path.ResourceName)
+	return token.Check("v2:cluster:show_subcapacity")
+}
+
+// removeEmptyClusterAZReports removes the AZ reports for "any" and "unknown" when they do not carry any information.
+// az_resources always has entries for "any" and "unknown", even if the resource is AZ-aware, because
+// the location of usages or capacities may be unknown.
+func removeEmptyClusterAZReports(reports map[limes.AvailabilityZone]resourcesv2.ClusterAvailabilityZoneReport) {
+	if len(reports) < 2 {
+		return
+	}
+	for _, az := range []limes.AvailabilityZone{limes.AvailabilityZoneAny, limes.AvailabilityZoneUnknown} {
+		azReport, exists := reports[az]
+		if !exists {
+			continue
+		}
+		if azReport.RawCapacity == 0 && azReport.Usage == 0 && azReport.OverallUsage.UnwrapOr(0) == 0 &&
+			azReport.PhysicalUsage.UnwrapOr(0) == 0 && len(azReport.Committed) == 0 && len(azReport.Subcapacities) == 0 {
+			delete(reports, az)
+		}
+	}
+}
+
+// findInClusterResourceReport creates or iterates higher level structs on the way to the nested
+// location of the db.ResourceID in the report and returns the resourcesv2.ClusterResourceReport,
+// whose AvailabilityZones can be filled by the caller.
+// If this resource is not part of the filter or its service is excluded, false is returned.
+func findInClusterResourceReport(filter Filter, cluster *core.Cluster, report *resourcesv2.ClusterGetResponse, resourceID db.ResourceID, isServiceIncluded func(db.ServiceType) bool) (resourcesv2.ClusterResourceReport, db.ResourcePath, bool) {
+	services := filter.GetServices()
+	categories := filter.GetCategories()
+
+	for _, serviceType := range slices.Sorted(maps.Keys(services)) {
+		resources, _ := filter.GetResourcesForType(serviceType) // can have no resources
+		for _, resourceName := range slices.Sorted(maps.Keys(resources)) {
+			resource := resources[resourceName]
+			if resource.ID != resourceID {
+				continue
+			}
+			if !isServiceIncluded(serviceType) {
+				return resourcesv2.ClusterResourceReport{}, resource.Path, false
+			}
+
+			config := cluster.Config.Liquids[serviceType]
+			area := config.Area
+			// defense in depth: config should be in sync with serviceInfo
+			if area == "" {
+				return resourcesv2.ClusterResourceReport{}, resource.Path, false
+			}
+
+			// check area level (might be uninitialized)
+			if report.ClusterReport.Areas == nil {
+				report.ClusterReport.Areas = make(map[string]resourcesv2.ClusterAreaReport)
+			}
+			if _, exists := report.ClusterReport.Areas[area]; !exists {
+				report.ClusterReport.Areas[area] = resourcesv2.ClusterAreaReport{Services: make(map[db.ServiceType]resourcesv2.ClusterServiceReport)}
+			}
+			areaReport := report.ClusterReport.Areas[area]
+
+			// check service level
+			if _, exists := areaReport.Services[serviceType]; !exists {
+				areaReport.Services[serviceType] = resourcesv2.ClusterServiceReport{Categories: make(map[liquid.CategoryName]resourcesv2.ClusterCategoryReport)}
+			}
+			serviceReport := areaReport.Services[serviceType]
+
+			// check category level
+			category := liquid.CategoryName(serviceType)
+			if categoryID, exists := resource.CategoryID.Unpack(); exists {
+				category = categories[categoryID].Name
+			}
+			if _, exists := serviceReport.Categories[category]; !exists {
+				serviceReport.Categories[category] = resourcesv2.ClusterCategoryReport{Resources: make(map[liquid.ResourceName]resourcesv2.ClusterResourceReport)}
+			}
+			categoryReport := serviceReport.Categories[category]
+
+			// check resource level
+			if _, exists := categoryReport.Resources[resource.Name]; !exists {
+				categoryReport.Resources[resource.Name] = resourcesv2.ClusterResourceReport{AvailabilityZones: make(map[limes.AvailabilityZone]resourcesv2.ClusterAvailabilityZoneReport)}
+			}
+			return categoryReport.Resources[resource.Name], resource.Path, true
+		}
+	}
+	return resourcesv2.ClusterResourceReport{}, db.ResourcePath{}, false
+}
+
 var clusterRateReportQuery = sqlext.SimplifyWhitespace(`
 	SELECT pra.rate_id, SUM(pra.usage_as_bigint::BIGINT)::TEXT AS usage_as_bigint
 	FROM project_rates pra
@@ -7,19 +7,280 @@ import (
 	"database/sql"
 	"maps"
 	"slices"
+	"time"
 
 	. "go.xyrillian.de/gg/option"
 
+	"github.com/sapcc/go-api-declarations/limes"
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
 	"github.com/sapcc/go-api-declarations/liquid"
 	"github.com/sapcc/go-bits/gopherpolicy"
 	"github.com/sapcc/go-bits/sqlext"
 
-	"github.com/sapcc/limes/internal/apideclarations/apiv2/common"
-	ratesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/rates"
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	ratesv2 "github.com/sapcc/limes/apideclarations/apiv2/rates"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
 	"github.com/sapcc/limes/internal/core"
 	"github.com/sapcc/limes/internal/db"
 )
 
+var domainResourceReportQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+	WITH project_commitment_sums AS (
+		SELECT project_id, az_resource_id, SUM(amount) AS amount
+		FROM project_commitments
+		WHERE status = {{liquid.CommitmentStatusConfirmed}}
+		GROUP BY project_id, az_resource_id
+	)
+	SELECT d.uuid, d.name, azr.resource_id, azr.az,
+		SUM(pazr.usage) AS usage, SUM(pazr.quota) AS quota,
+		SUM(COALESCE(pazr.physical_usage, pazr.usage)) AS physical_usage,
+		COUNT(pazr.physical_usage) > 0 AS show_physical_usage,
+		SUM(GREATEST(0, COALESCE(pcs.amount, 0) - pazr.usage)) AS committed_confirmed_unutilized,
+		SUM(GREATEST(0, pazr.usage - COALESCE(pcs.amount, 0))) AS uncommitted_usage
+	FROM project_az_resources pazr
+	JOIN az_resources azr
+	ON azr.id = pazr.az_resource_id
+	JOIN resources r
+	ON r.id = azr.resource_id
+	JOIN projects p
+	ON p.id = pazr.project_id
+	JOIN domains d
+	ON d.id = p.domain_id
+	JOIN project_services ps
+	ON ps.project_id = p.id AND ps.service_id = r.service_id
+	LEFT OUTER JOIN project_commitment_sums pcs
+	ON pcs.az_resource_id = azr.id AND pcs.project_id = pazr.project_id
+	WHERE ($1::TIMESTAMPTZ IS NULL OR ps.scraped_at >= $1)
+	AND ($2::TIMESTAMPTZ IS NULL OR ps.scraped_at <= $2)
+	AND azr.az != {{liquid.AvailabilityZoneTotal}}
+	AND {{azr.resource_id = ANY($resource_id)}}
+	AND {{d.id = $domain_id}}
+	GROUP BY d.uuid, d.name, azr.resource_id, azr.az
+`))
+
+var domainResourceCommitmentStatsQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+	SELECT d.uuid, azr.resource_id, azr.az, pc.status, pc.duration, SUM(pc.amount)
+	FROM project_commitments pc
+	JOIN az_resources azr
+	ON azr.id = pc.az_resource_id
+	JOIN projects p
+	ON p.id = pc.project_id
+	JOIN domains d
+	ON d.id = p.domain_id
+	WHERE pc.status IN ({{liquid.CommitmentStatusPlanned}}, {{liquid.CommitmentStatusPending}}, {{liquid.CommitmentStatusGuaranteed}}, {{liquid.CommitmentStatusConfirmed}})
+	AND {{azr.resource_id = ANY($resource_id)}}
+	AND {{d.id = $domain_id}}
+	GROUP BY d.uuid, azr.resource_id, azr.az, pc.status, pc.duration
+`))
+
+// GetDomainResources returns a resourcesv2.DomainGetResponse.
+func GetDomainResources(cluster *core.Cluster, token *gopherpolicy.Token, now time.Time, filter Filter, options common.DomainResourceReportOpts, scope Scope) (resourcesv2.DomainGetResponse, error) {
+	result := &resourcesv2.DomainGetResponse{}
+
+	// fill info report
+	if options.WithInfo {
+		infoReport, err := GetResourcesInfo(cluster, token, now, filter)
+		if err != nil {
+			return *result, err
+		}
+		result.InfoReport = Some(infoReport)
+	}
+
+	// first query: summed up project data per AZ
+	type resourceKey struct {
+		DomainUUID string
+		ResourceID db.ResourceID
+	}
+	resourceReports := make(map[resourceKey]resourcesv2.DomainResourceReport)
+	query, args := filter.ExpandServiceFilters(domainResourceReportQuery, options.MinScrapedAt, options.MaxScrapedAt)
+	query, args = scope.ExpandScopeFilters(query, args...)
+	err := sqlext.ForeachRow(cluster.DB, query, args, func(rows *sql.Rows) error {
+		var (
+			domainUUID                   string
+			domainName                   string
+			resourceID                   db.ResourceID
+			az                           limes.AvailabilityZone
+			usage                        uint64
+			quota                        Option[uint64]
+			physicalUsage                uint64
+			showPhysicalUsage            bool
+			committedConfirmedUnutilized uint64
+			uncommittedUsage             uint64
+		)
+		err := rows.Scan(&domainUUID, &domainName, &resourceID, &az, &usage, &quota,
+			&physicalUsage, &showPhysicalUsage, &committedConfirmedUnutilized, &uncommittedUsage)
+		if err != nil {
+			return err
+		}
+		resourceReport, exists := findInDomainResourceReport(filter, cluster, result, resourceID, common.DomainMetadata{
+			UUID: domainUUID,
+			Name: domainName,
+		})
+		if !exists {
+			return nil
+		}
+
+		azReport := resourcesv2.DomainAvailabilityZoneReport{
+			Usage: usage,
+			Quota: quota,
+		}
+		if showPhysicalUsage {
+			azReport.PhysicalUsage = Some(physicalUsage)
+		}
+		if options.WithCommitmentStats {
+			azReport.CommittedConfirmedUnutilized = Some(committedConfirmedUnutilized)
+			azReport.UncommittedUsage = Some(uncommittedUsage)
+		}
+		resourceReport.AvailabilityZones[az] = azReport
+		resourceReports[resourceKey{domainUUID, resourceID}] = resourceReport
+		return nil
+	})
+	if err != nil {
+		return *result, err
+	}
+
+	// second query: commitment amounts grouped by status and duration
+	if options.WithCommitmentStats {
+		query, args = filter.ExpandServiceFilters(domainResourceCommitmentStatsQuery)
+		query, args = scope.ExpandScopeFilters(query, args...)
+		err = sqlext.ForeachRow(cluster.DB, query, args, func(rows *sql.Rows) error {
+			var (
+				domainUUID string
+				resourceID db.ResourceID
+				az         limes.AvailabilityZone
+				status     liquid.CommitmentStatus
+				duration   limesresources.CommitmentDuration
+				amount     uint64
+			)
+			err := rows.Scan(&domainUUID, &resourceID, &az, &status, &duration, &amount)
+			if err != nil {
+				return err
+			}
+			// only fill AZ reports which were created by the first query
+			resourceReport, exists := resourceReports[resourceKey{domainUUID, resourceID}]
+			if !exists {
+				return nil
+			}
+			azReport, exists := resourceReport.AvailabilityZones[az]
+			if !exists {
+				return nil
+			}
+			if azReport.Committed == nil {
+				azReport.Committed = make(map[liquid.CommitmentStatus]map[limesresources.CommitmentDuration]uint64)
+			}
+			if azReport.Committed[status] == nil {
+				azReport.Committed[status] = make(map[limesresources.CommitmentDuration]uint64)
+			}
+			azReport.Committed[status][duration] += amount
+			resourceReport.AvailabilityZones[az] = azReport
+			return nil
+		})
+		if err != nil {
+			return *result, err
+		}
+	}
+
+	// epilogue: perform some operations on the finished report
+	for _, domainReport := range result.DomainReports {
+		for _, areaReport := range domainReport.Areas {
+			for _, serviceReport := range areaReport.Services {
+				for _, categoryReport := range serviceReport.Categories {
+					for _, resourceReport := range categoryReport.Resources {
+						removeEmptyDomainAZReports(resourceReport.AvailabilityZones)
+					}
+				}
+			}
+		}
+	}
+
+	return *result, nil
+}
+
+// removeEmptyDomainAZReports removes the AZ reports for "any" and "unknown" when they do not carry any information.
+// project_az_resources always has entries for "any", even if the resource is AZ-aware, because
+// ApplyComputedProjectQuota needs somewhere to write the base quotas.
+func removeEmptyDomainAZReports(reports map[limes.AvailabilityZone]resourcesv2.DomainAvailabilityZoneReport) {
+	if len(reports) < 2 {
+		return
+	}
+	for _, az := range []limes.AvailabilityZone{limes.AvailabilityZoneAny, limes.AvailabilityZoneUnknown} {
+		azReport, exists := reports[az]
+		if !exists {
+			continue
+		}
+		if azReport.Usage == 0 && azReport.Quota.UnwrapOr(0) == 0 && azReport.PhysicalUsage.UnwrapOr(0) == 0 && len(azReport.Committed) == 0 {
+			delete(reports, az)
+		}
+	}
+}
+
+// findInDomainResourceReport creates or iterates higher level structs on the way to the nested
+// location of the db.ResourceID in the report and returns the resourcesv2.DomainResourceReport,
+// whose AvailabilityZones can be filled by the caller.
+// If this resource is not part of the filter, false is returned.
+func findInDomainResourceReport(filter Filter, cluster *core.Cluster, report *resourcesv2.DomainGetResponse, resourceID db.ResourceID, domain common.DomainMetadata) (resourcesv2.DomainResourceReport, bool) {
+	services := filter.GetServices()
+	categories := filter.GetCategories()
+
+	for _, serviceType := range slices.Sorted(maps.Keys(services)) {
+		resources, _ := filter.GetResourcesForType(serviceType) // can have no resources
+		for _, resourceName := range slices.Sorted(maps.Keys(resources)) {
+			resource := resources[resourceName]
+			if resource.ID != resourceID {
+				continue
+			}
+
+			config := cluster.Config.Liquids[serviceType]
+			area := config.Area
+			// defense in depth: config should be in sync with serviceInfo
+			if area == "" {
+				return resourcesv2.DomainResourceReport{}, false
+			}
+
+			// check domain level (might be uninitialized)
+			if report.DomainReports == nil {
+				report.DomainReports = make(map[string]resourcesv2.DomainReport)
+			}
+			if _, exists := report.DomainReports[domain.UUID]; !exists {
+				report.DomainReports[domain.UUID] = resourcesv2.DomainReport{
+					DomainMetadata: domain,
+					Areas:          make(map[string]resourcesv2.DomainAreaReport),
+				}
+			}
+			domainReport := report.DomainReports[domain.UUID]
+
+			// check area level
+			if _, exists := domainReport.Areas[area]; !exists {
+				domainReport.Areas[area] = resourcesv2.DomainAreaReport{Services: make(map[db.ServiceType]resourcesv2.DomainServiceReport)}
+			}
+			areaReport := domainReport.Areas[area]
+
+			// check service level
+			if _, exists := areaReport.Services[serviceType]; !exists {
+				areaReport.Services[serviceType] = resourcesv2.DomainServiceReport{Categories: make(map[liquid.CategoryName]resourcesv2.DomainCategoryReport)}
+			}
+			serviceReport := areaReport.Services[serviceType]
+
+			// check category level
+			category := liquid.CategoryName(serviceType)
+			if categoryID, exists := resource.CategoryID.Unpack(); exists {
+				category = categories[categoryID].Name
+			}
+			if _, exists := serviceReport.Categories[category]; !exists {
+				serviceReport.Categories[category] = resourcesv2.DomainCategoryReport{Resources: make(map[liquid.ResourceName]resourcesv2.DomainResourceReport)}
+			}
+			categoryReport := serviceReport.Categories[category]
+
+			// check resource level
+			if _, exists := categoryReport.Resources[resource.Name]; !exists {
+				categoryReport.Resources[resource.Name] = resourcesv2.DomainResourceReport{AvailabilityZones: make(map[limes.AvailabilityZone]resourcesv2.DomainAvailabilityZoneReport)}
+			}
+			return categoryReport.Resources[resource.Name], true
+		}
+	}
+	return resourcesv2.DomainResourceReport{}, false
+}
+
 var domainRateReportQuery = sqlext.SimplifyWhitespace(`
 	SELECT d.uuid, d.name, pra.rate_id, SUM(pra.usage_as_bigint::BIGINT)::TEXT AS usage_as_bigint
 	FROM project_rates pra
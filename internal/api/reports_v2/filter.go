@@ -10,7 +10,7 @@ import (
 
 	"github.com/lib/pq"
 
-	"github.com/sapcc/limes/internal/apideclarations/apiv2/common"
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
 	"github.com/sapcc/limes/internal/core"
 	"github.com/sapcc/limes/internal/db"
 	"github.com/sapcc/limes/internal/util"
@@ -76,6 +76,23 @@ func FilterFromRateOpts(cluster *core.Cluster, opts common.RateReportOpts) (f Fi
 	return f, nil
 }
 
+// FilterFromAdminReportOpts returns a Filter from apiv2.AdminReportOpts.
+func FilterFromAdminReportOpts(cluster *core.Cluster, opts common.AdminReportOpts) (f Filter, err error) {
+	sis := cluster.SIC.GetSnapshot()
+	f = Filter{sis.Filter(core.ServiceInfoFilter{
+		ServiceArea: opts.Area,
+		ServiceType: opts.ServiceType,
+	})}
+	services := f.GetServices()
+	if area, ok := opts.Area.Unpack(); ok && len(services) == 0 {
+		return f, fmt.Errorf(`no services found for area %q`, area)
+	}
+	if serviceType, ok := opts.ServiceType.Unpack(); ok && len(services) == 0 {
+		return f, fmt.Errorf(`no services found for type %q`, serviceType)
+	}
+	return f, nil
+}
+
 var filterReplaceRx = regexp.MustCompile(`{{(.*?) = ANY\(\$(service_id|resource_id|rate_id)\)}}`)
 
 // ExpandServiceFilters takes an SQL query string with curly-bracketed
@@ -13,8 +13,8 @@ import (
 
 	. "go.xyrillian.de/gg/option"
 
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
 	"github.com/sapcc/limes/internal/api/reports_v2"
-	"github.com/sapcc/limes/internal/apideclarations/apiv2/common"
 	"github.com/sapcc/limes/internal/db"
 	"github.com/sapcc/limes/internal/test"
 	"github.com/sapcc/limes/internal/test/common_fixtures"
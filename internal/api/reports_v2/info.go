@@ -15,8 +15,8 @@ import (
 	"github.com/sapcc/go-bits/gopherpolicy"
 	"github.com/sapcc/go-bits/sqlext"
 
-	ratesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/rates"
-	resourcesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/resources"
+	ratesv2 "github.com/sapcc/limes/apideclarations/apiv2/rates"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
 	"github.com/sapcc/limes/internal/core"
 	"github.com/sapcc/limes/internal/db"
 )
@@ -0,0 +1,108 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package reports_v2
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/go-gorp/gorp/v3"
+	"github.com/lib/pq"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/respondwith"
+	"github.com/sapcc/go-bits/sqlext"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	"github.com/sapcc/limes/internal/db"
+)
+
+// ProjectPage describes which projects are shown in one page of a project report.
+// Projects are always paginated in order of their UUID, to ensure a stable ordering across pages.
+type ProjectPage struct {
+	// ProjectIDs contains the projects on this page, or None if the report is not paginated.
+	ProjectIDs Option[[]db.ProjectID]
+	// NextMarker is the UUID of the last project on this page, or None if this is the last page.
+	NextMarker Option[liquid.ProjectUUID]
+}
+
+var projectPageQuery = sqlext.SimplifyWhitespace(`
+	SELECT p.id, p.uuid
+	FROM projects p
+	WHERE ($1::TEXT IS NULL OR p.uuid > $1)
+	AND {{p.domain_id = $domain_id}}
+	AND {{p.id = $project_id}}
+	ORDER BY p.uuid
+	LIMIT $2
+`)
+
+// NewProjectPage selects the projects within the given scope that are shown in the current page of a project report.
+// When neither limit nor marker are given, the report is not paginated.
+func NewProjectPage(dbm *gorp.DbMap, opts common.ProjectPaginationOpts, scope Scope) (page ProjectPage, err error) {
+	limit, hasLimit := opts.Limit.Unpack()
+	if hasLimit && limit == 0 {
+		return page, respondwith.CustomStatus(http.StatusBadRequest, errors.New("limit must be greater than zero"))
+	}
+	if !hasLimit && opts.Marker.IsNone() {
+		return page, nil
+	}
+
+	// query one more project than requested to find out if there is a next page
+	var sqlLimit Option[uint64]
+	if hasLimit {
+		sqlLimit = Some(limit + 1)
+	}
+	query, args := scope.ExpandScopeFilters(projectPageQuery, opts.Marker, sqlLimit)
+
+	projectIDs := []db.ProjectID{} // must not be nil, otherwise the page filter would match all projects
+	var lastUUID liquid.ProjectUUID
+	err = sqlext.ForeachRow(dbm, query, args, func(rows *sql.Rows) error {
+		var (
+			projectID   db.ProjectID
+			projectUUID liquid.ProjectUUID
+		)
+		err := rows.Scan(&projectID, &projectUUID)
+		if err != nil {
+			return err
+		}
+		if hasLimit && uint64(len(projectIDs)) == limit {
+			page.NextMarker = Some(lastUUID)
+			return nil
+		}
+		projectIDs = append(projectIDs, projectID)
+		lastUUID = projectUUID
+		return nil
+	})
+	if err != nil {
+		return page, err
+	}
+	page.ProjectIDs = Some(projectIDs)
+	return page, nil
+}
+
+// filterArg returns an SQL argument for restricting a query to the projects on this page.
+// The respective where-clause must be of the form "($N::BIGINT[] IS NULL OR p.id = ANY($N))".
+func (p ProjectPage) filterArg() any {
+	return pq.Array(p.ProjectIDs.UnwrapOr(nil))
+}
+
+// NextLink returns a link to the next page of the report at the given URL, or None if this is the last page.
+func (p ProjectPage) NextLink(u *url.URL) Option[string] {
+	return NextPageLink(u, p.NextMarker)
+}
+
+// NextPageLink returns a link to the page starting after the given marker for the paginated listing at the given URL,
+// or None if there is no marker because the current page is the last one.
+func NextPageLink[M ~string](u *url.URL, nextMarker Option[M]) Option[string] {
+	marker, ok := nextMarker.Unpack()
+	if !ok {
+		return None[string]()
+	}
+	query := u.Query()
+	query.Set("marker", string(marker))
+	next := url.URL{Path: u.Path, RawQuery: query.Encode()}
+	return Some(next.String())
+}
@@ -5,22 +5,331 @@ package reports_v2
 
 import (
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"maps"
 	"slices"
+	"time"
 
+	"github.com/sapcc/go-api-declarations/limes"
 	limesrates "github.com/sapcc/go-api-declarations/limes/rates"
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
 	. "go.xyrillian.de/gg/option"
 
 	"github.com/sapcc/go-api-declarations/liquid"
 	"github.com/sapcc/go-bits/gopherpolicy"
 	"github.com/sapcc/go-bits/sqlext"
 
-	"github.com/sapcc/limes/internal/apideclarations/apiv2/common"
-	ratesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/rates"
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
+	ratesv2 "github.com/sapcc/limes/apideclarations/apiv2/rates"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
 	"github.com/sapcc/limes/internal/core"
 	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/internal/util"
 )
 
+var projectResourceReportQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+	SELECT d.uuid, d.name, p.uuid, p.name, p.parent_uuid, ps.scraped_at, azr.resource_id, azr.az,
+		pr.max_quota_from_outside_admin, pr.forbid_autogrowth,
+		pazr.quota, pazr.usage, pazr.physical_usage, pazr.historical_usage, pazr.subresources
+	FROM project_az_resources pazr
+	JOIN az_resources azr
+	ON azr.id = pazr.az_resource_id
+	JOIN resources r
+	ON r.id = azr.resource_id
+	JOIN project_resources pr
+	ON pr.resource_id = r.id AND pr.project_id = pazr.project_id
+	JOIN projects p
+	ON p.id = pazr.project_id
+	JOIN domains d
+	ON d.id = p.domain_id
+	JOIN project_services ps
+	ON ps.project_id = p.id AND ps.service_id = r.service_id
+	WHERE ($1::TIMESTAMPTZ IS NULL OR ps.scraped_at >= $1)
+	AND ($2::TIMESTAMPTZ IS NULL OR ps.scraped_at <= $2)
+	AND ($3::BIGINT[] IS NULL OR p.id = ANY($3))
+	AND azr.az != {{liquid.AvailabilityZoneTotal}}
+	AND {{azr.resource_id = ANY($resource_id)}}
+	AND {{d.id = $domain_id}}
+	AND {{p.id = $project_id}}
+`))
+
+var projectResourceCommitmentStatsQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+	SELECT p.uuid, azr.resource_id, azr.az, pc.status, pc.duration, SUM(pc.amount)
+	FROM project_commitments pc
+	JOIN az_resources azr
+	ON azr.id = pc.az_resource_id
+	JOIN projects p
+	ON p.id = pc.project_id
+	JOIN domains d
+	ON d.id = p.domain_id
+	WHERE pc.status IN ({{liquid.CommitmentStatusPlanned}}, {{liquid.CommitmentStatusPending}}, {{liquid.CommitmentStatusGuaranteed}}, {{liquid.CommitmentStatusConfirmed}})
+	AND ($1::BIGINT[] IS NULL OR p.id = ANY($1))
+	AND {{azr.resource_id = ANY($resource_id)}}
+	AND {{d.id = $domain_id}}
+	AND {{p.id = $project_id}}
+	GROUP BY p.uuid, azr.resource_id, azr.az, pc.status, pc.duration
+`))
+
+// GetProjectResources returns a resourcesv2.ProjectGetResponse.
+// Only the projects on the given page are reported.
+func GetProjectResources(cluster *core.Cluster, token *gopherpolicy.Token, now time.Time, filter Filter, options common.ProjectResourceReportOpts, scope Scope, page ProjectPage) (resourcesv2.ProjectGetResponse, error) {
+	result := &resourcesv2.ProjectGetResponse{}
+
+	// fill info report
+	if options.WithInfo {
+		infoReport, err := GetResourcesInfo(cluster, token, now, filter)
+		if err != nil {
+			return *result, err
+		}
+		result.InfoReport = Some(infoReport)
+	}
+
+	// first query: project data per AZ
+	type resourceKey struct {
+		ProjectUUID string
+		ResourceID  db.ResourceID
+	}
+	knownResourceReports := make(map[resourceKey]resourcesv2.ProjectResourceReport)
+	query, args := filter.ExpandServiceFilters(projectResourceReportQuery, options.MinScrapedAt, options.MaxScrapedAt, page.filterArg())
+	query, args = scope.ExpandScopeFilters(query, args...)
+	err := sqlext.ForeachRow(cluster.DB, query, args, func(rows *sql.Rows) error {
+		var (
+			domainUUID               string
+			domainName               string
+			projectUUID              string
+			projectName              string
+			projectParentUUID        string
+			scrapedAt                Option[time.Time]
+			resourceID               db.ResourceID
+			az                       limes.AvailabilityZone
+			maxQuotaFromOutsideAdmin Option[uint64]
+			forbidAutogrowth         bool
+			quota                    Option[uint64]
+			usage                    uint64
+			physicalUsage            Option[uint64]
+			historicalUsage          string
+			subresources             string
+		)
+		err := rows.Scan(&domainUUID, &domainName, &projectUUID, &projectName, &projectParentUUID, &scrapedAt, &resourceID, &az,
+			&maxQuotaFromOutsideAdmin, &forbidAutogrowth, &quota, &usage, &physicalUsage, &historicalUsage, &subresources)
+		if err != nil {
+			return err
+		}
+		project := common.ProjectMetadata{
+			UUID:       projectUUID,
+			Name:       projectName,
+			ParentUUID: projectParentUUID,
+			DomainInfo: common.DomainMetadata{
+				UUID: domainUUID,
+				Name: domainName,
+			},
+		}
+		var scrapedAtForReport Option[limes.UnixEncodedTime]
+		if t, ok := scrapedAt.Unpack(); ok && options.WithTiming {
+			scrapedAtForReport = Some(util.IntoUnixEncodedTime(t))
+		}
+		resourceReports, resource, exists := findInProjectResourceReport(filter, cluster, result, resourceID, project, scrapedAtForReport)
+		if !exists {
+			return nil
+		}
+		resourceReport := resourceReports[resource.Name]
+
+		if options.WithUserSpecifiedConstraints && resource.HasQuota {
+			resourceReport.MaxQuota = maxQuotaFromOutsideAdmin
+			resourceReport.ForbidAutogrowth = Some(forbidAutogrowth)
+		}
+
+		azReport := resourcesv2.ProjectAvailabilityZoneReport{
+			Usage:         usage,
+			PhysicalUsage: physicalUsage,
+		}
+		if resource.HasQuota {
+			azReport.Quota = quota
+		}
+		if options.WithSubresources && subresources != "" {
+			azReport.Subresources = json.RawMessage(subresources)
+		}
+		if historicalUsage != "" {
+			autogrowCfg, ok := cluster.QuotaDistributionConfigForResource(resource.Path.ServiceType, resource.Path.ResourceName).Autogrow.Unpack()
+			if ok {
+				ts, err := util.ParseTimeSeries[uint64](historicalUsage)
+				if err != nil {
+					return fmt.Errorf("could not parse historical usage in %s/%s of project %s: %w", resource.Path, az, projectUUID, err)
+				}
+				azReport.HistoricalUsage = Some(resourcesv2.ProjectHistoricalReport{
+					MinUsage: ts.MinOr(usage),
+					MaxUsage: ts.MaxOr(usage),
+					Duration: limesrates.Window(autogrowCfg.UsageDataRetentionPeriod.Into()),
+				})
+			}
+		}
+		resourceReport.AvailabilityZones[az] = azReport
+		resourceReports[resource.Name] = resourceReport
+		knownResourceReports[resourceKey{projectUUID, resourceID}] = resourceReport
+		return nil
+	})
+	if err != nil {
+		return *result, err
+	}
+
+	// second query: commitment amounts grouped by status and duration
+	if options.WithCommitmentStats {
+		query, args = filter.ExpandServiceFilters(projectResourceCommitmentStatsQuery, page.filterArg())
+		query, args = scope.ExpandScopeFilters(query, args...)
+		err = sqlext.ForeachRow(cluster.DB, query, args, func(rows *sql.Rows) error {
+			var (
+				projectUUID string
+				resourceID  db.ResourceID
+				az          limes.AvailabilityZone
+				status      liquid.CommitmentStatus
+				duration    limesresources.CommitmentDuration
+				amount      uint64
+			)
+			err := rows.Scan(&projectUUID, &resourceID, &az, &status, &duration, &amount)
+			if err != nil {
+				return err
+			}
+			// only fill AZ reports which were created by the first query
+			resourceReport, exists := knownResourceReports[resourceKey{projectUUID, resourceID}]
+			if !exists {
+				return nil
+			}
+			azReport, exists := resourceReport.AvailabilityZones[az]
+			if !exists {
+				return nil
+			}
+			if azReport.Committed == nil {
+				azReport.Committed = make(map[liquid.CommitmentStatus]map[limesresources.CommitmentDuration]uint64)
+			}
+			if azReport.Committed[status] == nil {
+				azReport.Committed[status] = make(map[limesresources.CommitmentDuration]uint64)
+			}
+			azReport.Committed[status][duration] += amount
+			resourceReport.AvailabilityZones[az] = azReport
+			return nil
+		})
+		if err != nil {
+			return *result, err
+		}
+	}
+
+	// epilogue: perform some operations on the finished report
+	for _, domainReport := range result.DomainReports {
+		for _, projectReport := range domainReport.ProjectReports {
+			for _, areaReport := range projectReport.Areas {
+				for _, serviceReport := range areaReport.Services {
+					for _, categoryReport := range serviceReport.Categories {
+						for _, resourceReport := range categoryReport.Resources {
+							removeEmptyProjectAZReports(resourceReport.AvailabilityZones)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return *result, nil
+}
+
+// removeEmptyProjectAZReports removes the AZ reports for "any" and "unknown" when they do not carry any information.
+// project_az_resources always has entries for "any", even if the resource is AZ-aware, because
+// ApplyComputedProjectQuota needs somewhere to write the base quotas.
+func removeEmptyProjectAZReports(reports map[limes.AvailabilityZone]resourcesv2.ProjectAvailabilityZoneReport) {
+	if len(reports) < 2 {
+		return
+	}
+	for _, az := range []limes.AvailabilityZone{limes.AvailabilityZoneAny, limes.AvailabilityZoneUnknown} {
+		azReport, exists := reports[az]
+		if !exists {
+			continue
+		}
+		if azReport.Usage == 0 && azReport.Quota.UnwrapOr(0) == 0 && azReport.PhysicalUsage.UnwrapOr(0) == 0 &&
+			len(azReport.Committed) == 0 && len(azReport.Subresources) == 0 {
+			delete(reports, az)
+		}
+	}
+}
+
+// findInProjectResourceReport creates or iterates higher level structs on the way to the nested
+// location of the db.ResourceID in the report and returns the map containing the
+// resourcesv2.ProjectResourceReport, so that the caller can update it, together with the matching db.Resource.
+// The scrapedAt value is set on the service level when it is created.
+// If this resource is not part of the filter, false is returned.
+func findInProjectResourceReport(filter Filter, cluster *core.Cluster, report *resourcesv2.ProjectGetResponse, resourceID db.ResourceID, project common.ProjectMetadata, scrapedAt Option[limes.UnixEncodedTime]) (map[liquid.ResourceName]resourcesv2.ProjectResourceReport, db.Resource, bool) {
+	services := filter.GetServices()
+	categories := filter.GetCategories()
+
+	for _, serviceType := range slices.Sorted(maps.Keys(services)) {
+		resources, _ := filter.GetResourcesForType(serviceType) // can have no resources
+		for _, resourceName := range slices.Sorted(maps.Keys(resources)) {
+			resource := resources[resourceName]
+			if resource.ID != resourceID {
+				continue
+			}
+
+			config := cluster.Config.Liquids[serviceType]
+			area := config.Area
+			// defense in depth: config should be in sync with serviceInfo
+			if area == "" {
+				return nil, resource, false
+			}
+
+			// check domain level (might be uninitialized)
+			if report.DomainReports == nil {
+				report.DomainReports = make(map[string]resourcesv2.ProjectsByDomainReport)
+			}
+			if _, exists := report.DomainReports[project.DomainInfo.UUID]; !exists {
+				report.DomainReports[project.DomainInfo.UUID] = resourcesv2.ProjectsByDomainReport{
+					ProjectReports: make(map[string]resourcesv2.ProjectReport),
+				}
+			}
+			domainReport := report.DomainReports[project.DomainInfo.UUID]
+
+			// check project level
+			if _, exists := domainReport.ProjectReports[project.UUID]; !exists {
+				domainReport.ProjectReports[project.UUID] = resourcesv2.ProjectReport{
+					ProjectMetadata: project,
+					Areas:           make(map[string]resourcesv2.ProjectAreaReport),
+				}
+			}
+			projectReport := domainReport.ProjectReports[project.UUID]
+
+			// check area level
+			if _, exists := projectReport.Areas[area]; !exists {
+				projectReport.Areas[area] = resourcesv2.ProjectAreaReport{Services: make(map[db.ServiceType]resourcesv2.ProjectServiceReport)}
+			}
+			areaReport := projectReport.Areas[area]
+
+			// check service level
+			if _, exists := areaReport.Services[serviceType]; !exists {
+				areaReport.Services[serviceType] = resourcesv2.ProjectServiceReport{
+					ScrapedAt:  scrapedAt,
+					Categories: make(map[liquid.CategoryName]resourcesv2.ProjectCategoryReport),
+				}
+			}
+			serviceReport := areaReport.Services[serviceType]
+
+			// check category level
+			category := liquid.CategoryName(serviceType)
+			if categoryID, exists := resource.CategoryID.Unpack(); exists {
+				category = categories[categoryID].Name
+			}
+			if _, exists := serviceReport.Categories[category]; !exists {
+				serviceReport.Categories[category] = resourcesv2.ProjectCategoryReport{Resources: make(map[liquid.ResourceName]resourcesv2.ProjectResourceReport)}
+			}
+			categoryReport := serviceReport.Categories[category]
+
+			// check resource level
+			if _, exists := categoryReport.Resources[resource.Name]; !exists {
+				categoryReport.Resources[resource.Name] = resourcesv2.ProjectResourceReport{AvailabilityZones: make(map[limes.AvailabilityZone]resourcesv2.ProjectAvailabilityZoneReport)}
+			}
+			return categoryReport.Resources, resource, true
+		}
+	}
+	return nil, db.Resource{}, false
+}
+
 var projectRateReportQuery = sqlext.SimplifyWhitespace(`
 	SELECT d.uuid, d.name, p.uuid, p.name, p.parent_uuid, pra.rate_id, pra.usage_as_bigint, pra.rate_limit, pra.window_ns
 	FROM project_rates pra
@@ -28,13 +337,15 @@ var projectRateReportQuery = sqlext.SimplifyWhitespace(`
 	ON p.id = pra.project_id
 	JOIN domains d
 	ON d.id = p.domain_id
-	WHERE {{pra.rate_id = ANY($rate_id)}}
+	WHERE ($1::BIGINT[] IS NULL OR p.id = ANY($1))
+	AND {{pra.rate_id = ANY($rate_id)}}
 	AND {{d.id = $domain_id}}
 	AND {{p.id = $project_id}}
 `)
 
 // GetProjectRates returns a ratesv2.ProjectGetResponse.
-func GetProjectRates(cluster *core.Cluster, token *gopherpolicy.Token, filter Filter, options common.ProjectRateReportOpts, scope Scope) (ratesv2.ProjectGetResponse, error) {
+// Only the projects on the given page are reported.
+func GetProjectRates(cluster *core.Cluster, token *gopherpolicy.Token, filter Filter, options common.ProjectRateReportOpts, scope Scope, page ProjectPage) (ratesv2.ProjectGetResponse, error) {
 	result := &ratesv2.ProjectGetResponse{}
 
 	// fill info report
@@ -47,7 +358,7 @@ func GetProjectRates(cluster *core.Cluster, token *gopherpolicy.Token, filter Fi
 	}
 
 	// the result will have all rates without usage --> we will filter later
-	query, args := filter.ExpandServiceFilters(projectRateReportQuery)
+	query, args := filter.ExpandServiceFilters(projectRateReportQuery, page.filterArg())
 	query, args = scope.ExpandScopeFilters(query, args...)
 	err := sqlext.ForeachRow(cluster.DB, query, args, func(rows *sql.Rows) error {
 		var (
@@ -0,0 +1,48 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package reports_v2
+
+import (
+	"github.com/sapcc/go-bits/sqlext"
+
+	"github.com/sapcc/limes/internal/db"
+)
+
+// This query collects everything that resource and rate reports are derived from,
+// in a form that is much cheaper to compute than the reports themselves:
+//
+//   - The service info (via the liquid versions) and the capacity (via the time of the last capacity scrape).
+//   - The usage and quota sync state of the projects in scope (via the times of their last scrape and quota sync).
+//   - The commitments in scope (via their last update, which is also bumped on status changes).
+//   - The domain and project metadata, the quota constraints and the rate limits in scope,
+//     which are changed without leaving a timestamp behind, so we use a checksum of the records instead.
+//
+// Quota is not listed explicitly because it is only ever recomputed as a consequence of changes to the above.
+var reportVersionQuery = sqlext.SimplifyWhitespace(`
+	WITH scoped_projects AS (
+		SELECT * FROM projects p WHERE {{p.domain_id = $domain_id}} AND {{p.id = $project_id}}
+	)
+	SELECT ROW(s.*, d.*, p.*, ps.*, pc.*, pr.*, pra.*)::TEXT FROM
+		(SELECT COALESCE(SUM(liquid_version), 0), MAX(scraped_at) FROM services) s,
+		(SELECT COUNT(*), COALESCE(BIT_XOR(HASHTEXTEXTENDED(d::TEXT, 0)), 0) FROM domains d WHERE {{d.id = $domain_id}}) d,
+		(SELECT COUNT(*), COALESCE(BIT_XOR(HASHTEXTEXTENDED(p::TEXT, 0)), 0) FROM scoped_projects p) p,
+		(SELECT COUNT(*), MAX(ps.scraped_at), MAX(ps.checked_at), MAX(ps.quota_desynced_at), COUNT(ps.quota_desynced_at)
+		   FROM project_services ps JOIN scoped_projects p ON p.id = ps.project_id) ps,
+		(SELECT COUNT(*), MAX(pc.updated_at)
+		   FROM project_commitments pc JOIN scoped_projects p ON p.id = pc.project_id) pc,
+		(SELECT COALESCE(BIT_XOR(HASHTEXTEXTENDED(pr::TEXT, 0)), 0)
+		   FROM project_resources pr JOIN scoped_projects p ON p.id = pr.project_id) pr,
+		(SELECT COALESCE(BIT_XOR(HASHTEXTEXTENDED(ROW(pra.id, pra.rate_limit, pra.window_ns)::TEXT, 0)), 0)
+		   FROM project_rates pra JOIN scoped_projects p ON p.id = pra.project_id) pra
+`)
+
+// GetReportVersion returns an opaque string that changes whenever the resource
+// or rate reports for the given scope change. Since it can be computed without
+// building the report, it is used to answer conditional requests on reports.
+func GetReportVersion(dbi db.Interface, scope Scope) (string, error) {
+	var version string
+	query, args := scope.ExpandScopeFilters(reportVersionQuery)
+	err := dbi.QueryRow(query, args...).Scan(&version)
+	return version, err
+}
@@ -77,6 +77,9 @@ var (
 	// project_commitments to replace lengthy and time-dependent conditions with
 	// simple checks on the enum value in `status`.
 	//
+	// Guaranteed commitments keep their status until ConfirmPendingCommitments
+	// moves them into "confirmed" once their `confirm_by` has passed.
+	//
 	// When moving to expired or superseded state, the transfer status, token and time are cleared.
 	//
 	// The structure of this query is slightly convoluted to ensure
@@ -85,6 +88,7 @@ var (
 		WITH possible_updates AS (
 			SELECT id, status, CASE WHEN superseded_at IS NOT NULL THEN {{liquid.CommitmentStatusSuperseded}}
 			                        WHEN expires_at <= $2          THEN {{liquid.CommitmentStatusExpired}}
+			                        WHEN status = {{liquid.CommitmentStatusGuaranteed}} AND confirmed_at IS NULL THEN {{liquid.CommitmentStatusGuaranteed}}
 			                        WHEN confirm_by > $2           THEN {{liquid.CommitmentStatusPlanned}}
 			                        WHEN confirmed_at IS NULL      THEN {{liquid.CommitmentStatusPending}}
 			                                                       ELSE {{liquid.CommitmentStatusConfirmed}} END AS new_status
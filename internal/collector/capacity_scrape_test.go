@@ -2388,3 +2388,42 @@ func TestCommitmentConfirmationTakesOverMultipleSmallCommitments(t *testing.T) {
 		%[9]s
 	`, createdAt[0].Unix(), createdAt[1].Unix(), createdAt[2].Unix(), expiresAt[0].Unix(), expiresAt[1].Unix(), expiresAt[2].Unix(), confirmedAt1.Unix(), confirmedAt2.Unix(), timestampUpdates())
 }
+
+func TestScanCapacityConfirmsGuaranteedCommitments(t *testing.T) {
+	s, add := commonScanCapacityWithCommitmentsSetup(t, string(must.Return(scanCapacityWithCommitmentsConfig.MarshalJSON())), false)
+	job := s.Collector.CapacityScrapeJob(s.Registry)
+	must.SucceedT(t, jobloop.ProcessMany(job, s.Ctx, len(s.Cluster.LiquidConnections)))
+	s.Auditor.IgnoreEventsUntilNow()
+
+	committedForTenDays := must.Return(limesresources.ParseCommitmentDuration("10 days"))
+	const oneDay = 24 * time.Hour
+	confirmBy := s.Clock.Now().Add(oneDay)
+	id := add(db.ProjectCommitment{
+		ProjectID:    s.GetProjectID("berlin"),
+		AZResourceID: s.GetAZResourceID("first", "capacity", "az-one"),
+		Amount:       10,
+		CreatedAt:    s.Clock.Now(),
+		ConfirmBy:    Some(confirmBy),
+		Duration:     committedForTenDays,
+	})
+	s.MustDBExec(`UPDATE project_commitments SET status = $1 WHERE id = $2`, liquid.CommitmentStatusGuaranteed, id)
+	getStatus := func() string {
+		t.Helper()
+		return must.ReturnT(s.DB.SelectStr(`SELECT status FROM project_commitments WHERE id = $1`, id))(t)
+	}
+
+	// before its confirm_by date, the guaranteed commitment is left alone
+	// (in particular, it must not be mistaken for a "planned" commitment)
+	s.Clock.StepBy(1 * time.Hour)
+	must.SucceedT(t, jobloop.ProcessMany(job, s.Ctx, len(s.Cluster.LiquidConnections)))
+	assert.Equal(t, getStatus(), string(liquid.CommitmentStatusGuaranteed))
+	s.Auditor.ExpectEvents(t /*, nothing */)
+
+	// once confirm_by has passed, it is confirmed without further ado
+	s.Clock.StepBy(oneDay)
+	must.SucceedT(t, jobloop.ProcessMany(job, s.Ctx, len(s.Cluster.LiquidConnections)))
+	assert.Equal(t, getStatus(), string(liquid.CommitmentStatusConfirmed))
+	events := s.Auditor.RecordedEvents()
+	assert.Equal(t, len(events), 1)
+	assert.Equal(t, events[0].Action, datamodel.ConfirmAction)
+}
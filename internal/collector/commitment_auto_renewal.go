@@ -0,0 +1,218 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package collector
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/go-bits/audittools"
+	"github.com/sapcc/go-bits/errext"
+	"github.com/sapcc/go-bits/jobloop"
+	"github.com/sapcc/go-bits/sqlext"
+
+	"github.com/sapcc/limes/internal/audit"
+	"github.com/sapcc/limes/internal/core"
+	"github.com/sapcc/limes/internal/datamodel"
+	"github.com/sapcc/limes/internal/db"
+)
+
+// CommitmentAutoRenewalJob is a jobloop.CronJob.
+//
+// It renews confirmed commitments once they are within the configured lead time before their expiration date,
+// but only in those domains where ClusterConfiguration.CommitmentAutoRenewal enables this.
+// If mail notifications are configured, each affected project is informed about its renewed commitments.
+func (c *Collector) CommitmentAutoRenewalJob(registerer prometheus.Registerer) jobloop.Job {
+	return (&jobloop.CronJob{
+		Metadata: jobloop.JobMetadata{
+			ReadableName: "renew commitments automatically",
+			CounterOpts: prometheus.CounterOpts{
+				Name: "limes_commitment_auto_renewals",
+				Help: "Counts runs of the automatic renewal of commitments.",
+			},
+		},
+		Interval: 5 * time.Minute,
+		Task:     c.renewCommitmentsAutomatically,
+	}).Setup(registerer)
+}
+
+var (
+	discoverRenewableCommitmentsQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+		SELECT d.name, pc.project_id, pc.id
+		  FROM project_commitments pc
+		  JOIN projects p ON p.id = pc.project_id
+		  JOIN domains d ON d.id = p.domain_id
+		 WHERE pc.status = {{liquid.CommitmentStatusConfirmed}} AND pc.renew_context_json IS NULL
+		   AND pc.expires_at > $1 AND pc.expires_at <= $2
+		 ORDER BY pc.project_id, pc.id
+	`))
+	// This repeats the checks from the discovery query, since the commitment
+	// could have been renewed manually in the meantime.
+	lockRenewableCommitmentQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+		SELECT * FROM project_commitments
+		 WHERE id = $1 AND status = {{liquid.CommitmentStatusConfirmed}} AND renew_context_json IS NULL
+		   FOR UPDATE
+	`))
+)
+
+func (c *Collector) renewCommitmentsAutomatically(ctx context.Context, _ prometheus.Labels) error {
+	autoRenewalConfig := c.Cluster.Config.CommitmentAutoRenewal.UnwrapOrPanic("this task should not have been called if automatic renewal is not configured")
+	now := c.MeasureTime()
+
+	// find commitments that are due for renewal, grouped by project
+	var projectIDs []db.ProjectID
+	commitmentIDsByProject := make(map[db.ProjectID][]db.ProjectCommitmentID)
+	queryArgs := []any{now, autoRenewalConfig.LeadTime.AddTo(now)}
+	err := sqlext.ForeachRow(c.DB, discoverRenewableCommitmentsQuery, queryArgs, func(rows *sql.Rows) error {
+		var (
+			domainName   string
+			projectID    db.ProjectID
+			commitmentID db.ProjectCommitmentID
+		)
+		err := rows.Scan(&domainName, &projectID, &commitmentID)
+		if err != nil {
+			return err
+		}
+		if !autoRenewalConfig.IsEnabledForDomain(domainName) {
+			return nil
+		}
+		if _, exists := commitmentIDsByProject[projectID]; !exists {
+			projectIDs = append(projectIDs, projectID)
+		}
+		commitmentIDsByProject[projectID] = append(commitmentIDsByProject[projectID], commitmentID)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// an error in one project shall not prevent the renewal of commitments in other projects
+	var errs errext.ErrorSet
+	for _, projectID := range projectIDs {
+		err := c.renewCommitmentsInProject(ctx, projectID, commitmentIDsByProject[projectID], now)
+		if err != nil {
+			errs.Addf("while renewing commitments in project %d: %w", projectID, err)
+		}
+	}
+	if !errs.IsEmpty() {
+		return errs.JoinedError(", ")
+	}
+	return nil
+}
+
+func (c *Collector) renewCommitmentsInProject(ctx context.Context, projectID db.ProjectID, commitmentIDs []db.ProjectCommitmentID, now time.Time) error {
+	tx, err := c.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer sqlext.RollbackUnlessCommitted(tx)
+
+	var dbProject db.Project
+	err = tx.SelectOne(&dbProject, `SELECT * FROM projects WHERE id = $1`, projectID)
+	if err != nil {
+		return err
+	}
+	var dbDomain db.Domain
+	err = tx.SelectOne(&dbDomain, `SELECT * FROM domains WHERE id = $1`, dbProject.DomainID)
+	if err != nil {
+		return err
+	}
+
+	sis := c.Cluster.SIC.GetSnapshot()
+	auditContext := audit.Context{
+		UserIdentity: audit.CollectorUserInfo{
+			TaskName: "commitment-auto-renewal",
+		},
+		Request: audit.CollectorDummyRequest,
+	}
+	var (
+		auditEvents   []audittools.Event
+		notifications []core.CommitmentNotification
+	)
+	for _, commitmentID := range commitmentIDs {
+		var dbCommitment db.ProjectCommitment
+		err := tx.SelectOne(&dbCommitment, lockRenewableCommitmentQuery, commitmentID)
+		if errors.Is(err, sql.ErrNoRows) {
+			continue
+		} else if err != nil {
+			return err
+		}
+
+		var path db.AZResourcePath
+		err = tx.QueryRow(`SELECT path FROM az_resources WHERE id = $1`, dbCommitment.AZResourceID).Scan(&path)
+		if err != nil {
+			return err
+		}
+		if _, exists := sis.GetResourceForPath(path.Resource()); !exists {
+			// commitments on resources that are not known to the ServiceInfoCache cannot be renewed
+			continue
+		}
+
+		// the renewed commitment is attributed to the creator of the original commitment,
+		// while the audit trail shows that the renewal was initiated by the collector
+		dbRenewedCommitment, ccr, err := datamodel.RenewCommitment(ctx, c.Cluster, tx, datamodel.CommitmentRenewal{
+			Commitment:  dbCommitment,
+			Project:     dbProject,
+			Domain:      dbDomain,
+			Path:        path,
+			CreatorUUID: dbCommitment.CreatorUUID,
+			CreatorName: dbCommitment.CreatorName,
+		}, sis, now, c.GenerateProjectCommitmentUUID)
+		if err != nil {
+			return fmt.Errorf("while renewing commitment %s: %w", dbCommitment.UUID, err)
+		}
+
+		auditEvents = append(auditEvents, audit.CommitmentEventTarget{
+			CommitmentChangeRequest: ccr,
+		}.ReplicateForAllProjectsWithDefaults(audittools.Event{
+			Time:       now,
+			Request:    auditContext.Request,
+			User:       auditContext.UserIdentity,
+			ReasonCode: http.StatusAccepted,
+			Action:     cadf.UpdateAction,
+		})...)
+
+		apiIdentity := c.Cluster.BehaviorForResourcePath(path.Resource()).IdentityInV1API
+		notifications = append(notifications, core.CommitmentNotification{
+			Commitment: dbRenewedCommitment,
+			DateString: dbCommitment.ExpiresAt.Format(time.DateOnly),
+			Resource: core.AZResourceLocationV1{
+				ServiceType:      apiIdentity.ServiceType,
+				ResourceName:     apiIdentity.Name,
+				AvailabilityZone: path.AvailabilityZone,
+			},
+		})
+	}
+
+	if mailConfig, ok := c.Cluster.Config.MailNotifications.Unpack(); ok && len(notifications) > 0 {
+		notification := core.CommitmentGroupNotification{
+			DomainName:  dbDomain.Name,
+			ProjectName: dbProject.Name,
+			Commitments: notifications,
+		}
+		mail, err := mailConfig.Templates.RenewedCommitments.Render(notification, projectID, now)
+		if err != nil {
+			return err
+		}
+		err = tx.Insert(&mail)
+		if err != nil {
+			return err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return err
+	}
+	for _, ae := range auditEvents {
+		c.Auditor.Record(ae)
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package collector_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/easypg"
+	"github.com/sapcc/go-bits/httptest"
+	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/assert"
+	. "go.xyrillian.de/gg/option"
+
+	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/internal/test"
+	"github.com/sapcc/limes/internal/test/common_fixtures"
+)
+
+func Test_CommitmentAutoRenewal(t *testing.T) {
+	s := test.NewSetup(t,
+		test.WithConfig(string(must.Return(httptest.NewJQModifiableJSONString(`{
+			"commitment_auto_renewal": {
+				"enabled_per_domain": [
+					{ "key": "france", "value": false },
+					{ "key": ".*", "value": true }
+				],
+				"lead_time": "30 days"
+			},
+			"mail_notifications": {
+				"templates": {
+					"renewed_commitments": {
+						"subject": "Your commitments were renewed",
+						"body": "Domain:{{ .DomainName }} Project:{{ .ProjectName }}{{ range .Commitments }} UUID:{{ .Commitment.UUID }} Amount:{{ .Commitment.Amount }} Date:{{ .DateString }} Service:{{ .Resource.ServiceType }} Resource:{{ .Resource.ResourceName }} AZ:{{ .Resource.AvailabilityZone }}{{ end }}"
+					}
+				}
+			}
+		}`, "Test_CommitmentAutoRenewal").
+			ModifyWithVariable(".availability_zones = $ref", common_fixtures.AZsOneTwo).
+			ModifyWithVariable(".discovery = $ref", common_fixtures.DiscoveryBerlinDresdenParis).
+			ModifyWithVariable(". * $ref", common_fixtures.AreaLiquidFirstSecond).
+			MarshalJSON()))),
+		test.WithPersistedServiceInfo("first", test.DefaultLiquidServiceInfo("First")),
+		test.WithPersistedServiceInfo("second", test.DefaultLiquidServiceInfo("Second")),
+		test.WithInitialDiscovery,
+		test.WithEmptyResourceRecordsAsNeeded,
+	)
+
+	// shorthands for the DB setup below
+	berlin := s.GetProjectID("berlin")
+	dresden := s.GetProjectID("dresden")
+	paris := s.GetProjectID("paris")
+	firstCapacityAZOne := s.GetAZResourceID("first", "capacity", "az-one")
+	committedForOneYear := must.Return(limesresources.ParseCommitmentDuration("1 year"))
+	const oneDay = 24 * time.Hour
+
+	add := func(c db.ProjectCommitment) {
+		t.Helper()
+		c.UUID = s.Collector.GenerateProjectCommitmentUUID()
+		c.AZResourceID = firstCapacityAZOne
+		c.Duration = committedForOneYear
+		c.CreatorUUID = "dummy"
+		c.CreatorName = "dummy"
+		c.CreationContextJSON = json.RawMessage(`{}`)
+		c.ExpiresAt = c.Duration.AddTo(c.ConfirmBy.UnwrapOr(c.CreatedAt))
+		c.UpdatedAt = c.CreatedAt
+		if c.Status != liquid.CommitmentStatusPlanned {
+			c.ConfirmedAt = Some(c.ConfirmBy.UnwrapOr(c.CreatedAt))
+		}
+		s.MustDBInsert(&c)
+	}
+
+	// commitments that expire within the lead time are renewed (ID = 1, 2)
+	add(db.ProjectCommitment{
+		ProjectID: berlin,
+		Amount:    10,
+		CreatedAt: s.Clock.Now().Add(-345 * oneDay),
+		Status:    liquid.CommitmentStatusConfirmed,
+	})
+	add(db.ProjectCommitment{
+		ProjectID: dresden,
+		Amount:    5,
+		CreatedAt: s.Clock.Now().Add(-340 * oneDay),
+		Status:    liquid.CommitmentStatusConfirmed,
+	})
+	// commitments that expire later are not renewed yet (ID = 3)
+	add(db.ProjectCommitment{
+		ProjectID: berlin,
+		Amount:    20,
+		CreatedAt: s.Clock.Now().Add(-305 * oneDay),
+		Status:    liquid.CommitmentStatusConfirmed,
+	})
+	// commitments in domains without automatic renewal are ignored (ID = 4)
+	add(db.ProjectCommitment{
+		ProjectID: paris,
+		Amount:    10,
+		CreatedAt: s.Clock.Now().Add(-345 * oneDay),
+		Status:    liquid.CommitmentStatusConfirmed,
+	})
+	// planned commitments are ignored (ID = 5)
+	add(db.ProjectCommitment{
+		ProjectID: berlin,
+		Amount:    10,
+		CreatedAt: s.Clock.Now().Add(-345 * oneDay),
+		ConfirmBy: Some(s.Clock.Now().Add(-345 * oneDay)),
+		Status:    liquid.CommitmentStatusPlanned,
+	})
+
+	job := s.Collector.CommitmentAutoRenewalJob(nil)
+	tr, tr0 := easypg.NewTracker(t, s.DB.Db)
+	tr0.Ignore()
+
+	// first run: commitments 1 and 2 are renewed, and each project gets one mail
+	must.SucceedT(t, job.ProcessOne(s.Ctx))
+	tr.DBChanges().AssertEqualf(`
+		UPDATE project_commitments SET renew_context_json = '{"reason": "renew", "related_ids": [6], "related_uuids": ["00000000-0000-0000-0000-000000000006"]}', updated_at = %[1]d WHERE id = 1 AND uuid = '00000000-0000-0000-0000-000000000001' AND transfer_token = NULL;
+		UPDATE project_commitments SET renew_context_json = '{"reason": "renew", "related_ids": [7], "related_uuids": ["00000000-0000-0000-0000-000000000007"]}', updated_at = %[1]d WHERE id = 2 AND uuid = '00000000-0000-0000-0000-000000000002' AND transfer_token = NULL;
+		INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirm_by, expires_at, creation_context_json, updated_at) VALUES (6, '00000000-0000-0000-0000-000000000006', 1, %[2]d, 'planned', 10, '1 year', %[1]d, 'dummy', 'dummy', %[3]d, %[4]d, '{"reason": "renew", "related_ids": [1], "related_uuids": ["00000000-0000-0000-0000-000000000001"]}', %[1]d);
+		INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirm_by, expires_at, creation_context_json, updated_at) VALUES (7, '00000000-0000-0000-0000-000000000007', 2, %[2]d, 'planned', 5, '1 year', %[1]d, 'dummy', 'dummy', %[5]d, %[6]d, '{"reason": "renew", "related_ids": [2], "related_uuids": ["00000000-0000-0000-0000-000000000002"]}', %[1]d);
+		INSERT INTO project_mail_notifications (id, project_id, subject, body, next_submission_at) VALUES (1, 1, 'Your commitments were renewed', 'Domain:germany Project:berlin UUID:00000000-0000-0000-0000-000000000006 Amount:10 Date:1970-01-21 Service:first Resource:capacity AZ:az-one', %[1]d);
+		INSERT INTO project_mail_notifications (id, project_id, subject, body, next_submission_at) VALUES (2, 2, 'Your commitments were renewed', 'Domain:germany Project:dresden UUID:00000000-0000-0000-0000-000000000007 Amount:5 Date:1970-01-26 Service:first Resource:capacity AZ:az-one', %[1]d);
+	`,
+		s.Clock.Now().Unix(), firstCapacityAZOne,
+		s.Clock.Now().Add(20*oneDay).Unix(), s.Clock.Now().Add((365+20)*oneDay).Unix(),
+		s.Clock.Now().Add(25*oneDay).Unix(), s.Clock.Now().Add((365+25)*oneDay).Unix(),
+	)
+	events := s.Auditor.RecordedEvents()
+	assert.Equal(t, len(events), 2)
+
+	// second run: nothing to do, since renewed commitments are not renewed again
+	must.SucceedT(t, job.ProcessOne(s.Ctx))
+	tr.DBChanges().AssertEmpty()
+	s.Auditor.ExpectEvents(t /*, nothing */)
+
+	// once commitment 3 gets close enough to its expiration date, it is renewed as well
+	s.Clock.StepBy(31 * oneDay)
+	must.SucceedT(t, job.ProcessOne(s.Ctx))
+	tr.DBChanges().AssertEqualf(`
+		UPDATE project_commitments SET renew_context_json = '{"reason": "renew", "related_ids": [8], "related_uuids": ["00000000-0000-0000-0000-000000000008"]}', updated_at = %[1]d WHERE id = 3 AND uuid = '00000000-0000-0000-0000-000000000003' AND transfer_token = NULL;
+		INSERT INTO project_commitments (id, uuid, project_id, az_resource_id, status, amount, duration, created_at, creator_uuid, creator_name, confirm_by, expires_at, creation_context_json, updated_at) VALUES (8, '00000000-0000-0000-0000-000000000008', 1, %[2]d, 'planned', 20, '1 year', %[1]d, 'dummy', 'dummy', %[3]d, %[4]d, '{"reason": "renew", "related_ids": [3], "related_uuids": ["00000000-0000-0000-0000-000000000003"]}', %[1]d);
+		INSERT INTO project_mail_notifications (id, project_id, subject, body, next_submission_at) VALUES (3, 1, 'Your commitments were renewed', 'Domain:germany Project:berlin UUID:00000000-0000-0000-0000-000000000008 Amount:20 Date:1970-03-02 Service:first Resource:capacity AZ:az-one', %[1]d);
+	`,
+		s.Clock.Now().Unix(), firstCapacityAZOne,
+		time.Unix(0, 0).Add(60*oneDay).Unix(), time.Unix(0, 0).Add((365+60)*oneDay).Unix(),
+	)
+	events = s.Auditor.RecordedEvents()
+	assert.Equal(t, len(events), 1)
+}
@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	limesresources "github.com/sapcc/go-api-declarations/limes/resources"
 	"github.com/sapcc/go-api-declarations/liquid"
 	"github.com/sapcc/go-bits/jobloop"
 	"github.com/sapcc/go-bits/sqlext"
@@ -18,8 +19,8 @@ import (
 
 // CleanupOldCommitmentsJob is a jobloop.CronJob.
 //
-// It moves expired commitments to status "expired" and cleans up old expired commitments
-// that do not have any non-expired predecessors.
+// It moves expired commitments to status "expired" and moves old expired, superseded
+// or deleted commitments into the commitment archive once their retention period has passed.
 func (c *Collector) CleanupOldCommitmentsJob(registerer prometheus.Registerer) jobloop.Job {
 	return (&jobloop.CronJob{
 		Metadata: jobloop.JobMetadata{
@@ -44,14 +45,29 @@ var (
 		       transfer_started_at = NULL
 		 WHERE status NOT IN ({{liquid.CommitmentStatusSuperseded}}, {{liquid.CommitmentStatusExpired}}, {{util.CommitmentStatusDeleted}}) AND expires_at <= $1
 	`))
-	hardDeleteCommitmentsQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
-		DELETE FROM project_commitments
-		 WHERE status = {{util.CommitmentStatusDeleted}} AND deleted_at + interval '6 months' <= $1
-	`))
-	expiredCommitmentsCleanupQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
-		DELETE FROM project_commitments pc
-		 WHERE status != {{util.CommitmentStatusDeleted}} AND expires_at + interval '1 month' <= $1
+	// The joins cannot drop any of the deleted rows, because project_commitments references
+	// projects and az_resources with ON DELETE RESTRICT.
+	archiveOldCommitmentsQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+		WITH archived AS (
+			DELETE FROM project_commitments
+			 WHERE (status = {{util.CommitmentStatusDeleted}} AND deleted_at <= $2)
+			    OR (status != {{util.CommitmentStatusDeleted}} AND expires_at <= $3)
+			RETURNING *
+		)
+		INSERT INTO project_commitments_archive (id, uuid, domain_uuid, project_uuid, service_type, resource_name, availability_zone,
+		       status, amount, duration, created_at, creator_uuid, creator_name, confirm_by, confirmed_at, expires_at,
+		       superseded_at, deleted_at, updated_at, creation_context_json, supersede_context_json, renew_context_json, archived_at)
+		SELECT a.id, a.uuid, d.uuid, p.uuid, s.type, r.name, azr.az,
+		       a.status, a.amount, a.duration, a.created_at, a.creator_uuid, a.creator_name, a.confirm_by, a.confirmed_at, a.expires_at,
+		       a.superseded_at, a.deleted_at, a.updated_at, a.creation_context_json, a.supersede_context_json, a.renew_context_json, $1
+		  FROM archived a
+		  JOIN projects p ON p.id = a.project_id
+		  JOIN domains d ON d.id = p.domain_id
+		  JOIN az_resources azr ON azr.id = a.az_resource_id
+		  JOIN resources r ON r.id = azr.resource_id
+		  JOIN services s ON s.id = r.service_id
 	`))
+	expiredIdempotencyKeysCleanupQuery = `DELETE FROM idempotency_keys WHERE expires_at <= $1`
 )
 
 func (c *Collector) cleanupOldCommitments(_ context.Context, _ prometheus.Labels) error {
@@ -63,24 +79,30 @@ func (c *Collector) cleanupOldCommitments(_ context.Context, _ prometheus.Labels
 		return fmt.Errorf("while moving commitments to status %q: %w", liquid.CommitmentStatusExpired, err)
 	}
 
-	// step 2: delete expired commitments after a grace period
+	// step 2: move expired, superseded and deleted commitments into the archive after their retention period
 	//
 	// NOTE: Expired commitments do not contribute to any calculations, so it would
-	// be fine to delete them immediately from a technical perspective. However,
-	// they don't take up that much space in the short run, and having them stick
-	// around in the DB for a little bit (in this case, one month) can
-	// potentially help in investigations when customers complain about
-	// commitments expiring unexpectedly.
-	_, err = c.DB.Exec(expiredCommitmentsCleanupQuery, now)
+	// be fine to archive them immediately from a technical perspective. However,
+	// having them stick around in the regular commitment listings for a little bit
+	// (by default, one month) can potentially help in investigations when customers
+	// complain about commitments expiring unexpectedly.
+	deletedCutoff := subtractCommitmentDuration(now, c.Cluster.Config.DeletedCommitmentRetentionPeriod())
+	expiredCutoff := subtractCommitmentDuration(now, c.Cluster.Config.ExpiredCommitmentRetentionPeriod())
+	_, err = c.DB.Exec(archiveOldCommitmentsQuery, now, deletedCutoff, expiredCutoff)
 	if err != nil {
-		return fmt.Errorf("while deleting expired commitments without undeleted successors: %w", err)
+		return fmt.Errorf("while archiving old commitments: %w", err)
 	}
 
-	// step 3: hard-delete soft-deleted commitments after a grace period
-	_, err = c.DB.Exec(hardDeleteCommitmentsQuery, now)
+	// step 3: forget about idempotency keys of commitment API requests after their retention period
+	_, err = c.DB.Exec(expiredIdempotencyKeysCleanupQuery, now)
 	if err != nil {
-		return fmt.Errorf("while deleting soft-deleted commitments: %w", err)
+		return fmt.Errorf("while deleting expired idempotency keys: %w", err)
 	}
 
 	return nil
 }
+
+// subtractCommitmentDuration is the inverse of CommitmentDuration.AddTo().
+func subtractCommitmentDuration(t time.Time, d limesresources.CommitmentDuration) time.Time {
+	return t.AddDate(-d.Years, -d.Months, -d.Days).Add(-d.Short)
+}
@@ -95,6 +95,9 @@ func TestCleanupOldCommitmentsJob(t *testing.T) {
 	commitmentForThreeYears, err := limesresources.ParseCommitmentDuration("3 years")
 	must.SucceedT(t, err)
 
+	// commitments in this test are inserted without updated_at, unless they get updated by the job
+	neverUpdated := time.Time{}.Unix()
+
 	// as a control group, this commitment will not expire for the entire duration of the test
 	creationContext := db.CommitmentWorkflowContext{Reason: db.CommitmentReasonCreate}
 	buf, err := json.Marshal(creationContext)
@@ -115,6 +118,7 @@ func TestCleanupOldCommitmentsJob(t *testing.T) {
 
 	// test 1: create an expired commitment
 	s.Clock.StepBy(30 * oneDay)
+	createdAt := s.Clock.Now()
 	s.MustDBInsert(&db.ProjectCommitment{
 		UUID:                "00000000-0000-0000-0000-000000000002",
 		ID:                  2,
@@ -132,12 +136,13 @@ func TestCleanupOldCommitmentsJob(t *testing.T) {
 
 	// job should set it to "expired", but leave it around for now
 	s.Clock.StepBy(1 * time.Minute)
+	expiredAt := s.Clock.Now()
 	must.SucceedT(t, job.ProcessOne(s.Ctx))
 	tr.DBChanges().AssertEqualf(`
 		UPDATE project_commitments SET status = 'expired', updated_at = %d WHERE id = 2 AND uuid = '00000000-0000-0000-0000-000000000002' AND transfer_token = NULL;
-	`, s.Clock.Now().Unix())
+	`, expiredAt.Unix())
 
-	// one month later, the commitment should be deleted
+	// one month later, the commitment should be moved into the archive
 	s.Clock.StepBy(10 * oneDay)
 	must.SucceedT(t, job.ProcessOne(s.Ctx))
 	tr.DBChanges().AssertEmpty()
@@ -145,7 +150,8 @@ func TestCleanupOldCommitmentsJob(t *testing.T) {
 	must.SucceedT(t, job.ProcessOne(s.Ctx))
 	tr.DBChanges().AssertEqualf(`
 		DELETE FROM project_commitments WHERE id = 2 AND uuid = '00000000-0000-0000-0000-000000000002' AND transfer_token = NULL;
-	`)
+		INSERT INTO project_commitments_archive (id, uuid, domain_uuid, project_uuid, service_type, resource_name, availability_zone, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, updated_at, creation_context_json, archived_at) VALUES (2, '00000000-0000-0000-0000-000000000002', 'uuid-for-germany', 'uuid-for-berlin', 'unittest', 'capacity', 'any', 'expired', 10, '1 day', %[1]d, '', '', %[1]d, %[2]d, %[3]d, '{"reason": "create"}', %[4]d);
+	`, createdAt.Add(-oneDay).Unix(), createdAt.Unix(), expiredAt.Unix(), s.Clock.Now().Unix())
 
 	// test 2: simulate a commitment that was created yesterday,
 	// and then converted five minutes later
@@ -159,6 +165,7 @@ func TestCleanupOldCommitmentsJob(t *testing.T) {
 	}
 	supersedeBuf, err := json.Marshal(supersedeContext)
 	must.SucceedT(t, err)
+	createdAt = s.Clock.Now()
 	s.MustDBInsert(&db.ProjectCommitment{
 		ID:                   3,
 		UUID:                 "00000000-0000-0000-0000-000000000003",
@@ -198,18 +205,24 @@ func TestCleanupOldCommitmentsJob(t *testing.T) {
 
 	// the commitment in status "superseded" should not be touched when moving to status "expired"
 	s.Clock.StepBy(1 * time.Minute)
+	expiredAt = s.Clock.Now()
 	must.SucceedT(t, job.ProcessOne(s.Ctx))
 	tr.DBChanges().AssertEqualf(`
 		UPDATE project_commitments SET status = 'expired', updated_at = %d WHERE id = 4 AND uuid = '00000000-0000-0000-0000-000000000004' AND transfer_token = NULL;
-	`, s.Clock.Now().Unix())
+	`, expiredAt.Unix())
 
-	// when cleaning up, both commitments should be deleted simultaneously
+	// when cleaning up, both commitments should be archived simultaneously
 	s.Clock.StepBy(40 * oneDay)
 	must.SucceedT(t, job.ProcessOne(s.Ctx))
 	tr.DBChanges().AssertEqualf(`
 		DELETE FROM project_commitments WHERE id = 3 AND uuid = '00000000-0000-0000-0000-000000000003' AND transfer_token = NULL;
 		DELETE FROM project_commitments WHERE id = 4 AND uuid = '00000000-0000-0000-0000-000000000004' AND transfer_token = NULL;
-	`)
+		INSERT INTO project_commitments_archive (id, uuid, domain_uuid, project_uuid, service_type, resource_name, availability_zone, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, superseded_at, updated_at, creation_context_json, supersede_context_json, archived_at) VALUES (3, '00000000-0000-0000-0000-000000000003', 'uuid-for-germany', 'uuid-for-berlin', 'unittest', 'capacity', 'any', 'superseded', 10, '1 day', %[1]d, '', '', %[1]d, %[2]d, %[3]d, %[4]d, '{"reason": "create"}', '{"reason": "convert", "related_ids": [4], "related_uuids": ["00000000-0000-0000-0000-000000000004"]}', %[6]d);
+		INSERT INTO project_commitments_archive (id, uuid, domain_uuid, project_uuid, service_type, resource_name, availability_zone, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, updated_at, creation_context_json, archived_at) VALUES (4, '00000000-0000-0000-0000-000000000004', 'uuid-for-germany', 'uuid-for-berlin', 'unittest', 'capacity', 'az-one', 'expired', 10, '1 day', %[3]d, '', '', %[1]d, %[2]d, %[5]d, '{"reason": "convert", "related_ids": [3], "related_uuids": ["00000000-0000-0000-0000-000000000003"]}', %[6]d);
+	`,
+		createdAt.Add(-oneDay).Unix(), createdAt.Unix(), createdAt.Add(-oneDay).Add(5*time.Minute).Unix(),
+		neverUpdated, expiredAt.Unix(), s.Clock.Now().Unix(),
+	)
 
 	// test 3: simulate two commitments with different expiration dates that were merged
 	creationContext = db.CommitmentWorkflowContext{
@@ -219,6 +232,7 @@ func TestCleanupOldCommitmentsJob(t *testing.T) {
 	}
 	buf, err = json.Marshal(creationContext)
 	must.SucceedT(t, err)
+	createdAt = s.Clock.Now()
 	commitment5 := db.ProjectCommitment{
 		ID:                  5,
 		UUID:                "00000000-0000-0000-0000-000000000005",
@@ -274,21 +288,29 @@ func TestCleanupOldCommitmentsJob(t *testing.T) {
 	// only the merged commitment should be set to status expired,
 	// the superseded commitments should not be touched
 	s.Clock.StepBy(5 * time.Minute)
+	expiredAt = s.Clock.Now()
 	must.SucceedT(t, job.ProcessOne(s.Ctx))
 	tr.DBChanges().AssertEqualf(`
 		UPDATE project_commitments SET status = 'expired', updated_at = %d WHERE id = 7 AND uuid = '00000000-0000-0000-0000-000000000007' AND transfer_token = NULL;
-	`, s.Clock.Now().Unix())
+	`, expiredAt.Unix())
 
-	// when cleaning up, all commitments related to the merge should be deleted simultaneously
+	// when cleaning up, all commitments related to the merge should be archived simultaneously
 	s.Clock.StepBy(40 * oneDay)
 	must.SucceedT(t, job.ProcessOne(s.Ctx))
 	tr.DBChanges().AssertEqualf(`
 		DELETE FROM project_commitments WHERE id = 5 AND uuid = '00000000-0000-0000-0000-000000000005' AND transfer_token = NULL;
 		DELETE FROM project_commitments WHERE id = 6 AND uuid = '00000000-0000-0000-0000-000000000006' AND transfer_token = NULL;
 		DELETE FROM project_commitments WHERE id = 7 AND uuid = '00000000-0000-0000-0000-000000000007' AND transfer_token = NULL;
-	`)
+		INSERT INTO project_commitments_archive (id, uuid, domain_uuid, project_uuid, service_type, resource_name, availability_zone, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, superseded_at, updated_at, creation_context_json, archived_at) VALUES (5, '00000000-0000-0000-0000-000000000005', 'uuid-for-germany', 'uuid-for-berlin', 'unittest', 'capacity', 'any', 'superseded', 10, '1 day', %[1]d, '', '', %[1]d, %[2]d, %[4]d, %[6]d, '{"reason": "merge", "related_ids": [7], "related_uuids": ["00000000-0000-0000-0000-000000000007"]}', %[7]d);
+		INSERT INTO project_commitments_archive (id, uuid, domain_uuid, project_uuid, service_type, resource_name, availability_zone, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, superseded_at, updated_at, creation_context_json, archived_at) VALUES (6, '00000000-0000-0000-0000-000000000006', 'uuid-for-germany', 'uuid-for-berlin', 'unittest', 'capacity', 'any', 'superseded', 5, '1 day', %[3]d, '', '', %[3]d, %[5]d, %[4]d, %[6]d, '{"reason": "merge", "related_ids": [7], "related_uuids": ["00000000-0000-0000-0000-000000000007"]}', %[7]d);
+		INSERT INTO project_commitments_archive (id, uuid, domain_uuid, project_uuid, service_type, resource_name, availability_zone, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, updated_at, creation_context_json, archived_at) VALUES (7, '00000000-0000-0000-0000-000000000007', 'uuid-for-germany', 'uuid-for-berlin', 'unittest', 'capacity', 'any', 'expired', 15, '1 day', %[4]d, '', '', %[4]d, %[5]d, %[5]d, '{"reason": "merge", "related_ids": [5, 6], "related_uuids": ["00000000-0000-0000-0000-000000000005", "00000000-0000-0000-0000-000000000006"]}', %[7]d);
+	`,
+		createdAt.Add(-oneDay).Unix(), createdAt.Unix(), createdAt.Add(-oneDay).Add(5*time.Minute).Unix(), createdAt.Add(-oneDay).Add(10*time.Minute).Unix(),
+		expiredAt.Unix(), neverUpdated, s.Clock.Now().Unix(),
+	)
 
-	// lastly, we test the hard-delete of soft deleted commitments after 6 months
+	// lastly, we test the archival of soft deleted commitments after 6 months
+	createdAt = s.Clock.Now()
 	s.MustDBInsert(&db.ProjectCommitment{
 		ID:                  8,
 		UUID:                "00000000-0000-0000-0000-000000000008",
@@ -310,10 +332,14 @@ func TestCleanupOldCommitmentsJob(t *testing.T) {
 	must.SucceedT(t, job.ProcessOne(s.Ctx))
 	tr.DBChanges().AssertEmpty()
 
-	// now we pass the 6 months mark, hard-delete happens
+	// now we pass the 6 months mark, archival happens
 	s.Clock.StepBy(33 * oneDay)
 	must.SucceedT(t, job.ProcessOne(s.Ctx))
 	tr.DBChanges().AssertEqualf(`
 		DELETE FROM project_commitments WHERE id = 8 AND uuid = '00000000-0000-0000-0000-000000000008' AND transfer_token = NULL;
-	`)
+		INSERT INTO project_commitments_archive (id, uuid, domain_uuid, project_uuid, service_type, resource_name, availability_zone, status, amount, duration, created_at, creator_uuid, creator_name, confirmed_at, expires_at, deleted_at, updated_at, creation_context_json, archived_at) VALUES (8, '00000000-0000-0000-0000-000000000008', 'uuid-for-germany', 'uuid-for-berlin', 'unittest', 'capacity', 'any', 'deleted', 15, '10 days', %[1]d, '', '', %[1]d, %[2]d, %[3]d, %[4]d, '{"reason": "merge", "related_ids": [5, 6], "related_uuids": ["00000000-0000-0000-0000-000000000005", "00000000-0000-0000-0000-000000000006"]}', %[5]d);
+	`,
+		createdAt.Add(-2*oneDay).Unix(), commitmentForTenDays.AddTo(createdAt.Add(-2*oneDay)).Unix(), createdAt.Add(-oneDay).Unix(),
+		neverUpdated, s.Clock.Now().Unix(),
+	)
 }
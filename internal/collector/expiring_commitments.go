@@ -14,18 +14,16 @@ import (
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/sapcc/go-bits/jobloop"
 	"github.com/sapcc/go-bits/sqlext"
+	. "go.xyrillian.de/gg/option"
 
 	"github.com/sapcc/limes/internal/core"
 	"github.com/sapcc/limes/internal/db"
 )
 
-const (
-	expiringCommitmentsNoticePeriod = 28 * 24 * time.Hour // 4 weeks
-)
-
 // ExpiringCommitmentNotificationJob is a jobloop.Job. A task scrapes commitments that are or are about to expire.
 // For all applicable commitments within a project the mail content to inform customers will be prepared and added to a queue.
-// Long-term commitments will be queued while short-term commitments will only be marked as notified.
+// Reminders are sent in several stages, as configured in MailConfiguration.ExpiryReminders.
+// For each stage, long-term commitments will be queued while short-term commitments will only be marked as notified.
 func (c *Collector) ExpiringCommitmentNotificationJob(registerer prometheus.Registerer) jobloop.Job {
 	return (&jobloop.ProducerConsumerJob[[]db.ProjectCommitment]{
 		Metadata: jobloop.JobMetadata{
@@ -41,9 +39,12 @@ func (c *Collector) ExpiringCommitmentNotificationJob(registerer prometheus.Regi
 }
 
 var (
+	// NOTE: Commitments that have already received the final reminder stage are filtered out here.
+	// Commitments that are in between stages are filtered out by findDueExpiryReminder().
 	discoverExpiringCommitmentsQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
 		SELECT * FROM project_commitments
-		 WHERE expires_at <= $1 AND status = {{liquid.CommitmentStatusConfirmed}} AND renew_context_json IS NULL AND NOT notified_for_expiration
+		 WHERE expires_at <= $1 AND status = {{liquid.CommitmentStatusConfirmed}} AND renew_context_json IS NULL
+		   AND (expiry_reminder_notice_period IS NULL OR expiry_reminder_notice_period != $2)
 	`))
 	locateExpiringCommitmentsQuery = sqlext.SimplifyWhitespace(`
 		SELECT pc.project_id, azr.path, pc.id
@@ -52,32 +53,83 @@ var (
 		WHERE pc.id = ANY($1)
 		ORDER BY azr.path ASC, pc.amount DESC
 	`)
-	updateCommitmentAsNotifiedQuery = `UPDATE project_commitments SET notified_for_expiration = TRUE, updated_at = $1 WHERE id = ANY($2)`
+	updateCommitmentAsNotifiedQuery = `UPDATE project_commitments SET expiry_reminder_notice_period = $1, updated_at = $2 WHERE id = ANY($3)`
 )
 
 func (c *Collector) discoverExpiringCommitments(_ context.Context, _ prometheus.Labels) (result []db.ProjectCommitment, err error) {
 	now := c.MeasureTime()
-	cutoff := now.Add(expiringCommitmentsNoticePeriod)
-	_, err = c.DB.Select(&result, discoverExpiringCommitmentsQuery, cutoff)
-	switch {
-	case err != nil:
+	mailConfig := c.Cluster.Config.MailNotifications.UnwrapOrPanic("this task should not have been called if mail notifications are not configured")
+	stages := mailConfig.ExpiryReminderStages()
+	cutoff := stages[0].NoticePeriod.AddTo(now)
+	finalNoticePeriod := stages[len(stages)-1].NoticePeriod
+
+	var candidates []db.ProjectCommitment
+	_, err = c.DB.Select(&candidates, discoverExpiringCommitmentsQuery, cutoff, finalNoticePeriod)
+	if err != nil {
 		return nil, err
-	case len(result) == 0:
+	}
+	for _, commitment := range candidates {
+		if findDueExpiryReminder(commitment, stages, now).IsSome() {
+			result = append(result, commitment)
+		}
+	}
+	if len(result) == 0 {
 		return nil, sql.ErrNoRows // instruct the jobloop to slow down
-	default:
-		return result, nil
 	}
+	return result, nil
+}
+
+// findDueExpiryReminder returns the index of the reminder stage that needs to be sent for this commitment right now, if any.
+// If several stages are due at once (e.g. because the commitment was created shortly before its expiration),
+// only the stage with the shortest notice period is sent.
+func findDueExpiryReminder(commitment db.ProjectCommitment, stages []core.ExpiryReminderConfiguration, now time.Time) Option[int] {
+	for idx := len(stages) - 1; idx >= 0; idx-- {
+		stageCutoff := stages[idx].NoticePeriod.AddTo(now)
+		if commitment.ExpiresAt.After(stageCutoff) {
+			continue
+		}
+		// this is the stage with the shortest notice period that is due, so we only need to check whether it was sent already
+		if lastNoticePeriod, ok := commitment.ExpiryReminderNoticePeriod.Unpack(); ok && !lastNoticePeriod.AddTo(now).After(stageCutoff) {
+			return None[int]()
+		}
+		return Some(idx)
+	}
+	return None[int]()
 }
 
 func (c *Collector) processExpiringCommitmentTask(ctx context.Context, commitments []db.ProjectCommitment, _ prometheus.Labels) error {
 	now := c.MeasureTime()
-	cutoff := now.Add(expiringCommitmentsNoticePeriod)
+	mailConfig := c.Cluster.Config.MailNotifications.UnwrapOrPanic("this task should not have been called if mail notifications are not configured")
+	stages := mailConfig.ExpiryReminderStages()
 	tx, err := c.DB.Begin()
 	if err != nil {
 		return err
 	}
 	defer sqlext.RollbackUnlessCommitted(tx)
 
+	// find which commitments need a notification in which stage
+	commitmentsByStage := make(map[int][]db.ProjectCommitment)
+	for _, c := range commitments {
+		stageIdx, ok := findDueExpiryReminder(c, stages, now).Unpack()
+		if ok {
+			commitmentsByStage[stageIdx] = append(commitmentsByStage[stageIdx], c)
+		}
+	}
+
+	// process stages in order for deterministic behavior in unit tests
+	for _, stageIdx := range slices.Sorted(maps.Keys(commitmentsByStage)) {
+		err := c.processExpiryReminderStage(tx, stages[stageIdx], &mailConfig.Templates, commitmentsByStage[stageIdx], now)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+func (c *Collector) processExpiryReminderStage(tx db.Interface, stage core.ExpiryReminderConfiguration, templates *core.MailTemplateConfiguration, commitments []db.ProjectCommitment, now time.Time) error {
+	cutoff := stage.NoticePeriod.AddTo(now)
+
 	// find which commitments need a notification
 	longTermCommitmentsByID := make(map[db.ProjectCommitmentID]db.ProjectCommitment)
 	var shortTermCommitmentIDs []db.ProjectCommitmentID
@@ -90,11 +142,10 @@ func (c *Collector) processExpiringCommitmentTask(ctx context.Context, commitmen
 	}
 
 	// mark short-term commitments as notified without queueing them
-	_, err = tx.Exec(updateCommitmentAsNotifiedQuery, now, pq.Array(shortTermCommitmentIDs))
+	_, err := tx.Exec(updateCommitmentAsNotifiedQuery, stage.NoticePeriod, now, pq.Array(shortTermCommitmentIDs))
 	if err != nil {
 		return err
 	}
-
 	// sort remaining commitments by project
 	notifications := make(map[db.ProjectID][]core.CommitmentNotification)
 	err = sqlext.ForeachRow(tx, locateExpiringCommitmentsQuery, []any{pq.Array(slices.Collect(maps.Keys(longTermCommitmentsByID)))}, func(rows *sql.Rows) error {
@@ -126,8 +177,7 @@ func (c *Collector) processExpiringCommitmentTask(ctx context.Context, commitmen
 	}
 
 	// generate notifications ordered by project_id for deterministic behavior in unit tests
-	mailConfig := c.Cluster.Config.MailNotifications.UnwrapOrPanic("this task should not have been called if mail notifications are not configured")
-	template := mailConfig.Templates.ExpiringCommitments
+	template := *stage.Template.UnwrapOr(&templates.ExpiringCommitments)
 	for _, projectID := range slices.Sorted(maps.Keys(notifications)) {
 		var notification core.CommitmentGroupNotification
 		commitments := notifications[projectID]
@@ -150,11 +200,10 @@ func (c *Collector) processExpiringCommitmentTask(ctx context.Context, commitmen
 		for idx, c := range commitments {
 			commitmentIDs[idx] = c.Commitment.ID
 		}
-		_, err = tx.Exec(updateCommitmentAsNotifiedQuery, now, pq.Array(commitmentIDs))
+		_, err = tx.Exec(updateCommitmentAsNotifiedQuery, stage.NoticePeriod, now, pq.Array(commitmentIDs))
 		if err != nil {
 			return err
 		}
 	}
-
-	return tx.Commit()
+	return nil
 }
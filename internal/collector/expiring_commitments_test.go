@@ -4,6 +4,7 @@
 package collector_test
 
 import (
+	"database/sql"
 	"encoding/json"
 	"html/template"
 	"testing"
@@ -14,6 +15,7 @@ import (
 	"github.com/sapcc/go-bits/easypg"
 	"github.com/sapcc/go-bits/httptest"
 	"github.com/sapcc/go-bits/must"
+	"go.xyrillian.de/gg/assert"
 	. "go.xyrillian.de/gg/option"
 
 	"github.com/sapcc/limes/internal/core"
@@ -168,11 +170,11 @@ func Test_ExpiringCommitmentNotification(t *testing.T) {
 	// successfully queue two projects with 2 commitments each. Ignore short-term commitments and mark them as notified.
 	must.SucceedT(t, job.ProcessOne(s.Ctx))
 	tr.DBChanges().AssertEqualf(`
-		UPDATE project_commitments SET notified_for_expiration = TRUE, updated_at = %[1]d WHERE id = 4 AND uuid = '00000000-0000-0000-0000-000000000004' AND transfer_token = NULL;
-		UPDATE project_commitments SET notified_for_expiration = TRUE, updated_at = %[1]d WHERE id = 5 AND uuid = '00000000-0000-0000-0000-000000000005' AND transfer_token = NULL;
-		UPDATE project_commitments SET notified_for_expiration = TRUE, updated_at = %[1]d WHERE id = 6 AND uuid = '00000000-0000-0000-0000-000000000006' AND transfer_token = NULL;
-		UPDATE project_commitments SET notified_for_expiration = TRUE, updated_at = %[1]d WHERE id = 7 AND uuid = '00000000-0000-0000-0000-000000000007' AND transfer_token = NULL;
-		UPDATE project_commitments SET notified_for_expiration = TRUE, updated_at = %[1]d WHERE id = 8 AND uuid = '00000000-0000-0000-0000-000000000008' AND transfer_token = NULL;
+		UPDATE project_commitments SET updated_at = %[1]d, expiry_reminder_notice_period = '28 days' WHERE id = 4 AND uuid = '00000000-0000-0000-0000-000000000004' AND transfer_token = NULL;
+		UPDATE project_commitments SET updated_at = %[1]d, expiry_reminder_notice_period = '28 days' WHERE id = 5 AND uuid = '00000000-0000-0000-0000-000000000005' AND transfer_token = NULL;
+		UPDATE project_commitments SET updated_at = %[1]d, expiry_reminder_notice_period = '28 days' WHERE id = 6 AND uuid = '00000000-0000-0000-0000-000000000006' AND transfer_token = NULL;
+		UPDATE project_commitments SET updated_at = %[1]d, expiry_reminder_notice_period = '28 days' WHERE id = 7 AND uuid = '00000000-0000-0000-0000-000000000007' AND transfer_token = NULL;
+		UPDATE project_commitments SET updated_at = %[1]d, expiry_reminder_notice_period = '28 days' WHERE id = 8 AND uuid = '00000000-0000-0000-0000-000000000008' AND transfer_token = NULL;
 		INSERT INTO project_mail_notifications (id, project_id, subject, body, next_submission_at) VALUES (1, 1, 'Information about expiring commitments', 'Domain:germany Project:berlin Creator:dummy Amount:5 Duration:1 year Date:1970-01-06 Service:service Resource:resource AZ:az-one Creator:dummy Amount:10 Duration:1 year Date:1970-01-06 Service:service Resource:resource AZ:az-two', %[1]d);
 		INSERT INTO project_mail_notifications (id, project_id, subject, body, next_submission_at) VALUES (2, 2, 'Information about expiring commitments', 'Domain:germany Project:dresden Creator:dummy Amount:5 Duration:1 year Date:1970-01-26 Service:service Resource:resource AZ:az-one Creator:dummy Amount:10 Duration:1 year Date:1970-01-26 Service:service Resource:resource AZ:az-two', %[1]d);
 	`, s.Clock.Now().Unix())
@@ -208,7 +210,124 @@ func Test_ExpiringCommitmentNotification(t *testing.T) {
 	mailConfig.Templates = originalMailTemplates
 	must.SucceedT(t, job.ProcessOne(s.Ctx))
 	tr.DBChanges().AssertEqualf(`
-		UPDATE project_commitments SET notified_for_expiration = TRUE, updated_at = %[1]d WHERE id = 10 AND uuid = '00000000-0000-0000-0000-000000000010' AND transfer_token = NULL;
+		UPDATE project_commitments SET updated_at = %[1]d, expiry_reminder_notice_period = '28 days' WHERE id = 10 AND uuid = '00000000-0000-0000-0000-000000000010' AND transfer_token = NULL;
 		INSERT INTO project_mail_notifications (id, project_id, subject, body, next_submission_at) VALUES (3, 1, 'Information about expiring commitments', 'Domain:germany Project:berlin Creator:dummy Amount:10 Duration:1 year Date:1970-01-02 Service:service Resource:resource AZ:az-one', %[1]d);
 	`, s.Clock.Now().Unix())
 }
+
+func Test_ExpiringCommitmentNotificationWithMultipleStages(t *testing.T) {
+	s := test.NewSetup(t,
+		test.WithConfig(string(must.Return(httptest.NewJQModifiableJSONString(`{
+			"mail_notifications": {
+				"templates": {
+					"expiring_commitments": {
+						"subject": "Information about expiring commitments",
+						"body": "Project:{{ .ProjectName }}{{ range .Commitments }} Amount:{{ .Commitment.Amount }} Date:{{ .DateString }}{{ end }}"
+					}
+				},
+				"expiry_reminders": [
+					{ "notice_period": "7 days" },
+					{
+						"notice_period": "90 days",
+						"template": {
+							"subject": "Early information about expiring commitments",
+							"body": "Early:{{ .ProjectName }}{{ range .Commitments }} Amount:{{ .Commitment.Amount }} Date:{{ .DateString }}{{ end }}"
+						}
+					},
+					{ "notice_period": "30 days" }
+				]
+			}
+		}`, "Test_ExpiringCommitmentNotificationWithMultipleStages").
+			ModifyWithVariable(".availability_zones = $ref", common_fixtures.AZsOneTwo).
+			ModifyWithVariable(".discovery = $ref", common_fixtures.DiscoveryBerlinDresdenParis).
+			ModifyWithVariable(". * $ref", common_fixtures.AreaLiquidFirstSecond).
+			MarshalJSON()))),
+		test.WithPersistedServiceInfo("first", test.DefaultLiquidServiceInfo("First")),
+		test.WithPersistedServiceInfo("second", test.DefaultLiquidServiceInfo("Second")),
+		test.WithInitialDiscovery,
+		test.WithEmptyResourceRecordsAsNeeded,
+	)
+
+	// shorthands for the DB setup below
+	berlin := s.GetProjectID("berlin")
+	dresden := s.GetProjectID("dresden")
+	firstCapacityAZOne := s.GetAZResourceID("first", "capacity", "az-one")
+	committedForOneYear := must.Return(limesresources.ParseCommitmentDuration("1 year"))
+	committedForSixtyDays := must.Return(limesresources.ParseCommitmentDuration("60 days"))
+	committedForTenDays := must.Return(limesresources.ParseCommitmentDuration("10 days"))
+	const oneDay = 24 * time.Hour
+
+	add := func(c db.ProjectCommitment) {
+		t.Helper()
+		c.AZResourceID = firstCapacityAZOne
+		c.CreatorUUID = "dummy"
+		c.CreatorName = "dummy"
+		c.CreationContextJSON = json.RawMessage(`{}`)
+		c.ExpiresAt = c.Duration.AddTo(c.CreatedAt)
+		c.ConfirmedAt = Some(c.CreatedAt)
+		c.Status = liquid.CommitmentStatusConfirmed
+		s.MustDBInsert(&c)
+	}
+
+	// expires in 65 days: only the first stage is due
+	add(db.ProjectCommitment{
+		UUID:      "00000000-0000-0000-0000-000000000001",
+		ProjectID: berlin,
+		Amount:    1,
+		CreatedAt: s.Clock.Now().Add(-300 * oneDay),
+		Duration:  committedForOneYear,
+	})
+	// expires in 15 days: the first stage is skipped because the second stage is also due
+	add(db.ProjectCommitment{
+		UUID:      "00000000-0000-0000-0000-000000000002",
+		ProjectID: dresden,
+		Amount:    2,
+		CreatedAt: s.Clock.Now().Add(-350 * oneDay),
+		Duration:  committedForOneYear,
+	})
+	// expires in 10 days: the second stage is due, and the commitment is long enough to receive it
+	add(db.ProjectCommitment{
+		UUID:      "00000000-0000-0000-0000-000000000003",
+		ProjectID: berlin,
+		Amount:    10,
+		CreatedAt: s.Clock.Now().Add(-50 * oneDay),
+		Duration:  committedForSixtyDays,
+	})
+	// expires in 10 days: the second stage is due, but the commitment is too short to receive it
+	add(db.ProjectCommitment{
+		UUID:      "00000000-0000-0000-0000-000000000004",
+		ProjectID: berlin,
+		Amount:    5,
+		CreatedAt: s.Clock.Now(),
+		Duration:  committedForTenDays,
+	})
+
+	job := s.Collector.ExpiringCommitmentNotificationJob(nil)
+	tr, tr0 := easypg.NewTracker(t, s.DB.Db)
+	tr0.Ignore()
+
+	// each stage is rendered with its own template (or the default template if it does not have one)
+	must.SucceedT(t, job.ProcessOne(s.Ctx))
+	tr.DBChanges().AssertEqualf(`
+		UPDATE project_commitments SET updated_at = %[1]d, expiry_reminder_notice_period = '90 days' WHERE id = 1 AND uuid = '00000000-0000-0000-0000-000000000001' AND transfer_token = NULL;
+		UPDATE project_commitments SET updated_at = %[1]d, expiry_reminder_notice_period = '30 days' WHERE id = 2 AND uuid = '00000000-0000-0000-0000-000000000002' AND transfer_token = NULL;
+		UPDATE project_commitments SET updated_at = %[1]d, expiry_reminder_notice_period = '30 days' WHERE id = 3 AND uuid = '00000000-0000-0000-0000-000000000003' AND transfer_token = NULL;
+		UPDATE project_commitments SET updated_at = %[1]d, expiry_reminder_notice_period = '30 days' WHERE id = 4 AND uuid = '00000000-0000-0000-0000-000000000004' AND transfer_token = NULL;
+		INSERT INTO project_mail_notifications (id, project_id, subject, body, next_submission_at) VALUES (1, 1, 'Early information about expiring commitments', 'Early:berlin Amount:1 Date:1970-03-07', %[1]d);
+		INSERT INTO project_mail_notifications (id, project_id, subject, body, next_submission_at) VALUES (2, 1, 'Information about expiring commitments', 'Project:berlin Amount:10 Date:1970-01-11', %[1]d);
+		INSERT INTO project_mail_notifications (id, project_id, subject, body, next_submission_at) VALUES (3, 2, 'Information about expiring commitments', 'Project:dresden Amount:2 Date:1970-01-16', %[1]d);
+	`, s.Clock.Now().Unix())
+
+	// no stage is sent twice
+	assert.ErrEqual(t, job.ProcessOne(s.Ctx), sql.ErrNoRows)
+	tr.DBChanges().AssertEmpty()
+
+	// when the final stage becomes due, it is sent to all commitments that are long enough to receive it
+	s.Clock.StepBy(4 * oneDay)
+	must.SucceedT(t, job.ProcessOne(s.Ctx))
+	tr.DBChanges().AssertEqualf(`
+		UPDATE project_commitments SET updated_at = %[1]d, expiry_reminder_notice_period = '7 days' WHERE id = 3 AND uuid = '00000000-0000-0000-0000-000000000003' AND transfer_token = NULL;
+		UPDATE project_commitments SET updated_at = %[1]d, expiry_reminder_notice_period = '7 days' WHERE id = 4 AND uuid = '00000000-0000-0000-0000-000000000004' AND transfer_token = NULL;
+		INSERT INTO project_mail_notifications (id, project_id, subject, body, next_submission_at) VALUES (4, 1, 'Information about expiring commitments', 'Project:berlin Amount:10 Date:1970-01-11 Amount:5 Date:1970-01-11', %[1]d);
+	`, s.Clock.Now().Unix())
+}
@@ -82,6 +82,18 @@ func NewCluster(config ClusterConfiguration, timeNow func() time.Time, dbm *gorp
 		if err != nil {
 			errs.Addf("could not parse transfer mail template: %w", err)
 		}
+		err = mailConfig.Templates.RenewedCommitments.Compile()
+		if err != nil {
+			errs.Addf("could not parse renewal mail template: %w", err)
+		}
+		for _, reminder := range mailConfig.ExpiryReminders {
+			if template, ok := reminder.Template.Unpack(); ok {
+				err = template.Compile()
+				if err != nil {
+					errs.Addf("could not parse expiration mail template for notice period %q: %w", reminder.NoticePeriod.String(), err)
+				}
+			}
+		}
 	}
 
 	if !fillLiquidConnections {
@@ -14,7 +14,7 @@ import (
 	"go.xyrillian.de/gg/is"
 	. "go.xyrillian.de/gg/option"
 
-	resourcesv2 "github.com/sapcc/limes/internal/apideclarations/apiv2/resources"
+	resourcesv2 "github.com/sapcc/limes/apideclarations/apiv2/resources"
 )
 
 // CommitmentBehavior describes how commitments work for a single resource.
@@ -30,6 +30,10 @@ type CommitmentBehavior struct {
 	MinConfirmDate Option[time.Time]                `json:"min_confirm_date"`
 	UntilPercent   Option[float64]                  `json:"until_percent"`
 	ConversionRule Option[CommitmentConversionRule] `json:"conversion_rule"`
+
+	// If true, amending a commitment restarts its duration at the time of the amendment.
+	// Otherwise, the amended commitment keeps the expiration date of the original commitment.
+	RestartExpiryOnAmend bool `json:"restart_expiry_on_amend"`
 }
 
 // Validate returns a list of all errors in this behavior configuration.
@@ -62,6 +66,8 @@ type ScopedCommitmentBehavior struct {
 	MinConfirmDate Option[time.Time]
 	UntilPercent   Option[float64]
 	ConversionRule Option[CommitmentConversionRule]
+
+	RestartExpiryOnAmend bool
 }
 
 // ForDomain resolves Durations.Pick() using the provided domain name.
@@ -71,6 +77,8 @@ func (b CommitmentBehavior) ForDomain(domainName string) ScopedCommitmentBehavio
 		MinConfirmDate: b.MinConfirmDate,
 		UntilPercent:   b.UntilPercent,
 		ConversionRule: b.ConversionRule,
+
+		RestartExpiryOnAmend: b.RestartExpiryOnAmend,
 	}
 }
 
@@ -98,6 +106,8 @@ func (b CommitmentBehavior) ForCluster() ScopedCommitmentBehavior {
 		MinConfirmDate: b.MinConfirmDate,
 		UntilPercent:   b.UntilPercent,
 		ConversionRule: b.ConversionRule,
+
+		RestartExpiryOnAmend: b.RestartExpiryOnAmend,
 	}
 }
 
@@ -36,6 +36,49 @@ type ClusterConfiguration struct {
 	RateBehaviors            []RateBehavior                         `json:"rate_behavior"`
 	QuotaDistributionConfigs []QuotaDistributionConfiguration       `json:"quota_distribution_configs"`
 	MailNotifications        Option[*MailConfiguration]             `json:"mail_notifications"`
+
+	// Use IdempotencyKeyRetentionPeriod() to access this.
+	IdempotencyKeyRetention Option[util.MarshalableTimeDuration] `json:"idempotency_key_retention_period"`
+	// Use ExpiredCommitmentRetentionPeriod() and DeletedCommitmentRetentionPeriod() to access these.
+	ExpiredCommitmentRetention Option[limesresources.CommitmentDuration] `json:"expired_commitment_retention_period"`
+	DeletedCommitmentRetention Option[limesresources.CommitmentDuration] `json:"deleted_commitment_retention_period"`
+
+	CommitmentAutoRenewal Option[CommitmentAutoRenewalConfiguration] `json:"commitment_auto_renewal"`
+}
+
+// IdempotencyKeyRetentionPeriod returns how long the API remembers the responses to requests with an Idempotency-Key header.
+func (cluster *ClusterConfiguration) IdempotencyKeyRetentionPeriod() time.Duration {
+	return cluster.IdempotencyKeyRetention.UnwrapOr(util.MarshalableTimeDuration(24 * time.Hour)).Into()
+}
+
+// ExpiredCommitmentRetentionPeriod returns how long expired and superseded commitments are kept around
+// after their expiration date, before they are moved into the commitment archive.
+func (cluster *ClusterConfiguration) ExpiredCommitmentRetentionPeriod() limesresources.CommitmentDuration {
+	return cluster.ExpiredCommitmentRetention.UnwrapOr(limesresources.CommitmentDuration{Months: 1})
+}
+
+// DeletedCommitmentRetentionPeriod returns how long deleted commitments are kept around
+// after their deletion, before they are moved into the commitment archive.
+func (cluster *ClusterConfiguration) DeletedCommitmentRetentionPeriod() limesresources.CommitmentDuration {
+	return cluster.DeletedCommitmentRetention.UnwrapOr(limesresources.CommitmentDuration{Months: 6})
+}
+
+// CommitmentRenewalPeriod is how long before their expiration date commitments can be renewed at the earliest.
+// This value is mandated by the API spec.
+const CommitmentRenewalPeriod = 90 * 24 * time.Hour
+
+// CommitmentAutoRenewalConfiguration appears in type ClusterConfiguration.
+// It describes in which domains commitments are renewed automatically by the collector.
+type CommitmentAutoRenewalConfiguration struct {
+	// This ConfigSet is keyed on domain name. Domains without a matching entry do not have automatic renewal.
+	EnabledPerDomain regexpext.ConfigSet[string, bool] `json:"enabled_per_domain"`
+	// How long before their expiration date commitments are renewed.
+	LeadTime limesresources.CommitmentDuration `json:"lead_time"`
+}
+
+// IsEnabledForDomain returns whether commitments in the given domain shall be renewed automatically.
+func (c CommitmentAutoRenewalConfiguration) IsEnabledForDomain(domainName string) bool {
+	return c.EnabledPerDomain.Pick(domainName).UnwrapOr(false)
 }
 
 // GetLiquidConfigurationForType returns the LiquidConfiguration or false.
@@ -171,6 +214,34 @@ type AutogrowQuotaDistributionConfiguration struct {
 type MailConfiguration struct {
 	Endpoint  string                    `json:"endpoint"`
 	Templates MailTemplateConfiguration `json:"templates"`
+	// Use ExpiryReminderStages() to access this.
+	ExpiryReminders []ExpiryReminderConfiguration `json:"expiry_reminders"`
+}
+
+// ExpiryReminderConfiguration appears in type MailConfiguration.
+// It describes one stage of the reminders that are sent for commitments nearing their expiration date.
+type ExpiryReminderConfiguration struct {
+	// The reminder is sent once the commitment expires within this period.
+	NoticePeriod limesresources.CommitmentDuration `json:"notice_period"`
+	// If not given, Templates.ExpiringCommitments is used.
+	Template Option[*MailTemplate] `json:"template"`
+}
+
+// defaultExpiryReminderNoticePeriod is used if no expiry reminders are configured.
+var defaultExpiryReminderNoticePeriod = limesresources.CommitmentDuration{Days: 28}
+
+// ExpiryReminderStages returns the configured expiry reminders, ordered from the longest to the shortest notice period.
+// If none are configured, a single reminder is sent 28 days before expiration.
+func (m *MailConfiguration) ExpiryReminderStages() []ExpiryReminderConfiguration {
+	if len(m.ExpiryReminders) == 0 {
+		return []ExpiryReminderConfiguration{{NoticePeriod: defaultExpiryReminderNoticePeriod}}
+	}
+	// notice periods can only be compared when applied to a concrete point in time
+	// (the choice of reference point does not matter for the durations that are used in practice)
+	var refTime time.Time
+	return slices.SortedStableFunc(slices.Values(m.ExpiryReminders), func(lhs, rhs ExpiryReminderConfiguration) int {
+		return rhs.NoticePeriod.AddTo(refTime).Compare(lhs.NoticePeriod.AddTo(refTime))
+	})
 }
 
 // MailTemplateConfiguration appears in type Configuration.
@@ -180,6 +251,7 @@ type MailTemplateConfiguration struct {
 	ConfirmedCommitments   MailTemplate `json:"confirmed_commitments"`
 	ExpiringCommitments    MailTemplate `json:"expiring_commitments"`
 	TransferredCommitments MailTemplate `json:"transferred_commitments"`
+	RenewedCommitments     MailTemplate `json:"renewed_commitments"`
 }
 
 // NewClusterFromJSON reads and validates the configuration in the given JSON document.
@@ -267,6 +339,44 @@ func (cluster ClusterConfiguration) validateConfig() (errs errext.ErrorSet) {
 		errs.Append(behavior.Validate(fmt.Sprintf("rate_behavior[%d]", idx)))
 	}
 
+	if period, ok := cluster.IdempotencyKeyRetention.Unpack(); ok && period.Into() <= 0 {
+		errs.Addf("invalid value for idempotency_key_retention_period: must be greater than 0")
+	}
+	if period, ok := cluster.ExpiredCommitmentRetention.Unpack(); ok && !period.AddTo(time.Time{}).After(time.Time{}) {
+		errs.Addf("invalid value for expired_commitment_retention_period: must be greater than 0")
+	}
+	if period, ok := cluster.DeletedCommitmentRetention.Unpack(); ok && !period.AddTo(time.Time{}).After(time.Time{}) {
+		errs.Addf("invalid value for deleted_commitment_retention_period: must be greater than 0")
+	}
+
+	if autoRenewal, ok := cluster.CommitmentAutoRenewal.Unpack(); ok {
+		leadTime := autoRenewal.LeadTime.AddTo(time.Time{}).Sub(time.Time{})
+		switch {
+		case leadTime == 0:
+			missing("commitment_auto_renewal.lead_time")
+		case leadTime < 0 || leadTime > CommitmentRenewalPeriod:
+			errs.Addf("invalid value for commitment_auto_renewal.lead_time: must be greater than 0 and no longer than %d days",
+				CommitmentRenewalPeriod/(24*time.Hour))
+		}
+		if mailConfig, ok := cluster.MailNotifications.Unpack(); ok && mailConfig.Templates.RenewedCommitments.Body == "" {
+			missing("mail_notifications.templates.renewed_commitments.body")
+		}
+	}
+
+	if mailConfig, ok := cluster.MailNotifications.Unpack(); ok {
+		isNoticePeriod := make(map[string]bool)
+		for idx, reminder := range mailConfig.ExpiryReminders {
+			noticePeriod := reminder.NoticePeriod.String()
+			switch {
+			case reminder.NoticePeriod.AddTo(time.Time{}).IsZero():
+				missing(fmt.Sprintf("mail_notifications.expiry_reminders[%d].notice_period", idx))
+			case isNoticePeriod[noticePeriod]:
+				errs.Addf("invalid value for mail_notifications.expiry_reminders[%d].notice_period: %q is used by multiple reminders", idx, noticePeriod)
+			}
+			isNoticePeriod[noticePeriod] = true
+		}
+	}
+
 	for idx, qdCfg := range cluster.QuotaDistributionConfigs {
 		if qdCfg.FullResourceNameRx == "" {
 			missing(fmt.Sprintf(`distribution_model_configs[%d].resource`, idx))
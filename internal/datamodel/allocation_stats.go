@@ -41,7 +41,7 @@ type clusterAZAllocationStats struct {
 func (c clusterAZAllocationStats) allowsQuotaOvercommit(cfg core.AutogrowQuotaDistributionConfiguration) (allowsGrowth, allowsBase bool) {
 	usedCapacity := uint64(0)
 	for _, stats := range c.ProjectStats {
-		usedCapacity += max(stats.Committed, stats.Usage)
+		usedCapacity += max(stats.Committed+stats.Guaranteed, stats.Usage)
 	}
 
 	if c.Capacity == 0 {
@@ -63,13 +63,14 @@ func (c clusterAZAllocationStats) allowsQuotaOvercommit(cfg core.AutogrowQuotaDi
 // considers the overcommit factor for the resource.
 func (c clusterAZAllocationStats) CanAcceptCommitmentChanges(additions, subtractions map[db.ProjectID]uint64, behavior core.CommitmentBehavior) bool {
 	// calculate `sum_over_projects(max(committed, usage))` before and after the requested changes
+	// (guaranteed commitments count as committed since their capacity is already reserved)
 	var (
 		usedCapacityBefore = uint64(0)
 		usedCapacityAfter  = uint64(0)
 	)
 	for projectID, stats := range c.ProjectStats {
-		usedCapacityBefore += max(stats.Committed, stats.Usage)
-		committedAfter := saturatingSub(stats.Committed+additions[projectID], subtractions[projectID])
+		usedCapacityBefore += max(stats.Committed+stats.Guaranteed, stats.Usage)
+		committedAfter := saturatingSub(stats.Committed+stats.Guaranteed+additions[projectID], subtractions[projectID])
 		usedCapacityAfter += max(committedAfter, stats.Usage)
 	}
 
@@ -96,6 +97,26 @@ func (c clusterAZAllocationStats) CanAcceptCommitmentChanges(additions, subtract
 	return false
 }
 
+// CommittableAmountForProject returns the largest amount by which the confirmed
+// commitments of the given project could grow in this AZ, such that the change
+// would still be accepted by CanAcceptCommitmentChanges.
+func (c clusterAZAllocationStats) CommittableAmountForProject(projectID db.ProjectID, behavior core.CommitmentBehavior) uint64 {
+	// Since CanAcceptCommitmentChanges is monotonic in the added amount, we can binary-search for the largest accepted amount.
+	// Additions are either accepted because they are covered by the project's own usage, or because they fit into the committable
+	// portion of the capacity (which is never bigger than the capacity itself), which gives us an upper bound for the search.
+	lowerBound := uint64(0) // invariant: always accepted
+	upperBound := max(c.Capacity, c.ProjectStats[projectID].Usage)
+	for lowerBound < upperBound {
+		candidate := lowerBound + (upperBound-lowerBound+1)/2
+		if c.CanAcceptCommitmentChanges(map[db.ProjectID]uint64{projectID: candidate}, nil, behavior) {
+			lowerBound = candidate
+		} else {
+			upperBound = candidate - 1
+		}
+	}
+	return lowerBound
+}
+
 // Like `lhs - rhs`, but never underflows below 0.
 func saturatingSub(lhs, rhs uint64) uint64 {
 	if lhs < rhs {
@@ -108,6 +129,7 @@ func saturatingSub(lhs, rhs uint64) uint64 {
 // resource by a single project.
 type projectAZAllocationStats struct {
 	Committed          uint64 // sum of confirmed commitments
+	Guaranteed         uint64 // sum of guaranteed commitments
 	Usage              uint64
 	MinHistoricalUsage uint64
 	MaxHistoricalUsage uint64
@@ -123,12 +145,15 @@ var (
 	`))
 
 	getUsageInResourceQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
-		SELECT pazr.project_id, azr.az, pazr.usage, pazr.historical_usage, COALESCE(SUM(pc.amount), 0)
+		SELECT pazr.project_id, azr.az, pazr.usage, pazr.historical_usage,
+		       COALESCE(SUM(pc.amount) FILTER (WHERE pc.status = {{liquid.CommitmentStatusConfirmed}}), 0),
+		       COALESCE(SUM(pc.amount) FILTER (WHERE pc.status = {{liquid.CommitmentStatusGuaranteed}}), 0)
 		  FROM services s
 		  JOIN resources r ON r.service_id = s.id
 		  JOIN az_resources azr ON azr.resource_id = r.id
 		  JOIN project_az_resources pazr ON pazr.az_resource_id = azr.id
-		  LEFT OUTER JOIN project_commitments pc ON pc.az_resource_id = azr.id AND pc.project_id = pazr.project_id AND pc.status = {{liquid.CommitmentStatusConfirmed}}
+		  LEFT OUTER JOIN project_commitments pc ON pc.az_resource_id = azr.id AND pc.project_id = pazr.project_id
+		                                        AND pc.status IN ({{liquid.CommitmentStatusConfirmed}}, {{liquid.CommitmentStatusGuaranteed}})
 		 WHERE s.type = $1 AND r.name = $2 AND ($3::text IS NULL OR azr.az = $3) AND azr.az != {{liquid.AvailabilityZoneTotal}}
 		 GROUP BY pazr.project_id, azr.az, pazr.usage, pazr.historical_usage
 	`))
@@ -171,7 +196,7 @@ func collectAZAllocationStats(serviceType db.ServiceType, resourceName liquid.Re
 			stats               projectAZAllocationStats
 			historicalUsageJSON string
 		)
-		err := rows.Scan(&projectID, &az, &stats.Usage, &historicalUsageJSON, &stats.Committed)
+		err := rows.Scan(&projectID, &az, &stats.Usage, &historicalUsageJSON, &stats.Committed, &stats.Guaranteed)
 		if err != nil {
 			return err
 		}
@@ -199,3 +224,25 @@ func collectAZAllocationStats(serviceType db.ServiceType, resourceName liquid.Re
 
 	return result, nil
 }
+
+// GetCommittableAmounts returns the amounts that the given project could commit
+// right now in each AZ of the given resource without being rejected for lack of capacity.
+// Transferable commitments, which may be consumed when confirming a new commitment, are not considered,
+// so the actually committable amount may be higher than reported.
+func GetCommittableAmounts(path db.ResourcePath, projectID db.ProjectID, cluster *core.Cluster, dbi db.Interface) (map[limes.AvailabilityZone]uint64, error) {
+	statsByAZ, err := collectAZAllocationStats(path.ServiceType, path.ResourceName, None[limes.AvailabilityZone](), cluster, dbi)
+	if err != nil {
+		return nil, err
+	}
+	behavior := cluster.CommitmentBehaviorForResourcePath(path)
+
+	result := make(map[limes.AvailabilityZone]uint64, len(statsByAZ))
+	for az, stats := range statsByAZ {
+		// if the project has no project_az_resources entry, it cannot have commitments here
+		if _, exists := stats.ProjectStats[projectID]; !exists {
+			continue
+		}
+		result[az] = stats.CommittableAmountForProject(projectID, behavior)
+	}
+	return result, nil
+}
@@ -77,4 +77,52 @@ func TestCanAcceptCommitmentChanges(t *testing.T) {
 	subtractions = map[db.ProjectID]uint64{4: 30}
 	result = stats.CanAcceptCommitmentChanges(additions, subtractions, behavior)
 	assert.Equal(t, result, true)
+
+	// not acceptable: guaranteed commitments have not been confirmed yet, but they already occupy capacity
+	// (without the guaranteed commitment, allocations would grow from 30 to 35, which would fit)
+	stats = clusterAZAllocationStats{
+		Capacity: 35,
+		ProjectStats: map[db.ProjectID]projectAZAllocationStats{
+			1: {Committed: 5, Usage: 10},
+			2: {Committed: 5, Usage: 10, Guaranteed: 10},
+			3: {Committed: 5, Usage: 10},
+		},
+	}
+	additions = map[db.ProjectID]uint64{1: 10}
+	result = stats.CanAcceptCommitmentChanges(additions, nil, behavior)
+	assert.Equal(t, result, false)
+}
+
+func TestCommittableAmountForProject(t *testing.T) {
+	stats := clusterAZAllocationStats{
+		Capacity: 100,
+		ProjectStats: map[db.ProjectID]projectAZAllocationStats{
+			1: {Committed: 10, Usage: 20},
+			2: {Committed: 30, Usage: 5},
+		},
+	}
+	behavior := core.CommitmentBehavior{}
+
+	// 50 of 100 are allocated: project 1 can commit the remaining 50 plus its 10 of uncommitted usage
+	assert.Equal(t, stats.CommittableAmountForProject(1, behavior), 60)
+	// project 2 has no uncommitted usage, so it can only commit the remaining 50
+	assert.Equal(t, stats.CommittableAmountForProject(2, behavior), 50)
+
+	// with until_percent, only 70 of 100 may be allocated
+	restrictiveBehavior := core.CommitmentBehavior{
+		UntilPercent: Some(70.0),
+	}
+	assert.Equal(t, stats.CommittableAmountForProject(1, restrictiveBehavior), 30)
+	assert.Equal(t, stats.CommittableAmountForProject(2, restrictiveBehavior), 20)
+
+	// when capacity is overcommitted, commitments can still cover existing usage
+	stats.Capacity = 20
+	assert.Equal(t, stats.CommittableAmountForProject(1, behavior), 10)
+	assert.Equal(t, stats.CommittableAmountForProject(2, behavior), 0)
+
+	// the result is consistent with CanAcceptCommitmentChanges
+	stats.Capacity = 100
+	amount := stats.CommittableAmountForProject(1, behavior)
+	assert.Equal(t, stats.CanAcceptCommitmentChanges(map[db.ProjectID]uint64{1: amount}, nil, behavior), true)
+	assert.Equal(t, stats.CanAcceptCommitmentChanges(map[db.ProjectID]uint64{1: amount + 1}, nil, behavior), false)
 }
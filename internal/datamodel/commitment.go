@@ -80,7 +80,8 @@ func BuildSplitCommitment(dbCommitment db.ProjectCommitment, amount uint64, now
 // This is either a regular user who deletes the commitment within 24 hours of creation or an admin.
 func CanDeleteCommitment(token *gopherpolicy.Token, commitment db.ProjectCommitment, timeNow func() time.Time) bool {
 	// up to 24 hours after creation of fresh commitments, future commitments can still be deleted by their creators
-	if commitment.Status == liquid.CommitmentStatusPlanned || commitment.Status == liquid.CommitmentStatusPending || commitment.Status == liquid.CommitmentStatusConfirmed {
+	switch commitment.Status {
+	case liquid.CommitmentStatusPlanned, liquid.CommitmentStatusPending, liquid.CommitmentStatusGuaranteed, liquid.CommitmentStatusConfirmed:
 		var creationContext db.CommitmentWorkflowContext
 		err := json.Unmarshal(commitment.CreationContextJSON, &creationContext)
 		if err == nil && creationContext.Reason == db.CommitmentReasonCreate && timeNow().Before(commitment.CreatedAt.Add(24*time.Hour)) {
@@ -222,16 +223,27 @@ func DelegateChangeCommitments(ctx context.Context, cluster *core.Cluster, req l
 			return result, fmt.Errorf("failed to check local ChangeCommitment: %w", err)
 		}
 		if !canAcceptLocally {
-			return liquid.CommitmentChangeResponse{
-				RejectionReason: "not enough capacity!",
-				RetryAt:         None[time.Time](),
-			}, nil
+			return rejectCommitmentChangeForLackOfCapacity(dbi, service.Type)
 		}
 	}
 
 	return result, nil
 }
 
+// rejectCommitmentChangeForLackOfCapacity builds the response for a commitment change that Limes itself rejected for lack of capacity.
+// Since capacity is only reassessed on the next capacity scrape, the client is advised to retry after that.
+func rejectCommitmentChangeForLackOfCapacity(dbi db.Interface, serviceType db.ServiceType) (liquid.CommitmentChangeResponse, error) {
+	var nextScrapeAt time.Time
+	err := dbi.QueryRow(`SELECT next_scrape_at FROM services WHERE type = $1`, serviceType).Scan(&nextScrapeAt)
+	if err != nil {
+		return liquid.CommitmentChangeResponse{}, fmt.Errorf("could not find next capacity scrape for %s: %w", serviceType, err)
+	}
+	return liquid.CommitmentChangeResponse{
+		RejectionReason: "not enough capacity!",
+		RetryAt:         Some(nextScrapeAt),
+	}, nil
+}
+
 // LiquidProjectMetadataFromDBProject converts a db.Project into liquid.ProjectMetadata.
 // We use this function regardless of `liquid.ServiceInfo.CommitmentHandlingNeedsProjectMetadata`, so that
 // ProjectMetadata is always filled for Limes-internal use. Before delegating it to the liquid,
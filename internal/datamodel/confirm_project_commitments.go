@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"maps"
+	"net/http"
 	"slices"
 	"time"
 
@@ -15,6 +16,7 @@ import (
 	"github.com/sapcc/go-api-declarations/liquid"
 	"github.com/sapcc/go-bits/audittools"
 	"github.com/sapcc/go-bits/logg"
+	"github.com/sapcc/go-bits/must"
 	"github.com/sapcc/go-bits/sqlext"
 
 	. "go.xyrillian.de/gg/option"
@@ -45,6 +47,15 @@ var getConfirmableCommitmentsQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlac
 		 ORDER BY pc.created_at ASC, pc.confirm_by ASC, pc.id ASC
 	`))
 
+// Guaranteed commitments do not need to wait for capacity, so their order only matters for deterministic behavior in tests.
+var getDueGuaranteedCommitmentsQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+		SELECT pc.*
+		  FROM az_resources azr
+		  JOIN project_commitments pc ON pc.az_resource_id = azr.id
+		 WHERE azr.path = $1 AND pc.status = {{liquid.CommitmentStatusGuaranteed}} AND pc.confirm_by <= $2
+		 ORDER BY pc.confirm_by ASC, pc.id ASC
+	`))
+
 const ConfirmAction cadf.Action = "confirm"
 const ConsumeAction cadf.Action = "consume"
 
@@ -80,11 +91,11 @@ func CanAcceptCommitmentChangeRequest(req liquid.CommitmentChangeRequest, servic
 				return false, fmt.Errorf("project %s not found in database", projectUUID)
 			}
 			for _, commitment := range projectCommitmentChangeset.ByResource[resourceName].Commitments {
-				if commitment.NewStatus == Some(liquid.CommitmentStatusConfirmed) && (commitment.OldStatus != Some(liquid.CommitmentStatusConfirmed)) {
+				if reservesCapacity(commitment.NewStatus) && !reservesCapacity(commitment.OldStatus) {
 					additions[project.ID] += commitment.Amount
 					additionSum += commitment.Amount
 				}
-				if commitment.OldStatus == Some(liquid.CommitmentStatusConfirmed) && (commitment.NewStatus != Some(liquid.CommitmentStatusConfirmed)) {
+				if reservesCapacity(commitment.OldStatus) && !reservesCapacity(commitment.NewStatus) {
 					subtractions[project.ID] += commitment.Amount
 					subtractionSum += commitment.Amount
 				}
@@ -112,12 +123,26 @@ func CanAcceptCommitmentChangeRequest(req liquid.CommitmentChangeRequest, servic
 	return true, nil
 }
 
+// reservesCapacity returns whether commitments in the given status occupy capacity.
+// Guaranteed commitments are included because the capacity for them is reserved ahead of their confirmation.
+func reservesCapacity(status Option[liquid.CommitmentStatus]) bool {
+	return status == Some(liquid.CommitmentStatusConfirmed) || status == Some(liquid.CommitmentStatusGuaranteed)
+}
+
 // ConfirmPendingCommitments goes through all unconfirmed commitments that
 // could be confirmed, in chronological creation order, and confirms as many of
 // them as possible given the currently available capacity. Simultaneously, it
 // releases transferable commitments that can be used to satisfy the pending ones.
+//
+// Guaranteed commitments whose ConfirmBy date has passed are confirmed
+// unconditionally before that, since their capacity was already reserved when they were created.
 func ConfirmPendingCommitments(ctx context.Context, path db.AZResourcePath, cluster *core.Cluster, dbi db.Interface, now time.Time, generateProjectCommitmentUUID func() liquid.CommitmentUUID, generateTransferToken func() string, auditContext audit.Context) (auditEvents []audittools.Event, err error) {
 	// load confirmable commitments
+	var guaranteedCommitments []db.ProjectCommitment
+	_, err = dbi.Select(&guaranteedCommitments, getDueGuaranteedCommitmentsQuery, path, now)
+	if err != nil {
+		return nil, fmt.Errorf("while enumerating due guaranteed commitments for %s: %w", path, err)
+	}
 	var confirmableCommitments []db.ProjectCommitment
 	_, err = dbi.Select(&confirmableCommitments, getConfirmableCommitmentsQuery, path)
 	if err != nil {
@@ -125,7 +150,7 @@ func ConfirmPendingCommitments(ctx context.Context, path db.AZResourcePath, clus
 	}
 
 	// optimization: do not do more loading, if we do not have anything to confirm
-	if len(confirmableCommitments) == 0 {
+	if len(guaranteedCommitments) == 0 && len(confirmableCommitments) == 0 {
 		return nil, nil
 	}
 
@@ -142,6 +167,9 @@ func ConfirmPendingCommitments(ctx context.Context, path db.AZResourcePath, clus
 
 	// load affected projects and domains
 	affectedProjectIDs := make(map[db.ProjectID]struct{})
+	for _, c := range guaranteedCommitments {
+		affectedProjectIDs[c.ProjectID] = struct{}{}
+	}
 	for _, c := range confirmableCommitments {
 		affectedProjectIDs[c.ProjectID] = struct{}{}
 	}
@@ -162,13 +190,27 @@ func ConfirmPendingCommitments(ctx context.Context, path db.AZResourcePath, clus
 		confirmationTemplate = Some(mailConfig.Templates.ConfirmedCommitments)
 	}
 
+	// guaranteed commitments go first: this needs to happen before the transferable commitment cache
+	// collects its allocation stats, so that it sees them as confirmed
+	confirmedCommitmentsByProjectID := make(map[db.ProjectID][]*db.ProjectCommitment)
+	if len(guaranteedCommitments) > 0 {
+		events, err := confirmGuaranteedCommitments(ctx, path, cluster, sis, dbi, now, guaranteedCommitments, affectedProjectsByID, affectedDomainsByID, auditContext)
+		if err != nil {
+			return nil, err
+		}
+		auditEvents = append(auditEvents, events...)
+		for i := range guaranteedCommitments {
+			c := &guaranteedCommitments[i]
+			confirmedCommitmentsByProjectID[c.ProjectID] = append(confirmedCommitmentsByProjectID[c.ProjectID], c)
+		}
+	}
+
 	// initiate cache of transferable commitments
 	transferableCommitmentCache, err := NewTransferableCommitmentCache(dbi, cluster, sis, path, now, generateProjectCommitmentUUID, generateTransferToken, transferTemplate)
 	if err != nil {
 		return nil, err
 	}
 
-	confirmedCommitmentsByProjectID := make(map[db.ProjectID][]*db.ProjectCommitment)
 	// foreach confirmable commitment in the order to be confirmed
 	for i := range confirmableCommitments {
 		cc := confirmableCommitments[i] // avoid pointer issues in loop
@@ -235,6 +277,78 @@ func ConfirmPendingCommitments(ctx context.Context, path db.AZResourcePath, clus
 	return auditEvents, nil
 }
 
+// confirmGuaranteedCommitments moves the given guaranteed commitments into status "confirmed".
+// The liquid is informed about this change, but it does not need to approve it,
+// because guaranteed commitments include an implicit approval for moving into status "confirmed".
+func confirmGuaranteedCommitments(ctx context.Context, path db.AZResourcePath, cluster *core.Cluster, sis core.ServiceInfoSnapshot, dbi db.Interface, now time.Time, commitments []db.ProjectCommitment, projectsByID map[db.ProjectID]db.Project, domainsByID map[db.DomainID]db.Domain, auditContext audit.Context) ([]audittools.Event, error) {
+	statsByAZ, err := collectAZAllocationStats(path.ServiceType, path.ResourceName, Some(path.AvailabilityZone), cluster, dbi)
+	if err != nil {
+		return nil, err
+	}
+	stats := statsByAZ[path.AvailabilityZone]
+
+	ccr := liquid.CommitmentChangeRequest{
+		AZ:          path.AvailabilityZone,
+		InfoVersion: must.BeOK(sis.GetServiceForType(path.ServiceType)).LiquidVersion,
+		ByProject:   make(map[liquid.ProjectUUID]liquid.ProjectCommitmentChangeset),
+	}
+	for i := range commitments {
+		c := &commitments[i]
+		project := projectsByID[c.ProjectID]
+		pcc, exists := ccr.ByProject[project.UUID]
+		if !exists {
+			pcc = liquid.ProjectCommitmentChangeset{
+				ProjectMetadata: LiquidProjectMetadataFromDBProject(project, domainsByID[project.DomainID]),
+				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
+					path.ResourceName: {
+						TotalConfirmedBefore:  stats.ProjectStats[c.ProjectID].Committed,
+						TotalConfirmedAfter:   stats.ProjectStats[c.ProjectID].Committed,
+						TotalGuaranteedBefore: stats.ProjectStats[c.ProjectID].Guaranteed,
+						TotalGuaranteedAfter:  stats.ProjectStats[c.ProjectID].Guaranteed,
+					},
+				},
+			}
+		}
+		rcc := pcc.ByResource[path.ResourceName]
+		rcc.TotalConfirmedAfter += c.Amount
+		rcc.TotalGuaranteedAfter = saturatingSub(rcc.TotalGuaranteedAfter, c.Amount)
+		rcc.Commitments = append(rcc.Commitments, liquid.Commitment{
+			UUID:      c.UUID,
+			OldStatus: Some(c.Status),
+			NewStatus: Some(liquid.CommitmentStatusConfirmed),
+			Amount:    c.Amount,
+			ConfirmBy: c.ConfirmBy,
+			ExpiresAt: c.ExpiresAt,
+		})
+		pcc.ByResource[path.ResourceName] = rcc
+		ccr.ByProject[project.UUID] = pcc
+
+		c.ConfirmedAt = Some(now)
+		c.Status = liquid.CommitmentStatusConfirmed
+		c.UpdatedAt = now
+		_, err = dbi.Update(c)
+		if err != nil {
+			return nil, fmt.Errorf("while confirming guaranteed commitment ID=%d for %s: %w", c.ID, path, err)
+		}
+	}
+
+	// since this change does not require confirmation, a rejection by the liquid is not actionable
+	_, err = DelegateChangeCommitments(ctx, cluster, ccr, sis, path.ServiceType, dbi)
+	if err != nil {
+		return nil, err
+	}
+
+	return audit.CommitmentEventTarget{
+		CommitmentChangeRequest: ccr,
+	}.ReplicateForAllProjectsWithDefaults(audittools.Event{
+		Time:       now,
+		Request:    auditContext.Request,
+		User:       auditContext.UserIdentity,
+		ReasonCode: http.StatusOK,
+		Action:     ConfirmAction,
+	}), nil
+}
+
 func generateConfirmationMails(mailTemplate Option[core.MailTemplate], dbi db.Interface, path db.AZResourcePath, apiIdentity core.ResourceRef, project db.Project, domain db.Domain, confirmedCommitments []*db.ProjectCommitment, now time.Time) error {
 	// The system can be configured to not send mails (e.g. for test systems).
 	tpl, tplExists := mailTemplate.Unpack()
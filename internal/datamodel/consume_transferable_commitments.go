@@ -177,11 +177,10 @@ func (t *TransferableCommitmentCache) CanConfirmWithTransfers(ctx context.Contex
 				ProjectMetadata: LiquidProjectMetadataFromDBProject(project, domain),
 				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
 					t.path.ResourceName: {
-						TotalConfirmedBefore: t.stats.ProjectStats[c.ProjectID].Committed,
-						TotalConfirmedAfter:  t.stats.ProjectStats[c.ProjectID].Committed + c.Amount,
-						// TODO: change when introducing "guaranteed" commitments
-						TotalGuaranteedBefore: 0,
-						TotalGuaranteedAfter:  0,
+						TotalConfirmedBefore:  t.stats.ProjectStats[c.ProjectID].Committed,
+						TotalConfirmedAfter:   t.stats.ProjectStats[c.ProjectID].Committed + c.Amount,
+						TotalGuaranteedBefore: t.stats.ProjectStats[c.ProjectID].Guaranteed,
+						TotalGuaranteedAfter:  t.stats.ProjectStats[c.ProjectID].Guaranteed,
 						Commitments: []liquid.Commitment{
 							{
 								UUID:      c.UUID,
@@ -242,11 +241,10 @@ func (t *TransferableCommitmentCache) CanConfirmWithTransfers(ctx context.Contex
 				ProjectMetadata: LiquidProjectMetadataFromDBProject(tcProject, tcDomain),
 				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
 					t.path.ResourceName: {
-						TotalConfirmedBefore: t.stats.ProjectStats[tc.ProjectID].Committed,
-						TotalConfirmedAfter:  t.stats.ProjectStats[tc.ProjectID].Committed, // will be adjusted below based on how much is consumed
-						// TODO: change when introducing "guaranteed" commitments
-						TotalGuaranteedBefore: 0,
-						TotalGuaranteedAfter:  0,
+						TotalConfirmedBefore:  t.stats.ProjectStats[tc.ProjectID].Committed,
+						TotalConfirmedAfter:   t.stats.ProjectStats[tc.ProjectID].Committed, // will be adjusted below based on how much is consumed
+						TotalGuaranteedBefore: t.stats.ProjectStats[tc.ProjectID].Guaranteed,
+						TotalGuaranteedAfter:  t.stats.ProjectStats[tc.ProjectID].Guaranteed,
 					},
 				},
 			}
@@ -309,12 +307,15 @@ func (t *TransferableCommitmentCache) CanConfirmWithTransfers(ctx context.Contex
 	if err != nil {
 		return result, err
 	}
-	if result.RejectionReason != "" || dryRun {
+	if result.RejectionReason != "" {
 		return result, nil
 	}
 
-	// adjust stats locally
+	// adjust stats locally (also for dry runs, so that subsequent calls check against the cumulative result)
 	t.updateStats(ccr)
+	if dryRun {
+		return result, nil
+	}
 
 	// add audit events
 	t.auditEventsByConfirmedCommitmentUUID[c.UUID] = t.assembleAuditEvents(ccr, cacs, project.UUID, auditAction, auditContext)
@@ -371,6 +372,98 @@ func (t *TransferableCommitmentCache) CanConfirmWithTransfers(ctx context.Contex
 	return result, nil
 }
 
+// CanTransferCommitment checks whether the given commitment, which has been marked for transfer,
+// can be moved from its current project into the given target project. The capacity check works
+// like in CanConfirmWithTransfers. If the move is accepted, the local stats get adjusted and the commitment
+// is no longer considered for consumption by subsequent calls to CanConfirmWithTransfers. Like in
+// CanConfirmWithTransfers, the moved commitment itself is not subject to change in this operation,
+// i.e. it must be updated by the caller. The returned CCR can be used for the audit trail.
+func (t *TransferableCommitmentCache) CanTransferCommitment(ctx context.Context, c db.ProjectCommitment, sourceProject db.Project, sourceDomain db.Domain, targetProject db.Project, targetDomain db.Domain) (ccr liquid.CommitmentChangeRequest, result liquid.CommitmentChangeResponse, err error) {
+	// We add the projects and domains to the affected lists, so that we can refer to them in private functions more easily.
+	for _, project := range []db.Project{sourceProject, targetProject} {
+		t.affectedProjectsByID[project.ID] = project
+		t.affectedProjectsByUUID[project.UUID] = project
+	}
+	t.affectedDomainsByID[sourceDomain.ID] = sourceDomain
+	t.affectedDomainsByID[targetDomain.ID] = targetDomain
+
+	sourceStats := t.stats.ProjectStats[sourceProject.ID]
+	targetStats := t.stats.ProjectStats[targetProject.ID]
+	sourceStatsAfter := sourceStats
+	targetStatsAfter := targetStats
+	switch c.Status {
+	case liquid.CommitmentStatusConfirmed:
+		sourceStatsAfter.Committed -= c.Amount
+		targetStatsAfter.Committed += c.Amount
+	case liquid.CommitmentStatusGuaranteed:
+		sourceStatsAfter.Guaranteed -= c.Amount
+		targetStatsAfter.Guaranteed += c.Amount
+	}
+
+	ccr = liquid.CommitmentChangeRequest{
+		AZ:          t.path.AvailabilityZone,
+		InfoVersion: must.BeOK(t.sis.GetServiceForType(t.path.ServiceType)).LiquidVersion,
+		ByProject: map[liquid.ProjectUUID]liquid.ProjectCommitmentChangeset{
+			sourceProject.UUID: {
+				ProjectMetadata: LiquidProjectMetadataFromDBProject(sourceProject, sourceDomain),
+				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
+					t.path.ResourceName: {
+						TotalConfirmedBefore:  sourceStats.Committed,
+						TotalConfirmedAfter:   sourceStatsAfter.Committed,
+						TotalGuaranteedBefore: sourceStats.Guaranteed,
+						TotalGuaranteedAfter:  sourceStatsAfter.Guaranteed,
+						Commitments: []liquid.Commitment{
+							{
+								UUID:      c.UUID,
+								OldStatus: Some(c.Status),
+								NewStatus: None[liquid.CommitmentStatus](),
+								Amount:    c.Amount,
+								ConfirmBy: c.ConfirmBy,
+								ExpiresAt: c.ExpiresAt,
+							},
+						},
+					},
+				},
+			},
+			targetProject.UUID: {
+				ProjectMetadata: LiquidProjectMetadataFromDBProject(targetProject, targetDomain),
+				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
+					t.path.ResourceName: {
+						TotalConfirmedBefore:  targetStats.Committed,
+						TotalConfirmedAfter:   targetStatsAfter.Committed,
+						TotalGuaranteedBefore: targetStats.Guaranteed,
+						TotalGuaranteedAfter:  targetStatsAfter.Guaranteed,
+						Commitments: []liquid.Commitment{
+							{
+								UUID:      c.UUID,
+								OldStatus: None[liquid.CommitmentStatus](),
+								NewStatus: Some(c.Status),
+								Amount:    c.Amount,
+								ConfirmBy: c.ConfirmBy,
+								ExpiresAt: c.ExpiresAt,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// check that the ccr is accepted
+	result, err = t.delegateChangeCommitmentsWithShortcut(ctx, ccr)
+	if err != nil || result.RejectionReason != "" {
+		return ccr, result, err
+	}
+
+	// adjust stats locally and make sure that the commitment does not get consumed anymore
+	t.updateStats(ccr)
+	if _, exists := t.transferableCommitmentsByID[c.ID]; exists {
+		delete(t.transferableCommitmentsByID, c.ID)
+		t.transferableCommitments = slices.DeleteFunc(t.transferableCommitments, func(tc *db.ProjectCommitment) bool { return tc.ID == c.ID })
+	}
+	return ccr, result, nil
+}
+
 // ConfirmTransferableCommitmentIfExists should be used between calls to CanConfirmWithTransfers
 // when a commitment has been confirmed outside of the cache. The function does nothing,
 // when the commitment with the given ID is not in the cache.
@@ -527,9 +620,9 @@ func (t *TransferableCommitmentCache) delegateChangeCommitmentsWithShortcut(ctx
 		}
 		accepted := t.stats.CanAcceptCommitmentChanges(additions, subtractions, behavior)
 		if !accepted {
-			result = liquid.CommitmentChangeResponse{
-				RejectionReason: "not enough capacity!",
-				RetryAt:         None[time.Time](),
+			result, err = rejectCommitmentChangeForLackOfCapacity(t.dbi, t.path.ServiceType)
+			if err != nil {
+				return result, err
 			}
 		}
 	default:
@@ -551,9 +644,10 @@ func (t *TransferableCommitmentCache) updateStats(ccr liquid.CommitmentChangeReq
 		rcc := pcc.ByResource[t.path.ResourceName]
 		affectedProject := t.affectedProjectsByUUID[projectUUID]
 		projectStats := t.stats.ProjectStats[affectedProject.ID]
-		if rcc.TotalConfirmedAfter != rcc.TotalConfirmedBefore {
+		if rcc.TotalConfirmedAfter != rcc.TotalConfirmedBefore || rcc.TotalGuaranteedAfter != rcc.TotalGuaranteedBefore {
 			newProjectStats := projectAZAllocationStats{
 				Committed:          rcc.TotalConfirmedAfter,
+				Guaranteed:         rcc.TotalGuaranteedAfter,
 				Usage:              projectStats.Usage,
 				MinHistoricalUsage: projectStats.MinHistoricalUsage,
 				MaxHistoricalUsage: projectStats.MaxHistoricalUsage,
@@ -0,0 +1,316 @@
+// SPDX-FileCopyrightText: 2022 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package datamodel
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/sapcc/go-api-declarations/cadf"
+	"github.com/sapcc/go-api-declarations/limes"
+	limesrates "github.com/sapcc/go-api-declarations/limes/rates"
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/audittools"
+	"github.com/sapcc/go-bits/gopherpolicy"
+	"github.com/sapcc/go-bits/sqlext"
+
+	"github.com/sapcc/limes/internal/audit"
+	"github.com/sapcc/limes/internal/core"
+	"github.com/sapcc/limes/internal/db"
+	"github.com/sapcc/limes/internal/reports"
+)
+
+// RateLimitUpdater contains most of the business logic for PUT on rate limits.
+// It is shared between the v1 and v2 APIs.
+type RateLimitUpdater struct {
+	// scope (all fields are always set since rate limits can only be updated on
+	// the project level)
+	Cluster *core.Cluster
+	Domain  *db.Domain
+	Project *db.Project
+
+	// AuthZ info
+	CanSetRateLimit func(db.ServiceType) bool
+	Auditor         audittools.Auditor
+
+	// Filled by ValidateInput() with the keys being the service type and the rate name.
+	Requests map[db.ServiceType]map[liquid.RateName]RateLimitRequest
+}
+
+// RateLimitRequest describes a single rate limit that a PUT requests wants to change.
+// It appears in type RateLimitUpdater.
+type RateLimitRequest struct {
+	Unit            limes.Unit
+	OldLimit        uint64
+	NewLimit        uint64
+	OldWindow       limesrates.Window
+	NewWindow       limesrates.Window
+	ValidationError *RateValidationError
+}
+
+// RateValidationError appears in the Limes API in the POST .../simulate-put responses.
+type RateValidationError struct {
+	Status  int    `json:"status"` // an HTTP status code, e.g. http.StatusForbidden
+	Message string `json:"message"`
+}
+
+var updateProjectRateLimitsQuery = sqlext.SimplifyWhitespace(`
+	MERGE INTO project_rates pr
+	USING json_to_recordset($1::json) src (project_id BIGINT, rate_id BIGINT, rate_limit BIGINT, window_ns BIGINT)
+	ON src.project_id = pr.project_id AND src.rate_id = pr.rate_id
+	WHEN MATCHED THEN UPDATE SET rate_limit = src.rate_limit, window_ns = src.window_ns
+	WHEN NOT MATCHED BY TARGET THEN INSERT (project_id, rate_id, rate_limit, window_ns, usage_as_bigint) VALUES (src.project_id, src.rate_id, src.rate_limit, src.window_ns, 0)
+	WHEN NOT MATCHED BY SOURCE THEN DO NOTHING
+`)
+
+// ValidateInput reads the given input and validates the quotas contained therein.
+// Results are collected into u.Requests. The return value is only set for unexpected
+// errors, not for validation errors.
+func (u *RateLimitUpdater) ValidateInput(input limesrates.RateRequest, dbi db.Interface) error {
+	sis := u.Cluster.SIC.GetSnapshot()
+	nm := core.BuildRateNameMapping(u.Cluster, sis)
+	requested := make(map[db.ServiceType]map[liquid.RateName]limesrates.RateLimitRequest)
+
+	for apiServiceType, in := range input {
+		for apiRateName, newRateLimit := range in {
+			dbServiceType, dbRateName, exists := nm.MapFromV1API(apiServiceType, apiRateName)
+			if !exists {
+				// it is ugly that this breaks the existing error reporting format, but
+				// since we don't have a DB-level identifier, we cannot record this in
+				// our usual structure
+				return fmt.Errorf("no such rate: %s/%s", apiServiceType, apiRateName)
+			}
+			if requested[dbServiceType] == nil {
+				requested[dbServiceType] = make(map[liquid.RateName]limesrates.RateLimitRequest)
+			}
+			requested[dbServiceType][dbRateName] = newRateLimit
+		}
+	}
+
+	return u.ValidateRequests(requested, dbi)
+}
+
+// ValidateRequests is like ValidateInput, but takes the requested rate limits
+// with the service types and rate names used in the DB. Requests for rates
+// that do not exist are reported as validation errors.
+func (u *RateLimitUpdater) ValidateRequests(requested map[db.ServiceType]map[liquid.RateName]limesrates.RateLimitRequest, dbi db.Interface) error {
+	sis := u.Cluster.SIC.GetSnapshot()
+
+	var projectReport *limesrates.ProjectReport
+	err := reports.GetProjectRates(u.Cluster, *u.Domain, u.Project, dbi, reports.Filter{}, sis, func(r *limesrates.ProjectReport) error {
+		projectReport = r
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if projectReport == nil {
+		return errors.New("no resource data found for project")
+	}
+
+	u.Requests = make(map[db.ServiceType]map[liquid.RateName]RateLimitRequest)
+
+	// Go through all services and validate the requested rate limits.
+	for dbServiceType, in := range requested {
+		for dbRateName, newRateLimit := range in {
+			if u.Requests[dbServiceType] == nil {
+				u.Requests[dbServiceType] = make(map[liquid.RateName]RateLimitRequest)
+			}
+
+			req := RateLimitRequest{
+				NewLimit:  newRateLimit.Limit,
+				NewWindow: newRateLimit.Window,
+			}
+
+			_, exists := sis.GetRateForPath(db.RatePath{ServiceType: dbServiceType, RateName: dbRateName})
+			if !exists {
+				req.ValidationError = &RateValidationError{
+					Status:  http.StatusNotFound,
+					Message: "no such rate",
+				}
+				u.Requests[dbServiceType][dbRateName] = req
+				continue
+			}
+			serviceConfig, ok := u.Cluster.Config.GetLiquidConfigurationForType(dbServiceType)
+			if !ok {
+				// defense in depth: should not occur because then we should have had `!exists` above
+				continue
+			}
+
+			// only allow setting rate limits for which a default exists
+			defaultRateLimit, exists := serviceConfig.RateLimits.GetProjectDefaultRateLimit(dbRateName).Unpack()
+			if exists {
+				req.Unit = defaultRateLimit.Unit
+			} else {
+				req.ValidationError = &RateValidationError{
+					Status:  http.StatusForbidden,
+					Message: "user is not allowed to create new rate limits",
+				}
+				u.Requests[dbServiceType][dbRateName] = req
+				continue
+			}
+
+			apiIdentity := u.Cluster.BehaviorForRate(dbServiceType, dbRateName).IdentityInV1API
+			if projectService, exists := projectReport.Services[apiIdentity.ServiceType]; exists {
+				projectRate, exists := projectService.Rates[apiIdentity.Name]
+				if exists && projectRate.Limit != 0 && projectRate.Window != nil {
+					req.OldLimit = projectRate.Limit
+					req.OldWindow = *projectRate.Window
+				} else {
+					req.OldLimit = defaultRateLimit.Limit
+					req.OldWindow = defaultRateLimit.Window
+				}
+			}
+
+			// skip if rate limit was not changed
+			if req.OldLimit == req.NewLimit && req.OldWindow == req.NewWindow {
+				continue
+			}
+
+			// value is valid and novel -> perform further validation
+			req.ValidationError = u.validateRateLimit(dbServiceType)
+			u.Requests[dbServiceType][dbRateName] = req
+		}
+	}
+
+	return nil
+}
+
+func (u RateLimitUpdater) validateRateLimit(serviceType db.ServiceType) *RateValidationError {
+	if u.CanSetRateLimit(serviceType) {
+		return nil
+	}
+	return &RateValidationError{
+		Status:  http.StatusForbidden,
+		Message: fmt.Sprintf("user is not allowed to set %q rate limits", serviceType),
+	}
+}
+
+// IsValid returns true if all u.LimitRequests are valid (i.e. ValidationError == nil).
+func (u RateLimitUpdater) IsValid() bool {
+	for _, reqs := range u.Requests {
+		for _, req := range reqs {
+			if req.ValidationError != nil {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// WriteRateLimits writes the requested rate limits into project_rates, creating records as necessary.
+// It may only be used when `u.IsValid()` is true.
+func (u RateLimitUpdater) WriteRateLimits(dbi db.Interface) error {
+	sis := u.Cluster.SIC.GetSnapshot()
+
+	// the db types do not have json tags, additionally the Window type serializes into a human readable format - not DB compatible.
+	type serializableProjectRate struct {
+		ProjectID db.ProjectID `json:"project_id"`
+		RateID    db.RateID    `json:"rate_id"`
+		Limit     uint64       `json:"rate_limit"`
+		Window    uint64       `json:"window_ns"`
+	}
+
+	var ratesToUpdate []serializableProjectRate
+	for _, dbServiceType := range slices.Sorted(maps.Keys(u.Requests)) {
+		reqs := u.Requests[dbServiceType]
+		for _, dbRateName := range slices.Sorted(maps.Keys(reqs)) {
+			rate, exists := sis.GetRateForPath(db.RatePath{ServiceType: dbServiceType, RateName: dbRateName})
+			if !exists {
+				// defense in depth: should not occur because ValidateRequests() rejects unknown rates
+				continue
+			}
+			req := reqs[dbRateName]
+			ratesToUpdate = append(ratesToUpdate, serializableProjectRate{
+				ProjectID: u.Project.ID,
+				RateID:    rate.ID,
+				Limit:     req.NewLimit,
+				Window:    uint64(req.NewWindow),
+			})
+		}
+	}
+	if len(ratesToUpdate) == 0 {
+		return nil
+	}
+
+	buf, err := json.Marshal(ratesToUpdate)
+	if err != nil {
+		return err
+	}
+	_, err = dbi.Exec(updateProjectRateLimitsQuery, string(buf))
+	return err
+}
+
+////////////////////////////////////////////////////////////////////////////////
+// integration with package audit
+
+// CommitAuditTrail prepares an audit.Trail instance for this updater and
+// commits it.
+func (u RateLimitUpdater) CommitAuditTrail(token *gopherpolicy.Token, r *http.Request, requestTime time.Time) {
+	statusCode := http.StatusOK
+	if !u.IsValid() {
+		statusCode = http.StatusUnprocessableEntity
+	}
+	u.CommitAuditTrailWithStatus(token, r, requestTime, statusCode)
+}
+
+// CommitAuditTrailWithStatus is like CommitAuditTrail, but reports the given
+// HTTP status code. If the status code does not indicate success, all requested
+// rate limits are reported as rejected.
+func (u RateLimitUpdater) CommitAuditTrailWithStatus(token *gopherpolicy.Token, r *http.Request, requestTime time.Time, statusCode int) {
+	invalid := statusCode >= http.StatusBadRequest
+	sis := u.Cluster.SIC.GetSnapshot()
+
+	for _, dbServiceType := range slices.Sorted(maps.Keys(u.Requests)) {
+		reqs := u.Requests[dbServiceType]
+		for _, dbRateName := range slices.Sorted(maps.Keys(reqs)) {
+			req := reqs[dbRateName]
+			if _, exists := sis.GetRateForPath(db.RatePath{ServiceType: dbServiceType, RateName: dbRateName}); !exists {
+				// rates that do not exist cannot be represented in the audit trail
+				continue
+			}
+
+			// if !u.IsValid(), then all requested quotas in this PUT are considered
+			// invalid (and none are committed), so set the rejectReason to explain this
+			rejectReason := ""
+			if invalid {
+				if req.ValidationError == nil {
+					rejectReason = "cannot commit this because other values in this request are unacceptable"
+				} else {
+					rejectReason = req.ValidationError.Message
+				}
+			}
+
+			apiIdentity := u.Cluster.BehaviorForRate(dbServiceType, dbRateName).IdentityInV1API
+			u.Auditor.Record(audittools.Event{
+				Time:       requestTime,
+				Request:    r,
+				User:       token,
+				ReasonCode: statusCode,
+				Action:     cadf.UpdateAction,
+				Target: audit.RateLimitEventTarget{
+					DomainID:    u.Domain.UUID,
+					DomainName:  u.Domain.Name,
+					ProjectID:   u.Project.UUID,
+					ProjectName: u.Project.Name,
+					ServiceType: apiIdentity.ServiceType,
+					Name:        apiIdentity.Name,
+					Payload: audit.RateLimitChange{
+						OldLimit:     req.OldLimit,
+						NewLimit:     req.NewLimit,
+						OldWindow:    req.OldWindow,
+						NewWindow:    req.NewWindow,
+						Unit:         req.Unit,
+						RejectReason: rejectReason,
+					},
+				},
+			})
+		}
+	}
+}
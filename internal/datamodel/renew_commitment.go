@@ -0,0 +1,138 @@
+// SPDX-FileCopyrightText: 2026 SAP SE or an SAP affiliate company
+// SPDX-License-Identifier: Apache-2.0
+
+package datamodel
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sapcc/go-api-declarations/liquid"
+	"github.com/sapcc/go-bits/sqlext"
+
+	"github.com/sapcc/limes/internal/core"
+	"github.com/sapcc/limes/internal/db"
+
+	. "go.xyrillian.de/gg/option"
+)
+
+// CommitmentRenewal describes a renewal of an existing commitment.
+// It appears in the arguments of RenewCommitment.
+type CommitmentRenewal struct {
+	Commitment  db.ProjectCommitment
+	Project     db.Project
+	Domain      db.Domain
+	Path        db.AZResourcePath
+	CreatorUUID string
+	CreatorName string
+}
+
+var renewCommitmentStatsQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
+	SELECT COALESCE(SUM(amount) FILTER (WHERE status = {{liquid.CommitmentStatusConfirmed}}), 0) AS total_confirmed,
+	       COALESCE(SUM(amount) FILTER (WHERE status = {{liquid.CommitmentStatusGuaranteed}}), 0) AS total_guaranteed
+	  FROM project_commitments
+	 WHERE project_id = $1 AND az_resource_id = $2
+`))
+
+// RenewCommitment creates a planned commitment that takes over once the given commitment expires,
+// and links both commitments through their workflow contexts.
+// This is used both by the renewal API and by the automatic renewal in the collector.
+//
+// The caller must check beforehand that the commitment can be renewed,
+// and must hold a lock on the commitment within the given transaction.
+// The returned CommitmentChangeRequest can be used to generate audit events.
+func RenewCommitment(ctx context.Context, cluster *core.Cluster, tx db.Interface, r CommitmentRenewal, sis core.ServiceInfoSnapshot, now time.Time, generateProjectCommitmentUUID func() liquid.CommitmentUUID) (db.ProjectCommitment, liquid.CommitmentChangeRequest, error) {
+	service, ok := sis.GetServiceForType(r.Path.ServiceType)
+	if !ok {
+		return db.ProjectCommitment{}, liquid.CommitmentChangeRequest{}, fmt.Errorf("service %s not found when renewing commitment %s", r.Path.ServiceType, r.Commitment.UUID)
+	}
+
+	var totalConfirmed, totalGuaranteed uint64
+	err := tx.QueryRow(renewCommitmentStatsQuery, r.Project.ID, r.Commitment.AZResourceID).Scan(&totalConfirmed, &totalGuaranteed)
+	if err != nil {
+		return db.ProjectCommitment{}, liquid.CommitmentChangeRequest{}, err
+	}
+
+	creationContext := db.CommitmentWorkflowContext{
+		Reason:                 db.CommitmentReasonRenew,
+		RelatedCommitmentIDs:   []db.ProjectCommitmentID{r.Commitment.ID},
+		RelatedCommitmentUUIDs: []liquid.CommitmentUUID{r.Commitment.UUID},
+	}
+	buf, err := json.Marshal(creationContext)
+	if err != nil {
+		return db.ProjectCommitment{}, liquid.CommitmentChangeRequest{}, err
+	}
+	dbRenewedCommitment := db.ProjectCommitment{
+		UUID:                generateProjectCommitmentUUID(),
+		ProjectID:           r.Project.ID,
+		AZResourceID:        r.Commitment.AZResourceID,
+		Amount:              r.Commitment.Amount,
+		Duration:            r.Commitment.Duration,
+		CreatedAt:           now,
+		UpdatedAt:           now,
+		CreatorUUID:         r.CreatorUUID,
+		CreatorName:         r.CreatorName,
+		ConfirmBy:           Some(r.Commitment.ExpiresAt),
+		ExpiresAt:           r.Commitment.Duration.AddTo(r.Commitment.ExpiresAt),
+		Status:              liquid.CommitmentStatusPlanned,
+		CreationContextJSON: json.RawMessage(buf),
+	}
+	err = tx.Insert(&dbRenewedCommitment)
+	if err != nil {
+		return db.ProjectCommitment{}, liquid.CommitmentChangeRequest{}, err
+	}
+
+	renewContext := db.CommitmentWorkflowContext{
+		Reason:                 db.CommitmentReasonRenew,
+		RelatedCommitmentIDs:   []db.ProjectCommitmentID{dbRenewedCommitment.ID},
+		RelatedCommitmentUUIDs: []liquid.CommitmentUUID{dbRenewedCommitment.UUID},
+	}
+	buf, err = json.Marshal(renewContext)
+	if err != nil {
+		return db.ProjectCommitment{}, liquid.CommitmentChangeRequest{}, err
+	}
+	dbCommitment := r.Commitment
+	dbCommitment.UpdatedAt = now
+	dbCommitment.RenewContextJSON = Some(json.RawMessage(buf))
+	_, err = tx.Update(&dbCommitment)
+	if err != nil {
+		return db.ProjectCommitment{}, liquid.CommitmentChangeRequest{}, err
+	}
+
+	// since the renewed commitment is only planned, the totals do not change, so CommitmentChangeRequest.RequiresConfirmation() = false
+	// and the liquid cannot reject this change
+	ccr := liquid.CommitmentChangeRequest{
+		AZ:          r.Path.AvailabilityZone,
+		InfoVersion: service.LiquidVersion,
+		ByProject: map[liquid.ProjectUUID]liquid.ProjectCommitmentChangeset{
+			r.Project.UUID: {
+				ProjectMetadata: LiquidProjectMetadataFromDBProject(r.Project, r.Domain),
+				ByResource: map[liquid.ResourceName]liquid.ResourceCommitmentChangeset{
+					r.Path.ResourceName: {
+						TotalConfirmedBefore:  totalConfirmed,
+						TotalConfirmedAfter:   totalConfirmed,
+						TotalGuaranteedBefore: totalGuaranteed,
+						TotalGuaranteedAfter:  totalGuaranteed,
+						Commitments: []liquid.Commitment{
+							{
+								UUID:      dbRenewedCommitment.UUID,
+								OldStatus: None[liquid.CommitmentStatus](),
+								NewStatus: Some(liquid.CommitmentStatusPlanned),
+								Amount:    dbRenewedCommitment.Amount,
+								ConfirmBy: dbRenewedCommitment.ConfirmBy,
+								ExpiresAt: dbRenewedCommitment.ExpiresAt,
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	_, err = DelegateChangeCommitments(ctx, cluster, ccr, sis, service.Type, tx)
+	if err != nil {
+		return db.ProjectCommitment{}, liquid.CommitmentChangeRequest{}, err
+	}
+	return dbRenewedCommitment, ccr, nil
+}
@@ -27,14 +27,15 @@ type capacityScrapeBackchannelImpl struct {
 
 var (
 	getResourceDemandQuery = sqlext.SimplifyWhitespace(db.ExpandEnumPlaceholders(`
-		SELECT azr.az, pazr.usage, COALESCE(pc_view.confirmed, 0), COALESCE(pc_view.pending, 0), r.topology
+		SELECT azr.az, pazr.usage, COALESCE(pc_view.active, 0), COALESCE(pc_view.pending, 0), r.topology
 		  FROM services s
 		  JOIN resources r ON r.service_id = s.id
 		  JOIN az_resources azr ON azr.resource_id = r.id
 		  JOIN project_az_resources pazr ON pazr.az_resource_id = azr.id
 		  LEFT OUTER JOIN (
 		    SELECT az_resource_id, project_id,
-		           SUM(amount) FILTER (WHERE status = {{liquid.CommitmentStatusConfirmed}}) AS confirmed,
+		           -- guaranteed commitments count as active because their capacity is already reserved
+		           SUM(amount) FILTER (WHERE status IN ({{liquid.CommitmentStatusConfirmed}}, {{liquid.CommitmentStatusGuaranteed}})) AS active,
 		           SUM(amount) FILTER (WHERE status = {{liquid.CommitmentStatusPending}}) AS pending
 		      FROM project_commitments
 		     GROUP BY az_resource_id, project_id
@@ -138,4 +138,67 @@ var sqlMigrations = map[string]string{
 		UPDATE resources SET unit = '' WHERE unit = 'piece';
 		UPDATE rates SET unit = '' WHERE unit = 'piece';
 	`,
+	"083_add_idempotency_keys.up.sql": `
+		CREATE TABLE idempotency_keys (
+			id                BIGSERIAL    NOT NULL PRIMARY KEY,
+			user_uuid         TEXT         NOT NULL,
+			key               TEXT         NOT NULL,
+			request_hash      TEXT         NOT NULL,
+			response_json     TEXT         DEFAULT NULL, -- NULL while the original request is still being processed
+			created_at        TIMESTAMPTZ  NOT NULL,
+			reserved_at       TIMESTAMPTZ  NOT NULL,      -- when the request that is currently being processed has started
+			expires_at        TIMESTAMPTZ  NOT NULL,
+			UNIQUE (user_uuid, key)
+		);
+	`,
+	"083_add_idempotency_keys.down.sql": `
+		DROP TABLE idempotency_keys;
+	`,
+	"084_add_expiry_reminder_stages.up.sql": `
+		ALTER TABLE project_commitments ADD COLUMN expiry_reminder_notice_period TEXT DEFAULT NULL;
+		UPDATE project_commitments SET expiry_reminder_notice_period = '28 days' WHERE notified_for_expiration;
+		ALTER TABLE project_commitments DROP COLUMN notified_for_expiration;
+	`,
+	"084_add_expiry_reminder_stages.down.sql": `
+		ALTER TABLE project_commitments ADD COLUMN notified_for_expiration BOOLEAN NOT NULL DEFAULT FALSE;
+		UPDATE project_commitments SET notified_for_expiration = TRUE WHERE expiry_reminder_notice_period IS NOT NULL;
+		ALTER TABLE project_commitments DROP COLUMN expiry_reminder_notice_period;
+	`,
+	"085_add_project_commitments_archive.up.sql": `
+		CREATE TABLE project_commitments_archive (
+			id                      BIGINT       NOT NULL PRIMARY KEY, -- same as the original project_commitments.id
+			uuid                    TEXT         NOT NULL UNIQUE,
+			domain_uuid             TEXT         NOT NULL,
+			project_uuid            TEXT         NOT NULL,
+			service_type            TEXT         NOT NULL,
+			resource_name           TEXT         NOT NULL,
+			availability_zone       TEXT         NOT NULL,
+			status                  TEXT         NOT NULL,
+			amount                  BIGINT       NOT NULL,
+			duration                TEXT         NOT NULL,
+			created_at              TIMESTAMPTZ  NOT NULL,
+			creator_uuid            TEXT         NOT NULL,
+			creator_name            TEXT         NOT NULL,
+			confirm_by              TIMESTAMPTZ  DEFAULT NULL,
+			confirmed_at            TIMESTAMPTZ  DEFAULT NULL,
+			expires_at              TIMESTAMPTZ  NOT NULL,
+			superseded_at           TIMESTAMPTZ  DEFAULT NULL,
+			deleted_at              TIMESTAMPTZ  DEFAULT NULL,
+			updated_at              TIMESTAMPTZ  NOT NULL,
+			creation_context_json   JSONB        NOT NULL,
+			supersede_context_json  JSONB        DEFAULT NULL,
+			renew_context_json      JSONB        DEFAULT NULL,
+			archived_at             TIMESTAMPTZ  NOT NULL
+		);
+		CREATE INDEX project_commitments_archive_project_uuid_idx ON project_commitments_archive (project_uuid);
+	`,
+	"085_add_project_commitments_archive.down.sql": `
+		DROP TABLE project_commitments_archive;
+	`,
+	"086_add_project_commitments_archive_archived_at_idx.up.sql": `
+		CREATE INDEX project_commitments_archive_archived_at_id_idx ON project_commitments_archive (archived_at, id);
+	`,
+	"086_add_project_commitments_archive_archived_at_idx.down.sql": `
+		DROP INDEX project_commitments_archive_archived_at_id_idx;
+	`,
 }
@@ -219,9 +219,10 @@ type ProjectCommitment struct {
 	// if a mail should be sent after the commitments confirmation.
 	NotifyOnConfirm bool `db:"notify_on_confirm"`
 
-	// If commitments are about to expire, they get added into the mail queue.
-	// This attribute helps to identify commitments that are already queued.
-	NotifiedForExpiration bool `db:"notified_for_expiration"`
+	// If commitments are about to expire, reminders get added into the mail queue in several stages.
+	// This attribute contains the notice period of the last stage that was queued (or skipped) for this commitment.
+	// Stages with longer notice periods than this are considered to be done as well.
+	ExpiryReminderNoticePeriod Option[limesresources.CommitmentDuration] `db:"expiry_reminder_notice_period"`
 }
 
 // CommitmentWorkflowContext is the type definition for the JSON payload in the
@@ -242,8 +243,40 @@ const (
 	CommitmentReasonMerge   CommitmentReason = "merge"
 	CommitmentReasonRenew   CommitmentReason = "renew"
 	CommitmentReasonConsume CommitmentReason = "consume"
+	CommitmentReasonAmend   CommitmentReason = "amend"
 )
 
+// ArchivedProjectCommitment contains a record from the `project_commitments_archive` table.
+// Commitments are moved there from the `project_commitments` table once their retention period has passed.
+//
+// Since the project and resource of an archived commitment may be deleted later on,
+// they are identified by UUID and name instead of by ID.
+type ArchivedProjectCommitment struct {
+	ID                   ProjectCommitmentID               `db:"id"`
+	UUID                 liquid.CommitmentUUID             `db:"uuid"`
+	DomainUUID           string                            `db:"domain_uuid"`
+	ProjectUUID          liquid.ProjectUUID                `db:"project_uuid"`
+	ServiceType          ServiceType                       `db:"service_type"`
+	ResourceName         liquid.ResourceName               `db:"resource_name"`
+	AvailabilityZone     limes.AvailabilityZone            `db:"availability_zone"`
+	Status               liquid.CommitmentStatus           `db:"status"`
+	Amount               uint64                            `db:"amount"`
+	Duration             limesresources.CommitmentDuration `db:"duration"`
+	CreatedAt            time.Time                         `db:"created_at"`
+	CreatorUUID          string                            `db:"creator_uuid"`
+	CreatorName          string                            `db:"creator_name"`
+	ConfirmBy            Option[time.Time]                 `db:"confirm_by"`
+	ConfirmedAt          Option[time.Time]                 `db:"confirmed_at"`
+	ExpiresAt            time.Time                         `db:"expires_at"`
+	SupersededAt         Option[time.Time]                 `db:"superseded_at"`
+	DeletedAt            Option[time.Time]                 `db:"deleted_at"`
+	UpdatedAt            time.Time                         `db:"updated_at"`
+	CreationContextJSON  json.RawMessage                   `db:"creation_context_json"`
+	SupersedeContextJSON Option[json.RawMessage]           `db:"supersede_context_json"`
+	RenewContextJSON     Option[json.RawMessage]           `db:"renew_context_json"`
+	ArchivedAt           time.Time                         `db:"archived_at"`
+}
+
 // MailNotification contains a record from the `project_mail_notifications` table.
 type MailNotification struct {
 	ID                int64     `db:"id"`
@@ -274,6 +307,7 @@ func initGorp(db *gorp.DbMap) {
 	db.AddTableWithName(ProjectAZResource{}, "project_az_resources").SetKeys(true, "id")
 	db.AddTableWithName(ProjectRate{}, "project_rates").SetKeys(true, "id")
 	db.AddTableWithName(ProjectCommitment{}, "project_commitments").SetKeys(true, "id")
+	db.AddTableWithName(ArchivedProjectCommitment{}, "project_commitments_archive").SetKeys(false, "id")
 	db.AddTableWithName(MailNotification{}, "project_mail_notifications").SetKeys(true, "id")
 	db.AddTableWithName(Category{}, "categories").SetKeys(true, "id")
 }
@@ -10,15 +10,18 @@ import (
 	"strings"
 
 	"github.com/sapcc/go-api-declarations/liquid"
+
+	"github.com/sapcc/limes/apideclarations/apiv2/common"
 )
 
 // ServiceType identifies a backend service that can have resources or rates.
 //
 // This type is used for the service type columns that appear in the DB.
-// It is legally distinct from `limes.ServiceType` to ensure that the
+// It is an alias because the same identifiers appear in the v2 API, whose declarations cannot import this package.
+// Like the v2 API type, it is legally distinct from `limes.ServiceType` to ensure that the
 // ResourceBehavior.IdentityInV1API mapping is applied when converting between
 // API-level and DB-level identifiers.
-type ServiceType string
+type ServiceType = common.ServiceType
 
 // ServiceID is an ID into the services table. This typedef is
 // used to distinguish these IDs from IDs of other tables or raw int64 values.
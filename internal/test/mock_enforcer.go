@@ -30,6 +30,7 @@ type PolicyEnforcer struct {
 	IsProjectRole bool
 	// match by request attribute
 	RejectServiceType string
+	RejectProjectUUID string
 }
 
 // Enforce implements the gopherpolicy.Enforcer interface.
@@ -37,6 +38,9 @@ func (e *PolicyEnforcer) Enforce(rule string, ctx policy.Context) bool {
 	if e.RejectServiceType != "" && ctx.Request["service_type"] == e.RejectServiceType {
 		return false
 	}
+	if e.RejectProjectUUID != "" && ctx.Request["project_uuid"] == e.RejectProjectUUID {
+		return false
+	}
 	switch rule {
 	case "v2:domain:role":
 		return e.IsDomainRole
@@ -248,6 +248,9 @@ func taskCollect(ctx context.Context, cluster *core.Cluster, args []string, prov
 	go c.CheckConsistencyJob(nil).Run(ctx)
 	go c.CleanupOldCommitmentsJob(nil).Run(ctx)
 	go c.ScanDomainsAndProjectsJob(nil).Run(ctx)
+	if cluster.Config.CommitmentAutoRenewal.IsSome() {
+		go c.CommitmentAutoRenewalJob(nil).Run(ctx)
+	}
 
 	// start mail processing if requested
 	if mc, ok := mailClient.Unpack(); ok {